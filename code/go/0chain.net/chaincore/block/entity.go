@@ -7,7 +7,6 @@ import (
 	"errors"
 	"fmt"
 	"math"
-	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -437,18 +436,7 @@ func (b *Block) GetMerkleTree() *util.MerkleTree {
 }
 
 func (b *Block) getHashData() string {
-	mt := b.GetMerkleTree()
-	merkleRoot := mt.GetRoot()
-	rmt := b.GetReceiptsMerkleTree()
-	rMerkleRoot := rmt.GetRoot()
-	hashData := b.MinerID + ":" + b.PrevHash + ":" + common.TimeToString(b.CreationDate) + ":" + strconv.FormatInt(b.Round, 10) + ":" + strconv.FormatInt(b.GetRoundRandomSeed(), 10) + ":" + merkleRoot + ":" + rMerkleRoot
-	if b.MagicBlock != nil {
-		if b.MagicBlock.Hash == "" {
-			b.MagicBlock.Hash = b.MagicBlock.GetHash()
-		}
-		hashData += ":" + b.MagicBlock.Hash
-	}
-	return hashData
+	return b.GetHeader().getHashData()
 }
 
 /*ComputeHash - compute the hash of the block */
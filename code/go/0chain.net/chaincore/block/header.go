@@ -0,0 +1,59 @@
+package block
+
+import (
+	"strconv"
+
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+)
+
+// Header is the signed part of a block. It's enough to check hash and
+// signature of a block without the block body.
+type Header struct {
+	MinerID               datastore.Key    `json:"miner_id"`
+	PrevHash              string           `json:"prev_hash"`
+	CreationDate          common.Timestamp `json:"creation_date"`
+	Round                 int64            `json:"round"`
+	RoundRandomSeed       int64            `json:"round_random_seed"`
+	MerkleTreeRoot        string           `json:"merkle_tree_root"`
+	ReceiptMerkleTreeRoot string           `json:"receipt_merkle_tree_root"`
+	MagicBlockHash        string           `json:"magic_block_hash,omitempty"`
+	Hash                  string           `json:"hash"`
+	Signature             string           `json:"signature"`
+}
+
+// GetHeader returns header of the block.
+func (b *Block) GetHeader() *Header {
+	var h = &Header{
+		MinerID:               b.MinerID,
+		PrevHash:              b.PrevHash,
+		CreationDate:          b.CreationDate,
+		Round:                 b.Round,
+		RoundRandomSeed:       b.GetRoundRandomSeed(),
+		MerkleTreeRoot:        b.GetMerkleTree().GetRoot(),
+		ReceiptMerkleTreeRoot: b.GetReceiptsMerkleTree().GetRoot(),
+		Hash:                  b.Hash,
+		Signature:             b.Signature,
+	}
+	if b.MagicBlock != nil {
+		if b.MagicBlock.Hash == "" {
+			b.MagicBlock.Hash = b.MagicBlock.GetHash()
+		}
+		h.MagicBlockHash = b.MagicBlock.Hash
+	}
+	return h
+}
+
+func (h *Header) getHashData() string {
+	hashData := h.MinerID + ":" + h.PrevHash + ":" + common.TimeToString(h.CreationDate) + ":" + strconv.FormatInt(h.Round, 10) + ":" + strconv.FormatInt(h.RoundRandomSeed, 10) + ":" + h.MerkleTreeRoot + ":" + h.ReceiptMerkleTreeRoot
+	if h.MagicBlockHash != "" {
+		hashData += ":" + h.MagicBlockHash
+	}
+	return hashData
+}
+
+// ComputeHash of the block by its header.
+func (h *Header) ComputeHash() string {
+	return encryption.Hash(h.getHashData())
+}
@@ -77,13 +77,17 @@ type Config struct {
 
 	ReuseTransactions bool `json:"reuse_txns"` // indicates if transactions from unrelated blocks can be reused
 
+	ParallelTxnsBatchSize int `json:"parallel_txns_batch_size"` // number of transactions executed in parallel generating a block, 0 or 1 is serial execution
+
 	ClientSignatureScheme string `json:"client_signature_scheme"` // indicates which signature scheme is being used
 
 	MinActiveSharders    int `json:"min_active_sharders"`    // Minimum active sharders required to validate blocks
 	MinActiveReplicators int `json:"min_active_replicators"` // Minimum active replicators of a block that should be active to verify the block
 
-	SmartContractTimeout             time.Duration `json:"smart_contract_timeout"`               // time after which the smart contract execution will timeout
+	SmartContractTimeout             time.Duration `json:"smart_contract_timeout"`               // smart contract execution time after which block generation stops adding transactions
 	SmartContractSettingUpdatePeriod int64         `json:"smart_contract_setting_update_period"` // rounds settings are updated
+	SmartContractMaxGas              int64         `json:"smart_contract_max_gas"`               // max gas of a transaction, zero disables the gas metering
+	SmartContractGasPrice            int64         `json:"smart_contract_gas_price"`             // fee charged for a unit of gas
 
 	RoundTimeoutSofttoMin  int `json:"softto_min"`         // minimum time for softtimeout to kick in milliseconds
 	RoundTimeoutSofttoMult int `json:"softto_mult"`        // multiplier of mean network time for soft timeout
@@ -203,6 +207,14 @@ func (conf *Config) Update(cf *minersc.GlobalSettings) error {
 	if err != nil {
 		return err
 	}
+	conf.SmartContractMaxGas, err = cf.GetInt64(minersc.SmartContractMaxGas)
+	if err != nil {
+		return err
+	}
+	conf.SmartContractGasPrice, err = cf.GetInt64(minersc.SmartContractGasPrice)
+	if err != nil {
+		return err
+	}
 	conf.DbsEvents.Enabled, err = cf.GetBool(minersc.DbsEventsEnabled)
 	if err != nil {
 		return err
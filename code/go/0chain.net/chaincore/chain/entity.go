@@ -397,6 +397,10 @@ func (c *Chain) Delete(ctx context.Context) error {
 // DefaultSmartContractTimeout represents the default smart contract execution timeout
 const DefaultSmartContractTimeout = time.Second
 
+// DefaultSimulationMaxGas is the gas limit of a simulated smart contract
+// execution when the gas metering is disabled
+const DefaultSimulationMaxGas int64 = 10000000
+
 //NewChainFromConfig - create a new chain from config
 func NewChainFromConfig() *Chain {
 	chain := Provider().(*Chain)
@@ -456,6 +460,7 @@ func NewChainFromConfig() *Chain {
 		chain.BlockProposalWaitMode = BlockProposalWaitDynamic
 	}
 	chain.ReuseTransactions = viper.GetBool("server_chain.block.reuse_txns")
+	chain.ParallelTxnsBatchSize = viper.GetInt("server_chain.block.parallel_txns_batch_size")
 	chain.SetSignatureScheme(viper.GetString("server_chain.client.signature_scheme"))
 
 	chain.MinActiveSharders = viper.GetInt("server_chain.block.sharding.min_active_sharders")
@@ -465,6 +470,8 @@ func NewChainFromConfig() *Chain {
 		chain.SmartContractTimeout = DefaultSmartContractTimeout
 	}
 	chain.SmartContractSettingUpdatePeriod = viper.GetInt64("server_chain.smart_contract.setting_update_period")
+	chain.SmartContractMaxGas = viper.GetInt64("server_chain.smart_contract.max_gas")
+	chain.SmartContractGasPrice = viper.GetInt64("server_chain.smart_contract.gas_price")
 	chain.RoundTimeoutSofttoMin = viper.GetInt("server_chain.round_timeouts.softto_min")
 	chain.RoundTimeoutSofttoMult = viper.GetInt("server_chain.round_timeouts.softto_mult")
 	chain.RoundRestartMult = viper.GetInt("server_chain.round_timeouts.round_restart_mult")
@@ -1457,6 +1464,9 @@ func (c *Chain) UpdateNodesFromMagicBlock(newMagicBlock *block.MagicBlock) {
 	c.GetNodesPreviousInfo(newMagicBlock)
 
 	node.DeregisterNodes(keep)
+	if err := node.RotateTLSCertificate(keep); err != nil {
+		logging.Logger.Error("update nodes - rotate tls certificate", zap.Error(err))
+	}
 
 	// reset the monitor
 	ResetStatusMonitor(newMagicBlock.StartingRound)
@@ -0,0 +1,126 @@
+package chain_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"0chain.net/chaincore/block"
+	"0chain.net/chaincore/chain"
+	"0chain.net/chaincore/config"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+	"0chain.net/smartcontract/storagesc"
+)
+
+func TestForkSchedule(t *testing.T) {
+	_, err := config.NewForkSchedule(map[string]int64{"unknown": 1})
+	require.Error(t, err)
+	_, err = config.NewForkSchedule(map[string]int64{config.ForkFeeValidation: -1})
+	require.Error(t, err)
+
+	fs, err := config.NewForkSchedule(map[string]int64{
+		config.ForkFeeValidation: 10,
+		config.ForkGasMetering:   0,
+	})
+	require.NoError(t, err)
+	require.True(t, fs.IsActive(config.ForkGasMetering, 0))
+	require.False(t, fs.IsActive(config.ForkFeeValidation, 9))
+	require.True(t, fs.IsActive(config.ForkFeeValidation, 10))
+	require.Equal(t, []config.Fork{
+		{Name: config.ForkGasMetering, Round: 0},
+		{Name: config.ForkFeeValidation, Round: 10},
+	}, fs.Forks())
+
+	require.NoError(t, fs.Set(nil))
+	require.False(t, fs.IsActive(config.ForkGasMetering, 100))
+}
+
+// replayAcrossFork executes a transaction without a fee in each of given
+// number of rounds, returning the state roots and the failed rounds
+func replayAcrossFork(t *testing.T, c *chain.Chain, rounds int64) (
+	roots []util.Key, failed []int64) {
+
+	var (
+		mpt  = util.NewMerklePatriciaTrie(util.NewMemoryNodeDB(), 0, nil)
+		prev = block.NewBlock("", 0)
+	)
+	_, err := mpt.Insert(util.Path(clientID("a")), newTestState(t, 100, 0))
+	require.NoError(t, err)
+
+	for round := int64(1); round <= rounds; round++ {
+		b := block.NewBlock("", round)
+		b.PrevBlock = prev
+		b.ClientState = mpt
+		txn := newSendTxn(fmt.Sprint(round), "a", "b", 1)
+		if _, err := c.UpdateState(context.Background(), b, txn); err != nil {
+			failed = append(failed, round)
+		}
+		roots = append(roots, b.ClientState.GetRoot())
+		prev = b
+	}
+	return
+}
+
+func TestChain_UpdateStateAcrossFork(t *testing.T) {
+	var minFee = transaction.TXN_MIN_FEE
+	transaction.SetTxnFee(1)
+	defer transaction.SetTxnFee(minFee)
+	require.NoError(t, config.Forks.Set(map[string]int64{
+		config.ForkFeeValidation: 3,
+	}))
+	defer func() { require.NoError(t, config.Forks.Set(nil)) }()
+
+	var (
+		c             = chain.NewChainFromConfig()
+		roots, failed = replayAcrossFork(t, c, 5)
+	)
+	require.Equal(t, []int64{3, 4, 5}, failed)
+
+	// replaying the chain gives the same state
+	replayed, _ := replayAcrossFork(t, c, 5)
+	require.Equal(t, roots, replayed)
+
+	// transactions fail from the fork round without changing the state
+	require.NotEqual(t, roots[0], roots[1])
+	require.Equal(t, roots[1], roots[2])
+}
+
+func TestChain_UpdateStateProtocolTxnFee(t *testing.T) {
+	var minFee = transaction.TXN_MIN_FEE
+	transaction.SetTxnFee(1)
+	defer transaction.SetTxnFee(minFee)
+	require.NoError(t, config.Forks.Set(map[string]int64{
+		config.ForkFeeValidation: 0,
+	}))
+	defer func() { require.NoError(t, config.Forks.Set(nil)) }()
+
+	var (
+		b   = newParallelTestBlock(t, map[string]state.Balance{"a": 100})
+		c   = chain.NewChainFromConfig()
+		txn = &transaction.Transaction{
+			HashIDField:     datastore.HashIDField{Hash: encryption.Hash("rewards")},
+			ClientID:        clientID("miner"),
+			ToClientID:      storagesc.ADDRESS,
+			TransactionType: transaction.TxnTypeSmartContract,
+			TransactionData: `{"name":"pay_blobber_block_rewards","input":{}}`,
+		}
+	)
+	b.MinerID = clientID("miner")
+
+	// the protocol transactions of the generator have no fee
+	require.True(t, txn.IsProtocolTxn(b.MinerID))
+	_, err := c.UpdateState(context.Background(), b, txn)
+	require.NoError(t, err)
+
+	// the same call of another client is validated
+	txn.ClientID = clientID("other")
+	require.False(t, txn.IsProtocolTxn(b.MinerID))
+	_, err = c.UpdateState(context.Background(), b, txn)
+	require.Error(t, err)
+}
@@ -50,6 +50,7 @@ func SetupHandlers() {
 	http.HandleFunc("/v1/block/get/latest_finalized_magic_block", common.UserRateLimit(common.ToJSONResponse(LatestFinalizedMagicBlockHandler)))
 	http.HandleFunc("/v1/block/get/recent_finalized", common.UserRateLimit(common.ToJSONResponse(RecentFinalizedBlockHandler)))
 	http.HandleFunc("/v1/block/get/fee_stats", common.UserRateLimit(common.ToJSONResponse(LatestBlockFeeStatsHandler)))
+	http.HandleFunc("/v1/chain/get/forks", common.UserRateLimit(common.ToJSONResponse(ForksHandler)))
 
 	http.HandleFunc("/", common.UserRateLimit(HomePageHandler))
 	http.HandleFunc("/_diagnostics", common.UserRateLimit(DiagnosticsHomepageHandler))
@@ -90,6 +91,30 @@ func LatestBlockFeeStatsHandler(ctx context.Context, r *http.Request) (interface
 	return GetServerChain().FeeStats, nil
 }
 
+// ForkInfo is a hard fork of the schedule and whether it's active at the
+// latest finalized round.
+type ForkInfo struct {
+	config.Fork
+	Active bool `json:"active"`
+}
+
+/*ForksHandler - the hard forks schedule of the chain */
+func ForksHandler(ctx context.Context, r *http.Request) (interface{}, error) {
+	var round int64
+	if lfb := GetServerChain().GetLatestFinalizedBlock(); lfb != nil {
+		round = lfb.Round
+	}
+	var forks = config.Forks.Forks()
+	var infos = make([]*ForkInfo, 0, len(forks))
+	for _, fork := range forks {
+		infos = append(infos, &ForkInfo{
+			Fork:   fork,
+			Active: config.IsActive(fork.Name, round),
+		})
+	}
+	return infos, nil
+}
+
 /*PutChainHandler - Given a chain data, it stores it */
 func PutChainHandler(ctx context.Context, entity datastore.Entity) (interface{}, error) {
 	return datastore.PutEntityHandler(ctx, entity)
@@ -0,0 +1,284 @@
+package chain
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"0chain.net/chaincore/block"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/logging"
+	"0chain.net/core/util"
+	"0chain.net/smartcontract/dbs/event"
+	"0chain.net/smartcontract/minersc"
+	metrics "github.com/rcrowley/go-metrics"
+	"go.uber.org/zap"
+)
+
+var (
+	// ParallelTxnsConflicts counts speculatively executed transactions
+	// executed again because of conflicts with preceding transactions.
+	ParallelTxnsConflicts = metrics.GetOrRegisterCounter("parallel_txns_conflicts", nil)
+	// ParallelTxnsCommitted counts speculatively executed transactions
+	// committed as is.
+	ParallelTxnsCommitted = metrics.GetOrRegisterCounter("parallel_txns_committed", nil)
+)
+
+// mptOp is an insert, or a delete if the value is nil, of a MPT path
+type mptOp struct {
+	path  util.Path
+	value util.Serializable
+}
+
+// accessRecorder is a MPT recording the paths read and written through it.
+// Iterating the MPT reads all the paths.
+type accessRecorder struct {
+	util.MerklePatriciaTrieI
+	mutex   sync.Mutex
+	reads   map[string]struct{}
+	writes  map[string]struct{}
+	ops     []mptOp
+	readAll bool
+}
+
+func newAccessRecorder(mpt util.MerklePatriciaTrieI) *accessRecorder {
+	return &accessRecorder{
+		MerklePatriciaTrieI: mpt,
+		reads:               make(map[string]struct{}),
+		writes:              make(map[string]struct{}),
+	}
+}
+
+func (ar *accessRecorder) read(path util.Path) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+	ar.reads[string(path)] = struct{}{}
+}
+
+func (ar *accessRecorder) write(path util.Path, value util.Serializable) {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+	ar.writes[string(path)] = struct{}{}
+	ar.ops = append(ar.ops, mptOp{path: path, value: value})
+}
+
+func (ar *accessRecorder) GetNodeValue(path util.Path) (util.Serializable, error) {
+	ar.read(path)
+	return ar.MerklePatriciaTrieI.GetNodeValue(path)
+}
+
+func (ar *accessRecorder) Insert(path util.Path, value util.Serializable) (util.Key, error) {
+	key, err := ar.MerklePatriciaTrieI.Insert(path, value)
+	if err == nil {
+		// the value can be changed after the insert
+		ar.write(path, &util.SecureSerializableValue{Buffer: value.Encode()})
+	}
+	return key, err
+}
+
+func (ar *accessRecorder) Delete(path util.Path) (util.Key, error) {
+	key, err := ar.MerklePatriciaTrieI.Delete(path)
+	if err == nil {
+		ar.write(path, nil)
+	}
+	return key, err
+}
+
+func (ar *accessRecorder) setReadAll() {
+	ar.mutex.Lock()
+	defer ar.mutex.Unlock()
+	ar.readAll = true
+}
+
+func (ar *accessRecorder) Iterate(ctx context.Context, handler util.MPTIteratorHandler, visitNodeTypes byte) error {
+	ar.setReadAll()
+	return ar.MerklePatriciaTrieI.Iterate(ctx, handler, visitNodeTypes)
+}
+
+func (ar *accessRecorder) IterateFrom(ctx context.Context, node util.Key, handler util.MPTIteratorHandler, visitNodeTypes byte) error {
+	ar.setReadAll()
+	return ar.MerklePatriciaTrieI.IterateFrom(ctx, node, handler, visitNodeTypes)
+}
+
+func (ar *accessRecorder) GetPathNodes(path util.Path) ([]util.Node, error) {
+	ar.setReadAll()
+	return ar.MerklePatriciaTrieI.GetPathNodes(path)
+}
+
+// touches returns true if the path is recorded as read or written
+func (ar *accessRecorder) touches(path string) bool {
+	if ar.readAll {
+		return true
+	}
+	var _, read = ar.reads[path]
+	var _, written = ar.writes[path]
+	return read || written
+}
+
+// conflicts returns true if the recorded reads or writes include any of
+// given written paths
+func (ar *accessRecorder) conflicts(written map[string]struct{}) bool {
+	if len(written) == 0 {
+		return false
+	}
+	if ar.readAll {
+		return true
+	}
+	for path := range ar.reads {
+		if _, ok := written[path]; ok {
+			return true
+		}
+	}
+	for path := range ar.writes {
+		if _, ok := written[path]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// speculation is a transaction executed against the block state snapshot
+type speculation struct {
+	state   *accessRecorder
+	events  []event.Event
+	fee     state.Balance
+	err     error
+	elapsed time.Duration
+}
+
+// TxnExecutedHandler is called for each transaction in order once it's
+// applied to the block state or failed, with the time the execution of the
+// transaction took. The execution stops if it returns false.
+type TxnExecutedHandler func(txn *transaction.Transaction, events []event.Event, err error, elapsed time.Duration) bool
+
+// UpdateStateParallel applies the transactions to the block state in given
+// order. The transactions are executed in parallel against the state the
+// block has before the batch, recording MPT paths they read and write. Then
+// the transactions are committed in order: a transaction that touches a path
+// written by a preceding transaction of the batch is executed again against
+// the current state, the writes of others are applied as is. The resulting
+// state is the same the serial execution gives. Transactions failed to apply
+// don't change the state. The fees are taken from the clients by the
+// transactions, but paid to the miner SC once for the batch, otherwise every
+// transaction would conflict with the preceding ones on the miner SC balance.
+func (c *Chain) UpdateStateParallel(ctx context.Context, b *block.Block,
+	txns []*transaction.Transaction, done TxnExecutedHandler) {
+
+	c.stateMutex.Lock()
+	defer c.stateMutex.Unlock()
+
+	if len(txns) == 1 {
+		var ts = time.Now()
+		events, err := c.updateState(ctx, b, txns[0])
+		done(txns[0], events, err, time.Since(ts))
+		return
+	}
+
+	var (
+		specs = make([]*speculation, len(txns))
+		wg    sync.WaitGroup
+	)
+	for i, txn := range txns {
+		wg.Add(1)
+		go func(i int, txn *transaction.Transaction) {
+			defer wg.Done()
+			var (
+				spec = &speculation{state: newAccessRecorder(CreateTxnMPT(b.ClientState))}
+				sctx = c.NewStateContext(b, spec.state, txn, nil)
+				ts   = time.Now()
+			)
+			_, spec.fee, spec.err = c.applyTxn(ctx, sctx, applyOptions{deferFee: true})
+			spec.elapsed = time.Since(ts)
+			spec.events = sctx.GetEvents()
+			specs[i] = spec
+		}(i, txn)
+	}
+	wg.Wait()
+
+	var (
+		written = make(map[string]struct{})
+		feePath = string(util.Path(minersc.ADDRESS))
+		fees    state.Balance // taken from the clients, not paid yet
+		feeTxn  *transaction.Transaction
+	)
+	var payFees = func() {
+		if fees == 0 {
+			return
+		}
+		if err := c.payTxnFees(b, feeTxn, fees); err != nil {
+			logging.Logger.Error("update state parallel - paying fees",
+				zap.Int64("round", b.Round), zap.Error(err))
+		}
+		fees, feeTxn = 0, nil
+		written[feePath] = struct{}{}
+	}
+	defer payFees()
+
+	for i, txn := range txns {
+		var (
+			spec   = specs[i]
+			events []event.Event
+			err    error
+		)
+		if fees > 0 && spec.state.touches(feePath) {
+			// the transaction uses the miner SC balance
+			payFees()
+		}
+		if spec.err == nil && !spec.state.conflicts(written) {
+			events, err = spec.events, c.commitTxnWrites(b, spec.state.ops)
+			if err == nil {
+				ParallelTxnsCommitted.Inc(1)
+			}
+		} else {
+			// the transaction can fail or succeed depending on the
+			// preceding transactions, execute it against the current state
+			ParallelTxnsConflicts.Inc(1)
+			var (
+				recorder = newAccessRecorder(CreateTxnMPT(b.ClientState))
+				sctx     = c.NewStateContext(b, recorder, txn, nil)
+				ts       = time.Now()
+			)
+			_, spec.fee, err = c.applyTxn(ctx, sctx, applyOptions{deferFee: true})
+			spec.elapsed = time.Since(ts)
+			if err == nil {
+				err = b.ClientState.MergeMPTChanges(recorder.MerklePatriciaTrieI)
+			}
+			events = sctx.GetEvents()
+			if err == nil {
+				spec.state = recorder
+			}
+		}
+		if err != nil {
+			logging.Logger.Debug("update state parallel - transaction failed",
+				zap.String("txn", txn.Hash), zap.Error(err))
+		} else {
+			for path := range spec.state.writes {
+				written[path] = struct{}{}
+			}
+			if spec.fee > 0 {
+				fees += spec.fee
+				feeTxn = txn
+			}
+		}
+		if !done(txn, events, err, spec.elapsed) {
+			return
+		}
+	}
+}
+
+// commitTxnWrites applies the writes of a transaction to the block state
+func (c *Chain) commitTxnWrites(b *block.Block, ops []mptOp) (err error) {
+	var clientState = CreateTxnMPT(b.ClientState)
+	for _, op := range ops {
+		if op.value == nil {
+			_, err = clientState.Delete(op.path)
+		} else {
+			_, err = clientState.Insert(op.path, op.value)
+		}
+		if err != nil {
+			return
+		}
+	}
+	return b.ClientState.MergeMPTChanges(clientState)
+}
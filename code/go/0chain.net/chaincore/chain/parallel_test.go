@@ -0,0 +1,155 @@
+package chain_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"0chain.net/chaincore/block"
+	"0chain.net/chaincore/chain"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+	"0chain.net/smartcontract/dbs/event"
+	"0chain.net/smartcontract/minersc"
+)
+
+// clientID of a test client, the state keys are hex
+func clientID(name string) string {
+	return encryption.Hash(name)
+}
+
+func newTestState(t *testing.T, balance state.Balance, round int64) *state.State {
+	s := &state.State{Balance: balance}
+	require.NoError(t, s.SetTxnHash(encryption.Hash("genesis")))
+	s.SetRound(round)
+	return s
+}
+
+func newParallelTestBlock(t *testing.T, balances map[string]state.Balance) *block.Block {
+	b := block.NewBlock("", 2)
+	b.PrevBlock = block.NewBlock("", 1)
+	b.ClientState = util.NewMerklePatriciaTrie(util.NewMemoryNodeDB(), 1, nil)
+	for id, balance := range balances {
+		_, err := b.ClientState.Insert(util.Path(clientID(id)), newTestState(t, balance, 1))
+		require.NoError(t, err)
+	}
+	return b
+}
+
+func newSendTxn(name, from, to string, value int64) *transaction.Transaction {
+	return &transaction.Transaction{
+		HashIDField:     datastore.HashIDField{Hash: encryption.Hash(name)},
+		ClientID:        clientID(from),
+		ToClientID:      clientID(to),
+		Value:           value,
+		TransactionType: transaction.TxnTypeSend,
+	}
+}
+
+func TestChain_UpdateStateParallel(t *testing.T) {
+	var (
+		balances = map[string]state.Balance{
+			"a": 100, "b": 100, "c": 100, "d": 100, "e": 100, "f": 50,
+		}
+		txns = func() []*transaction.Transaction {
+			return []*transaction.Transaction{
+				newSendTxn("1", "a", "b", 10),
+				newSendTxn("2", "c", "d", 5),
+				newSendTxn("3", "b", "e", 110), // depends on the first one
+				newSendTxn("4", "f", "a", 100), // insufficient balance
+				newSendTxn("5", "e", "f", 210), // depends on the third one
+				newSendTxn("6", "d", "c", 1),   // writes the paths of the second one
+			}
+		}
+		c = chain.NewChainFromConfig()
+	)
+
+	// serial execution
+	var (
+		serial       = newParallelTestBlock(t, balances)
+		serialFailed []string
+	)
+	for _, txn := range txns() {
+		if _, err := c.UpdateState(context.Background(), serial, txn); err != nil {
+			serialFailed = append(serialFailed, txn.Hash)
+		}
+	}
+
+	var (
+		parallel       = newParallelTestBlock(t, balances)
+		parallelFailed []string
+	)
+	c.UpdateStateParallel(context.Background(), parallel, txns(),
+		func(txn *transaction.Transaction, _ []event.Event, err error, _ time.Duration) bool {
+			if err != nil {
+				parallelFailed = append(parallelFailed, txn.Hash)
+			}
+			return true
+		})
+
+	require.Equal(t, []string{encryption.Hash("4")}, serialFailed)
+	require.Equal(t, serialFailed, parallelFailed)
+	require.Equal(t, serial.ClientState.GetRoot(), parallel.ClientState.GetRoot())
+
+	// stopped execution doesn't apply the rest of transactions
+	var (
+		stopped = newParallelTestBlock(t, balances)
+		applied int
+	)
+	c.UpdateStateParallel(context.Background(), stopped, txns()[:2],
+		func(*transaction.Transaction, []event.Event, error, time.Duration) bool {
+			applied++
+			return false
+		})
+	require.Equal(t, 1, applied)
+
+	var expected = newParallelTestBlock(t, balances)
+	_, err := c.UpdateState(context.Background(), expected, txns()[0])
+	require.NoError(t, err)
+	require.Equal(t, expected.ClientState.GetRoot(), stopped.ClientState.GetRoot())
+}
+
+func TestChain_UpdateStateParallelFees(t *testing.T) {
+	enableTestFees(t)
+	var (
+		balances = map[string]state.Balance{"a": 100, "b": 100, "c": 100}
+		txns     = func() []*transaction.Transaction {
+			var txns = []*transaction.Transaction{
+				newSendTxn("1", "a", "b", 10),
+				newSendTxn("2", "c", "d", 5), // independent of the first one
+				newSendTxn("3", "b", "c", 1), // depends on the first one
+			}
+			for _, txn := range txns {
+				txn.Fee = 2
+			}
+			return txns
+		}
+		c = chain.NewChainFromConfig()
+	)
+
+	var serial = newParallelTestBlock(t, balances)
+	for _, txn := range txns() {
+		_, err := c.UpdateState(context.Background(), serial, txn)
+		require.NoError(t, err)
+	}
+
+	var (
+		parallel  = newParallelTestBlock(t, balances)
+		conflicts = chain.ParallelTxnsConflicts.Count()
+	)
+	c.UpdateStateParallel(context.Background(), parallel, txns(),
+		func(_ *transaction.Transaction, _ []event.Event, err error, _ time.Duration) bool {
+			require.NoError(t, err)
+			return true
+		})
+
+	// the fees don't make the transactions conflict
+	require.EqualValues(t, 1, chain.ParallelTxnsConflicts.Count()-conflicts)
+	require.Equal(t, serial.ClientState.GetRoot(), parallel.ClientState.GetRoot())
+	require.EqualValues(t, 3*2, getTestBalance(t, parallel.ClientState, minersc.ADDRESS))
+}
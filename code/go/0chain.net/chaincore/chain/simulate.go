@@ -0,0 +1,120 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"0chain.net/chaincore/block"
+	"0chain.net/chaincore/config"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/util"
+	"0chain.net/smartcontract/dbs/event"
+)
+
+// SimulationResult is result of a transaction executed against the latest
+// finalized state without persisting it.
+type SimulationResult struct {
+	Hash            string                  `json:"hash"`
+	Round           int64                   `json:"round"` // round of the state
+	Output          string                  `json:"output,omitempty"`
+	Error           string                  `json:"error,omitempty"`
+	Transfers       []*state.Transfer       `json:"transfers"`
+	SignedTransfers []*state.SignedTransfer `json:"signed_transfers"`
+	Mints           []*state.Mint           `json:"mints"`
+	Events          []event.Event           `json:"events"`
+	GasUsed         int64                   `json:"gas_used"`
+	Fee             state.Balance           `json:"fee"` // estimated fee
+}
+
+// SimulateTransaction executes the transaction against the latest finalized
+// state in a throwaway state context. An error of the execution is the part
+// of the result, the returned error means the transaction can't be executed.
+// The state of the latest finalized block is not changed anymore, so the
+// simulation doesn't lock the chain state; a smart contract execution is
+// limited by both the gas and the timeout.
+func (c *Chain) SimulateTransaction(ctx context.Context,
+	txn *transaction.Transaction) (*SimulationResult, error) {
+
+	txn.ComputeProperties()
+	txn.TransactionOutput = ""
+	if txn.ClientID == "" {
+		return nil, common.NewError("simulate_transaction", "missing client id")
+	}
+	if txn.CreationDate == 0 {
+		txn.CreationDate = common.Now()
+	}
+	if txn.Signature != "" {
+		if err := txn.VerifyHash(ctx); err != nil {
+			return nil, err
+		}
+		if err := txn.VerifySignature(ctx); err != nil {
+			return nil, err
+		}
+	} else {
+		txn.Hash = txn.ComputeHash()
+	}
+
+	lfb := c.GetLatestFinalizedBlock()
+	if lfb == nil || lfb.ClientState == nil {
+		return nil, common.NewError("empty_lfb", "empty latest finalized block or state")
+	}
+	var lfbState = util.NewMerklePatriciaTrie(lfb.ClientState.GetNodeDB(),
+		util.Sequence(lfb.Round), lfb.ClientState.GetRoot())
+
+	// the block the transaction would be included in
+	b := block.NewBlock(lfb.ChainID, lfb.Round+1)
+	b.PrevBlock = lfb
+	b.PrevHash = lfb.Hash
+	b.CreationDate = txn.CreationDate
+	b.MagicBlock = lfb.MagicBlock
+	b.ClientState = CreateTxnMPT(lfbState) // discarded
+
+	var (
+		sctx       = c.NewStateContext(b, b.ClientState, txn, nil)
+		meter, _, err = c.applyTxn(ctx, sctx, applyOptions{simulate: true})
+		result     = &SimulationResult{
+			Hash:            txn.Hash,
+			Round:           lfb.Round,
+			Output:          txn.TransactionOutput,
+			Transfers:       sctx.GetTransfers(),
+			SignedTransfers: sctx.GetSignedTransfers(),
+			Mints:           sctx.GetMints(),
+			Events:          sctx.GetEvents(),
+		}
+	)
+	switch {
+	case err != nil:
+		result.Output, result.Error = "", err.Error()
+	case txn.Status == transaction.TxnFail:
+		// the error of a failed smart contract call is the output
+		result.Output, result.Error = "", txn.TransactionOutput
+	}
+	if meter != nil {
+		result.GasUsed = meter.Used()
+	}
+	if config.DevConfiguration.IsFeeEnabled {
+		result.Fee = c.gasFee(txn, b.Round, result.GasUsed)
+	}
+	return result, nil
+}
+
+// SimulateTransactionHandler - executes the posted transaction against the
+// latest finalized state without persisting anything, the transaction can be
+// unsigned
+func (c *Chain) SimulateTransactionHandler(ctx context.Context, r *http.Request) (interface{}, error) {
+	if r.Method != http.MethodPost {
+		return nil, common.NewErrBadRequest("only POST method is allowed")
+	}
+	var txn transaction.Transaction
+	if err := json.NewDecoder(r.Body).Decode(&txn); err != nil {
+		return nil, common.NewErrBadRequest("invalid transaction: " + err.Error())
+	}
+	if c.TxnMaxPayload > 0 && len(txn.TransactionData) > c.TxnMaxPayload {
+		return nil, common.NewError("txn_exceed_max_payload",
+			"transaction payload exceeds the max payload")
+	}
+	return c.SimulateTransaction(ctx, &txn)
+}
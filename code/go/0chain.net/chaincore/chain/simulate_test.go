@@ -0,0 +1,92 @@
+package chain_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"0chain.net/chaincore/chain"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/smartcontract/faucetsc"
+)
+
+func TestChain_SimulateTransaction(t *testing.T) {
+	lfb := newParallelTestBlock(t, map[string]state.Balance{"a": 100, "b": 100})
+	root := lfb.ClientState.GetRoot()
+
+	c := chain.NewChainFromConfig()
+	c.LatestFinalizedBlock = lfb
+
+	txn := &transaction.Transaction{
+		ClientID:        clientID("a"),
+		ToClientID:      clientID("b"),
+		Value:           10,
+		TransactionType: transaction.TxnTypeSend,
+	}
+	result, err := c.SimulateTransaction(context.Background(), txn)
+	require.NoError(t, err)
+	require.Empty(t, result.Error)
+	require.Equal(t, lfb.Round, result.Round)
+	require.NotEmpty(t, result.Hash)
+	require.Contains(t, result.Transfers, state.NewTransfer(clientID("a"), clientID("b"), 10))
+
+	// insufficient balance
+	txn.Value = 1000
+	result, err = c.SimulateTransaction(context.Background(), txn)
+	require.NoError(t, err)
+	require.NotEmpty(t, result.Error)
+
+	// nothing is persisted
+	require.Equal(t, root, lfb.ClientState.GetRoot())
+
+	// unknown client
+	_, err = c.SimulateTransaction(context.Background(), &transaction.Transaction{})
+	require.Error(t, err)
+}
+
+func TestChain_SimulateTransactionGasLimit(t *testing.T) {
+	lfb := newParallelTestBlock(t, map[string]state.Balance{"b": 1000})
+	c := chain.NewChainFromConfig()
+	c.LatestFinalizedBlock = lfb
+
+	// the gas metering is disabled, but the simulation is limited
+	c.SmartContractMaxGas = 150
+	result, err := c.SimulateTransaction(context.Background(), newRefillTxn(t, "b", 5, 0, 0))
+	require.NoError(t, err)
+	require.Contains(t, result.Error, "out_of_gas")
+	require.Empty(t, result.Output)
+	require.EqualValues(t, 150, result.GasUsed)
+
+	c.SmartContractMaxGas = 0
+	result, err = c.SimulateTransaction(context.Background(), newRefillTxn(t, "b", 5, 0, 0))
+	require.NoError(t, err)
+	require.Empty(t, result.Error)
+	require.Contains(t, result.Transfers, state.NewTransfer(clientID("b"), faucetsc.ADDRESS, 5))
+}
+
+func TestChain_SimulateTransactionHandler(t *testing.T) {
+	c := chain.NewChainFromConfig()
+	c.LatestFinalizedBlock = newParallelTestBlock(t, map[string]state.Balance{"a": 100})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/transaction/simulate", nil)
+	_, err := c.SimulateTransactionHandler(context.Background(), req)
+	require.Error(t, err)
+
+	body, err := json.Marshal(&transaction.Transaction{
+		ClientID:        clientID("a"),
+		ToClientID:      clientID("b"),
+		Value:           10,
+		TransactionType: transaction.TxnTypeSend,
+	})
+	require.NoError(t, err)
+	req = httptest.NewRequest(http.MethodPost, "/v1/transaction/simulate", bytes.NewReader(body))
+	resp, err := c.SimulateTransactionHandler(context.Background(), req)
+	require.NoError(t, err)
+	require.Empty(t, resp.(*chain.SimulationResult).Error)
+}
@@ -2,7 +2,9 @@ package chain
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"math"
 	"time"
 
 	"0chain.net/smartcontract/dbs/event"
@@ -13,6 +15,7 @@ import (
 	bcstate "0chain.net/chaincore/chain/state"
 	"0chain.net/chaincore/config"
 	"0chain.net/chaincore/smartcontract"
+	sci "0chain.net/chaincore/smartcontractinterface"
 	"0chain.net/chaincore/state"
 	"0chain.net/chaincore/transaction"
 	"0chain.net/core/common"
@@ -33,6 +36,11 @@ func init() {
 
 var ErrInsufficientBalance = common.NewError("insufficient_balance", "Balance not sufficient for transfer")
 
+// ErrSmartContractTimeout is returned when the smart contract execution
+// without the gas metering doesn't complete in time.
+var ErrSmartContractTimeout = common.NewError("smart_contract_execution_ctx_err",
+	"smart contract execution timed out")
+
 /*ComputeState - compute the state for the block */
 func (c *Chain) ComputeState(ctx context.Context, b *block.Block) (err error) {
 	return c.ComputeBlockStateWithLock(ctx, func() error {
@@ -99,24 +107,125 @@ func (c *Chain) rebaseState(lfb *block.Block) {
 	}
 }
 
-//ExecuteSmartContract - executes the smart contract for the transaction
-func (c *Chain) ExecuteSmartContract(ctx context.Context, t *transaction.Transaction, balances bcstate.StateContextI) (string, error) {
-	var output string
-	var err error
-	ts := time.Now()
-	done := make(chan bool, 1)
+// isGasMetered returns true if the smart contract execution of the round
+// is limited by gas
+func (c *Chain) isGasMetered(round int64) bool {
+	return c.SmartContractMaxGas > 0 && config.IsActive(config.ForkGasMetering, round)
+}
+
+// newGasMeter creates the gas meter of the smart contract transaction of
+// the round. The limit is declared in the transaction, without the gas
+// metering the meter is not limited.
+func (c *Chain) newGasMeter(t *transaction.Transaction, round int64) (*bcstate.GasMeter, error) {
+	if !c.isGasMetered(round) {
+		return bcstate.NewGasMeter(math.MaxInt64), nil
+	}
+	var data sci.SmartContractTransactionData
+	if err := json.Unmarshal([]byte(t.TransactionData), &data); err != nil {
+		// the error is reported by the execution
+		return bcstate.NewGasMeter(c.SmartContractMaxGas), nil
+	}
+	if data.GasLimit < 0 || data.GasLimit > c.SmartContractMaxGas {
+		return nil, common.NewErrorf("invalid_gas_limit",
+			"gas limit %d is out of range [0, %d]", data.GasLimit, c.SmartContractMaxGas)
+	}
+	var limit = data.GasLimit
+	if limit == 0 {
+		limit = c.SmartContractMaxGas
+	}
+	if config.DevConfiguration.IsFeeEnabled && c.SmartContractGasPrice > 0 &&
+		!transaction.IsFeeExempted(data.FunctionName) {
+		if paid := t.Fee / c.SmartContractGasPrice; paid < limit {
+			limit = paid
+		}
+	}
+	return bcstate.NewGasMeter(limit), nil
+}
+
+// gasFee returns the fee of the transaction of the round used given gas,
+// the fee doesn't exceed the transaction fee
+func (c *Chain) gasFee(t *transaction.Transaction, round, used int64) state.Balance {
+	if c.SmartContractGasPrice <= 0 || !c.isGasMetered(round) {
+		return state.Balance(t.Fee)
+	}
+	if fee := used * c.SmartContractGasPrice; fee < t.Fee {
+		return state.Balance(fee)
+	}
+	return state.Balance(t.Fee)
+}
+
+// newSimulationGasMeter creates the gas meter of the smart contract
+// transaction of the round simulated, the meter is limited even without
+// the gas metering
+func (c *Chain) newSimulationGasMeter(t *transaction.Transaction, round int64) (*bcstate.GasMeter, error) {
+	if !c.isGasMetered(round) {
+		var limit = c.SmartContractMaxGas
+		if limit <= 0 {
+			limit = DefaultSimulationMaxGas
+		}
+		return bcstate.NewGasMeter(limit), nil
+	}
+	return c.newGasMeter(t, round)
+}
+
+//ExecuteSmartContract - executes the smart contract for the transaction.
+// With the gas metering the gas is the only limit of the execution, so the
+// result doesn't depend on speed of the node executing it. Otherwise the
+// execution is limited by the timeout. The state changes of a failed
+// execution are discarded.
+func (c *Chain) ExecuteSmartContract(ctx context.Context, t *transaction.Transaction, meter *bcstate.GasMeter, balances bcstate.StateContextI) (string, error) {
+	return c.executeSmartContract(ctx, t, meter, balances,
+		!c.isGasMetered(balances.GetBlock().Round))
+}
+
+// executeSmartContract executes the smart contract for the transaction
+// limited by the gas meter, and by the timeout if timed is true
+func (c *Chain) executeSmartContract(ctx context.Context, t *transaction.Transaction,
+	meter *bcstate.GasMeter, balances bcstate.StateContextI, timed bool) (string, error) {
+
+	var (
+		ts     = time.Now()
+		gctx   = bcstate.NewGasStateContext(balances, meter)
+		output string
+	)
+	var execute = func(ctx context.Context) error {
+		// the state operations fail once the meter fails, but the
+		// contract can ignore the errors
+		return bcstate.Atomic(balances, func() (err error) {
+			output, err = smartcontract.ExecuteSmartContract(ctx, t, gctx)
+			if gerr := meter.Err(); gerr != nil {
+				return gerr
+			}
+			return
+		})
+	}
+
+	if !timed {
+		err := execute(ctx)
+		SmartContractExecutionTimer.Update(time.Since(ts))
+		if err != nil {
+			return "", err
+		}
+		return output, nil
+	}
+
+	done := make(chan error, 1)
 	cctx, cancelf := context.WithTimeout(ctx, c.SmartContractTimeout)
 	defer cancelf()
 	go func() {
-		output, err = smartcontract.ExecuteSmartContract(cctx, t, balances)
-		done <- true
+		done <- execute(cctx)
 	}()
 	select {
 	case <-cctx.Done():
-		return "", common.NewError("smart_contract_execution_ctx_err", cctx.Err().Error())
-	case <-done:
+		// the execution keeps running, stop it changing the state
+		meter.Abort(bcstate.ErrExecutionAborted)
+		return "", common.NewError(ErrSmartContractTimeout.Code, cctx.Err().Error())
+	case err := <-done:
 		SmartContractExecutionTimer.Update(time.Since(ts))
-		return output, err
+		if err != nil {
+			return "", err
+		}
+		return output, nil
 	}
 }
 
@@ -173,28 +282,115 @@ func (c *Chain) updateState(
 		sctx        = c.NewStateContext(b, clientState, txn, nil)
 	)
 	defer func() { events = sctx.GetEvents() }()
+	if _, _, err = c.applyTxn(ctx, sctx, applyOptions{}); err != nil {
+		return
+	}
+
+	// commit transaction
+	if err = b.ClientState.MergeMPTChanges(clientState); err != nil {
+		if state.DebugTxn() {
+			logging.Logger.DPanic("update state - merge mpt error",
+				zap.Int64("round", b.Round), zap.String("block", b.Hash),
+				zap.Any("txn", txn), zap.Error(err))
+		}
+
+		logging.Logger.Error("error committing txn", zap.Any("error", err))
+		return
+	}
+
+	if state.DebugTxn() {
+		if err = block.ValidateState(context.TODO(), b, startRoot); err != nil {
+			logging.Logger.DPanic("update state - state validation failure",
+				zap.Any("txn", txn), zap.Error(err))
+		}
+		var os *state.State
+		os, err = c.getState(b.ClientState, c.OwnerID)
+		if err != nil || os == nil || os.Balance == 0 {
+			logging.Logger.DPanic("update state - owner account",
+				zap.Int64("round", b.Round), zap.String("block", b.Hash),
+				zap.Any("txn", txn), zap.Any("os", os), zap.Error(err))
+		}
+	}
+
+	return
+}
+
+// applyOptions changes the way a transaction is applied
+type applyOptions struct {
+	// deferFee takes the fee from the client without paying it to the
+	// miner SC, the caller pays it by payTxnFees
+	deferFee bool
+	// simulate limits a smart contract execution by both the gas and the
+	// timeout, the gas is limited without the gas metering too
+	simulate bool
+}
+
+// applyTxn executes the transaction of the state context changing its state,
+// the state is not committed to the block. It returns the gas meter of a
+// smart contract transaction. A failed smart contract call doesn't fail the
+// transaction: the changes of the call are discarded, but the fee is charged
+// and the transaction is kept with the error as the output. The timed out
+// calls are the exception, their result depends on the node. It returns the
+// fee charged as well.
+func (c *Chain) applyTxn(ctx context.Context, sctx *bcstate.StateContext,
+	opts applyOptions) (meter *bcstate.GasMeter, fee state.Balance, err error) {
+
+	var (
+		b         = sctx.GetBlock()
+		txn       = sctx.GetTransaction()
+		startRoot = sctx.GetState().GetRoot()
+	)
+
+	// the protocol transactions of the block generator have no fee
+	if config.IsActive(config.ForkFeeValidation, b.Round) &&
+		!txn.IsProtocolTxn(b.MinerID) {
+		if err = txn.ValidateFee(); err != nil {
+			return
+		}
+	}
+
+	// the status is changed if the transaction fails
+	txn.Status = transaction.TxnSuccess
 
 	switch txn.TransactionType {
 
 	case transaction.TxnTypeSmartContract:
 		var output string
 		t := time.Now()
-		output, err = c.ExecuteSmartContract(ctx, txn, sctx)
+		if opts.simulate {
+			meter, err = c.newSimulationGasMeter(txn, b.Round)
+		} else {
+			meter, err = c.newGasMeter(txn, b.Round)
+		}
+		if err != nil {
+			return
+		}
+		output, err = c.executeSmartContract(ctx, txn, meter, sctx,
+			opts.simulate || !c.isGasMetered(b.Round))
 		if err != nil {
-			sctx.EmitError(err)
 			logging.Logger.Error("Error executing the SC",
 				zap.Error(err),
 				zap.String("block", b.Hash),
 				zap.String("begin client state", util.ToHex(startRoot)),
 				zap.String("prev block", b.PrevBlock.Hash),
 				zap.Any("txn", txn))
-			return
+			if errors.Is(err, ErrSmartContractTimeout) {
+				return
+			}
+			// the state changes of the call are rolled back already, the
+			// error is kept as the output and the gas used is paid
+			txn.TransactionOutput = err.Error()
+			txn.Status = transaction.TxnFail
+			sctx.EmitError(err)
+			err = nil
+			break
 		}
 		txn.TransactionOutput = output
 		logging.Logger.Info("SC executed with output",
 			zap.Any("txn_output", txn.TransactionOutput),
 			zap.Any("txn_hash", txn.Hash),
-			zap.Any("txn_exec_time", time.Since(t)))
+			zap.Any("txn_exec_time", time.Since(t)),
+			zap.Int64("txn_gas_used", meter.Used()))
 
 	case transaction.TxnTypeData:
 
@@ -211,18 +407,23 @@ func (c *Chain) updateState(
 		}
 	default:
 		logging.Logger.Error("Invalid transaction type", zap.Int("txn type", txn.TransactionType))
-		return nil, fmt.Errorf("invalid transaction type: %v", txn.TransactionType)
+		return nil, 0, fmt.Errorf("invalid transaction type: %v", txn.TransactionType)
 	}
 
+	var feeTransfer *state.Transfer
 	if config.DevConfiguration.IsFeeEnabled {
-		err = sctx.AddTransfer(state.NewTransfer(txn.ClientID, minersc.ADDRESS,
-			state.Balance(txn.Fee)))
+		fee = state.Balance(txn.Fee)
+		if meter != nil {
+			fee = c.gasFee(txn, b.Round, meter.Used())
+		}
+		feeTransfer = state.NewTransfer(txn.ClientID, minersc.ADDRESS, fee)
+		err = sctx.AddTransfer(feeTransfer)
 		if err != nil {
 			logging.Logger.Error("Failed to add transfer",
 				zap.Any("txn type", txn.TransactionType),
 				zap.Any("transaction_ClientID", txn.ClientID),
 				zap.Any("minersc_address", minersc.ADDRESS),
-				zap.Any("state_Balance", state.Balance(txn.Fee)))
+				zap.Any("state_Balance", fee))
 			return
 		}
 	}
@@ -232,7 +433,11 @@ func (c *Chain) updateState(
 	}
 
 	for _, transfer := range sctx.GetTransfers() {
-		err = c.transferAmount(sctx, transfer.ClientID, transfer.ToClientID, transfer.Amount)
+		if opts.deferFee && transfer == feeTransfer {
+			err = c.takeFee(sctx, transfer)
+		} else {
+			err = c.transferAmount(sctx, transfer.ClientID, transfer.ToClientID, transfer.Amount)
+		}
 		if err != nil {
 			logging.Logger.Error("Failed to transfer amount",
 				zap.Any("transfer_ClientID", transfer.ClientID),
@@ -264,33 +469,6 @@ func (c *Chain) updateState(
 		}
 	}
 
-	// commit transaction
-	if err = b.ClientState.MergeMPTChanges(clientState); err != nil {
-		if state.DebugTxn() {
-			logging.Logger.DPanic("update state - merge mpt error",
-				zap.Int64("round", b.Round), zap.String("block", b.Hash),
-				zap.Any("txn", txn), zap.Error(err))
-		}
-
-		logging.Logger.Error("error committing txn", zap.Any("error", err))
-		return
-	}
-
-	if state.DebugTxn() {
-		if err = block.ValidateState(context.TODO(), b, startRoot); err != nil {
-			logging.Logger.DPanic("update state - state validation failure",
-				zap.Any("txn", txn), zap.Error(err))
-		}
-		var os *state.State
-		os, err = c.getState(b.ClientState, c.OwnerID)
-		if err != nil || os == nil || os.Balance == 0 {
-			logging.Logger.DPanic("update state - owner account",
-				zap.Int64("round", b.Round), zap.String("block", b.Hash),
-				zap.Any("txn", txn), zap.Any("os", os), zap.Error(err))
-		}
-	}
-
-	txn.Status = transaction.TxnSuccess
 	return
 }
 
@@ -306,6 +484,14 @@ func (c *Chain) transferAmount(sctx bcstate.StateContextI, fromClient, toClient
 	if fromClient == toClient {
 		return common.InvalidRequest("from and to client should be different for balance transfer")
 	}
+	if err := c.takeAmount(sctx, fromClient, amount); err != nil {
+		return err
+	}
+	return c.giveAmount(sctx, toClient, amount)
+}
+
+// takeAmount is the debit half of the transfer
+func (c *Chain) takeAmount(sctx bcstate.StateContextI, fromClient datastore.Key, amount state.Balance) error {
 	b := sctx.GetBlock()
 	clientState := sctx.GetState()
 	txn := sctx.GetTransaction()
@@ -328,22 +514,6 @@ func (c *Chain) transferAmount(sctx bcstate.StateContextI, fromClient, toClient
 	if fs.Balance < amount {
 		return ErrInsufficientBalance
 	}
-	ts, err := c.getState(clientState, toClient)
-	if !isValid(err) {
-		if state.DebugTxn() {
-			logging.Logger.Error("transfer amount - to_client get", zap.Int64("round", b.Round), zap.String("block", b.Hash), zap.String("prev_block", b.PrevHash), zap.Any("txn", datastore.ToJSON(txn)), zap.Error(err))
-			for _, txn := range b.Txns {
-				if txn == nil {
-					break
-				}
-				fmt.Fprintf(block.StateOut, "transfer amount r=%v b=%v t=%+v\n", b.Round, b.Hash, txn)
-			}
-			fmt.Fprintf(block.StateOut, "transfer amount - error getting state value: %v %+v %v\n", toClient, txn, err)
-			block.PrintStates(clientState, b.ClientState)
-			logging.Logger.DPanic(fmt.Sprintf("transfer amount - error getting state value: %v %v", toClient, err))
-		}
-		return err
-	}
 	sctx.SetStateContext(fs)
 	fs.Balance -= amount
 	if fs.Balance == 0 {
@@ -363,6 +533,30 @@ func (c *Chain) transferAmount(sctx bcstate.StateContextI, fromClient, toClient
 		}
 		return err
 	}
+	return nil
+}
+
+// giveAmount is the credit half of the transfer
+func (c *Chain) giveAmount(sctx bcstate.StateContextI, toClient datastore.Key, amount state.Balance) error {
+	b := sctx.GetBlock()
+	clientState := sctx.GetState()
+	txn := sctx.GetTransaction()
+	ts, err := c.getState(clientState, toClient)
+	if !isValid(err) {
+		if state.DebugTxn() {
+			logging.Logger.Error("transfer amount - to_client get", zap.Int64("round", b.Round), zap.String("block", b.Hash), zap.String("prev_block", b.PrevHash), zap.Any("txn", datastore.ToJSON(txn)), zap.Error(err))
+			for _, txn := range b.Txns {
+				if txn == nil {
+					break
+				}
+				fmt.Fprintf(block.StateOut, "transfer amount r=%v b=%v t=%+v\n", b.Round, b.Hash, txn)
+			}
+			fmt.Fprintf(block.StateOut, "transfer amount - error getting state value: %v %+v %v\n", toClient, txn, err)
+			block.PrintStates(clientState, b.ClientState)
+			logging.Logger.DPanic(fmt.Sprintf("transfer amount - error getting state value: %v %v", toClient, err))
+		}
+		return err
+	}
 	sctx.SetStateContext(ts)
 	ts.Balance += amount
 	_, err = clientState.Insert(util.Path(toClient), ts)
@@ -380,6 +574,34 @@ func (c *Chain) transferAmount(sctx bcstate.StateContextI, fromClient, toClient
 	return nil
 }
 
+// takeFee takes the fee transfer amount from the client, the fee is paid
+// to the miner SC later by payTxnFees
+func (c *Chain) takeFee(sctx bcstate.StateContextI, fee *state.Transfer) error {
+	if fee.Amount == 0 {
+		return nil
+	}
+	if fee.ClientID == fee.ToClientID {
+		return common.InvalidRequest("from and to client should be different for balance transfer")
+	}
+	return c.takeAmount(sctx, fee.ClientID, fee.Amount)
+}
+
+// payTxnFees pays the fees taken from the clients to the miner SC in the
+// block state. The miner SC state is changed on behalf of the last of the
+// transactions, as the fee transfers of the transactions would do.
+func (c *Chain) payTxnFees(b *block.Block, last *transaction.Transaction,
+	fees state.Balance) error {
+
+	var (
+		clientState = CreateTxnMPT(b.ClientState)
+		sctx        = c.NewStateContext(b, clientState, last, nil)
+	)
+	if err := c.giveAmount(sctx, minersc.ADDRESS, fees); err != nil {
+		return err
+	}
+	return b.ClientState.MergeMPTChanges(clientState)
+}
+
 func (c *Chain) mintAmount(sctx bcstate.StateContextI, toClient datastore.Key, amount state.Balance) error {
 	if amount == 0 {
 		return nil
@@ -433,9 +655,7 @@ func (c *Chain) mintAmount(sctx bcstate.StateContextI, toClient datastore.Key, a
 }
 
 func CreateTxnMPT(mpt util.MerklePatriciaTrieI) util.MerklePatriciaTrieI {
-	tdb := util.NewLevelNodeDB(util.NewMemoryNodeDB(), mpt.GetNodeDB(), false)
-	tmpt := util.NewMerklePatriciaTrie(tdb, mpt.GetVersion(), mpt.GetRoot())
-	return tmpt
+	return util.NewLevelMPT(mpt)
 }
 
 func (c *Chain) getState(clientState util.MerklePatriciaTrieI, clientID string) (*state.State, error) {
@@ -0,0 +1,174 @@
+package state
+
+import (
+	"sync"
+
+	"0chain.net/chaincore/state"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/util"
+)
+
+// Units of gas charged for the state context operations. The costs are
+// constant and depend on the data only, so the gas used by a transaction is
+// the same on every miner.
+const (
+	GasRead      int64 = 100 // GetTrieNode, GetClientBalance
+	GasReadByte  int64 = 1   // per byte of a node read
+	GasWrite     int64 = 500 // InsertTrieNode
+	GasWriteByte int64 = 5   // per byte of a node written
+	GasDelete    int64 = 200 // DeleteTrieNode
+	GasTransfer  int64 = 300 // AddTransfer, AddSignedTransfer
+	GasMint      int64 = 300 // AddMint
+	GasEvent     int64 = 50  // EmitEvent
+	GasEventByte int64 = 1   // per byte of an event data
+)
+
+var (
+	// ErrOutOfGas is returned when a transaction exceeds its gas limit.
+	ErrOutOfGas = common.NewError("out_of_gas", "transaction gas limit exceeded")
+	// ErrExecutionAborted is returned by the state operations made after
+	// the execution of the transaction is aborted.
+	ErrExecutionAborted = common.NewError("execution_aborted",
+		"smart contract execution is aborted")
+)
+
+// GasMeter counts gas used by a transaction.
+type GasMeter struct {
+	mutex sync.Mutex
+	limit int64
+	used  int64
+	err   error
+}
+
+// NewGasMeter creates a meter with given limit.
+func NewGasMeter(limit int64) *GasMeter {
+	return &GasMeter{limit: limit}
+}
+
+// Charge given units of gas. Once the limit is exceeded or the meter is
+// aborted all following charges fail with the same error.
+func (gm *GasMeter) Charge(units int64) error {
+	gm.mutex.Lock()
+	defer gm.mutex.Unlock()
+	if gm.err != nil {
+		return gm.err
+	}
+	if units > gm.limit-gm.used {
+		gm.used = gm.limit
+		gm.err = ErrOutOfGas
+		return gm.err
+	}
+	gm.used += units
+	return nil
+}
+
+// Abort the meter, all following charges fail with given error.
+func (gm *GasMeter) Abort(err error) {
+	gm.mutex.Lock()
+	defer gm.mutex.Unlock()
+	if gm.err == nil {
+		gm.err = err
+	}
+}
+
+// Err returns the error the meter failed with, if any.
+func (gm *GasMeter) Err() error {
+	gm.mutex.Lock()
+	defer gm.mutex.Unlock()
+	return gm.err
+}
+
+// Used returns the gas used.
+func (gm *GasMeter) Used() int64 {
+	gm.mutex.Lock()
+	defer gm.mutex.Unlock()
+	return gm.used
+}
+
+// Limit returns the gas limit.
+func (gm *GasMeter) Limit() int64 {
+	return gm.limit
+}
+
+// GasStateContext is a state context charging the state operations of a
+// smart contract against a gas meter. The operations without an error
+// result are skipped when the meter fails; the error must be checked with
+// the meter after the execution.
+type GasStateContext struct {
+	StateContextI
+	meter *GasMeter
+}
+
+// NewGasStateContext wraps the state context with the gas meter.
+func NewGasStateContext(balances StateContextI, meter *GasMeter) *GasStateContext {
+	return &GasStateContext{StateContextI: balances, meter: meter}
+}
+
+// GetGasMeter returns the meter of the context.
+func (gc *GasStateContext) GetGasMeter() *GasMeter {
+	return gc.meter
+}
+
+func (gc *GasStateContext) GetClientBalance(clientID datastore.Key) (state.Balance, error) {
+	if err := gc.meter.Charge(GasRead); err != nil {
+		return 0, err
+	}
+	return gc.StateContextI.GetClientBalance(clientID)
+}
+
+func (gc *GasStateContext) GetTrieNode(key datastore.Key) (util.Serializable, error) {
+	if err := gc.meter.Charge(GasRead); err != nil {
+		return nil, err
+	}
+	node, err := gc.StateContextI.GetTrieNode(key)
+	if err != nil || node == nil {
+		return node, err
+	}
+	if err := gc.meter.Charge(GasReadByte * int64(len(node.Encode()))); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func (gc *GasStateContext) InsertTrieNode(key datastore.Key, node util.Serializable) (datastore.Key, error) {
+	if err := gc.meter.Charge(GasWrite + GasWriteByte*int64(len(node.Encode()))); err != nil {
+		return "", err
+	}
+	return gc.StateContextI.InsertTrieNode(key, node)
+}
+
+func (gc *GasStateContext) DeleteTrieNode(key datastore.Key) (datastore.Key, error) {
+	if err := gc.meter.Charge(GasDelete); err != nil {
+		return "", err
+	}
+	return gc.StateContextI.DeleteTrieNode(key)
+}
+
+func (gc *GasStateContext) AddTransfer(t *state.Transfer) error {
+	if err := gc.meter.Charge(GasTransfer); err != nil {
+		return err
+	}
+	return gc.StateContextI.AddTransfer(t)
+}
+
+func (gc *GasStateContext) AddSignedTransfer(st *state.SignedTransfer) {
+	if gc.meter.Charge(GasTransfer) != nil {
+		return
+	}
+	gc.StateContextI.AddSignedTransfer(st)
+}
+
+func (gc *GasStateContext) AddMint(m *state.Mint) error {
+	if err := gc.meter.Charge(GasMint); err != nil {
+		return err
+	}
+	return gc.StateContextI.AddMint(m)
+}
+
+func (gc *GasStateContext) EmitEvent(eventType, tag string, data string) {
+	if gc.meter.Charge(GasEvent+GasEventByte*int64(len(data))) != nil {
+		return
+	}
+	gc.StateContextI.EmitEvent(eventType, tag, data)
+}
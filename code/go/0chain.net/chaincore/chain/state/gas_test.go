@@ -0,0 +1,84 @@
+package state
+
+import (
+	"testing"
+
+	"0chain.net/chaincore/block"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/logging"
+	"0chain.net/core/memorystore"
+	"0chain.net/core/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logging.Logger = zap.NewNop()
+}
+
+func newTestGasStateContext(limit int64) (*GasStateContext, *StateContext) {
+	block.SetupEntity(memorystore.GetStorageProvider())
+	var (
+		txn = &transaction.Transaction{ClientID: "client", ToClientID: "sc"}
+		mpt = util.NewMerklePatriciaTrie(util.NewMemoryNodeDB(), 0, nil)
+		sc  = NewStateContext(block.NewBlock("", 1), mpt, &state.Deserializer{},
+			txn, nil, nil, nil, nil, nil)
+	)
+	return NewGasStateContext(sc, NewGasMeter(limit)), sc
+}
+
+func TestGasMeter_Charge(t *testing.T) {
+	gm := NewGasMeter(100)
+	require.NoError(t, gm.Charge(60))
+	require.NoError(t, gm.Charge(40))
+	require.Equal(t, int64(100), gm.Used())
+
+	require.Equal(t, ErrOutOfGas, gm.Charge(1))
+	require.Equal(t, ErrOutOfGas, gm.Err())
+	require.Equal(t, int64(100), gm.Used())
+
+	gm = NewGasMeter(100)
+	gm.Abort(ErrExecutionAborted)
+	require.Equal(t, ErrExecutionAborted, gm.Charge(1))
+	require.Equal(t, int64(0), gm.Used())
+}
+
+func TestGasStateContext(t *testing.T) {
+	var node = &util.SecureSerializableValue{Buffer: []byte("0123456789")}
+
+	gc, _ := newTestGasStateContext(10000)
+	_, err := gc.InsertTrieNode("key", node)
+	require.NoError(t, err)
+	require.Equal(t, GasWrite+10*GasWriteByte, gc.GetGasMeter().Used())
+
+	_, err = gc.GetTrieNode("key")
+	require.NoError(t, err)
+	require.Equal(t, GasWrite+10*GasWriteByte+GasRead+10*GasReadByte,
+		gc.GetGasMeter().Used())
+
+	// the same operations use the same gas
+	other, _ := newTestGasStateContext(10000)
+	_, err = other.InsertTrieNode("key", node)
+	require.NoError(t, err)
+	_, err = other.GetTrieNode("key")
+	require.NoError(t, err)
+	require.Equal(t, gc.GetGasMeter().Used(), other.GetGasMeter().Used())
+}
+
+func TestGasStateContext_OutOfGas(t *testing.T) {
+	gc, sc := newTestGasStateContext(GasTransfer + GasEvent)
+
+	require.NoError(t, gc.AddTransfer(state.NewTransfer("client", "sc", 1)))
+	require.Equal(t, ErrOutOfGas, gc.AddTransfer(state.NewTransfer("client", "sc", 1)))
+	require.Len(t, sc.GetTransfers(), 1)
+
+	// the operations without an error result are skipped
+	gc.EmitEvent("type", "tag", "data")
+	require.Empty(t, sc.GetEvents())
+
+	_, err := gc.InsertTrieNode("key", &util.SecureSerializableValue{Buffer: []byte("v")})
+	require.Equal(t, ErrOutOfGas, err)
+	_, err = sc.GetTrieNode("key")
+	require.Equal(t, util.ErrValueNotPresent, err)
+}
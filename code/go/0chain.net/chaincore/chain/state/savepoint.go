@@ -0,0 +1,129 @@
+package state
+
+import (
+	"0chain.net/core/common"
+	"0chain.net/core/util"
+)
+
+var ErrInvalidSavepoint = common.NewError("invalid_savepoint",
+	"savepoint is not the last one of the state context")
+
+// SavepointI is a state context with nested savepoints. The changes of the
+// state, transfers, mints and events made after a savepoint are merged to
+// the previous level by Release or discarded by Rollback. The savepoints are
+// released or rolled back in the reverse order.
+type SavepointI interface {
+	Savepoint() int
+	Release(sp int) error
+	Rollback(sp int) error
+}
+
+// savepoint is the state context before the savepoint
+type savepoint struct {
+	state           util.MerklePatriciaTrieI
+	transfers       int
+	signedTransfers int
+	mints           int
+	events          int
+}
+
+// Savepoint starts a scratch layer of the state, returns the savepoint
+func (sc *StateContext) Savepoint() int {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	sc.savepoints = append(sc.savepoints, &savepoint{
+		state:           sc.state,
+		transfers:       len(sc.transfers),
+		signedTransfers: len(sc.signedTransfers),
+		mints:           len(sc.mints),
+		events:          len(sc.events),
+	})
+	sc.state = util.NewLevelMPT(sc.state)
+	return len(sc.savepoints)
+}
+
+func (sc *StateContext) popSavepoint(sp int) (*savepoint, error) {
+	if sp <= 0 || sp != len(sc.savepoints) {
+		return nil, ErrInvalidSavepoint
+	}
+	var last = sc.savepoints[sp-1]
+	sc.savepoints = sc.savepoints[:sp-1]
+	return last, nil
+}
+
+// Release merges changes made after the savepoint to the previous level
+func (sc *StateContext) Release(sp int) error {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	last, err := sc.popSavepoint(sp)
+	if err != nil {
+		return err
+	}
+	if err := last.state.MergeMPTChanges(sc.state); err != nil {
+		return common.NewError("release_savepoint", err.Error())
+	}
+	sc.state = last.state
+	return nil
+}
+
+// Rollback discards changes made after the savepoint
+func (sc *StateContext) Rollback(sp int) error {
+	sc.mutex.Lock()
+	defer sc.mutex.Unlock()
+	last, err := sc.popSavepoint(sp)
+	if err != nil {
+		return err
+	}
+	sc.state = last.state
+	sc.transfers = sc.transfers[:last.transfers]
+	sc.signedTransfers = sc.signedTransfers[:last.signedTransfers]
+	sc.mints = sc.mints[:last.mints]
+	if last.events < len(sc.events) {
+		sc.events = sc.events[:last.events]
+	}
+	return nil
+}
+
+// savepointContext returns the state context with savepoints the given one
+// is or wraps, nil if there is no such, and the trace of a wrapping tracing
+// context
+func savepointContext(balances StateContextI) (spc SavepointI, trace *Trace) {
+	for {
+		switch b := balances.(type) {
+		case SavepointI:
+			return b, trace
+		case *GasStateContext:
+			balances = b.StateContextI
+		case *CountingStateContext:
+			balances = b.StateContextI
+		case *TracingStateContext:
+			if trace == nil {
+				trace = b.trace
+			}
+			balances = b.StateContextI
+		default:
+			return nil, trace
+		}
+	}
+}
+
+// Atomic runs f in a savepoint of the state context, the changes made by f
+// are kept if it succeeds and discarded if it fails. The state contexts
+// without the savepoints run f as is.
+func Atomic(balances StateContextI, f func() error) error {
+	var spc, trace = savepointContext(balances)
+	if spc == nil {
+		return f()
+	}
+	var sp = spc.Savepoint()
+	if err := f(); err != nil {
+		if trace != nil {
+			trace.add(&TraceOp{Op: TraceRollback}, err)
+		}
+		if rerr := spc.Rollback(sp); rerr != nil {
+			return common.NewErrorf("rollback_savepoint", "%v: %v", err, rerr)
+		}
+		return err
+	}
+	return spc.Release(sp)
+}
@@ -0,0 +1,96 @@
+package state
+
+import (
+	"errors"
+	"testing"
+
+	"0chain.net/chaincore/state"
+	"0chain.net/core/util"
+	"github.com/stretchr/testify/require"
+)
+
+func testValue(v string) *util.SecureSerializableValue {
+	return &util.SecureSerializableValue{Buffer: []byte(v)}
+}
+
+func requireNode(t *testing.T, sc StateContextI, key, value string) {
+	val, err := sc.GetTrieNode(key)
+	if value == "" {
+		require.Equal(t, util.ErrValueNotPresent, err, key)
+		return
+	}
+	require.NoError(t, err, key)
+	require.Equal(t, value, string(val.Encode()), key)
+}
+
+func TestStateContext_Savepoints(t *testing.T) {
+	_, sc := newTestGasStateContext(0)
+
+	_, err := sc.InsertTrieNode("a", testValue("a"))
+	require.NoError(t, err)
+
+	sp := sc.Savepoint()
+	_, err = sc.InsertTrieNode("b", testValue("b"))
+	require.NoError(t, err)
+	require.NoError(t, sc.AddTransfer(state.NewTransfer("client", "sc", 1)))
+	requireNode(t, sc, "b", "b")
+	require.NoError(t, sc.Rollback(sp))
+	requireNode(t, sc, "a", "a")
+	requireNode(t, sc, "b", "")
+	require.Empty(t, sc.GetTransfers())
+
+	outer := sc.Savepoint()
+	_, err = sc.InsertTrieNode("c", testValue("c"))
+	require.NoError(t, err)
+	inner := sc.Savepoint()
+	_, err = sc.InsertTrieNode("d", testValue("d"))
+	require.NoError(t, err)
+
+	require.Equal(t, ErrInvalidSavepoint, sc.Release(outer))
+	require.NoError(t, sc.Release(inner))
+	requireNode(t, sc, "d", "d")
+	require.NoError(t, sc.Rollback(outer))
+	requireNode(t, sc, "c", "")
+	requireNode(t, sc, "d", "")
+
+	sp = sc.Savepoint()
+	_, err = sc.InsertTrieNode("e", testValue("e"))
+	require.NoError(t, err)
+	require.NoError(t, sc.Release(sp))
+	requireNode(t, sc, "e", "e")
+	require.Equal(t, ErrInvalidSavepoint, sc.Release(sp))
+}
+
+func TestAtomic(t *testing.T) {
+	gc, sc := newTestGasStateContext(1000000)
+
+	err := Atomic(gc, func() error {
+		_, err := gc.InsertTrieNode("a", testValue("a"))
+		return err
+	})
+	require.NoError(t, err)
+	requireNode(t, sc, "a", "a")
+
+	var failure = errors.New("failure")
+	err = Atomic(gc, func() error {
+		if _, err := gc.InsertTrieNode("b", testValue("b")); err != nil {
+			return err
+		}
+		if err := gc.AddTransfer(state.NewTransfer("client", "sc", 1)); err != nil {
+			return err
+		}
+		gc.EmitEvent("type", "tag", "data")
+		// nested failure doesn't fail the outer call
+		require.Error(t, Atomic(gc, func() error {
+			_, _ = gc.InsertTrieNode("c", testValue("c"))
+			return failure
+		}))
+		requireNode(t, sc, "c", "")
+		return failure
+	})
+	require.Equal(t, failure, err)
+	requireNode(t, sc, "a", "a")
+	requireNode(t, sc, "b", "")
+	require.Empty(t, sc.GetTransfers())
+	require.Empty(t, sc.GetEvents())
+}
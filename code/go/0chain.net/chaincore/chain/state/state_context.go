@@ -75,6 +75,7 @@ type StateContext struct {
 	getChainCurrentMagicBlock     func() *block.MagicBlock
 	getSignature                  func() encryption.SignatureScheme
 	eventDb                       *event.EventDb
+	savepoints                    []*savepoint
 	mutex                         *sync.Mutex
 }
 
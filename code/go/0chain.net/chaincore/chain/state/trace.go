@@ -0,0 +1,221 @@
+package state
+
+import (
+	"context"
+	"sync"
+
+	"0chain.net/chaincore/state"
+	"0chain.net/core/datastore"
+	"0chain.net/core/util"
+)
+
+// Operations of a smart contract execution trace.
+const (
+	TraceGet            = "get"
+	TraceInsert         = "insert"
+	TraceDelete         = "delete"
+	TraceTransfer       = "transfer"
+	TraceSignedTransfer = "signed_transfer"
+	TraceMint           = "mint"
+	TraceEvent          = "event"
+	TraceRollback       = "rollback"
+)
+
+// TraceOp is a state context operation made by a smart contract.
+type TraceOp struct {
+	Op             string                `json:"op"`
+	Key            datastore.Key         `json:"key,omitempty"`
+	Size           int                   `json:"size,omitempty"` // encoded value size
+	Transfer       *state.Transfer       `json:"transfer,omitempty"`
+	SignedTransfer *state.SignedTransfer `json:"signed_transfer,omitempty"`
+	Mint           *state.Mint           `json:"mint,omitempty"`
+	EventType      string                `json:"event_type,omitempty"`
+	EventTag       string                `json:"event_tag,omitempty"`
+	EventData      string                `json:"event_data,omitempty"`
+	Error          string                `json:"error,omitempty"`
+}
+
+// Trace records the state context operations of a smart contract in order.
+type Trace struct {
+	mutex sync.Mutex
+	ops   []*TraceOp
+}
+
+// NewTrace creates an empty trace.
+func NewTrace() *Trace {
+	return new(Trace)
+}
+
+func (tr *Trace) add(op *TraceOp, err error) {
+	if err != nil {
+		op.Error = err.Error()
+	}
+	tr.mutex.Lock()
+	defer tr.mutex.Unlock()
+	tr.ops = append(tr.ops, op)
+}
+
+// Len returns number of the recorded operations.
+func (tr *Trace) Len() int {
+	tr.mutex.Lock()
+	defer tr.mutex.Unlock()
+	return len(tr.ops)
+}
+
+// Ops returns the operations recorded starting from given index.
+func (tr *Trace) Ops(from int) []*TraceOp {
+	tr.mutex.Lock()
+	defer tr.mutex.Unlock()
+	if from >= len(tr.ops) {
+		return nil
+	}
+	return append([]*TraceOp(nil), tr.ops[from:]...)
+}
+
+// IOStats is number and size of the state reads, writes and deletes.
+type IOStats struct {
+	Reads        int64 `json:"reads"`
+	ReadBytes    int64 `json:"read_bytes"`
+	Writes       int64 `json:"writes"`
+	WrittenBytes int64 `json:"written_bytes"`
+	Deletes      int64 `json:"deletes"`
+}
+
+// IOStats of the operations recorded starting from given index.
+func (tr *Trace) IOStats(from int) (stats IOStats) {
+	for _, op := range tr.Ops(from) {
+		switch op.Op {
+		case TraceGet:
+			stats.Reads++
+			stats.ReadBytes += int64(op.Size)
+		case TraceInsert:
+			stats.Writes++
+			stats.WrittenBytes += int64(op.Size)
+		case TraceDelete:
+			stats.Deletes++
+		}
+	}
+	return
+}
+
+// CountingStateContext is a state context counting the state I/O of a
+// smart contract, unlike the trace it doesn't record the operations.
+type CountingStateContext struct {
+	StateContextI
+	stats IOStats
+}
+
+// NewCountingStateContext wraps the state context with the counters.
+func NewCountingStateContext(balances StateContextI) *CountingStateContext {
+	return &CountingStateContext{StateContextI: balances}
+}
+
+// IOStats returns the state I/O counted.
+func (cc *CountingStateContext) IOStats() IOStats {
+	return cc.stats
+}
+
+func (cc *CountingStateContext) GetTrieNode(key datastore.Key) (util.Serializable, error) {
+	node, err := cc.StateContextI.GetTrieNode(key)
+	cc.stats.Reads++
+	if err == nil && node != nil {
+		cc.stats.ReadBytes += int64(len(node.Encode()))
+	}
+	return node, err
+}
+
+func (cc *CountingStateContext) InsertTrieNode(key datastore.Key, node util.Serializable) (datastore.Key, error) {
+	k, err := cc.StateContextI.InsertTrieNode(key, node)
+	cc.stats.Writes++
+	cc.stats.WrittenBytes += int64(len(node.Encode()))
+	return k, err
+}
+
+func (cc *CountingStateContext) DeleteTrieNode(key datastore.Key) (datastore.Key, error) {
+	k, err := cc.StateContextI.DeleteTrieNode(key)
+	cc.stats.Deletes++
+	return k, err
+}
+
+type traceContextKey struct{}
+
+// WithTrace returns context requesting the smart contract executed with it
+// to record its operations in given trace.
+func WithTrace(ctx context.Context, tr *Trace) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, tr)
+}
+
+// GetTrace returns the trace of the context, if any.
+func GetTrace(ctx context.Context) *Trace {
+	if ctx == nil {
+		return nil
+	}
+	tr, _ := ctx.Value(traceContextKey{}).(*Trace)
+	return tr
+}
+
+// TracingStateContext is a state context recording the state operations of
+// a smart contract in a trace.
+type TracingStateContext struct {
+	StateContextI
+	trace *Trace
+}
+
+// NewTracingStateContext wraps the state context with the trace.
+func NewTracingStateContext(balances StateContextI, trace *Trace) *TracingStateContext {
+	return &TracingStateContext{StateContextI: balances, trace: trace}
+}
+
+// GetTrace returns the trace of the context.
+func (tc *TracingStateContext) GetTrace() *Trace {
+	return tc.trace
+}
+
+func (tc *TracingStateContext) GetTrieNode(key datastore.Key) (util.Serializable, error) {
+	node, err := tc.StateContextI.GetTrieNode(key)
+	var op = &TraceOp{Op: TraceGet, Key: key}
+	if err == nil && node != nil {
+		op.Size = len(node.Encode())
+	}
+	tc.trace.add(op, err)
+	return node, err
+}
+
+func (tc *TracingStateContext) InsertTrieNode(key datastore.Key, node util.Serializable) (datastore.Key, error) {
+	k, err := tc.StateContextI.InsertTrieNode(key, node)
+	tc.trace.add(&TraceOp{Op: TraceInsert, Key: key, Size: len(node.Encode())}, err)
+	return k, err
+}
+
+func (tc *TracingStateContext) DeleteTrieNode(key datastore.Key) (datastore.Key, error) {
+	k, err := tc.StateContextI.DeleteTrieNode(key)
+	tc.trace.add(&TraceOp{Op: TraceDelete, Key: key}, err)
+	return k, err
+}
+
+func (tc *TracingStateContext) AddTransfer(t *state.Transfer) error {
+	err := tc.StateContextI.AddTransfer(t)
+	tc.trace.add(&TraceOp{Op: TraceTransfer, Transfer: t}, err)
+	return err
+}
+
+func (tc *TracingStateContext) AddSignedTransfer(st *state.SignedTransfer) {
+	tc.StateContextI.AddSignedTransfer(st)
+	tc.trace.add(&TraceOp{Op: TraceSignedTransfer, SignedTransfer: st}, nil)
+}
+
+func (tc *TracingStateContext) AddMint(m *state.Mint) error {
+	err := tc.StateContextI.AddMint(m)
+	tc.trace.add(&TraceOp{Op: TraceMint, Mint: m}, err)
+	return err
+}
+
+func (tc *TracingStateContext) EmitEvent(eventType, tag string, data string) {
+	tc.StateContextI.EmitEvent(eventType, tag, data)
+	tc.trace.add(&TraceOp{
+		Op:        TraceEvent,
+		EventType: eventType,
+		EventTag:  tag,
+		EventData: data,
+	}, nil)
+}
@@ -0,0 +1,87 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"0chain.net/chaincore/state"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracingStateContext(t *testing.T) {
+	var (
+		_, sc = newTestGasStateContext(0)
+		trace = NewTrace()
+		tc    = NewTracingStateContext(sc, trace)
+	)
+	require.Nil(t, GetTrace(context.Background()))
+	require.Equal(t, trace, GetTrace(WithTrace(context.Background(), trace)))
+
+	_, err := tc.InsertTrieNode("a", testValue("value"))
+	require.NoError(t, err)
+	_, err = tc.GetTrieNode("a")
+	require.NoError(t, err)
+	_, err = tc.GetTrieNode("b")
+	require.Error(t, err)
+	_, err = tc.DeleteTrieNode("a")
+	require.NoError(t, err)
+	require.NoError(t, tc.AddTransfer(state.NewTransfer("client", "sc", 1)))
+	tc.EmitEvent("type", "tag", "data")
+
+	var failure = errors.New("failure")
+	require.Equal(t, failure, Atomic(tc, func() error {
+		return failure
+	}))
+
+	var ops = trace.Ops(0)
+	require.Len(t, ops, 7)
+	for i, op := range []string{TraceInsert, TraceGet, TraceGet, TraceDelete,
+		TraceTransfer, TraceEvent, TraceRollback} {
+		require.Equal(t, op, ops[i].Op)
+	}
+	require.Equal(t, 5, ops[0].Size)
+	require.Equal(t, 5, ops[1].Size)
+	require.NotEmpty(t, ops[2].Error)
+	require.Equal(t, failure.Error(), ops[6].Error)
+
+	require.Equal(t, IOStats{
+		Reads:        2,
+		ReadBytes:    5,
+		Writes:       1,
+		WrittenBytes: 5,
+		Deletes:      1,
+	}, trace.IOStats(0))
+	require.Equal(t, IOStats{Deletes: 1}, trace.IOStats(3))
+	require.Nil(t, trace.Ops(7))
+}
+
+func TestCountingStateContext(t *testing.T) {
+	var (
+		_, sc = newTestGasStateContext(0)
+		cc    = NewCountingStateContext(sc)
+	)
+	_, err := cc.InsertTrieNode("a", testValue("value"))
+	require.NoError(t, err)
+	_, err = cc.GetTrieNode("a")
+	require.NoError(t, err)
+	_, err = cc.GetTrieNode("b")
+	require.Error(t, err)
+
+	// the changes are rolled back through the counting context
+	require.Error(t, Atomic(cc, func() error {
+		_, err := cc.DeleteTrieNode("a")
+		require.NoError(t, err)
+		return errors.New("failure")
+	}))
+	_, err = cc.GetTrieNode("a")
+	require.NoError(t, err)
+
+	require.Equal(t, IOStats{
+		Reads:        3,
+		ReadBytes:    10,
+		Writes:       1,
+		WrittenBytes: 5,
+		Deletes:      1,
+	}, cc.IOStats())
+}
@@ -25,6 +25,7 @@ func SetupStateHandlers() {
 	http.HandleFunc("/v1/scstate/get", common.UserRateLimit(common.ToJSONResponse(c.GetNodeFromSCState)))
 	http.HandleFunc("/v1/scstats/", common.UserRateLimit(c.GetSCStats))
 	http.HandleFunc("/v1/screst/", common.UserRateLimit(c.HandleSCRest))
+	http.HandleFunc("/v1/transaction/simulate", common.UserRateLimit(common.ToJSONResponse(c.SimulateTransactionHandler)))
 	http.HandleFunc("/_smart_contract_stats", common.UserRateLimit(c.SCStats))
 }
 
@@ -127,23 +128,97 @@ func (c *Chain) GetSCStats(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "text/html")
 	PrintCSS(w)
-	smartcontract.ExecuteStats(ctx, scAddress, r.URL.Query(), w)
+	version := c.printSCVersions(w, scAddress)
+	smartcontract.ExecuteStats(ctx, scAddress, version, r.URL.Query(), w)
+}
+
+// getSCVersions returns activations of the smart contracts versions recorded
+// in the latest finalized state and round of the state
+func (c *Chain) getSCVersions() (*smartcontract.ContractVersions, int64, error) {
+	c.stateMutex.RLock()
+	defer c.stateMutex.RUnlock()
+
+	lfb := c.GetLatestFinalizedBlock()
+	if lfb == nil || lfb.ClientState == nil {
+		return nil, 0, common.NewError("empty_lfb", "empty latest finalized block or state")
+	}
+	clientState := CreateTxnMPT(lfb.ClientState) // begin transaction
+	sctx := c.NewStateContext(lfb, clientState, &transaction.Transaction{}, nil)
+	cv, err := smartcontract.GetContractVersionsNode(sctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	return cv, lfb.Round, nil
+}
+
+// printSCVersions prints the default and scheduled versions of the smart
+// contract, the version active at the latest finalized round and whether
+// the node supports them; it returns the active version
+func (c *Chain) printSCVersions(w http.ResponseWriter, scAddress string) int {
+	cv, round, err := c.getSCVersions()
+	if err != nil {
+		fmt.Fprintf(w, "sc_versions: %v<br>", err)
+		return smartcontract.DefaultContractVersion
+	}
+	versions := append([]*smartcontract.VersionActivation{
+		{Version: smartcontract.DefaultContractVersion},
+	}, cv.Activations[scAddress]...)
+	active := 0
+	for i, v := range versions {
+		if v.Round <= round {
+			active = i
+		}
+	}
+	fmt.Fprintf(w, "<table class='menu' style='border-collapse: collapse;'>")
+	fmt.Fprintf(w, "<tr class='header'><td>Version</td><td>Activation round</td><td>Status</td><td>Supported</td></tr>")
+	for i, v := range versions {
+		status := "inactive"
+		switch {
+		case i == active:
+			status = "active"
+		case i > active:
+			status = "scheduled"
+		}
+		supported := smartcontract.GetSmartContractVersion(scAddress, v.Version) != nil
+		fmt.Fprintf(w, "<tr><td>%v</td><td>%v</td><td>%v</td><td>%v</td></tr>", v.Version, v.Round, status, supported)
+	}
+	fmt.Fprintf(w, "</table><br>")
+	return versions[active].Version
 }
 
 func (c *Chain) SCStats(w http.ResponseWriter, r *http.Request) {
 	PrintCSS(w)
 	fmt.Fprintf(w, "<table class='menu' style='border-collapse: collapse;'>")
-	fmt.Fprintf(w, "<tr class='header'><td>Type</td><td>ID</td><td>Link</td><td>RestAPIs</td></tr>")
+	fmt.Fprintf(w, "<tr class='header'><td>Type</td><td>ID</td><td>Version</td><td>Link</td><td>RestAPIs</td></tr>")
 	re := regexp.MustCompile(`\*.*\.`)
-	keys := make([]string, 0, len(smartcontract.ContractMap))
-	for k := range smartcontract.ContractMap {
-		keys = append(keys, k)
+	cv, round, err := c.getSCVersions()
+	if err != nil {
+		cv = new(smartcontract.ContractVersions)
+	}
+	keys := smartcontract.GetSmartContractAddresses()
+	for _, k := range keys {
+		version := cv.Active(k, round)
+		scType := "unsupported"
+		if sc := smartcontract.GetSmartContractVersion(k, version); sc != nil {
+			scType = re.ReplaceAllString(reflect.TypeOf(sc).String(), "")
+		}
+		fmt.Fprintf(w, `<tr><td>%v</td><td>%v</td><td>%v</td><td><li><a href='%v'>%v</a></li></td><td><li><a href='%v'>%v</a></li></td></tr>`, scType, strings.ToLower(k), version, "v1/scstats/"+k, "/v1/scstats/"+scType, "v1/screst/"+k, "/v1/screst/*key*")
 	}
-	sort.SliceStable(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	fmt.Fprintf(w, "</table>")
+
+	// errors and state I/O per execution of the functions
+	fmt.Fprintf(w, "<br><table class='menu' style='border-collapse: collapse;'>")
+	fmt.Fprintf(w, "<tr class='header'><td>SC</td><td>Function</td><td>Calls</td><td>Errors</td><td>Reads (mean/max)</td><td>Read bytes (mean/max)</td><td>Writes (mean/max)</td><td>Written bytes (mean/max)</td><td>Deletes (mean/max)</td></tr>")
 	for _, k := range keys {
-		sc := smartcontract.ContractMap[k]
-		scType := re.ReplaceAllString(reflect.TypeOf(sc).String(), "")
-		fmt.Fprintf(w, `<tr><td>%v</td><td>%v</td><td><li><a href='%v'>%v</a></li></td><td><li><a href='%v'>%v</a></li></td></tr>`, scType, strings.ToLower(k), "v1/scstats/"+k, "/v1/scstats/"+scType, "v1/screst/"+k, "/v1/screst/*key*")
+		for _, fs := range smartcontract.GetFunctionStats(k) {
+			fmt.Fprintf(w, "<tr><td>%v</td><td>%v</td><td>%v</td><td>%v</td><td>%.2f/%v</td><td>%.2f/%v</td><td>%.2f/%v</td><td>%.2f/%v</td><td>%.2f/%v</td></tr>",
+				strings.ToLower(k), fs.Name, fs.Reads.Count(), fs.Errors.Count(),
+				fs.Reads.Mean(), fs.Reads.Max(),
+				fs.ReadBytes.Mean(), fs.ReadBytes.Max(),
+				fs.Writes.Mean(), fs.Writes.Max(),
+				fs.WrittenBytes.Mean(), fs.WrittenBytes.Max(),
+				fs.Deletes.Mean(), fs.Deletes.Max())
+		}
 	}
 	fmt.Fprintf(w, "</table>")
 }
@@ -155,8 +230,12 @@ func (c *Chain) GetSCRestPoints(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	key := pathParams[1]
-	scInt, ok := smartcontract.ContractMap[key]
-	if !ok {
+	cv, round, err := c.getSCVersions()
+	if err != nil {
+		cv = new(smartcontract.ContractVersions)
+	}
+	scInt := smartcontract.GetSmartContractVersion(key, cv.Active(key, round))
+	if scInt == nil {
 		return
 	}
 	PrintCSS(w)
@@ -0,0 +1,108 @@
+package chain_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"0chain.net/chaincore/chain"
+	"0chain.net/chaincore/config"
+	sci "0chain.net/chaincore/smartcontractinterface"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+	"0chain.net/smartcontract/faucetsc"
+	"0chain.net/smartcontract/minersc"
+)
+
+func getTestBalance(t *testing.T, mpt util.MerklePatriciaTrieI, key string) state.Balance {
+	val, err := mpt.GetNodeValue(util.Path(key))
+	if err == util.ErrValueNotPresent {
+		return 0
+	}
+	require.NoError(t, err)
+	var s = &state.State{}
+	require.NoError(t, s.Decode(val.Encode()))
+	return s.Balance
+}
+
+// newRefillTxn returns a faucet refill transaction
+func newRefillTxn(t *testing.T, from string, value, fee, gasLimit int64) *transaction.Transaction {
+	data, err := json.Marshal(&sci.SmartContractTransactionData{
+		FunctionName: "refill",
+		InputData:    json.RawMessage("{}"),
+		GasLimit:     gasLimit,
+	})
+	require.NoError(t, err)
+	return &transaction.Transaction{
+		HashIDField:     datastore.HashIDField{Hash: encryption.Hash("refill")},
+		ClientID:        clientID(from),
+		ToClientID:      faucetsc.ADDRESS,
+		Value:           value,
+		Fee:             fee,
+		TransactionType: transaction.TxnTypeSmartContract,
+		TransactionData: string(data),
+	}
+}
+
+func enableTestFees(t *testing.T) {
+	var feeEnabled = config.DevConfiguration.IsFeeEnabled
+	config.DevConfiguration.IsFeeEnabled = true
+	t.Cleanup(func() { config.DevConfiguration.IsFeeEnabled = feeEnabled })
+}
+
+func TestChain_UpdateStateFailedSmartContract(t *testing.T) {
+	enableTestFees(t)
+	var (
+		b      = newParallelTestBlock(t, map[string]state.Balance{"b": 1000})
+		c      = chain.NewChainFromConfig()
+		refill = newRefillTxn(t, "b", 5000, 10, 0)
+	)
+
+	// the transaction fails, but it's not dropped
+	_, err := c.UpdateState(context.Background(), b, refill)
+	require.NoError(t, err)
+	require.Equal(t, transaction.TxnFail, refill.Status)
+	require.Contains(t, refill.TransactionOutput, "broke")
+
+	// the fee is charged, the changes are discarded
+	require.EqualValues(t, 1000-10, getTestBalance(t, b.ClientState, clientID("b")))
+	require.EqualValues(t, 10, getTestBalance(t, b.ClientState, minersc.ADDRESS))
+	require.Zero(t, getTestBalance(t, b.ClientState, faucetsc.ADDRESS))
+
+	// the transaction can't pay the fee
+	refill = newRefillTxn(t, "b", 5000, 2000, 0)
+	_, err = c.UpdateState(context.Background(), b, refill)
+	require.Error(t, err)
+}
+
+func TestChain_UpdateStateOutOfGas(t *testing.T) {
+	require.NoError(t, config.Forks.Set(map[string]int64{
+		config.ForkGasMetering: 0,
+	}))
+	defer func() { require.NoError(t, config.Forks.Set(nil)) }()
+	enableTestFees(t)
+
+	var (
+		b      = newParallelTestBlock(t, map[string]state.Balance{"b": 1000})
+		c      = chain.NewChainFromConfig()
+		refill = newRefillTxn(t, "b", 5, 1000, 150)
+	)
+	c.SmartContractMaxGas = 10000
+	c.SmartContractGasPrice = 1
+
+	// the transaction fails, but it's not dropped
+	_, err := c.UpdateState(context.Background(), b, refill)
+	require.NoError(t, err)
+	require.Equal(t, transaction.TxnFail, refill.Status)
+	require.Contains(t, refill.TransactionOutput, "out_of_gas")
+
+	// the gas used is charged, the changes are discarded
+	require.EqualValues(t, 1000-150, getTestBalance(t, b.ClientState, clientID("b")))
+	require.EqualValues(t, 150, getTestBalance(t, b.ClientState, minersc.ADDRESS))
+	require.Zero(t, getTestBalance(t, b.ClientState, faucetsc.ADDRESS))
+}
@@ -0,0 +1,87 @@
+package chain
+
+import (
+	"context"
+
+	"0chain.net/chaincore/block"
+	bcstate "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/util"
+)
+
+// TransactionTrace is the state operations of a smart contract transaction
+// replayed against the state of the block it's included in.
+type TransactionTrace struct {
+	Hash      string                   `json:"hash"`
+	BlockHash string                   `json:"block_hash"`
+	Round     int64                    `json:"round"`
+	Output    string                   `json:"output,omitempty"`
+	Error     string                   `json:"error,omitempty"`
+	Ops       []*bcstate.TraceOp       `json:"ops"`
+	IO        bcstate.IOStats          `json:"io"`
+	Status    int                      `json:"status"`
+	Recorded  *transaction.Transaction `json:"recorded"` // as stored in the block
+}
+
+// TraceTransaction replays the transactions of the block preceding given one
+// against the state of the previous block, then replays the transaction
+// recording its state operations. Nothing is persisted.
+func (c *Chain) TraceTransaction(ctx context.Context, b, prev *block.Block,
+	hash string) (*TransactionTrace, error) {
+
+	var recorded = b.GetTransaction(hash)
+	if recorded == nil {
+		return nil, common.NewError("trace_transaction",
+			"transaction not found in the block")
+	}
+
+	var prevState = prev.ClientState
+	if prevState == nil {
+		prevState = util.NewMerklePatriciaTrie(c.stateDB,
+			util.Sequence(prev.Round), prev.ClientStateHash)
+	}
+
+	// the block the transactions are replayed in
+	var rb = block.NewBlock(b.ChainID, b.Round)
+	rb.Hash = b.Hash
+	rb.PrevHash = b.PrevHash
+	rb.PrevBlock = prev
+	rb.MinerID = b.MinerID
+	rb.CreationDate = b.CreationDate
+	rb.MagicBlock = b.MagicBlock
+	rb.SetRoundRandomSeed(b.GetRoundRandomSeed())
+	rb.ClientState = CreateTxnMPT(prevState) // discarded
+
+	for _, txn := range b.Txns {
+		txn = txn.Clone()
+		if txn.Hash != hash {
+			// the transactions failed to apply don't change the state
+			_, _ = c.UpdateState(ctx, rb, txn)
+			continue
+		}
+		var (
+			trace  = bcstate.NewTrace()
+			_, err = c.UpdateState(bcstate.WithTrace(ctx, trace), rb, txn)
+			result = &TransactionTrace{
+				Hash:      hash,
+				BlockHash: b.Hash,
+				Round:     b.Round,
+				Output:    txn.TransactionOutput,
+				Ops:       trace.Ops(0),
+				IO:        trace.IOStats(0),
+				Status:    txn.Status,
+				Recorded:  recorded,
+			}
+		)
+		switch {
+		case err != nil:
+			result.Error = err.Error()
+		case txn.Status == transaction.TxnFail:
+			result.Error = txn.TransactionOutput
+		}
+		return result, nil
+	}
+	return nil, common.NewError("trace_transaction",
+		"transaction not found in the block")
+}
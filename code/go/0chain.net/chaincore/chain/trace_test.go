@@ -0,0 +1,67 @@
+package chain_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"0chain.net/chaincore/block"
+	"0chain.net/chaincore/chain"
+	bcstate "0chain.net/chaincore/chain/state"
+	sci "0chain.net/chaincore/smartcontractinterface"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/smartcontract/faucetsc"
+)
+
+func TestChain_TraceTransaction(t *testing.T) {
+	var (
+		prev = newParallelTestBlock(t, map[string]state.Balance{"a": 100})
+		root = prev.ClientState.GetRoot()
+		b    = block.NewBlock("", prev.Round+1)
+		c    = chain.NewChainFromConfig()
+	)
+
+	data, err := json.Marshal(&sci.SmartContractTransactionData{
+		FunctionName: "refill",
+		InputData:    json.RawMessage("{}"),
+	})
+	require.NoError(t, err)
+	var refill = &transaction.Transaction{
+		HashIDField:     datastore.HashIDField{Hash: encryption.Hash("refill")},
+		ClientID:        clientID("b"),
+		ToClientID:      faucetsc.ADDRESS,
+		Value:           5,
+		TransactionType: transaction.TxnTypeSmartContract,
+		TransactionData: string(data),
+	}
+	// the refill depends on the preceding transaction
+	b.Txns = []*transaction.Transaction{newSendTxn("send", "a", "b", 10), refill}
+
+	trace, err := c.TraceTransaction(context.Background(), b, prev, refill.Hash)
+	require.NoError(t, err)
+	require.Empty(t, trace.Error)
+	require.Equal(t, refill, trace.Recorded)
+
+	var ops = make(map[string]*bcstate.TraceOp)
+	for _, op := range trace.Ops {
+		ops[op.Op] = op
+	}
+	require.Contains(t, ops, bcstate.TraceGet)
+	require.Contains(t, ops, bcstate.TraceInsert)
+	require.Contains(t, ops, bcstate.TraceTransfer)
+	require.Equal(t, state.NewTransfer(clientID("b"), faucetsc.ADDRESS, 5),
+		ops[bcstate.TraceTransfer].Transfer)
+	require.EqualValues(t, 1, trace.IO.Writes)
+
+	// nothing is persisted, the block transactions are not changed
+	require.Equal(t, root, prev.ClientState.GetRoot())
+	require.Empty(t, refill.TransactionOutput)
+
+	_, err = c.TraceTransaction(context.Background(), b, prev, "unknown")
+	require.Error(t, err)
+}
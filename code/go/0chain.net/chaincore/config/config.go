@@ -84,6 +84,7 @@ func SetupConfig() {
 		panic(fmt.Errorf("fatal error config file: %s", err))
 	}
 	setupDevConfig()
+	setupForks()
 }
 
 func SetupDefaultSmartContractConfig() {
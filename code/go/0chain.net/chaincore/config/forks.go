@@ -0,0 +1,124 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"0chain.net/core/viper"
+)
+
+// Hard forks, features of the protocol changing the consensus rules. A
+// feature is active from the round configured for it in the fork schedule,
+// a feature the schedule doesn't have is never active.
+const (
+	// ForkGasMetering - smart contract transactions are limited by the gas
+	// and charged for the gas used
+	ForkGasMetering = "gas_metering"
+	// ForkFeeValidation - transactions with a fee less than the min fee
+	// fail executing, not only rejected by the transactions pool
+	ForkFeeValidation = "fee_validation"
+	// ForkPerformanceRewards - the block rewards and fees paid to the
+	// generator and the sharders are weighted by their performance
+	ForkPerformanceRewards = "performance_rewards"
+)
+
+// ForkNames is the list of the known hard forks.
+var ForkNames = []string{
+	ForkGasMetering,
+	ForkFeeValidation,
+	ForkPerformanceRewards,
+}
+
+// Fork is a hard fork and the round it's active from.
+type Fork struct {
+	Name  string `json:"name"`
+	Round int64  `json:"round"`
+}
+
+// ForkSchedule maps names of the features to their activation rounds.
+type ForkSchedule struct {
+	mutex  sync.RWMutex
+	rounds map[string]int64
+}
+
+// NewForkSchedule returns schedule of given activation rounds.
+func NewForkSchedule(rounds map[string]int64) (*ForkSchedule, error) {
+	fs := new(ForkSchedule)
+	if err := fs.Set(rounds); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+// Set replaces the activation rounds, all the features must be known.
+func (fs *ForkSchedule) Set(rounds map[string]int64) error {
+	var next = make(map[string]int64, len(rounds))
+	for name, round := range rounds {
+		if !isKnownFork(name) {
+			return fmt.Errorf("unknown fork %q", name)
+		}
+		if round < 0 {
+			return fmt.Errorf("negative activation round %d of fork %q",
+				round, name)
+		}
+		next[name] = round
+	}
+
+	fs.mutex.Lock()
+	defer fs.mutex.Unlock()
+	fs.rounds = next
+	return nil
+}
+
+// IsActive returns true if the feature is active at given round.
+func (fs *ForkSchedule) IsActive(feature string, round int64) bool {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+	activation, ok := fs.rounds[feature]
+	return ok && round >= activation
+}
+
+// Forks returns the scheduled forks ordered by activation round.
+func (fs *ForkSchedule) Forks() (forks []Fork) {
+	fs.mutex.RLock()
+	defer fs.mutex.RUnlock()
+	forks = make([]Fork, 0, len(fs.rounds))
+	for name, round := range fs.rounds {
+		forks = append(forks, Fork{Name: name, Round: round})
+	}
+	sort.Slice(forks, func(i, j int) bool {
+		if forks[i].Round == forks[j].Round {
+			return forks[i].Name < forks[j].Name
+		}
+		return forks[i].Round < forks[j].Round
+	})
+	return
+}
+
+func isKnownFork(name string) bool {
+	for _, known := range ForkNames {
+		if known == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Forks is the hard forks schedule of the chain.
+var Forks = new(ForkSchedule)
+
+// IsActive returns true if the feature is active at given round on the chain.
+func IsActive(feature string, round int64) bool {
+	return Forks.IsActive(feature, round)
+}
+
+func setupForks() {
+	var rounds = make(map[string]int64)
+	for name := range viper.GetStringMap("server_chain.forks") {
+		rounds[name] = viper.GetInt64("server_chain.forks." + name)
+	}
+	if err := Forks.Set(rounds); err != nil {
+		panic(fmt.Errorf("fatal error config file: forks: %v", err))
+	}
+}
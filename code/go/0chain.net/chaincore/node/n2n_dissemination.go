@@ -0,0 +1,430 @@
+package node
+
+import (
+	"context"
+	"math/bits"
+	"math/rand"
+	"net/http"
+	"sort"
+	"sync"
+
+	"0chain.net/core/cache"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/logging"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// Dissemination strategies.
+const (
+	DisseminationBroadcast = "broadcast"
+	DisseminationGossip    = "gossip"
+	DisseminationHypercube = "hypercube"
+	DisseminationTree      = "tree"
+)
+
+/*Disseminator - selects the nodes a node sends a message to when it's sent to all
+* the nodes of a pool. The nodes not selected get the message relayed by the
+* selected ones, the relay state is carried by the InitialNodeID, CurrentRelayLength
+* and MaxRelayLength of the send options */
+type Disseminator interface {
+	Name() string
+	// Targets returns the nodes self sends the message, originated by the
+	// initial node and having given relay length, to.
+	Targets(nodes []*Node, self, initial *Node, relayLength int64) []*Node
+	// MaxRelayLength returns the max relay length a message disseminated in
+	// a pool of given size can have, 0 for not relayed messages.
+	MaxRelayLength(size int) int64
+}
+
+/*NewDisseminator - create a dissemination strategy by its name */
+func NewDisseminator(name string, fanout int) (Disseminator, error) {
+	switch name {
+	case "", DisseminationBroadcast:
+		return Broadcast{}, nil
+	case DisseminationGossip:
+		return NewGossip(fanout), nil
+	case DisseminationHypercube:
+		return Hypercube{}, nil
+	case DisseminationTree:
+		return NewTree(fanout), nil
+	}
+	return nil, common.NewErrorf("unknown_dissemination",
+		"unknown dissemination strategy: %q", name)
+}
+
+/*Broadcast - the originating node sends the message to every node */
+type Broadcast struct{}
+
+func (Broadcast) Name() string {
+	return DisseminationBroadcast
+}
+
+func (Broadcast) Targets(nodes []*Node, self, initial *Node, relayLength int64) []*Node {
+	if relayLength > 0 {
+		return nil
+	}
+	return excludeNodes(nodes, self)
+}
+
+func (Broadcast) MaxRelayLength(size int) int64 {
+	return 0
+}
+
+/*Gossip - every node sends the message to fanout random nodes, nodes relay
+* the message only the first time they receive it */
+type Gossip struct {
+	Fanout int
+}
+
+/*NewGossip - create a gossip dissemination with given fanout, at least 2 */
+func NewGossip(fanout int) Gossip {
+	if fanout < 2 {
+		fanout = 2
+	}
+	return Gossip{Fanout: fanout}
+}
+
+func (g Gossip) Name() string {
+	return DisseminationGossip
+}
+
+func (g Gossip) Targets(nodes []*Node, self, initial *Node, relayLength int64) []*Node {
+	var others = excludeNodes(nodes, self, initial)
+	rand.Shuffle(len(others), func(i, j int) {
+		others[i], others[j] = others[j], others[i]
+	})
+	if len(others) > g.Fanout {
+		others = others[:g.Fanout]
+	}
+	return others
+}
+
+// MaxRelayLength allows one relay more than a message needs to reach all the
+// nodes without duplicates, to make up for the random duplicates.
+func (g Gossip) MaxRelayLength(size int) (max int64) {
+	for reached := 1; reached < size; reached *= g.Fanout {
+		max++
+	}
+	return
+}
+
+/*Hypercube - binomial tree rooted at the initial node, the node at position
+* p relative to the initial node sends the message to p + 2^k for every
+* 2^k greater than p */
+type Hypercube struct{}
+
+func (Hypercube) Name() string {
+	return DisseminationHypercube
+}
+
+func (Hypercube) Targets(nodes []*Node, self, initial *Node, relayLength int64) []*Node {
+	var sorted, pos, ok = relativePosition(nodes, self, initial)
+	if !ok {
+		return Broadcast{}.Targets(nodes, self, initial, relayLength)
+	}
+	var targets []*Node
+	for k := bits.Len(uint(pos)); pos+1<<uint(k) < len(sorted); k++ {
+		targets = append(targets, nodeAt(sorted, initial, pos+1<<uint(k)))
+	}
+	return targets
+}
+
+func (Hypercube) MaxRelayLength(size int) int64 {
+	if size < 2 {
+		return 0
+	}
+	return int64(bits.Len(uint(size-1))) - 1
+}
+
+/*Tree - tree rooted at the initial node, the node at position p relative to
+* the initial node sends the message to its fanout children p*fanout+1 ... */
+type Tree struct {
+	Fanout int
+}
+
+/*NewTree - create a tree dissemination with given fanout, at least 2 */
+func NewTree(fanout int) Tree {
+	if fanout < 2 {
+		fanout = 2
+	}
+	return Tree{Fanout: fanout}
+}
+
+func (t Tree) Name() string {
+	return DisseminationTree
+}
+
+func (t Tree) Targets(nodes []*Node, self, initial *Node, relayLength int64) []*Node {
+	var sorted, pos, ok = relativePosition(nodes, self, initial)
+	if !ok {
+		return Broadcast{}.Targets(nodes, self, initial, relayLength)
+	}
+	var targets []*Node
+	for child := pos*t.Fanout + 1; child <= pos*t.Fanout+t.Fanout && child < len(sorted); child++ {
+		targets = append(targets, nodeAt(sorted, initial, child))
+	}
+	return targets
+}
+
+func (t Tree) MaxRelayLength(size int) (max int64) {
+	var level, covered = 1, 1
+	for covered+level*t.Fanout < size {
+		level *= t.Fanout
+		covered += level
+		max++
+	}
+	return
+}
+
+func excludeNodes(nodes []*Node, exclude ...*Node) []*Node {
+	var list = make([]*Node, 0, len(nodes))
+outer:
+	for _, nd := range nodes {
+		for _, ex := range exclude {
+			if ex != nil && ex.GetKey() == nd.GetKey() {
+				continue outer
+			}
+		}
+		list = append(list, nd)
+	}
+	return list
+}
+
+// relativePosition sorts the nodes the same way on every node and returns
+// position of self relative to the initial node. Both are expected to be
+// in the pool for the structured dissemination.
+func relativePosition(nodes []*Node, self, initial *Node) (sorted []*Node, pos int, ok bool) {
+	sorted = make([]*Node, len(nodes))
+	copy(sorted, nodes)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].GetKey() < sorted[j].GetKey()
+	})
+	var selfIdx, initIdx = -1, -1
+	for i, nd := range sorted {
+		if self != nil && nd.GetKey() == self.GetKey() {
+			selfIdx = i
+		}
+		if initial != nil && nd.GetKey() == initial.GetKey() {
+			initIdx = i
+		}
+	}
+	if selfIdx < 0 || initIdx < 0 {
+		return nil, 0, false
+	}
+	return sorted, (selfIdx - initIdx + len(sorted)) % len(sorted), true
+}
+
+func nodeAt(sorted []*Node, initial *Node, pos int) *Node {
+	for i, nd := range sorted {
+		if nd.GetKey() == initial.GetKey() {
+			return sorted[(i+pos)%len(sorted)]
+		}
+	}
+	return nil
+}
+
+var (
+	disseminatorsMutex sync.RWMutex
+	disseminators      = make(map[string]Disseminator)
+	uriSendOptions     = make(map[string]*SendOptions)
+)
+
+/*SetDisseminator - set the dissemination strategy of the messages sent to the given uri */
+func SetDisseminator(uri string, d Disseminator) {
+	disseminatorsMutex.Lock()
+	defer disseminatorsMutex.Unlock()
+	disseminators[uri] = d
+}
+
+/*GetDisseminator - get the dissemination strategy of the messages sent to the given uri */
+func GetDisseminator(uri string) Disseminator {
+	disseminatorsMutex.RLock()
+	defer disseminatorsMutex.RUnlock()
+	if d, ok := disseminators[uri]; ok {
+		return d
+	}
+	return Broadcast{}
+}
+
+func setURISendOptions(uri string, options *SendOptions) {
+	disseminatorsMutex.Lock()
+	defer disseminatorsMutex.Unlock()
+	uriSendOptions[uri] = options
+}
+
+func getURISendOptions(uri string) (*SendOptions, bool) {
+	disseminatorsMutex.RLock()
+	defer disseminatorsMutex.RUnlock()
+	options, ok := uriSendOptions[uri]
+	return options, ok
+}
+
+func readDisseminationConfig() {
+	var (
+		gossipFanout = viper.GetInt("network.dissemination.gossip_fanout")
+		treeFanout   = viper.GetInt("network.dissemination.tree_fanout")
+	)
+	for uri, name := range viper.GetStringMapString("network.dissemination.messages") {
+		var fanout = gossipFanout
+		if name == DisseminationTree {
+			fanout = treeFanout
+		}
+		d, err := NewDisseminator(name, fanout)
+		if err != nil {
+			logging.Logger.Error("read dissemination config", zap.String("uri", uri),
+				zap.Error(err))
+			continue
+		}
+		SetDisseminator(uri, d)
+	}
+}
+
+type sendPoolKey struct{}
+
+func withSendPool(ctx context.Context, np *Pool) context.Context {
+	return context.WithValue(ctx, sendPoolKey{}, np)
+}
+
+func getSendPool(ctx context.Context) *Pool {
+	np, _ := ctx.Value(sendPoolKey{}).(*Pool)
+	return np
+}
+
+// entityDissemination selects the nodes an entity sent to all the nodes of a
+// pool is sent to directly, once for all the nodes.
+type entityDissemination struct {
+	uri     string
+	options *SendOptions
+	data    []byte
+	once    sync.Once
+	send    *SendOptions
+	targets map[string]bool
+}
+
+func newEntityDissemination(uri string, options *SendOptions, data []byte) *entityDissemination {
+	return &entityDissemination{uri: uri, options: options, data: data}
+}
+
+// sendOptions returns options to send the entity to the receiver with, false
+// if the entity isn't sent to the receiver directly.
+func (ed *entityDissemination) sendOptions(ctx context.Context, receiver *Node) (*SendOptions, bool) {
+	var np = getSendPool(ctx)
+	if np == nil {
+		return ed.options, true // sent to the specific node
+	}
+	ed.once.Do(func() {
+		ed.send = ed.options
+		var d = GetDisseminator(ed.uri)
+		if _, ok := d.(Broadcast); ok {
+			return
+		}
+		var (
+			nodes   = np.CopyNodes()
+			self    = Self.Underlying()
+			initial = self
+			send    = *ed.options
+		)
+		if send.InitialNodeID == "" {
+			// originated by this node
+			send.InitialNodeID = self.GetKey()
+			send.CurrentRelayLength = 0
+			if max := d.MaxRelayLength(len(nodes)); send.MaxRelayLength == 0 || max < send.MaxRelayLength {
+				send.MaxRelayLength = max
+			}
+			// the relaying nodes can't change the data
+			send.PayloadHash = encryption.Hash(ed.data)
+			signature, err := Self.Sign(relayHash(send.InitialNodeID, ed.uri, send.PayloadHash))
+			if err != nil {
+				logging.N2n.Error("sign disseminated message", zap.String("uri", ed.uri),
+					zap.Error(err))
+				return
+			}
+			send.InitialSignature = signature
+		} else if initial = np.GetNode(send.InitialNodeID); initial == nil {
+			initial = GetNode(send.InitialNodeID)
+		}
+		ed.send = &send
+		ed.targets = make(map[string]bool)
+		for _, nd := range d.Targets(nodes, self, initial, send.CurrentRelayLength) {
+			ed.targets[nd.GetKey()] = true
+		}
+	})
+	if ed.targets != nil && !ed.targets[receiver.GetKey()] {
+		return nil, false
+	}
+	return ed.send, true
+}
+
+// relayHash returns hash the initial node of a disseminated message signs
+func relayHash(initialNodeID, uri, payloadHash string) string {
+	return encryption.Hash(initialNodeID + ":" + uri + ":" + payloadHash)
+}
+
+// validateRelayedRequest checks the data of a disseminated message is the
+// data signed by the initial node
+func validateRelayedRequest(initial *Node, r *http.Request, data []byte) bool {
+	var payloadHash = r.Header.Get(HeaderPayloadHash)
+	if payloadHash != encryption.Hash(data) {
+		return false
+	}
+	ok, err := initial.Verify(r.Header.Get(HeaderInitialSignature),
+		relayHash(initial.GetKey(), r.URL.Path, payloadHash))
+	return ok && err == nil
+}
+
+// relayEntity relays the entity received with given relay state to the
+// nodes of the pool selected by the dissemination strategy of the uri. The
+// data is relayed as received, signed by the initial node.
+func relayEntity(ctx context.Context, np *Pool, uri string, received *SendOptions,
+	entity datastore.Entity, data []byte) {
+
+	options, ok := getURISendOptions(uri)
+	if !ok || received.CurrentRelayLength >= received.MaxRelayLength {
+		return
+	}
+	var relay = *options
+	relay.InitialNodeID = received.InitialNodeID
+	relay.CurrentRelayLength = received.CurrentRelayLength + 1
+	relay.MaxRelayLength = received.MaxRelayLength
+	relay.PayloadHash = received.PayloadHash
+	relay.InitialSignature = received.InitialSignature
+	np.SendAll(ctx, sendEntityData(uri, &relay, entity, data))
+}
+
+/*MessageCache - a message filter accepting a message only the first time it's seen,
+* used to stop relaying the messages already relayed */
+type MessageCache struct {
+	mutex sync.Mutex
+	seen  *cache.LRU
+}
+
+/*NewMessageCache - create a message cache remembering given number of messages */
+func NewMessageCache(size int) *MessageCache {
+	return &MessageCache{seen: cache.NewLRUCache(size)}
+}
+
+// AcceptMessage - implement the node.MessageFilterI interface
+func (mc *MessageCache) AcceptMessage(entityName string, entityID string) bool {
+	return mc.accept(entityName + ":" + entityID)
+}
+
+// AcceptRelayed accepts a disseminated message the first time it's seen, the
+// messages are told apart by the initial node, uri and hash of the data.
+func (mc *MessageCache) AcceptRelayed(initialNodeID, uri, payloadHash string) bool {
+	return mc.accept(initialNodeID + ":" + uri + ":" + payloadHash)
+}
+
+func (mc *MessageCache) accept(key string) bool {
+	mc.mutex.Lock()
+	defer mc.mutex.Unlock()
+	if _, err := mc.seen.Get(key); err == nil {
+		return false
+	}
+	mc.seen.Add(key, true)
+	return true
+}
+
+var relayedMessages = NewMessageCache(1000)
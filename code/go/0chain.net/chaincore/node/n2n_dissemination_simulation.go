@@ -0,0 +1,78 @@
+package node
+
+import (
+	"time"
+)
+
+/*DisseminationSimulation - outcome of a message disseminated in a simulated network */
+type DisseminationSimulation struct {
+	MaxRelayLength int64
+	// Received is the time it takes a message to reach a node
+	Received map[string]time.Duration
+	// Hops is the number of messages on the path of the first message a node receives
+	Hops map[string]int64
+	// Sent is the number of messages sent by a node
+	Sent       map[string]int
+	Messages   int
+	Duplicates int
+	Latency    time.Duration
+}
+
+type simulatedMessage struct {
+	at          time.Duration
+	from, to    *Node
+	relayLength int64
+}
+
+/*SimulateDissemination - simulate dissemination of a message originated by the given
+* node, the delay function gives the network delay between two nodes */
+func SimulateDissemination(d Disseminator, nodes []*Node, origin *Node,
+	delay func(from, to *Node) time.Duration) *DisseminationSimulation {
+
+	var (
+		sim = &DisseminationSimulation{
+			MaxRelayLength: d.MaxRelayLength(len(nodes)),
+			Received:       map[string]time.Duration{origin.GetKey(): 0},
+			Hops:           map[string]int64{origin.GetKey(): 0},
+			Sent:           make(map[string]int),
+		}
+		queue []*simulatedMessage
+	)
+	var send = func(at time.Duration, from *Node, relayLength int64) {
+		for _, to := range d.Targets(nodes, from, origin, relayLength) {
+			var msg = &simulatedMessage{at: at, from: from, to: to, relayLength: relayLength}
+			if delay != nil {
+				msg.at += delay(from, to)
+			}
+			queue = append(queue, msg)
+			sim.Sent[from.GetKey()]++
+			sim.Messages++
+		}
+	}
+	send(0, origin, 0)
+	for len(queue) > 0 {
+		var next = 0
+		for i, msg := range queue {
+			if msg.at < queue[next].at {
+				next = i
+			}
+		}
+		var msg = queue[next]
+		queue = append(queue[:next], queue[next+1:]...)
+
+		var key = msg.to.GetKey()
+		if _, ok := sim.Received[key]; ok {
+			sim.Duplicates++
+			continue
+		}
+		sim.Received[key] = msg.at
+		sim.Hops[key] = msg.relayLength + 1
+		if msg.at > sim.Latency {
+			sim.Latency = msg.at
+		}
+		if msg.relayLength < sim.MaxRelayLength {
+			send(msg.at, msg.to, msg.relayLength+1)
+		}
+	}
+	return sim
+}
@@ -0,0 +1,229 @@
+package node
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"0chain.net/chaincore/client"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/memorystore"
+)
+
+func newDisseminationNodes(n int) []*Node {
+	var nodes = make([]*Node, n)
+	for i := range nodes {
+		nodes[i] = Provider()
+		nodes[i].ID = fmt.Sprintf("node-%02d", i)
+		nodes[i].N2NHost = fmt.Sprintf("198.18.0.%d", i+1)
+	}
+	return nodes
+}
+
+func TestNewDisseminator(t *testing.T) {
+	for _, name := range []string{"", DisseminationBroadcast, DisseminationGossip,
+		DisseminationHypercube, DisseminationTree} {
+
+		d, err := NewDisseminator(name, 3)
+		require.NoError(t, err)
+		if name != "" {
+			require.Equal(t, name, d.Name())
+		}
+	}
+	_, err := NewDisseminator("flood", 3)
+	require.Error(t, err)
+
+	require.Equal(t, Broadcast{}, GetDisseminator("/v1/_n2n/test/unknown"))
+	SetDisseminator("/v1/_n2n/test/tree", NewTree(1))
+	require.Equal(t, Tree{Fanout: 2}, GetDisseminator("/v1/_n2n/test/tree"))
+}
+
+func TestSimulateDissemination_Structured(t *testing.T) {
+	for _, d := range []Disseminator{Broadcast{}, Hypercube{}, NewTree(2), NewTree(3)} {
+		for n := 1; n <= 20; n++ {
+			var nodes = newDisseminationNodes(n)
+			for _, origin := range nodes {
+				var sim = SimulateDissemination(d, nodes, origin, nil)
+				require.Len(t, sim.Received, n, "%s %d", d.Name(), n)
+				// every node receives the message exactly once
+				require.Equal(t, n-1, sim.Messages, "%s %d", d.Name(), n)
+				require.Zero(t, sim.Duplicates)
+				for _, hops := range sim.Hops {
+					require.True(t, hops <= sim.MaxRelayLength+1, "%s %d", d.Name(), n)
+				}
+			}
+		}
+	}
+
+	var (
+		nodes  = newDisseminationNodes(16)
+		origin = nodes[5]
+	)
+	require.Equal(t, 15, SimulateDissemination(Broadcast{}, nodes, origin, nil).Sent[origin.GetKey()])
+	require.Equal(t, 4, SimulateDissemination(Hypercube{}, nodes, origin, nil).Sent[origin.GetKey()])
+	require.Equal(t, 2, SimulateDissemination(NewTree(2), nodes, origin, nil).Sent[origin.GetKey()])
+}
+
+func TestSimulateDissemination_Gossip(t *testing.T) {
+	var (
+		nodes  = newDisseminationNodes(32)
+		origin = nodes[0]
+		g      = NewGossip(4)
+		sim    = SimulateDissemination(g, nodes, origin, nil)
+	)
+	require.EqualValues(t, 3, sim.MaxRelayLength)
+	require.Equal(t, 4, sim.Sent[origin.GetKey()])
+	for key, sent := range sim.Sent {
+		require.True(t, sent <= g.Fanout, key)
+	}
+	for _, hops := range sim.Hops {
+		require.True(t, hops <= sim.MaxRelayLength+1)
+	}
+	// duplicates are not relayed
+	require.Equal(t, sim.Messages, len(sim.Received)-1+sim.Duplicates)
+}
+
+func TestSimulateDissemination_Delays(t *testing.T) {
+	var (
+		nodes  = newDisseminationNodes(8)
+		origin = nodes[0]
+		delay  = func(from, to *Node) time.Duration {
+			return 10 * time.Millisecond
+		}
+	)
+	var sim = SimulateDissemination(Hypercube{}, nodes, origin, delay)
+	for key, received := range sim.Received {
+		require.Equal(t, time.Duration(sim.Hops[key])*10*time.Millisecond, received)
+	}
+	require.Equal(t, 30*time.Millisecond, sim.Latency)
+	require.Equal(t, 10*time.Millisecond,
+		SimulateDissemination(Broadcast{}, nodes, origin, delay).Latency)
+}
+
+func TestMessageCache(t *testing.T) {
+	var mc = NewMessageCache(2)
+	require.True(t, mc.AcceptMessage("block", "a"))
+	require.False(t, mc.AcceptMessage("block", "a"))
+	require.True(t, mc.AcceptMessage("block_notarization", "a"))
+	require.True(t, mc.AcceptMessage("block", "b"))
+	// evicted
+	require.True(t, mc.AcceptMessage("block", "a"))
+
+	// the relayed messages are told apart by the initial node and data
+	require.True(t, mc.AcceptRelayed("node-a", "/v1/_m2m/round/vrf_share", "hash"))
+	require.False(t, mc.AcceptRelayed("node-a", "/v1/_m2m/round/vrf_share", "hash"))
+	require.True(t, mc.AcceptRelayed("node-b", "/v1/_m2m/round/vrf_share", "hash"))
+	require.True(t, mc.AcceptRelayed("node-a", "/v1/_m2m/round/vrf_share", "other"))
+}
+
+// newTestRelayNode returns a registered node, its id is the public key hash
+func newTestRelayNode(t *testing.T) (*Node, encryption.SignatureScheme) {
+	var nd = Provider()
+	nd.Type = NodeTypeMiner
+	nd.Status = NodeStatusActive
+	var scheme = encryption.NewED25519Scheme()
+	require.NoError(t, scheme.GenerateKeys())
+	nd.SetSignatureScheme(scheme)
+	RegisterNode(nd)
+	return nd, scheme
+}
+
+// newTestShare returns an entity with the same id and given content
+func newTestShare(share string) *client.Client {
+	var entity = client.NewClient()
+	entity.PublicKey = "aa"
+	entity.ID = encryption.Hash([]byte{0xaa})
+	entity.Version = share
+	return entity
+}
+
+func TestToN2NReceiveEntityHandler_Relayed(t *testing.T) {
+	common.SetupRootContext(context.Background())
+	client.SetupEntity(memorystore.GetStorageProvider())
+
+	const uri = "/v1/_n2n/test/relayed_share"
+	var (
+		self     = Self
+		received = make(chan string, 10)
+		handler  = func(ctx context.Context, entity datastore.Entity) (interface{}, error) {
+			received <- GetSender(ctx).GetKey() + ":" + entity.(*client.Client).Version
+			return nil, nil
+		}
+		server = httptest.NewServer(http.HandlerFunc(ToN2NReceiveEntityHandler(handler, nil)))
+	)
+	defer server.Close()
+	defer func() { Self = self }()
+	SetDisseminator(uri, NewGossip(2))
+
+	addr, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(addr.Port())
+	require.NoError(t, err)
+
+	var (
+		receiver, _          = newTestRelayNode(t)
+		first, firstScheme   = newTestRelayNode(t)
+		second, secondScheme = newTestRelayNode(t)
+		schemes              = map[*Node]encryption.SignatureScheme{
+			first:  firstScheme,
+			second: secondScheme,
+		}
+	)
+	receiver.N2NHost, receiver.Port = addr.Hostname(), port
+
+	// share of the same round, thus of the same entity id, of a node
+	var send = func(sender *Node, share string) {
+		Self = &SelfNode{Node: sender}
+		Self.SetSignatureScheme(schemes[sender])
+		var np = NewPool(NodeTypeMiner)
+		np.AddNode(sender)
+		np.AddNode(receiver)
+		np.SendAll(context.Background(),
+			SendEntityHandler(uri, &SendOptions{CODEC: CODEC_JSON})(newTestShare(share)))
+	}
+	var expect = func(message string) {
+		select {
+		case got := <-received:
+			require.Equal(t, message, got)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("%s is not received", message)
+		}
+	}
+
+	send(first, "share-1")
+	expect(first.GetKey() + ":share-1")
+	send(second, "share-2")
+	expect(second.GetKey() + ":share-2")
+
+	// the same message is received once
+	send(first, "share-1")
+
+	// the data doesn't match the signature of the initial node
+	Self = &SelfNode{Node: second}
+	Self.SetSignatureScheme(secondScheme)
+	var entity = newTestShare("share-3")
+	signature, err := Self.Sign(relayHash(first.GetKey(), uri, encryption.Hash("share-3")))
+	require.NoError(t, err)
+	SendEntityHandler(uri, &SendOptions{
+		CODEC:            CODEC_JSON,
+		InitialNodeID:    first.GetKey(),
+		MaxRelayLength:   1,
+		PayloadHash:      encryption.Hash("share-3"),
+		InitialSignature: signature,
+	})(entity)(context.Background(), receiver)
+
+	select {
+	case got := <-received:
+		t.Fatalf("unexpected message %s", got)
+	case <-time.After(500 * time.Millisecond):
+	}
+}
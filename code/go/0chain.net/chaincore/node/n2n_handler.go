@@ -52,6 +52,7 @@ var pullDataRequestor EntityRequestor
 func SetupN2NHandlers() {
 	http.HandleFunc("/v1/_n2n/entity/post", common.N2NRateLimit(ToN2NReceiveEntityHandler(datastore.PrintEntityHandler, nil)))
 	http.HandleFunc(pullURL, common.N2NRateLimit(ToN2NSendEntityHandler(PushToPullHandler)))
+	http.HandleFunc(streamURL, StreamHandler)
 	options := &SendOptions{Timeout: TimeoutLargeMessage, CODEC: CODEC_MSGPACK, Compress: true}
 	pullDataRequestor = RequestEntityHandler(pullURL, options, nil)
 }
@@ -68,6 +69,8 @@ var (
 	HeaderRequestToPull         = "X-Request-To-Pull"
 
 	HeaderInitialNodeID        = "X-Initial-Node-Id"
+	HeaderInitialSignature     = "X-Initial-Node-Signature"
+	HeaderPayloadHash          = "X-Payload-Hash"
 	HeaderNodeID               = "X-Node-Id"
 	HeaderNodeRequestSignature = "X-Node-Request-Signature"
 )
@@ -94,6 +97,10 @@ type SendOptions struct {
 	InitialNodeID      string
 	CODEC              int
 	Pull               bool
+	// PayloadHash is hash of the data of a disseminated message, signed by
+	// the initial node with the InitialSignature
+	PayloadHash      string
+	InitialSignature string
 }
 
 /*MessageFilterI - tells wether the given message should be processed or not
@@ -106,6 +113,8 @@ type MessageFilterI interface {
 /*ReceiveOptions - options to tune how the messages are received within the network */
 type ReceiveOptions struct {
 	MessageFilter MessageFilterI
+	// RelayPool is the pool the disseminated messages are relayed to
+	RelayPool func() *Pool
 }
 
 var httpClient *http.Client
@@ -126,6 +135,7 @@ func init() {
 		TLSHandshakeTimeout:   10 * time.Second,
 		ExpectContinueTimeout: 1 * time.Second,
 		MaxIdleConnsPerHost:   5,
+		DialTLSContext:        dialTLS,
 	}
 	httpClient = &http.Client{Transport: transport}
 
@@ -197,7 +197,7 @@ func RequestEntityHandler(uri string, options *SendOptions, entityMetadata datas
 			if options.Timeout > 0 {
 				timeout = options.Timeout
 			}
-			u := provider.GetN2NURLBase() + uri
+			u := provider.n2nURLBase() + uri
 			var data io.Reader
 			if params != nil {
 				data = strings.NewReader(params.Encode())
@@ -240,7 +240,7 @@ func RequestEntityHandler(uri string, options *SendOptions, entityMetadata datas
 
 				var cctx context.Context
 				tm = time.NewTimer(timeout)
-				cctx, cancel = context.WithCancel(ctx)
+				cctx, cancel = context.WithCancel(withTLSPeer(ctx, provider))
 				go func() {
 					select {
 					case <-tm.C:
@@ -361,6 +361,9 @@ func RequestEntityHandler(uri string, options *SendOptions, entityMetadata datas
 }
 
 func validateRequest(sender *Node, r *http.Request) bool {
+	if !validateTLSPeer(sender, r) {
+		return false
+	}
 	if !validateChain(sender, r) {
 		return false
 	}
@@ -31,7 +31,8 @@ func SetMaxConcurrentRequests(maxConcurrentRequests int) {
 	MaxConcurrentRequests = maxConcurrentRequests
 }
 
-/*SendAll - send to every node */
+/*SendAll - send to every node, directly or relayed by the other nodes depending on the
+* dissemination strategy of the message */
 func (np *Pool) SendAll(ctx context.Context, handler SendHandler) []*Node {
 	ts := time.Now()
 	defer func() {
@@ -42,7 +43,7 @@ func (np *Pool) SendAll(ctx context.Context, handler SendHandler) []*Node {
 		}
 	}()
 
-	return np.SendAtleast(ctx, np.Size(), handler)
+	return np.SendAtleast(withSendPool(ctx, np), np.Size(), handler)
 }
 
 /*SendTo - send to a specific node */
@@ -211,127 +212,164 @@ func prepareSenderSign(entity datastore.Entity, num int) ([]*senderSignInfo, err
 
 /*SendEntityHandler provides a client API to send an entity */
 func SendEntityHandler(uri string, options *SendOptions) EntitySendHandler {
+	setURISendOptions(uri, options)
+	return sendEntityHandler(uri, options)
+}
+
+func sendEntityHandler(uri string, options *SendOptions) EntitySendHandler {
+	return func(entity datastore.Entity) SendHandler {
+		return sendEntityData(uri, options, entity, getResponseData(options, entity).Bytes())
+	}
+}
+
+// sendEntityData returns handler sending the entity encoded as the data
+func sendEntityData(uri string, options *SendOptions, entity datastore.Entity, data []byte) SendHandler {
 	timeout := 500 * time.Millisecond
 	if options.Timeout > 0 {
 		timeout = options.Timeout
 	}
-	return func(entity datastore.Entity) SendHandler {
-		data := getResponseData(options, entity).Bytes()
-
-		toPull := options.Pull
-		if len(data) > LargeMessageThreshold || toPull {
-			toPull = true
-			key := p2pKey(uri, entity.GetKey())
-			pdce := &pushDataCacheEntry{Options: *options, Data: data, EntityName: entity.GetEntityMetadata().GetName()}
-			pushDataCache.Add(key, pdce)
+	toPull := options.Pull
+	if len(data) > LargeMessageThreshold || toPull {
+		toPull = true
+		key := p2pKey(uri, entity.GetKey())
+		pdce := &pushDataCacheEntry{Options: *options, Data: data, EntityName: entity.GetEntityMetadata().GetName()}
+		pushDataCache.Add(key, pdce)
+	}
+
+	preparedSignatures, err := prepareSenderSign(entity, 5)
+	if err != nil {
+		logging.N2n.Panic("failed to prepare sender signature", zap.Error(err))
+	}
+
+	setSignHeader := func(r *http.Request) {
+		for _, ssi := range preparedSignatures {
+			if common.Within(int64(ssi.Ts), int64(time.Second)) {
+				r.Header.Set(HeaderRequestTimeStamp, ssi.TsStr)
+				r.Header.Set(HeaderRequestHash, ssi.Hash)
+				r.Header.Set(HeaderNodeRequestSignature, ssi.Signature)
+				return
+			}
 		}
 
-		preparedSignatures, err := prepareSenderSign(entity, 5)
+		// there's no prepared signature within valid time range.
+		// generate a new one
+		ssis, err := prepareSenderSign(entity, 1)
 		if err != nil {
 			logging.N2n.Panic("failed to prepare sender signature", zap.Error(err))
 		}
 
-		setSignHeader := func(r *http.Request) {
-			for _, ssi := range preparedSignatures {
-				if common.Within(int64(ssi.Ts), int64(time.Second)) {
-					r.Header.Set(HeaderRequestTimeStamp, ssi.TsStr)
-					r.Header.Set(HeaderRequestHash, ssi.Hash)
-					r.Header.Set(HeaderNodeRequestSignature, ssi.Signature)
-					return
-				}
-			}
+		r.Header.Set(HeaderRequestTimeStamp, ssis[0].TsStr)
+		r.Header.Set(HeaderRequestHash, ssis[0].Hash)
+		r.Header.Set(HeaderNodeRequestSignature, ssis[0].Signature)
+	}
 
-			// there's no prepared signature within valid time range.
-			// generate a new one
-			ssis, err := prepareSenderSign(entity, 1)
-			if err != nil {
-				logging.N2n.Panic("failed to prepare sender signature", zap.Error(err))
-			}
+	dissemination := newEntityDissemination(uri, options, data)
 
-			r.Header.Set(HeaderRequestTimeStamp, ssis[0].TsStr)
-			r.Header.Set(HeaderRequestHash, ssis[0].Hash)
-			r.Header.Set(HeaderNodeRequestSignature, ssis[0].Signature)
+	return func(ctx context.Context, receiver *Node) bool {
+		sendOptions, ok := dissemination.sendOptions(ctx, receiver)
+		if !ok {
+			return false
 		}
+		// the disseminated data is pushed to be checked against the
+		// signature of the initial node
+		toPull := toPull && sendOptions.InitialNodeID == ""
+		timer := receiver.GetTimer(uri)
+		addr := receiver.n2nURLBase() + uri
+		var buffer *bytes.Buffer
+		push := !toPull || shouldPush(options, receiver, uri, entity, timer)
+		if push {
+			buffer = bytes.NewBuffer(data)
+		} else {
+			buffer = bytes.NewBuffer(nil)
+		}
+		req, err := http.NewRequest("POST", addr, buffer)
+		if err != nil {
+			return false
+		}
+		defer req.Body.Close()
 
-		return func(ctx context.Context, receiver *Node) bool {
-			timer := receiver.GetTimer(uri)
-			addr := receiver.GetN2NURLBase() + uri
-			var buffer *bytes.Buffer
-			push := !toPull || shouldPush(options, receiver, uri, entity, timer)
+		if options.Compress {
+			req.Header.Set("Content-Encoding", compDecomp.Encoding())
+		}
+
+		if toPull {
+			req.Header.Set(HeaderRequestToPull, "true")
+		}
+
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		SetSendHeaders(req, entity, sendOptions)
+
+		setSignHeader(req)
+
+		if ps := getPeerStream(receiver); ps != nil {
+			var body []byte
 			if push {
-				buffer = bytes.NewBuffer(data)
-			} else {
-				buffer = bytes.NewBuffer(nil)
+				body = data
 			}
-			req, err := http.NewRequest("POST", addr, buffer)
-			if err != nil {
-				return false
+			Self.Underlying().InduceDelay(receiver)
+			var frame = newStreamFrame(uri, req, body)
+			frame.timeout = timeout
+			if ps.send(frame) {
+				logging.N2n.Info("sending over stream", zap.Int("to", receiver.SetIndex),
+					zap.String("handler", uri), zap.String("entity", entity.GetEntityMetadata().GetName()),
+					zap.Any("id", entity.GetKey()))
+				if push {
+					receiver.GetSizeMetric(uri).Update(int64(len(data)))
+				}
+				return true
 			}
-			defer req.Body.Close()
+		}
 
-			if options.Compress {
-				req.Header.Set("Content-Encoding", compDecomp.Encoding())
-			}
+		// Keep the number of messages to a node bounded
+		var (
+			selfNode *Node
+			resp     *http.Response
+			ts       = time.Now()
+		)
 
-			if toPull {
-				req.Header.Set(HeaderRequestToPull, "true")
-			}
+		func() {
+			receiver.Grab()
+			defer receiver.Release()
 
-			req.Header.Set("Content-Type", "application/json; charset=utf-8")
-			SetSendHeaders(req, entity, options)
-
-			setSignHeader(req)
-			// Keep the number of messages to a node bounded
-			var (
-				selfNode *Node
-				resp     *http.Response
-				ts       = time.Now()
-			)
-
-			func() {
-				receiver.Grab()
-				defer receiver.Release()
-
-				selfNode = Self.Underlying()
-				selfNode.SetLastActiveTime(ts)
-				selfNode.InduceDelay(receiver)
-
-				cctx, cancel := context.WithTimeout(ctx, timeout)
-				defer cancel()
-				req = req.WithContext(cctx)
-				//req = req.WithContext(httptrace.WithClientTrace(req.Context(), n2nTrace))
-				resp, err = httpClient.Do(req)
-			}()
-
-			logging.N2n.Info("sending", zap.Int("from", selfNode.SetIndex), zap.Int("to", receiver.SetIndex), zap.String("handler", uri), zap.Duration("duration", time.Since(ts)), zap.String("entity", entity.GetEntityMetadata().GetName()), zap.Any("id", entity.GetKey()))
-			switch err {
-			case nil:
-			default:
-				ue, ok := err.(*url.Error)
-				if ok && ue.Unwrap() != context.Canceled {
-					receiver.AddSendErrors(1)
-					receiver.AddErrorCount(1)
-					logging.N2n.Error("sending", zap.Int("from", selfNode.SetIndex), zap.Int("to", receiver.SetIndex), zap.String("handler", uri), zap.Duration("duration", time.Since(ts)), zap.String("entity", entity.GetEntityMetadata().GetName()), zap.Any("id", entity.GetKey()), zap.Error(err))
-				}
-				return false
-			}
+			selfNode = Self.Underlying()
+			selfNode.SetLastActiveTime(ts)
+			selfNode.InduceDelay(receiver)
 
-			receiver.SetStatus(NodeStatusActive)
-			receiver.SetLastActiveTime(time.Now())
-			receiver.SetErrorCount(receiver.GetSendErrors())
+			cctx, cancel := context.WithTimeout(withTLSPeer(ctx, receiver), timeout)
+			defer cancel()
+			req = req.WithContext(cctx)
+			//req = req.WithContext(httptrace.WithClientTrace(req.Context(), n2nTrace))
+			resp, err = httpClient.Do(req)
+		}()
 
-			readAndClose(resp.Body)
-			if push {
-				timer.UpdateSince(ts)
-				sizer := receiver.GetSizeMetric(uri)
-				sizer.Update(int64(len(data)))
-			}
-			if !(resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent) {
-				logging.N2n.Error("sending", zap.Int("from", selfNode.SetIndex), zap.Int("to", receiver.SetIndex), zap.String("handler", uri), zap.Duration("duration", time.Since(ts)), zap.String("entity", entity.GetEntityMetadata().GetName()), zap.Any("id", entity.GetKey()), zap.Any("status_code", resp.StatusCode))
-				return false
+		logging.N2n.Info("sending", zap.Int("from", selfNode.SetIndex), zap.Int("to", receiver.SetIndex), zap.String("handler", uri), zap.Duration("duration", time.Since(ts)), zap.String("entity", entity.GetEntityMetadata().GetName()), zap.Any("id", entity.GetKey()))
+		switch err {
+		case nil:
+		default:
+			ue, ok := err.(*url.Error)
+			if ok && ue.Unwrap() != context.Canceled {
+				receiver.AddSendErrors(1)
+				receiver.AddErrorCount(1)
+				logging.N2n.Error("sending", zap.Int("from", selfNode.SetIndex), zap.Int("to", receiver.SetIndex), zap.String("handler", uri), zap.Duration("duration", time.Since(ts)), zap.String("entity", entity.GetEntityMetadata().GetName()), zap.Any("id", entity.GetKey()), zap.Error(err))
 			}
-			return true
+			return false
 		}
+
+		receiver.SetStatus(NodeStatusActive)
+		receiver.SetLastActiveTime(time.Now())
+		receiver.SetErrorCount(receiver.GetSendErrors())
+
+		readAndClose(resp.Body)
+		if push {
+			timer.UpdateSince(ts)
+			sizer := receiver.GetSizeMetric(uri)
+			sizer.Update(int64(len(data)))
+		}
+		if !(resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusNoContent) {
+			logging.N2n.Error("sending", zap.Int("from", selfNode.SetIndex), zap.Int("to", receiver.SetIndex), zap.String("handler", uri), zap.Duration("duration", time.Since(ts)), zap.String("entity", entity.GetEntityMetadata().GetName()), zap.Any("id", entity.GetKey()), zap.Any("status_code", resp.StatusCode))
+			return false
+		}
+		return true
 	}
 }
 
@@ -340,6 +378,8 @@ func SetSendHeaders(req *http.Request, entity datastore.Entity, options *SendOpt
 	SetHeaders(req)
 	if options.InitialNodeID != "" {
 		req.Header.Set(HeaderInitialNodeID, options.InitialNodeID)
+		req.Header.Set(HeaderPayloadHash, options.PayloadHash)
+		req.Header.Set(HeaderInitialSignature, options.InitialSignature)
 	}
 	req.Header.Set(HeaderRequestEntityName, entity.GetEntityMetadata().GetName())
 	req.Header.Set(HeaderRequestEntityID, datastore.ToString(entity.GetKey()))
@@ -356,10 +396,21 @@ func SetSendHeaders(req *http.Request, entity datastore.Entity, options *SendOpt
 	return true
 }
 
+// getRelayLength returns the relay length and the max relay length of the
+// request, zeros for the messages not relayed.
+func getRelayLength(r *http.Request) (relayLength, maxRelayLength int64) {
+	relayLength, _ = strconv.ParseInt(r.Header.Get(HeaderRequestRelayLength), 10, 64)
+	maxRelayLength, _ = strconv.ParseInt(r.Header.Get(HeaderRequestMaxRelayLength), 10, 64)
+	return
+}
+
 func validateSendRequest(sender *Node, r *http.Request) bool {
 	entityName := r.Header.Get(HeaderRequestEntityName)
 	entityID := r.Header.Get(HeaderRequestEntityID)
 	selfSetIndex := Self.Underlying().SetIndex
+	if !validateTLSPeer(sender, r) {
+		return false
+	}
 	if !validateChain(sender, r) {
 		logging.N2n.Error("message received - invalid chain", zap.Int("from", sender.SetIndex),
 			zap.Int("to", selfSetIndex), zap.String("handler", r.RequestURI), zap.String("entity", entityName))
@@ -481,19 +532,51 @@ func ToN2NReceiveEntityHandler(handler datastore.JSONEntityReqResponderF, option
 				}
 			}
 			ctx := context.Background()
+			data := buf.Bytes()
 			initialNodeID := r.Header.Get(HeaderInitialNodeID)
+			received := &SendOptions{InitialNodeID: initialNodeID}
 			if initialNodeID != "" {
 				initSender := GetNode(initialNodeID)
 				if initSender == nil {
 					return
 				}
+				// the data is signed by the initial node, the relaying
+				// nodes are not trusted
+				if !validateRelayedRequest(initSender, r, data) {
+					logging.N2n.Error("message received - invalid initial node signature",
+						zap.String("from", nodeID), zap.String("initial", initialNodeID),
+						zap.String("handler", r.RequestURI), zap.String("entity", entityName))
+					return
+				}
+				received.PayloadHash = r.Header.Get(HeaderPayloadHash)
+				received.InitialSignature = r.Header.Get(HeaderInitialSignature)
+				if !relayedMessages.AcceptRelayed(initialNodeID, r.URL.Path, received.PayloadHash) {
+					// already received from another relaying node
+					return
+				}
 				ctx = WithNode(ctx, initSender)
 			} else {
 				ctx = WithNode(ctx, sender)
 			}
+			received.CurrentRelayLength, received.MaxRelayLength = getRelayLength(r)
+
+			relay := func(entity datastore.Entity) {
+				if received.CurrentRelayLength >= received.MaxRelayLength ||
+					initialNodeID == "" || options == nil || options.RelayPool == nil {
+					return
+				}
+				if np := options.RelayPool(); np != nil {
+					go relayEntity(context.Background(), np, r.URL.Path, received,
+						entity, data)
+				}
+			}
 
 			if r.Header.Get(HeaderRequestToPull) == "true" {
-				go pullEntityHandler(ctx, sender, r.RequestURI, handler, entityName, entityID)
+				phandler := func(pctx context.Context, entity datastore.Entity) (interface{}, error) {
+					relay(entity)
+					return handler(pctx, entity)
+				}
+				go pullEntityHandler(ctx, sender, r.RequestURI, phandler, entityName, entityID)
 				sender.AddReceived(1)
 				return
 			}
@@ -511,6 +594,7 @@ func ToN2NReceiveEntityHandler(handler datastore.JSONEntityReqResponderF, option
 					zap.String("entity.id", entity.GetKey()))
 				return
 			}
+			relay(entity)
 			start := time.Now()
 			_, err = handler(ctx, entity)
 			duration := time.Since(start)
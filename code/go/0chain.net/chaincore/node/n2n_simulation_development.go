@@ -24,18 +24,48 @@ func (n *Node) InduceDelay(toNode *Node) {
 }
 
 func ReadNetworkDelays(file string) {
-	delayConfig := config.ReadConfig(file)
-	delay := delayConfig.Get("delay")
+	for from, fromRoutes := range readDelayRoutes(file) {
+		if Self.Underlying().N2NHost == from {
+			for to, route := range fromRoutes {
+				routes[to] = route
+			}
+		}
+	}
+}
+
+// readDelayRoutes reads the routes of all the nodes by their N2N hosts.
+func readDelayRoutes(file string) map[string]map[string]*Route {
+	var (
+		delayConfig = config.ReadConfig(file)
+		delay       = delayConfig.Get("delay")
+		all         = make(map[string]map[string]*Route)
+	)
 	if configRoutes, ok := delay.([]interface{}); ok {
 		for _, route := range configRoutes {
 			if routeMap, ok := route.(map[interface{}]interface{}); ok {
 				from := routeMap["from"].(string)
 				to := routeMap["to"].(string)
 				delayTime := routeMap["time"].(int)
-				if Self.Underlying().N2NHost == from {
-					routes[to] = &Route{To: to, Delay: time.Duration(delayTime) * time.Millisecond}
+				if all[from] == nil {
+					all[from] = make(map[string]*Route)
 				}
+				all[from][to] = &Route{To: to, Delay: time.Duration(delayTime) * time.Millisecond}
 			}
 		}
 	}
+	return all
+}
+
+/*SimulateDisseminationWithDelays - simulate dissemination of a message using the network
+* delays configuration, the routes not configured have the given default delay */
+func SimulateDisseminationWithDelays(d Disseminator, nodes []*Node, origin *Node,
+	file string, defaultDelay time.Duration) *DisseminationSimulation {
+
+	var all = readDelayRoutes(file)
+	return SimulateDissemination(d, nodes, origin, func(from, to *Node) time.Duration {
+		if route, ok := all[from.N2NHost][to.N2NHost]; ok {
+			return route.Delay
+		}
+		return defaultDelay
+	})
 }
@@ -0,0 +1,466 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"0chain.net/core/common"
+	"0chain.net/core/encryption"
+	"0chain.net/core/logging"
+	"github.com/spf13/viper"
+	"github.com/vmihailenco/msgpack/v5"
+	"go.uber.org/zap"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+/*
+Peer streams - a long lived HTTP/2 stream per peer multiplexed over a single
+persistent connection. The stream carries the n2n requests, with the same
+headers, signatures, codecs and compression, as length prefixed frames. The
+receiving node dispatches them to the regular n2n handlers. A stream is
+rotated before it reaches the server write timeout and any message that can't
+be sent over a stream, including the ones queued to a failed stream, is sent
+over the regular n2n HTTP endpoint.
+*/
+
+const streamURL = "/v1/_n2n/stream"
+
+// the n2n endpoints a peer stream can carry messages to
+var streamURIPrefixes = []string{"/v1/_m2m/", "/v1/_m2s/", "/v1/_n2n/"}
+
+// MaxStreamFrameSize - max size of a message sent over a peer stream
+const MaxStreamFrameSize = 64 * 1024 * 1024
+
+var (
+	streamEnabled       = false
+	streamMaxAge        = 20 * time.Second
+	streamQueueSize     = 256
+	streamRetryInterval = 30 * time.Second
+)
+
+/*SetStreamConfig - enable or disable the peer streams and set the max age and the queue size of a stream */
+func SetStreamConfig(enabled bool, maxAge time.Duration, queueSize int) {
+	streamEnabled = enabled
+	if maxAge > 0 {
+		streamMaxAge = maxAge
+	}
+	if queueSize > 0 {
+		streamQueueSize = queueSize
+	}
+}
+
+func readStreamConfig() {
+	SetStreamConfig(viper.GetBool("network.stream.enabled"),
+		viper.GetDuration("network.stream.max_age")*time.Second,
+		viper.GetInt("network.stream.queue_size"))
+}
+
+// HTTP/2 over plain TCP (prior knowledge) or over mutual TLS, a single
+// connection per peer. The transport doesn't pass the receiver to the dial,
+// the server certificate is checked against the receiver on the stream open.
+var streamClient = &http.Client{
+	Transport: &http2.Transport{
+		AllowHTTP:       true,
+		TLSClientConfig: clientTLSConfig(""),
+		DialTLS: func(network, addr string, cfg *tls.Config) (net.Conn, error) {
+			var dialer = &net.Dialer{Timeout: 30 * time.Second}
+			if tlsEnabled {
+				return tls.DialWithDialer(dialer, network, addr, cfg)
+			}
+			return dialer.Dial(network, addr)
+		},
+		ReadIdleTimeout: 30 * time.Second,
+		PingTimeout:     15 * time.Second,
+	},
+}
+
+type streamFrame struct {
+	URI    string            `msgpack:"u"`
+	Header map[string]string `msgpack:"h"`
+	Body   []byte            `msgpack:"b,omitempty"`
+
+	// timeout of the message sent over the n2n HTTP endpoint instead
+	timeout time.Duration
+}
+
+func newStreamFrame(uri string, req *http.Request, body []byte) *streamFrame {
+	var frame = &streamFrame{
+		URI:    uri,
+		Header: make(map[string]string, len(req.Header)),
+		Body:   body,
+	}
+	for key := range req.Header {
+		frame.Header[key] = req.Header.Get(key)
+	}
+	return frame
+}
+
+// request returns the n2n request carried by the frame.
+func (f *streamFrame) request(remoteAddr string) (*http.Request, error) {
+	req, err := http.NewRequest(http.MethodPost, f.URI, bytes.NewReader(f.Body))
+	if err != nil {
+		return nil, err
+	}
+	for key, value := range f.Header {
+		req.Header.Set(key, value)
+	}
+	req.RequestURI = f.URI
+	req.RemoteAddr = remoteAddr
+	return req, nil
+}
+
+// isStreamURI checks the frame is sent to an n2n endpoint, but the stream one.
+func isStreamURI(uri string) bool {
+	u, err := url.Parse(uri)
+	if err != nil || u.Path != uri || path.Clean(uri) != uri || uri == streamURL {
+		return false
+	}
+	for _, prefix := range streamURIPrefixes {
+		if strings.HasPrefix(uri, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// send sends the message of the frame over the n2n HTTP endpoint of the receiver.
+func (f *streamFrame) send(receiver *Node) error {
+	req, err := http.NewRequest(http.MethodPost, receiver.n2nURLBase()+f.URI,
+		bytes.NewReader(f.Body))
+	if err != nil {
+		return err
+	}
+	for key, value := range f.Header {
+		req.Header.Set(key, value)
+	}
+	var timeout = f.timeout
+	if timeout <= 0 {
+		timeout = 500 * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(withTLSPeer(context.Background(), receiver), timeout)
+	defer cancel()
+	resp, err := httpClient.Do(req.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	readAndClose(resp.Body)
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return common.NewErrorf("stream_fallback", "unexpected status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func writeStreamFrame(w io.Writer, f *streamFrame) error {
+	data, err := msgpack.Marshal(f)
+	if err != nil {
+		return err
+	}
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], uint32(len(data)))
+	if _, err = w.Write(size[:]); err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func readStreamFrame(r io.Reader) (*streamFrame, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+	var n = binary.BigEndian.Uint32(size[:])
+	if n > MaxStreamFrameSize {
+		return nil, common.NewErrorf("stream_frame_too_large",
+			"stream frame size %d exceeds %d", n, MaxStreamFrameSize)
+	}
+	var data = make([]byte, n)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	var f = new(streamFrame)
+	if err := msgpack.Unmarshal(data, f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+type peerStream struct {
+	receiver *Node
+	frames   chan *streamFrame
+
+	mutex  sync.Mutex
+	open   bool
+	closed bool
+}
+
+var (
+	streamsMutex sync.Mutex
+	streams      = make(map[string]*peerStream)
+	streamRetry  = make(map[string]time.Time)
+)
+
+// getPeerStream returns the open stream to the receiver, if any, opening
+// one in background otherwise.
+func getPeerStream(receiver *Node) *peerStream {
+	if !streamEnabled {
+		return nil
+	}
+	streamsMutex.Lock()
+	defer streamsMutex.Unlock()
+
+	var id = receiver.GetKey()
+	if ps, ok := streams[id]; ok {
+		return ps
+	}
+	if retry, ok := streamRetry[id]; ok && time.Now().Before(retry) {
+		return nil
+	}
+	var ps = &peerStream{
+		receiver: receiver,
+		frames:   make(chan *streamFrame, streamQueueSize),
+	}
+	streams[id] = ps
+	go ps.run()
+	return nil
+}
+
+func removePeerStream(ps *peerStream, failed bool) {
+	streamsMutex.Lock()
+	defer streamsMutex.Unlock()
+	var id = ps.receiver.GetKey()
+	if streams[id] == ps {
+		delete(streams, id)
+	}
+	if failed {
+		streamRetry[id] = time.Now().Add(streamRetryInterval)
+	} else {
+		delete(streamRetry, id)
+	}
+}
+
+// send queues the frame, false if the stream can't take it.
+func (ps *peerStream) send(f *streamFrame) bool {
+	ps.mutex.Lock()
+	defer ps.mutex.Unlock()
+	if !ps.open || ps.closed {
+		return false
+	}
+	select {
+	case ps.frames <- f:
+		return true
+	default:
+		return false
+	}
+}
+
+func (ps *peerStream) run() {
+	var (
+		pr, pw = io.Pipe()
+		addr   = ps.receiver.n2nURLBase() + streamURL
+	)
+	defer pr.Close()
+
+	req, err := http.NewRequest(http.MethodPost, addr, pr)
+	if err != nil {
+		removePeerStream(ps, true)
+		return
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if err = setStreamSignHeaders(req, ps.receiver); err != nil {
+		removePeerStream(ps, true)
+		return
+	}
+
+	resp, err := streamClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		// the peer doesn't support the streams, use the n2n HTTP endpoints
+		logging.N2n.Info("peer stream - open failed", zap.String("to", ps.receiver.GetKey()),
+			zap.Any("response", resp), zap.Error(err))
+		if resp != nil {
+			readAndClose(resp.Body)
+		}
+		removePeerStream(ps, true)
+		pw.Close()
+		return
+	}
+	defer readAndClose(resp.Body)
+	if !validateTLSServer(ps.receiver, resp) {
+		removePeerStream(ps, true)
+		pw.Close()
+		return
+	}
+
+	ps.mutex.Lock()
+	ps.open = true
+	ps.mutex.Unlock()
+
+	var (
+		expire = time.NewTimer(streamMaxAge)
+		failed *streamFrame
+	)
+	defer expire.Stop()
+
+loop:
+	for {
+		select {
+		case f := <-ps.frames:
+			if err = writeStreamFrame(pw, f); err != nil {
+				failed = f
+				break loop
+			}
+		case <-expire.C:
+			break loop
+		}
+	}
+
+	// the new messages go to the next stream, the queued ones are sent
+	ps.mutex.Lock()
+	ps.closed = true
+	ps.mutex.Unlock()
+	removePeerStream(ps, failed != nil)
+
+	for failed == nil && len(ps.frames) > 0 {
+		var f = <-ps.frames
+		if err = writeStreamFrame(pw, f); err != nil {
+			failed = f
+		}
+	}
+	if failed != nil {
+		logging.N2n.Error("peer stream - send", zap.String("to", ps.receiver.GetKey()),
+			zap.Error(err))
+		pw.CloseWithError(err)
+		ps.fallback(failed)
+		return
+	}
+	pw.Close()
+}
+
+// fallback sends the failed frame and the frames queued to the closed stream
+// over the n2n HTTP endpoint.
+func (ps *peerStream) fallback(failed *streamFrame) {
+	var frames = append(make([]*streamFrame, 0, len(ps.frames)+1), failed)
+	for len(ps.frames) > 0 {
+		frames = append(frames, <-ps.frames)
+	}
+	for _, f := range frames {
+		if err := f.send(ps.receiver); err != nil {
+			logging.N2n.Error("peer stream - fallback", zap.String("to", ps.receiver.GetKey()),
+				zap.String("handler", f.URI), zap.Error(err))
+		}
+	}
+}
+
+// setStreamSignHeaders signs the stream open request, binding the stream
+// to the receiver.
+func setStreamSignHeaders(req *http.Request, receiver *Node) error {
+	SetHeaders(req)
+	var (
+		ts   = common.Now()
+		hash = encryption.Hash(getHashData(Self.Underlying().GetKey(), ts, receiver.GetKey()))
+	)
+	signature, err := Self.Sign(hash)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(HeaderRequestTimeStamp, strconv.FormatInt(int64(ts), 10))
+	req.Header.Set(HeaderRequestHash, hash)
+	req.Header.Set(HeaderNodeRequestSignature, signature)
+	return nil
+}
+
+func validateStreamRequest(sender *Node, r *http.Request) bool {
+	if !validateTLSPeer(sender, r) || !validateChain(sender, r) {
+		return false
+	}
+	ts, err := strconv.ParseInt(r.Header.Get(HeaderRequestTimeStamp), 10, 64)
+	if err != nil || !common.Within(ts, int64(N2NTimeTolerance*time.Second)) {
+		return false
+	}
+	var hash = r.Header.Get(HeaderRequestHash)
+	if hash != encryption.Hash(getHashData(sender.GetKey(), common.Timestamp(ts),
+		Self.Underlying().GetKey())) {
+		return false
+	}
+	ok, _ := sender.Verify(r.Header.Get(HeaderNodeRequestSignature), hash)
+	return ok
+}
+
+type streamResponseWriter struct {
+	header http.Header
+}
+
+func (w *streamResponseWriter) Header() http.Header {
+	if w.header == nil {
+		w.header = make(http.Header)
+	}
+	return w.header
+}
+
+func (w *streamResponseWriter) Write(data []byte) (int, error) {
+	return len(data), nil
+}
+
+func (w *streamResponseWriter) WriteHeader(int) {
+}
+
+/*StreamServerHandler - wraps the server handler to accept the peer streams (HTTP/2 without TLS) along with the HTTP/1 requests */
+func StreamServerHandler(handler http.Handler) http.Handler {
+	return h2c.NewHandler(handler, &http2.Server{})
+}
+
+/*StreamHandler - receives the n2n messages sent over a peer stream and dispatches them to the n2n handlers */
+func StreamHandler(w http.ResponseWriter, r *http.Request) {
+	if r.ProtoMajor < 2 {
+		http.Error(w, "peer streams require HTTP/2", http.StatusHTTPVersionNotSupported)
+		return
+	}
+	var sender = GetNode(r.Header.Get(HeaderNodeID))
+	if sender == nil || !validateStreamRequest(sender, r) {
+		http.Error(w, "invalid stream request", http.StatusUnauthorized)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	for {
+		f, err := readStreamFrame(r.Body)
+		if err != nil {
+			if err != io.EOF {
+				logging.N2n.Error("peer stream - receive", zap.String("from", sender.GetKey()),
+					zap.Error(err))
+			}
+			return
+		}
+		if !isStreamURI(f.URI) {
+			logging.N2n.Error("peer stream - invalid handler",
+				zap.String("from", sender.GetKey()), zap.String("handler", f.URI))
+			continue
+		}
+		if f.Header[HeaderNodeID] != sender.GetKey() {
+			logging.N2n.Error("peer stream - message of another node",
+				zap.String("from", sender.GetKey()), zap.String("node", f.Header[HeaderNodeID]))
+			continue
+		}
+		req, err := f.request(r.RemoteAddr)
+		if err != nil {
+			continue
+		}
+		req.TLS = r.TLS
+		http.DefaultServeMux.ServeHTTP(&streamResponseWriter{}, req)
+	}
+}
@@ -0,0 +1,204 @@
+package node
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"0chain.net/chaincore/client"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/memorystore"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamFrame(t *testing.T) {
+	req, err := http.NewRequest(http.MethodPost, "http://127.0.0.1:7071/v1/_m2m/round/vrf_share", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set(HeaderNodeID, "node-id")
+	req.Header.Set(HeaderRequestRelayLength, "0")
+
+	var (
+		buf   = bytes.NewBuffer(nil)
+		frame = newStreamFrame("/v1/_m2m/round/vrf_share", req, []byte(`{"id":"vrf"}`))
+	)
+	require.NoError(t, writeStreamFrame(buf, frame))
+	require.NoError(t, writeStreamFrame(buf, newStreamFrame("/v1/_n2n/entity/post", req, nil)))
+
+	got, err := readStreamFrame(buf)
+	require.NoError(t, err)
+	require.Equal(t, frame, got)
+	got, err = readStreamFrame(buf)
+	require.NoError(t, err)
+	require.Empty(t, got.Body)
+
+	r, err := frame.request("127.0.0.1:7072")
+	require.NoError(t, err)
+	require.Equal(t, "/v1/_m2m/round/vrf_share", r.URL.Path)
+	require.Equal(t, "/v1/_m2m/round/vrf_share", r.RequestURI)
+	require.Equal(t, "127.0.0.1:7072", r.RemoteAddr)
+	require.Equal(t, "node-id", r.Header.Get(HeaderNodeID))
+	require.Equal(t, req.Header.Get("Content-Type"), r.Header.Get("Content-Type"))
+
+	var body = bytes.NewBuffer(nil)
+	_, err = body.ReadFrom(r.Body)
+	require.NoError(t, err)
+	require.Equal(t, `{"id":"vrf"}`, body.String())
+
+	// the stream is over
+	_, err = readStreamFrame(buf)
+	require.Error(t, err)
+}
+
+func TestStreamFrame_TooLarge(t *testing.T) {
+	var size [4]byte
+	binary.BigEndian.PutUint32(size[:], MaxStreamFrameSize+1)
+	_, err := readStreamFrame(bytes.NewReader(size[:]))
+	require.Error(t, err)
+}
+
+func TestGetPeerStream_Disabled(t *testing.T) {
+	SetStreamConfig(false, 0, 0)
+	var receiver = Provider()
+	receiver.ID = "stream-receiver"
+	require.Nil(t, getPeerStream(receiver))
+	require.Empty(t, streams)
+}
+
+func TestIsStreamURI(t *testing.T) {
+	for uri, ok := range map[string]bool{
+		"/v1/_m2m/round/vrf_share":        true,
+		"/v1/_m2s/block/finalized":        true,
+		"/v1/_n2n/entity/post":            true,
+		streamURL:                         false,
+		"/v1/_n2n/../sc/rest":             false,
+		"/v1/_n2n/entity/post?id=1":       false,
+		"/v1/client/put":                  false,
+		"http://node/v1/_n2n/entity/post": false,
+		"":                                false,
+	} {
+		require.Equal(t, ok, isStreamURI(uri), uri)
+	}
+}
+
+func TestPeerStream_Fallback(t *testing.T) {
+	common.SetupRootContext(context.Background())
+	client.SetupEntity(memorystore.GetStorageProvider())
+
+	// the stream handler dispatches the messages to the default mux
+	var (
+		uri      = fmt.Sprintf("/v1/_n2n/test/stream_share/%d", time.Now().UnixNano())
+		received = make(chan string, 10)
+		viaHTTP  int32
+	)
+	http.HandleFunc(uri, ToN2NReceiveEntityHandler(
+		func(ctx context.Context, entity datastore.Entity) (interface{}, error) {
+			received <- entity.(*client.Client).Version
+			return nil, nil
+		}, nil))
+
+	var mux = http.NewServeMux()
+	mux.HandleFunc(streamURL, StreamHandler)
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == uri {
+			atomic.AddInt32(&viaHTTP, 1)
+		}
+		http.DefaultServeMux.ServeHTTP(w, r)
+	})
+
+	// the connections are tracked to break the stream
+	var (
+		connsMutex sync.Mutex
+		conns      []net.Conn
+		server     = httptest.NewUnstartedServer(StreamServerHandler(mux))
+	)
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connsMutex.Lock()
+			conns = append(conns, conn)
+			connsMutex.Unlock()
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	addr, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	port, err := strconv.Atoi(addr.Port())
+	require.NoError(t, err)
+
+	// the node sends the messages to itself
+	var self = Self
+	defer func() { Self = self }()
+	nd, scheme := newTestRelayNode(t)
+	nd.N2NHost, nd.Port = addr.Hostname(), port
+	Self = &SelfNode{Node: nd}
+	Self.SetSignatureScheme(scheme)
+
+	SetStreamConfig(true, time.Minute, 16)
+	defer func() {
+		SetStreamConfig(false, 0, 0)
+		streamsMutex.Lock()
+		delete(streams, nd.GetKey())
+		delete(streamRetry, nd.GetKey())
+		streamsMutex.Unlock()
+	}()
+
+	var send = func(share string, sentViaHTTP int32) {
+		var sent = SendEntityHandler(uri, &SendOptions{CODEC: CODEC_JSON})(newTestShare(share))
+		require.True(t, sent(context.Background(), nd))
+		select {
+		case got := <-received:
+			require.Equal(t, share, got)
+		case <-time.After(3 * time.Second):
+			t.Fatalf("%s is not received", share)
+		}
+		require.Equal(t, sentViaHTTP, atomic.LoadInt32(&viaHTTP), share)
+	}
+	var stream = func() *peerStream {
+		streamsMutex.Lock()
+		defer streamsMutex.Unlock()
+		return streams[nd.GetKey()]
+	}
+
+	// sent over HTTP while the stream is being opened
+	send("share-1", 1)
+	require.Eventually(t, func() bool {
+		var ps = stream()
+		if ps == nil {
+			return false
+		}
+		ps.mutex.Lock()
+		defer ps.mutex.Unlock()
+		return ps.open
+	}, 3*time.Second, 10*time.Millisecond)
+
+	send("share-2", 1)
+
+	// the frame queued to the broken stream is sent over HTTP
+	connsMutex.Lock()
+	for _, conn := range conns {
+		conn.Close()
+	}
+	connsMutex.Unlock()
+	time.Sleep(200 * time.Millisecond)
+
+	send("share-3", 2)
+	require.Eventually(t, func() bool { return stream() == nil },
+		3*time.Second, 10*time.Millisecond)
+
+	// the failed stream isn't reopened for a while
+	send("share-4", 3)
+	require.Nil(t, stream())
+}
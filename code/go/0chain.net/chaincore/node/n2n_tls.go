@@ -0,0 +1,390 @@
+package node
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"0chain.net/core/common"
+	"0chain.net/core/encryption"
+	"0chain.net/core/logging"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+/*
+Mutual TLS between the nodes. Every node generates a TLS key and a self signed
+certificate carrying its node ID and a signature of the TLS public key made
+with the node key from the magic block. A peer certificate is accepted only if
+the node is a member of the current (or the previous) magic block and the
+signature verifies with the node public key, a server certificate only if it's
+the certificate of the node dialed. The certificate is regenerated and the
+members are updated on every view change. The n2n messages are served by a
+separate TLS listener, on the node port shifted by the TLS port offset, the
+node port keeps serving the plain HTTP requests of the clients.
+*/
+
+// oidNodeIdentity is the certificate extension binding the TLS key to the node.
+var oidNodeIdentity = asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 59999, 1, 1}
+
+// TLSCertificateValidity - validity of a node certificate, the certificates
+// are regenerated on view changes.
+var TLSCertificateValidity = 30 * 24 * time.Hour
+
+var (
+	tlsEnabled    bool
+	tlsPortOffset = 1000
+
+	tlsMutex       sync.RWMutex
+	tlsCertificate *tls.Certificate
+	tlsMembers     map[string]struct{}
+)
+
+/*SetTLSEnabled - enable or disable the mutual TLS for the n2n communication */
+func SetTLSEnabled(enabled bool) {
+	tlsEnabled = enabled
+}
+
+/*TLSEnabled - whether the n2n communication uses mutual TLS */
+func TLSEnabled() bool {
+	return tlsEnabled
+}
+
+/*SetTLSPortOffset - set the offset of the n2n TLS port from the node port */
+func SetTLSPortOffset(offset int) {
+	if offset > 0 {
+		tlsPortOffset = offset
+	}
+}
+
+func readTLSConfig() {
+	SetTLSEnabled(viper.GetBool("network.tls.enabled"))
+	SetTLSPortOffset(viper.GetInt("network.tls.port_offset"))
+}
+
+// n2nURLBase is the end point base of the n2n messages sent to the node, the
+// n2n TLS listener if the mutual TLS is enabled.
+func (n *Node) n2nURLBase() string {
+	if tlsEnabled {
+		return fmt.Sprintf("https://%v:%v", n.N2NHost, n.Port+tlsPortOffset)
+	}
+	return n.GetN2NURLBase()
+}
+
+// nodeIdentity is the content of the node identity extension.
+type nodeIdentity struct {
+	NodeID    string `json:"node_id"`
+	Signature string `json:"signature"`
+}
+
+func nodeIdentityHash(nodeID string, publicKeyInfo []byte) string {
+	return encryption.Hash(append([]byte(nodeID+":"), publicKeyInfo...))
+}
+
+// generateCertificate creates a TLS key and a self signed certificate
+// bound to the node by the signature of the node.
+func generateCertificate(nodeID string, sign func(hash string) (string, error)) (*tls.Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	publicKeyInfo, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := sign(nodeIdentityHash(nodeID, publicKeyInfo))
+	if err != nil {
+		return nil, err
+	}
+	identity, err := json.Marshal(&nodeIdentity{NodeID: nodeID, Signature: signature})
+	if err != nil {
+		return nil, err
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, err
+	}
+	var now = time.Now()
+	var template = &x509.Certificate{
+		SerialNumber:    serial,
+		Subject:         pkix.Name{CommonName: nodeID},
+		NotBefore:       now.Add(-time.Hour),
+		NotAfter:        now.Add(TLSCertificateValidity),
+		KeyUsage:        x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:     []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		ExtraExtensions: []pkix.Extension{{Id: oidNodeIdentity, Value: identity}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key, Leaf: leaf}, nil
+}
+
+// verifyCertificate checks the certificate is bound to a known node and
+// returns the node.
+func verifyCertificate(cert *x509.Certificate, lookup func(nodeID string) *Node) (*Node, error) {
+	var now = time.Now()
+	if now.Before(cert.NotBefore) || now.After(cert.NotAfter) {
+		return nil, common.NewError("invalid_node_certificate", "certificate expired")
+	}
+	var identity *nodeIdentity
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(oidNodeIdentity) {
+			identity = new(nodeIdentity)
+			if err := json.Unmarshal(ext.Value, identity); err != nil {
+				return nil, common.NewErrorf("invalid_node_certificate",
+					"decoding node identity: %v", err)
+			}
+		}
+	}
+	if identity == nil || identity.NodeID != cert.Subject.CommonName {
+		return nil, common.NewError("invalid_node_certificate", "missing node identity")
+	}
+	var nd = lookup(identity.NodeID)
+	if nd == nil {
+		return nil, common.NewErrorf("unknown_node", "node %s is not a magic block member",
+			identity.NodeID)
+	}
+	var hash = nodeIdentityHash(identity.NodeID, cert.RawSubjectPublicKeyInfo)
+	if ok, err := nd.Verify(identity.Signature, hash); err != nil || !ok {
+		return nil, common.NewErrorf("invalid_node_certificate",
+			"node %s signature doesn't match the certificate key", identity.NodeID)
+	}
+	return nd, nil
+}
+
+// getTLSMember returns the registered node if it's a magic block member.
+func getTLSMember(nodeID string) *Node {
+	tlsMutex.RLock()
+	_, ok := tlsMembers[nodeID]
+	ok = ok || tlsMembers == nil // no view change yet, the registered nodes
+	tlsMutex.RUnlock()
+	if !ok {
+		return nil
+	}
+	return GetNode(nodeID)
+}
+
+func verifyPeerCertificate(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return nil // not a node, the client and user requests
+	}
+	cert, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return err
+	}
+	_, err = verifyCertificate(cert, getTLSMember)
+	return err
+}
+
+// verifyServerCertificate checks the server is the node dialed, any magic
+// block member if the node ID is empty.
+func verifyServerCertificate(nodeID string) func([][]byte, [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+		if len(rawCerts) == 0 {
+			return common.NewError("invalid_node_certificate", "no server certificate")
+		}
+		cert, err := x509.ParseCertificate(rawCerts[0])
+		if err != nil {
+			return err
+		}
+		nd, err := verifyCertificate(cert, getTLSMember)
+		if err != nil {
+			return err
+		}
+		if nodeID != "" && nd.GetKey() != nodeID {
+			return common.NewErrorf("invalid_node_certificate",
+				"certificate of node %s, expected %s", nd.GetKey(), nodeID)
+		}
+		return nil
+	}
+}
+
+/*RotateTLSCertificate - set the magic block members allowed to connect and generate a new
+* certificate of this node, to be called on view changes */
+func RotateTLSCertificate(members map[string]struct{}) error {
+	tlsMutex.Lock()
+	tlsMembers = members
+	tlsMutex.Unlock()
+	if !tlsEnabled {
+		return nil
+	}
+
+	cert, err := generateCertificate(Self.Underlying().GetKey(), Self.Sign)
+	if err != nil {
+		return err
+	}
+	tlsMutex.Lock()
+	tlsCertificate = cert
+	tlsMutex.Unlock()
+
+	// reconnect to the peers with the new certificate
+	if transport, ok := httpClient.Transport.(*http.Transport); ok {
+		transport.CloseIdleConnections()
+	}
+	logging.N2n.Info("tls certificate rotated", zap.Int("members", len(members)),
+		zap.Time("not_after", cert.Leaf.NotAfter))
+	return nil
+}
+
+func getTLSCertificate() (*tls.Certificate, error) {
+	tlsMutex.RLock()
+	var cert = tlsCertificate
+	tlsMutex.RUnlock()
+	if cert != nil {
+		return cert, nil
+	}
+	// no view change yet
+	cert, err := generateCertificate(Self.Underlying().GetKey(), Self.Sign)
+	if err != nil {
+		return nil, err
+	}
+	tlsMutex.Lock()
+	defer tlsMutex.Unlock()
+	if tlsCertificate == nil {
+		tlsCertificate = cert
+	}
+	return tlsCertificate, nil
+}
+
+/*ServerTLSConfig - TLS configuration of the n2n server, the node certificates are required */
+func ServerTLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+			return getTLSCertificate()
+		},
+		ClientAuth:            tls.RequireAnyClientCert,
+		VerifyPeerCertificate: verifyPeerCertificate,
+	}
+}
+
+// clientTLSConfig presents the node certificate and verifies the server is
+// the given node, or any magic block member, instead of verifying the
+// certificate chain.
+func clientTLSConfig(nodeID string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			return getTLSCertificate()
+		},
+		InsecureSkipVerify:    true,
+		VerifyPeerCertificate: verifyServerCertificate(nodeID),
+	}
+}
+
+type tlsPeerKey struct{}
+
+// withTLSPeer sets the node the connections dialed for the requests of the
+// context are expected to reach.
+func withTLSPeer(ctx context.Context, nd *Node) context.Context {
+	return context.WithValue(ctx, tlsPeerKey{}, nd.GetKey())
+}
+
+// dialTLS dials the n2n TLS listener of the node expected by the request.
+func dialTLS(ctx context.Context, network, addr string) (net.Conn, error) {
+	nodeID, _ := ctx.Value(tlsPeerKey{}).(string)
+	if nodeID == "" {
+		return nil, common.NewErrorf("unknown_tls_peer", "no node expected at %s", addr)
+	}
+	var dialer = &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second},
+		Config:    clientTLSConfig(nodeID),
+	}
+	return dialer.DialContext(ctx, network, addr)
+}
+
+// n2nServer returns the TLS server of the n2n messages, on the port of the
+// server shifted by the TLS port offset.
+func n2nServer(server *http.Server) (*http.Server, error) {
+	host, port, err := net.SplitHostPort(server.Addr)
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(port)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Server{
+		Addr:           net.JoinHostPort(host, strconv.Itoa(n+tlsPortOffset)),
+		Handler:        server.Handler,
+		ReadTimeout:    server.ReadTimeout,
+		WriteTimeout:   server.WriteTimeout,
+		MaxHeaderBytes: server.MaxHeaderBytes,
+		TLSConfig:      ServerTLSConfig(),
+	}, nil
+}
+
+/*ListenAndServe - start the node server and, if the mutual TLS is enabled, the n2n TLS server */
+func ListenAndServe(server *http.Server) error {
+	if !tlsEnabled {
+		return server.ListenAndServe()
+	}
+	tlsServer, err := n2nServer(server)
+	if err != nil {
+		return err
+	}
+	server.RegisterOnShutdown(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		tlsServer.Shutdown(ctx)
+	})
+	var errs = make(chan error, 2)
+	go func() { errs <- tlsServer.ListenAndServeTLS("", "") }()
+	go func() { errs <- server.ListenAndServe() }()
+	return <-errs
+}
+
+// validateTLSServer checks the response came over a connection
+// authenticated with the certificate of the receiver.
+func validateTLSServer(receiver *Node, resp *http.Response) bool {
+	if !tlsEnabled {
+		return true
+	}
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 ||
+		resp.TLS.PeerCertificates[0].Subject.CommonName != receiver.GetKey() {
+		logging.N2n.Error("peer stream - certificate of another node",
+			zap.String("to", receiver.GetKey()))
+		return false
+	}
+	return true
+}
+
+// validateTLSPeer checks the n2n request came over a connection
+// authenticated with the certificate of the sender.
+func validateTLSPeer(sender *Node, r *http.Request) bool {
+	if !tlsEnabled {
+		return true
+	}
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		logging.N2n.Error("message received - no node certificate",
+			zap.String("from", sender.GetKey()), zap.String("handler", r.RequestURI))
+		return false
+	}
+	var cn = r.TLS.PeerCertificates[0].Subject.CommonName
+	if cn != sender.GetKey() || getTLSMember(cn) == nil {
+		logging.N2n.Error("message received - certificate of another node",
+			zap.String("from", sender.GetKey()), zap.String("certificate", cn),
+			zap.String("handler", r.RequestURI))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,176 @@
+package node
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"0chain.net/core/encryption"
+)
+
+func newTLSTestNode(t *testing.T, id string) (*Node, encryption.SignatureScheme) {
+	var scheme = encryption.NewED25519Scheme()
+	require.NoError(t, scheme.GenerateKeys())
+	var nd = Provider()
+	nd.SetSignatureScheme(scheme)
+	nd.ID = id
+	return nd, scheme
+}
+
+func signer(scheme encryption.SignatureScheme) func(hash string) (string, error) {
+	return func(hash string) (string, error) {
+		return scheme.Sign(hash)
+	}
+}
+
+func TestNodeCertificate(t *testing.T) {
+	var (
+		nd, scheme = newTLSTestNode(t, "tls-node-a")
+		other, _   = newTLSTestNode(t, "tls-node-b")
+		lookup     = func(id string) *Node {
+			if id == nd.GetKey() {
+				return nd
+			}
+			return nil
+		}
+	)
+	cert, err := generateCertificate(nd.GetKey(), signer(scheme))
+	require.NoError(t, err)
+	got, err := verifyCertificate(cert.Leaf, lookup)
+	require.NoError(t, err)
+	require.Equal(t, nd, got)
+
+	// unknown node
+	_, err = verifyCertificate(cert.Leaf, func(string) *Node { return nil })
+	require.Error(t, err)
+
+	// the TLS key isn't signed by the node key
+	_, err = verifyCertificate(cert.Leaf, func(string) *Node { return other })
+	require.Error(t, err)
+
+	// the certificate of another node presented as this node
+	forged, err := generateCertificate(nd.GetKey(), signer(other.GetSignatureScheme()))
+	require.NoError(t, err)
+	_, err = verifyCertificate(forged.Leaf, lookup)
+	require.Error(t, err)
+
+	// no node identity
+	var plain = *cert.Leaf
+	plain.Extensions = nil
+	_, err = verifyCertificate(&plain, lookup)
+	require.Error(t, err)
+}
+
+func TestValidateTLSPeer(t *testing.T) {
+	var nd, scheme = newTLSTestNode(t, "tls-node-c")
+	RegisterNode(nd)
+	cert, err := generateCertificate(nd.GetKey(), signer(scheme))
+	require.NoError(t, err)
+
+	var r = &http.Request{RequestURI: "/v1/_m2m/round/vrf_share"}
+	require.True(t, validateTLSPeer(nd, r))
+
+	nd.N2NHost, nd.Port = "127.0.0.1", 7071
+	require.Equal(t, "http://127.0.0.1:7071", nd.n2nURLBase())
+
+	SetTLSEnabled(true)
+	defer SetTLSEnabled(false)
+	require.Equal(t, "https://127.0.0.1:8071", nd.n2nURLBase())
+	require.Equal(t, "http://127.0.0.1:7071", nd.GetN2NURLBase())
+	require.False(t, validateTLSPeer(nd, r))
+
+	r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert.Leaf}}
+	require.True(t, validateTLSPeer(nd, r))
+	require.NoError(t, verifyPeerCertificate(cert.Certificate, nil))
+
+	// not a member of the magic block anymore
+	tlsMutex.Lock()
+	tlsMembers = map[string]struct{}{"tls-node-d": {}}
+	tlsMutex.Unlock()
+	defer func() {
+		tlsMutex.Lock()
+		tlsMembers = nil
+		tlsMutex.Unlock()
+	}()
+	require.False(t, validateTLSPeer(nd, r))
+	require.Error(t, verifyPeerCertificate(cert.Certificate, nil))
+}
+
+func TestN2NServer(t *testing.T) {
+	var server = &http.Server{Addr: ":7071", ReadTimeout: 30 * time.Second}
+	tlsServer, err := n2nServer(server)
+	require.NoError(t, err)
+	require.Equal(t, ":8071", tlsServer.Addr)
+	require.Equal(t, server.ReadTimeout, tlsServer.ReadTimeout)
+	require.Equal(t, tls.RequireAnyClientCert, tlsServer.TLSConfig.ClientAuth)
+}
+
+func TestDialTLS(t *testing.T) {
+	var (
+		nd, scheme = newTLSTestNode(t, "tls-node-e")
+		other, _   = newTLSTestNode(t, "tls-node-f")
+		self       = Self
+	)
+	Self = &SelfNode{Node: nd}
+	Self.SetSignatureScheme(scheme) // recomputes the node ID
+	RegisterNode(nd)
+	RegisterNode(other)
+	SetTLSEnabled(true)
+	defer func() {
+		Self = self
+		SetTLSEnabled(false)
+		tlsMutex.Lock()
+		tlsCertificate = nil
+		tlsMutex.Unlock()
+	}()
+
+	cert, err := getTLSCertificate()
+	require.NoError(t, err)
+	var server = httptest.NewUnstartedServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+		}))
+	server.TLS = ServerTLSConfig()
+	server.TLS.Certificates = []tls.Certificate{*cert}
+	server.StartTLS()
+	defer server.Close()
+
+	var get = func(ctx context.Context) (string, error) {
+		var client = &http.Client{Transport: &http.Transport{DialTLSContext: dialTLS}}
+		req, err := http.NewRequest(http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req.WithContext(ctx))
+		if err != nil {
+			return "", err
+		}
+		defer resp.Body.Close()
+		data, err := ioutil.ReadAll(resp.Body)
+		return string(data), err
+	}
+
+	got, err := get(withTLSPeer(context.Background(), nd))
+	require.NoError(t, err)
+	require.Equal(t, nd.GetKey(), got)
+
+	// the server isn't the node expected
+	_, err = get(withTLSPeer(context.Background(), other))
+	require.Error(t, err)
+
+	// no node expected
+	_, err = get(context.Background())
+	require.Error(t, err)
+
+	// the n2n listener requires the node certificate
+	var plain = &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+	_, err = plain.Get(server.URL)
+	require.Error(t, err)
+}
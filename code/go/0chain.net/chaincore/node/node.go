@@ -575,6 +575,9 @@ func ReadConfig() {
 	SetTimeoutLargeMessage(viper.GetDuration("network.timeout.large_message") * time.Millisecond)
 	SetMaxConcurrentRequests(viper.GetInt("network.max_concurrent_requests"))
 	SetLargeMessageThresholdSize(viper.GetInt("network.large_message_th_size"))
+	readDisseminationConfig()
+	readStreamConfig()
+	readTLSConfig()
 }
 
 //SetID - set the id of the node
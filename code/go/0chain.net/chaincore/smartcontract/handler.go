@@ -22,20 +22,22 @@ var ContractMap = map[string]sci.SmartContractInterface{}
 
 //ExecuteRestAPI - executes the rest api on the smart contract
 func ExecuteRestAPI(ctx context.Context, scAdress string, restpath string, params url.Values, balances c_state.StateContextI) (interface{}, error) {
-	scI := getSmartContract(scAdress)
-	if scI != nil {
-		//add bc context here
-		handler, restpathok := scI.GetRestPoints()[restpath]
-		if !restpathok {
-			return nil, common.NewError("invalid_path", "Invalid path")
-		}
-		return handler(ctx, params, balances)
+	// the version active at round of the state
+	scI, err := GetActiveSmartContract(scAdress, balances)
+	if err != nil {
+		return nil, err
 	}
-	return nil, common.NewError("invalid_sc", "Invalid Smart contract address")
+	//add bc context here
+	handler, restpathok := scI.GetRestPoints()[restpath]
+	if !restpathok {
+		return nil, common.NewError("invalid_path", "Invalid path")
+	}
+	return handler(ctx, params, balances)
 }
 
-func ExecuteStats(ctx context.Context, scAdress string, params url.Values, w http.ResponseWriter) {
-	scI := getSmartContract(scAdress)
+// ExecuteStats prints handlers stats of given version of the smart contract
+func ExecuteStats(ctx context.Context, scAdress string, version int, params url.Values, w http.ResponseWriter) {
+	scI := GetSmartContractVersion(scAdress, version)
 	if scI != nil {
 		i, err := scI.GetHandlerStats(ctx, params)
 		if err != nil {
@@ -60,22 +62,27 @@ func GetSmartContract(scAddress string) sci.SmartContractInterface {
 }
 
 func ExecuteWithStats(smcoi sci.SmartContractInterface, t *transaction.Transaction, funcName string, input []byte, balances c_state.StateContextI) (string, error) {
+	// the state I/O of the execution is counted
+	cbalances := c_state.NewCountingStateContext(balances)
 	ts := time.Now()
-	inter, err := smcoi.Execute(t, funcName, input, balances)
-	if err == nil {
-		if tm := smcoi.GetExecutionStats()[funcName]; tm != nil {
-			if timer, ok := tm.(metrics.Timer); ok {
-				timer.Update(time.Since(ts))
-			}
+	inter, err := smcoi.Execute(t, funcName, input, cbalances)
+	tm, known := smcoi.GetExecutionStats()[funcName]
+	if err == nil && tm != nil {
+		if timer, ok := tm.(metrics.Timer); ok {
+			timer.Update(time.Since(ts))
 		}
 	}
+	// failed calls of unknown functions are not counted
+	if known || err == nil {
+		getFunctionStats(smcoi.GetAddress(), funcName).update(err,
+			cbalances.IOStats())
+	}
 	return inter, err
 }
 
 //ExecuteSmartContract - executes the smart contract in the context of the given transaction
 func ExecuteSmartContract(ctx context.Context, t *transaction.Transaction, balances c_state.StateContextI) (string, error) {
-	contractObj := getSmartContract(t.ToClientID)
-	if contractObj != nil {
+	if isSmartContract(t.ToClientID) {
 		var smartContractData sci.SmartContractTransactionData
 		dataBytes := []byte(t.TransactionData)
 		err := json.Unmarshal(dataBytes, &smartContractData)
@@ -83,8 +90,22 @@ func ExecuteSmartContract(ctx context.Context, t *transaction.Transaction, balan
 			logging.Logger.Error("Error while decoding the JSON from transaction", zap.Any("input", t.TransactionData), zap.Any("error", err))
 			return "", err
 		}
+		// dispatch to the contract version active at the block round
+		contractObj, err := GetActiveSmartContract(t.ToClientID, balances)
+		if err != nil {
+			return "", err
+		}
 		// transactionOutput, err := contractObj.ExecuteWithStats(t, smartContractData.FunctionName, []byte(smartContractData.InputData), balances)
-		transactionOutput, err := ExecuteWithStats(contractObj, t, smartContractData.FunctionName, []byte(smartContractData.InputData), balances)
+		// the call runs in a savepoint, its changes are discarded on error
+		// the state operations are recorded if the trace is requested
+		if trace := c_state.GetTrace(ctx); trace != nil {
+			balances = c_state.NewTracingStateContext(balances, trace)
+		}
+		var transactionOutput string
+		err = c_state.Atomic(balances, func() (err error) {
+			transactionOutput, err = ExecuteWithStats(contractObj, t, smartContractData.FunctionName, []byte(smartContractData.InputData), balances)
+			return
+		})
 		if err != nil {
 			return "", err
 		}
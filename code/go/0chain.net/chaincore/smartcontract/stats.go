@@ -0,0 +1,88 @@
+package smartcontract
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	c_state "0chain.net/chaincore/chain/state"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+// FunctionStats is the errors and the state I/O of the executions of a
+// smart contract function, per execution.
+type FunctionStats struct {
+	Name         string
+	Errors       metrics.Counter
+	Reads        metrics.Histogram
+	ReadBytes    metrics.Histogram
+	Writes       metrics.Histogram
+	WrittenBytes metrics.Histogram
+	Deletes      metrics.Histogram
+}
+
+var (
+	functionStatsMutex sync.Mutex
+	// functionStats - by address and function name
+	functionStats = map[string]map[string]*FunctionStats{}
+)
+
+func newFunctionStats(scAddress, funcName string) *FunctionStats {
+	var (
+		prefix    = fmt.Sprintf("sc:%v:func:%v", scAddress, funcName)
+		histogram = func(name string) metrics.Histogram {
+			return metrics.GetOrRegisterHistogram(prefix+":"+name, nil,
+				metrics.NewUniformSample(1024))
+		}
+	)
+	return &FunctionStats{
+		Name:         funcName,
+		Errors:       metrics.GetOrRegisterCounter(prefix+":errors", nil),
+		Reads:        histogram("reads"),
+		ReadBytes:    histogram("read_bytes"),
+		Writes:       histogram("writes"),
+		WrittenBytes: histogram("written_bytes"),
+		Deletes:      histogram("deletes"),
+	}
+}
+
+func getFunctionStats(scAddress, funcName string) *FunctionStats {
+	functionStatsMutex.Lock()
+	defer functionStatsMutex.Unlock()
+	byName, ok := functionStats[scAddress]
+	if !ok {
+		byName = make(map[string]*FunctionStats)
+		functionStats[scAddress] = byName
+	}
+	fs, ok := byName[funcName]
+	if !ok {
+		fs = newFunctionStats(scAddress, funcName)
+		byName[funcName] = fs
+	}
+	return fs
+}
+
+func (fs *FunctionStats) update(err error, io c_state.IOStats) {
+	if err != nil {
+		fs.Errors.Inc(1)
+	}
+	fs.Reads.Update(io.Reads)
+	fs.ReadBytes.Update(io.ReadBytes)
+	fs.Writes.Update(io.Writes)
+	fs.WrittenBytes.Update(io.WrittenBytes)
+	fs.Deletes.Update(io.Deletes)
+}
+
+// GetFunctionStats returns the stats of the executed functions of the smart
+// contract, sorted by name.
+func GetFunctionStats(scAddress string) (stats []*FunctionStats) {
+	functionStatsMutex.Lock()
+	defer functionStatsMutex.Unlock()
+	for _, fs := range functionStats[scAddress] {
+		stats = append(stats, fs)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Name < stats[j].Name
+	})
+	return
+}
@@ -0,0 +1,57 @@
+package smartcontract_test
+
+import (
+	"testing"
+
+	"github.com/rcrowley/go-metrics"
+	"github.com/stretchr/testify/require"
+
+	"0chain.net/chaincore/block"
+	chstate "0chain.net/chaincore/chain/state"
+	. "0chain.net/chaincore/smartcontract"
+	sci "0chain.net/chaincore/smartcontractinterface"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/memorystore"
+	"0chain.net/core/util"
+)
+
+func TestExecuteWithStats_FunctionStats(t *testing.T) {
+	block.SetupEntity(memorystore.GetStorageProvider())
+
+	const address = "function_stats_sc_address"
+
+	var (
+		vsc = &versionedSC{SmartContract: sci.NewSC(address), output: "output"}
+		txn = &transaction.Transaction{ToClientID: address}
+		mpt = util.NewMerklePatriciaTrie(util.NewMemoryNodeDB(), 0, nil)
+
+		balances = chstate.NewStateContext(block.NewBlock("", 1), mpt,
+			&state.Deserializer{}, txn, nil, nil, nil, nil, nil)
+	)
+	vsc.SmartContractExecutionStats["fail"] = metrics.NewTimer()
+
+	for i := 0; i < 2; i++ {
+		_, err := ExecuteWithStats(vsc, txn, "store", nil, balances)
+		require.NoError(t, err)
+	}
+	_, err := ExecuteWithStats(vsc, txn, "fail", nil, balances)
+	require.Error(t, err)
+
+	// failed calls of unknown functions are not counted
+	vsc.SmartContractExecutionStats = map[string]interface{}{}
+	_, err = ExecuteWithStats(vsc, txn, "fail", nil, balances)
+	require.Error(t, err)
+
+	var stats = GetFunctionStats(vsc.GetAddress())
+	require.Len(t, stats, 2)
+	require.Equal(t, "fail", stats[0].Name)
+	require.EqualValues(t, 1, stats[0].Errors.Count())
+	require.EqualValues(t, 0, stats[0].Writes.Max())
+
+	require.Equal(t, "store", stats[1].Name)
+	require.EqualValues(t, 0, stats[1].Errors.Count())
+	require.EqualValues(t, 2, stats[1].Writes.Count())
+	require.EqualValues(t, 1, stats[1].Writes.Max())
+	require.EqualValues(t, len("output"), stats[1].WrittenBytes.Max())
+}
@@ -0,0 +1,293 @@
+package smartcontract
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	c_state "0chain.net/chaincore/chain/state"
+	sci "0chain.net/chaincore/smartcontractinterface"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+
+	lru "github.com/hashicorp/golang-lru"
+)
+
+// DefaultContractVersion is version of the implementations of the
+// ContractMap, it's active until another version is activated.
+const DefaultContractVersion = 1
+
+// ContractVersionsKey is key of the state node recording activations of
+// the smart contracts versions.
+var ContractVersionsKey = datastore.Key(encryption.Hash("smart_contract_versions"))
+
+// contractVersions - implementations of the smart contracts versions other
+// than the default one, by address and version
+var contractVersions = map[string]map[int]sci.SmartContractInterface{}
+
+// RegisterContractVersion registers implementation of given version of a
+// smart contract. The default version goes to the ContractMap.
+func RegisterContractVersion(version int, contract sci.SmartContractInterface) {
+	var address = contract.GetAddress()
+	if version == DefaultContractVersion {
+		ContractMap[address] = contract
+		return
+	}
+	if _, ok := contractVersions[address]; !ok {
+		contractVersions[address] = make(map[int]sci.SmartContractInterface)
+	}
+	contractVersions[address][version] = contract
+}
+
+// GetSmartContractVersion returns implementation of given version of the
+// smart contract, or nil if the node doesn't have it.
+func GetSmartContractVersion(scAddress string, version int) sci.SmartContractInterface {
+	if version == DefaultContractVersion {
+		return getSmartContract(scAddress)
+	}
+	return contractVersions[scAddress][version]
+}
+
+// GetSmartContractAddresses returns sorted addresses of the smart contracts
+// the node has implementations of, of any version.
+func GetSmartContractAddresses() (addresses []string) {
+	for address := range ContractMap {
+		addresses = append(addresses, address)
+	}
+	for address := range contractVersions {
+		if _, ok := ContractMap[address]; !ok {
+			addresses = append(addresses, address)
+		}
+	}
+	sort.Strings(addresses)
+	return
+}
+
+// isSmartContract returns true if the node has an implementation of any
+// version of the smart contract.
+func isSmartContract(scAddress string) bool {
+	return getSmartContract(scAddress) != nil ||
+		len(contractVersions[scAddress]) > 0
+}
+
+// GetContractVersions returns sorted versions of the smart contract the
+// node has implementations of.
+func GetContractVersions(scAddress string) (versions []int) {
+	if getSmartContract(scAddress) != nil {
+		versions = append(versions, DefaultContractVersion)
+	}
+	for version := range contractVersions[scAddress] {
+		versions = append(versions, version)
+	}
+	sort.Ints(versions)
+	return
+}
+
+// VersionActivation is version of a smart contract active from the round.
+type VersionActivation struct {
+	Version int   `json:"version"`
+	Round   int64 `json:"round"`
+}
+
+// ScheduleVersionRequest is input of SC functions scheduling activation of
+// a smart contract version. The activation round is optional.
+type ScheduleVersionRequest struct {
+	Address         string `json:"address"`
+	Version         int    `json:"version"`
+	ActivationRound int64  `json:"activation_round,omitempty"`
+}
+
+func (sr *ScheduleVersionRequest) Decode(input []byte) error {
+	return json.Unmarshal(input, sr)
+}
+
+// Activation returns round the version should be activated at for given
+// current round and time lock (min number of rounds of notice).
+func (sr *ScheduleVersionRequest) Activation(round, timeLock int64) (int64, error) {
+	if sr.ActivationRound == 0 {
+		if timeLock <= 0 {
+			return round + 1, nil
+		}
+		return round + timeLock, nil
+	}
+	if sr.ActivationRound <= round {
+		return 0, fmt.Errorf("activation round %d is not in future, "+
+			"current round %d", sr.ActivationRound, round)
+	}
+	if sr.ActivationRound < round+timeLock {
+		return 0, fmt.Errorf("activation round %d violates time lock, "+
+			"min activation round is %d", sr.ActivationRound, round+timeLock)
+	}
+	return sr.ActivationRound, nil
+}
+
+// ContractVersions is the state node of the smart contracts versions
+// activations, ordered by round for each address.
+type ContractVersions struct {
+	Activations map[string][]*VersionActivation `json:"activations"`
+}
+
+func (cv *ContractVersions) Encode() []byte {
+	var b, err = json.Marshal(cv)
+	if err != nil {
+		panic(err) // must never happen
+	}
+	return b
+}
+
+func (cv *ContractVersions) Decode(input []byte) error {
+	return json.Unmarshal(input, cv)
+}
+
+// Active returns version of the smart contract active at given round.
+func (cv *ContractVersions) Active(scAddress string, round int64) int {
+	var version = DefaultContractVersion
+	for _, a := range cv.Activations[scAddress] {
+		if a.Round > round {
+			break
+		}
+		version = a.Version
+	}
+	return version
+}
+
+// Pending returns activations of the smart contract after given round.
+func (cv *ContractVersions) Pending(scAddress string, round int64) (
+	pending []*VersionActivation) {
+
+	for _, a := range cv.Activations[scAddress] {
+		if a.Round > round {
+			pending = append(pending, a)
+		}
+	}
+	return
+}
+
+// Schedule activation of the smart contract version at given round. The
+// round must be after all activations of the smart contract scheduled
+// already. The schedule doesn't depend on implementations the node has,
+// a node without the version fails executing the contract after the
+// activation only.
+func (cv *ContractVersions) Schedule(scAddress string, version int, round int64) error {
+	if version < DefaultContractVersion {
+		return fmt.Errorf("invalid version %d of smart contract %s",
+			version, scAddress)
+	}
+	var (
+		activations = cv.Activations[scAddress]
+		last        = DefaultContractVersion
+	)
+	if n := len(activations); n > 0 {
+		if activations[n-1].Round >= round {
+			return fmt.Errorf("activation round %d is not after round %d "+
+				"of the last scheduled version", round, activations[n-1].Round)
+		}
+		last = activations[n-1].Version
+	}
+	if last == version {
+		return fmt.Errorf("version %d is activated already", version)
+	}
+	if cv.Activations == nil {
+		cv.Activations = make(map[string][]*VersionActivation)
+	}
+	cv.Activations[scAddress] = append(activations,
+		&VersionActivation{Version: version, Round: round})
+	return nil
+}
+
+// Cancel removes activations of the smart contract after given round.
+func (cv *ContractVersions) Cancel(scAddress string, round int64) bool {
+	var (
+		activations = cv.Activations[scAddress]
+		i           = sort.Search(len(activations), func(i int) bool {
+			return activations[i].Round > round
+		})
+	)
+	if i == len(activations) {
+		return false
+	}
+	if i == 0 {
+		delete(cv.Activations, scAddress)
+	} else {
+		cv.Activations[scAddress] = activations[:i]
+	}
+	return true
+}
+
+// GetContractVersionsNode returns the smart contracts versions activations
+// recorded in the state.
+func GetContractVersionsNode(balances c_state.StateContextI) (
+	*ContractVersions, error) {
+
+	var val, err = balances.GetTrieNode(ContractVersionsKey)
+	if err != nil || val == nil {
+		if err != nil && err != util.ErrValueNotPresent {
+			return nil, err
+		}
+		return new(ContractVersions), nil
+	}
+
+	var cv = new(ContractVersions)
+	if err = cv.Decode(val.Encode()); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return cv, nil
+}
+
+// SaveContractVersionsNode saves the smart contracts versions activations
+// in the state.
+func SaveContractVersionsNode(cv *ContractVersions,
+	balances c_state.StateContextI) (err error) {
+
+	_, err = balances.InsertTrieNode(ContractVersionsKey, cv)
+	return
+}
+
+// blockVersions caches the smart contracts versions activations by round
+// and previous block. The activations are scheduled for the next rounds
+// only, thus the versions active at round of a block are defined by state
+// of the previous block and don't change during the block.
+var blockVersions, _ = lru.New(16)
+
+// getBlockContractVersions returns the smart contracts versions activations
+// for the block of given state context
+func getBlockContractVersions(balances c_state.StateContextI) (
+	*ContractVersions, error) {
+
+	var (
+		b   = balances.GetBlock()
+		key = fmt.Sprintf("%d:%s", b.Round, b.PrevHash)
+	)
+	if cv, ok := blockVersions.Get(key); ok {
+		return cv.(*ContractVersions), nil
+	}
+	cv, err := GetContractVersionsNode(balances)
+	if err != nil {
+		return nil, err
+	}
+	blockVersions.Add(key, cv)
+	return cv, nil
+}
+
+// GetActiveSmartContract returns implementation of the smart contract
+// version active at round of the block of given state context
+func GetActiveSmartContract(scAddress string,
+	balances c_state.StateContextI) (sci.SmartContractInterface, error) {
+
+	if !isSmartContract(scAddress) {
+		return nil, common.NewError("invalid_sc", "Invalid Smart contract address")
+	}
+	cv, err := getBlockContractVersions(balances)
+	if err != nil {
+		return nil, common.NewError("get_sc_versions", err.Error())
+	}
+	var version = cv.Active(scAddress, balances.GetBlock().Round)
+	if impl := GetSmartContractVersion(scAddress, version); impl != nil {
+		return impl, nil
+	}
+	return nil, common.NewErrorf("unsupported_sc_version",
+		"version %d of smart contract %s is not supported by the node, "+
+			"upgrade required", version, scAddress)
+}
@@ -0,0 +1,119 @@
+package smartcontract_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"0chain.net/chaincore/block"
+	chstate "0chain.net/chaincore/chain/state"
+	. "0chain.net/chaincore/smartcontract"
+	sci "0chain.net/chaincore/smartcontractinterface"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/memorystore"
+	"0chain.net/core/util"
+)
+
+const versionedSCAddress = "versioned_sc_address"
+
+type versionedSC struct {
+	*sci.SmartContract
+	output string
+}
+
+func (vsc *versionedSC) Execute(_ *transaction.Transaction, funcName string,
+	_ []byte, balances chstate.StateContextI) (string, error) {
+	if funcName == "fail" {
+		return "", errors.New("failure")
+	}
+	_, err := balances.InsertTrieNode("output",
+		&util.SecureSerializableValue{Buffer: []byte(vsc.output)})
+	return vsc.output, err
+}
+
+func (vsc *versionedSC) GetHandlerStats(ctx context.Context, params url.Values) (interface{}, error) {
+	return vsc.HandlerStats(ctx, params)
+}
+
+func (vsc *versionedSC) GetExecutionStats() map[string]interface{} {
+	return vsc.SmartContractExecutionStats
+}
+
+func (vsc *versionedSC) GetName() string {
+	return "versioned"
+}
+
+func (vsc *versionedSC) GetAddress() string {
+	return vsc.ID
+}
+
+func (vsc *versionedSC) GetRestPoints() map[string]sci.SmartContractRestHandler {
+	return vsc.RestHandlers
+}
+
+func TestContractVersions(t *testing.T) {
+	block.SetupEntity(memorystore.GetStorageProvider())
+
+	RegisterContractVersion(DefaultContractVersion,
+		&versionedSC{SmartContract: sci.NewSC(versionedSCAddress), output: "v1"})
+	RegisterContractVersion(2,
+		&versionedSC{SmartContract: sci.NewSC(versionedSCAddress), output: "v2"})
+	require.Equal(t, []int{1, 2}, GetContractVersions(versionedSCAddress))
+
+	var cv ContractVersions
+	require.Error(t, cv.Schedule(versionedSCAddress, 0, 10))   // invalid
+	require.Error(t, cv.Schedule(versionedSCAddress, 1, 10))   // active
+	require.NoError(t, cv.Schedule(versionedSCAddress, 2, 10)) // upgrade
+	require.Error(t, cv.Schedule(versionedSCAddress, 1, 10))   // same round
+	require.NoError(t, cv.Schedule(versionedSCAddress, 1, 20)) // downgrade
+	require.Equal(t, 1, cv.Active(versionedSCAddress, 9))
+	require.Equal(t, 2, cv.Active(versionedSCAddress, 10))
+	require.Equal(t, 1, cv.Active(versionedSCAddress, 20))
+	require.Len(t, cv.Pending(versionedSCAddress, 10), 1)
+	require.True(t, cv.Cancel(versionedSCAddress, 10))
+	require.False(t, cv.Cancel(versionedSCAddress, 10))
+	require.Equal(t, 2, cv.Active(versionedSCAddress, 20))
+	require.Equal(t, DefaultContractVersion, cv.Active("unknown", 100))
+
+	var (
+		b   = block.NewBlock("", 9)
+		mpt = util.NewMerklePatriciaTrie(util.NewMemoryNodeDB(), 0, nil)
+		txn = &transaction.Transaction{
+			ToClientID: versionedSCAddress,
+			TransactionData: func() string {
+				data, err := json.Marshal(&sci.SmartContractTransactionData{
+					FunctionName: "any",
+				})
+				require.NoError(t, err)
+				return string(data)
+			}(),
+		}
+		balances = chstate.NewStateContext(b, mpt, &state.Deserializer{},
+			txn, nil, nil, nil, nil, nil)
+	)
+	require.NoError(t, SaveContractVersionsNode(&cv, balances))
+
+	var execute = func(round int64) string {
+		b.Round = round
+		output, err := ExecuteSmartContract(context.Background(), txn, balances)
+		require.NoError(t, err)
+		return output
+	}
+	require.Equal(t, "v1", execute(9))
+	require.Equal(t, "v2", execute(10))
+
+	// the schedule doesn't depend on the node implementations, the node
+	// fails after the activation only
+	require.NoError(t, cv.Schedule(versionedSCAddress, 3, 30))
+	require.NoError(t, SaveContractVersionsNode(&cv, balances))
+	require.Equal(t, "v2", execute(29))
+	b.Round = 30
+	_, err := ExecuteSmartContract(context.Background(), txn, balances)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported_sc_version")
+}
@@ -28,9 +28,11 @@ func NewSC(id string) *SmartContract {
 // SmartContractTransactionData is passed in Transaction.TransactionData
 // InputData may contain Public Key in some cases
 // FunctionName is user to invoke SC API function
+// GasLimit is max gas the execution can use, zero is the chain max
 type SmartContractTransactionData struct {
 	FunctionName string          `json:"name"`
 	InputData    json.RawMessage `json:"input"`
+	GasLimit     int64           `json:"gas_limit,omitempty"`
 }
 
 type SmartContractInterface interface {
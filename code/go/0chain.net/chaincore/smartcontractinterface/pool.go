@@ -13,6 +13,8 @@ type PoolStats struct {
 	Low          state.Balance `json:"low"`  // }
 	InterestPaid state.Balance `json:"interest_paid"`
 	RewardPaid   state.Balance `json:"reward_paid"`
+	Compounded   state.Balance `json:"compounded"` // rewards reinvested
+	Slashed      state.Balance `json:"slashed,omitempty"`
 	NumRounds    int64         `json:"number_rounds"`
 	Status       string        `json:"status"`
 }
@@ -53,6 +55,12 @@ func (ps *PoolStats) Decode(input []byte) error {
 type DelegatePool struct {
 	*PoolStats                `json:"stats"`
 	*tokenpool.ZcnLockingPool `json:"pool"`
+	// AutoCompound reinvests rewards into the pool instead of paying
+	// them to the delegate wallet.
+	AutoCompound bool `json:"auto_compound"`
+	// RoundCreated is round the pool has been created in. Governance
+	// counts only stake created before a proposal.
+	RoundCreated int64 `json:"round_created,omitempty"`
 }
 
 func NewDelegatePool() *DelegatePool {
@@ -86,5 +94,19 @@ func (dp *DelegatePool) Decode(input []byte, tokenlock tokenpool.TokenLockInterf
 			return err
 		}
 	}
+	ac, ok := objMap["auto_compound"]
+	if ok {
+		err = json.Unmarshal(*ac, &dp.AutoCompound)
+		if err != nil {
+			return err
+		}
+	}
+	rc, ok := objMap["round_created"]
+	if ok {
+		err = json.Unmarshal(*rc, &dp.RoundCreated)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
@@ -154,6 +154,8 @@ func TestPoolStats_Encode(t *testing.T) {
 		Low          state.Balance
 		InterestPaid state.Balance
 		RewardPaid   state.Balance
+		Compounded   state.Balance
+		Slashed      state.Balance
 		NumRounds    int64
 		Status       string
 	}
@@ -179,6 +181,8 @@ func TestPoolStats_Encode(t *testing.T) {
 				Low:          tt.fields.Low,
 				InterestPaid: tt.fields.InterestPaid,
 				RewardPaid:   tt.fields.RewardPaid,
+				Compounded:   tt.fields.Compounded,
+				Slashed:      tt.fields.Slashed,
 				NumRounds:    tt.fields.NumRounds,
 				Status:       tt.fields.Status,
 			}
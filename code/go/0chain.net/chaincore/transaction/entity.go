@@ -100,6 +100,33 @@ var exemptedSCFunctions = map[string]bool{
 	"wait":                 true,
 }
 
+// IsFeeExempted returns true if the smart contract function is processed
+// without a fee
+func IsFeeExempted(funcName string) bool {
+	return exemptedSCFunctions[funcName]
+}
+
+// protocolSCFunctions - smart contract functions the block generator calls
+// in each block, the transactions are created without a fee
+var protocolSCFunctions = map[string]bool{
+	"payFees":                   true,
+	"commit_settings_changes":   true,
+	"pay_blobber_block_rewards": true,
+}
+
+// IsProtocolTxn returns true if the transaction is a smart contract call
+// made by the generator of a block for the protocol, given the generator
+func (t *Transaction) IsProtocolTxn(minerID string) bool {
+	if t.TransactionType != TxnTypeSmartContract || t.ClientID != minerID {
+		return false
+	}
+	var smartContractData smartContractTransactionData
+	if err := json.Unmarshal([]byte(t.TransactionData), &smartContractData); err != nil {
+		return false
+	}
+	return protocolSCFunctions[smartContractData.FunctionName]
+}
+
 // ValidateFee - Validate fee
 func (t *Transaction) ValidateFee() error {
 	if t.TransactionData != "" {
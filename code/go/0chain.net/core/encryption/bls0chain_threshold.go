@@ -1,6 +1,8 @@
 package encryption
 
 import (
+	"encoding/hex"
+	"errors"
 	"fmt"
 
 	"github.com/herumi/bls/ffi/go/bls"
@@ -111,3 +113,28 @@ func (rec BLS0ChainReconstruction) Reconstruct() (string, error) {
 
 	return s.SerializeToHexStr(), nil
 }
+
+//BLS0RecoverPublicKey - recover the group public key from T threshold public key shares
+func BLS0RecoverPublicKey(shares []ThresholdSignatureScheme) (string, error) {
+	var (
+		pks = make([]bls.PublicKey, 0, len(shares))
+		ids = make([]bls.ID, 0, len(shares))
+	)
+	for _, tss := range shares {
+		b0tss, ok := tss.(*BLS0ChainThresholdScheme)
+		if !ok {
+			return "", ErrInvalidSignatureScheme
+		}
+		if b0tss.pubKey == nil {
+			return "", errors.New("public key share is not set")
+		}
+		pks = append(pks, *b0tss.pubKey)
+		ids = append(ids, b0tss.id)
+	}
+
+	var pk bls.PublicKey
+	if err := pk.Recover(pks, ids); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(pk.Serialize()), nil
+}
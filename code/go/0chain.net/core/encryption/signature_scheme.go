@@ -155,6 +155,16 @@ func GetReconstructSignatureScheme(sigScheme string, t, n int) ReconstructSignat
 	}
 }
 
+//RecoverThresholdPublicKey - recover the group public key from T threshold public key shares
+func RecoverThresholdPublicKey(sigScheme string, shares []ThresholdSignatureScheme) (string, error) {
+	switch sigScheme {
+	case SignatureSchemeBls0chain:
+		return BLS0RecoverPublicKey(shares)
+	default:
+		return "", ErrInvalidSignatureScheme
+	}
+}
+
 //GetRawHash - given a hash interface (raw hash, hex string), return the raw hash
 func GetRawHash(hash interface{}) ([]byte, error) {
 	switch hashImpl := hash.(type) {
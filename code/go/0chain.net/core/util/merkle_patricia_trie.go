@@ -43,6 +43,14 @@ func CloneMPT(mpt MerklePatriciaTrieI) *MerklePatriciaTrie {
 	return clone
 }
 
+//NewLevelMPT - create a MPT on top of the given one keeping the changes in
+// memory, the changes are merged to the given MPT with MergeMPTChanges or
+// discarded with the level MPT
+func NewLevelMPT(mpt MerklePatriciaTrieI) *MerklePatriciaTrie {
+	db := NewLevelNodeDB(NewMemoryNodeDB(), mpt.GetNodeDB(), false)
+	return NewMerklePatriciaTrie(db, mpt.GetVersion(), mpt.GetRoot())
+}
+
 /*SetNodeDB - implement interface */
 func (mpt *MerklePatriciaTrie) SetNodeDB(ndb NodeDB) {
 	mpt.mutex.Lock()
@@ -64,20 +64,27 @@ func SetupM2MSenders() {
 
 /*SetupM2MReceivers - setup receivers for miner to miner communication */
 func SetupM2MReceivers(c node.Chainer) {
+	// relay the messages disseminated by gossip or structured relay to miners
+	options := &node.ReceiveOptions{RelayPool: func() *node.Pool {
+		if mb := GetMinerChain().GetCurrentMagicBlock(); mb != nil {
+			return mb.Miners
+		}
+		return nil
+	}}
 	http.HandleFunc("/v1/_m2m/round/vrf_share",
-		common.N2NRateLimit(node.ToN2NReceiveEntityHandler(VRFShareHandler, nil)))
+		common.N2NRateLimit(node.ToN2NReceiveEntityHandler(VRFShareHandler, options)))
 	http.HandleFunc("/v1/_m2m/block/verification_ticket",
 		common.N2NRateLimit(
 			node.StopOnBlockSyncingHandler(c,
 				node.ToN2NReceiveEntityHandler(
-					VerificationTicketReceiptHandler, nil))))
+					VerificationTicketReceiptHandler, options))))
 	http.HandleFunc("/v1/_m2m/block/verify",
 		common.N2NRateLimit(
 			node.ToN2NReceiveEntityHandler(
 				memorystore.WithConnectionEntityJSONHandler(
-					VerifyBlockHandler, datastore.GetEntityMetadata("block")), nil)))
+					VerifyBlockHandler, datastore.GetEntityMetadata("block")), options)))
 	http.HandleFunc("/v1/_m2m/block/notarization",
-		common.N2NRateLimit(node.ToN2NReceiveEntityHandler(NotarizationReceiptHandler, nil)))
+		common.N2NRateLimit(node.ToN2NReceiveEntityHandler(NotarizationReceiptHandler, options)))
 	http.HandleFunc("/v1/_m2m/block/notarized_block",
 		common.N2NRateLimit(node.ToN2NReceiveEntityHandler(NotarizedBlockHandler, nil)))
 }
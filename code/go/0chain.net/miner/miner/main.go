@@ -193,12 +193,14 @@ func main() {
 		// No WriteTimeout setup to enable pprof
 		server = &http.Server{
 			Addr:           address,
+			Handler:        node.StreamServerHandler(http.DefaultServeMux),
 			ReadTimeout:    30 * time.Second,
 			MaxHeaderBytes: 1 << 20,
 		}
 	} else {
 		server = &http.Server{
 			Addr:           address,
+			Handler:        node.StreamServerHandler(http.DefaultServeMux),
 			ReadTimeout:    30 * time.Second,
 			WriteTimeout:   30 * time.Second,
 			MaxHeaderBytes: 1 << 20,
@@ -270,7 +272,7 @@ func main() {
 
 	go func() {
 		logging.Logger.Info("Ready to listen to the requests")
-		log.Fatal(server.ListenAndServe())
+		log.Fatal(node.ListenAndServe(server))
 	}()
 
 	go mc.RegisterClient()
@@ -20,6 +20,8 @@ import (
 	"0chain.net/core/datastore"
 
 	"0chain.net/core/logging"
+	"0chain.net/smartcontract/dbs/event"
+	"0chain.net/smartcontract/storagesc"
 	"go.uber.org/zap"
 )
 
@@ -57,12 +59,14 @@ func (mc *Chain) GenerateBlock(ctx context.Context, b *block.Block,
 		count            int32
 		roundMismatch    bool
 		roundTimeout     bool
+		scTimeout        bool
 		failedStateCount int32
 		byteSize         int64
 		txnMap           = make(map[datastore.Key]bool, mc.BlockSize)
 	)
 
-	var txnProcessor = func(ctx context.Context, txn *transaction.Transaction) bool {
+	// txnCheck returns true if the transaction can be added to the block
+	var txnCheck = func(ctx context.Context, txn *transaction.Transaction) bool {
 		if _, ok := txnMap[txn.GetKey()]; ok {
 			return false
 		}
@@ -88,7 +92,11 @@ func (mc *Chain) GenerateBlock(ctx context.Context, b *block.Block,
 			}
 			return false
 		}
-		events, err := mc.UpdateState(ctx, b, txn)
+		return true
+	}
+	// txnApplied adds the transaction to the block if the state is updated
+	var txnApplied = func(txn *transaction.Transaction, events []event.Event, err error) bool {
+		var debugTxn = txn.DebugTxn()
 		b.Events = append(b.Events, events...)
 		if err != nil {
 			if debugTxn {
@@ -118,6 +126,52 @@ func (mc *Chain) GenerateBlock(ctx context.Context, b *block.Block,
 		idx++
 		return true
 	}
+	// checkSCTimeout stops adding transactions to the block once a smart
+	// contract execution takes longer than the timeout; the result is kept,
+	// with the gas metering the execution is limited by gas only
+	var checkSCTimeout = func(txn *transaction.Transaction, elapsed time.Duration) {
+		if txn.TransactionType == transaction.TxnTypeSmartContract &&
+			elapsed > mc.SmartContractTimeout {
+			logging.Logger.Error("generate block (smart contract timeout)",
+				zap.String("txn", txn.Hash), zap.Duration("duration", elapsed))
+			scTimeout = true
+		}
+	}
+	var txnProcessor = func(ctx context.Context, txn *transaction.Transaction) bool {
+		if !txnCheck(ctx, txn) {
+			return false
+		}
+		var ts = time.Now()
+		events, err := mc.UpdateState(ctx, b, txn)
+		checkSCTimeout(txn, time.Since(ts))
+		return txnApplied(txn, events, err)
+	}
+	var blockFull = func() bool {
+		return idx >= mc.BlockSize || byteSize >= mc.MaxByteSize
+	}
+	// the transactions executed in parallel, marked in the txnMap until
+	// they are applied
+	var batch []*transaction.Transaction
+	var flushBatch = func(ctx context.Context) {
+		if len(batch) == 0 {
+			return
+		}
+		var executed int
+		mc.UpdateStateParallel(ctx, b, batch,
+			func(txn *transaction.Transaction, events []event.Event, err error, elapsed time.Duration) bool {
+				executed++
+				checkSCTimeout(txn, elapsed)
+				if !txnApplied(txn, events, err) {
+					delete(txnMap, txn.GetKey())
+				}
+				return !blockFull() && !scTimeout
+			})
+		// the rest of the batch is not applied
+		for _, txn := range batch[executed:] {
+			delete(txnMap, txn.GetKey())
+		}
+		batch = batch[:0]
+	}
 	var roundTimeoutCount = mc.GetRoundTimeoutCount()
 	var txnIterHandler = func(ctx context.Context, qe datastore.CollectionEntity) bool {
 		count++
@@ -134,7 +188,33 @@ func (mc *Chain) GenerateBlock(ctx context.Context, b *block.Block,
 			logging.Logger.Error("generate block (invalid entity)", zap.Any("entity", qe))
 			return true
 		}
+		if mc.ParallelTxnsBatchSize > 1 {
+			if !txnCheck(ctx, txn) {
+				return true
+			}
+			txnMap[txn.GetKey()] = true
+			batch = append(batch, txn)
+			if len(batch) < mc.ParallelTxnsBatchSize && int32(len(batch)) < mc.BlockSize-idx {
+				return true
+			}
+			flushBatch(ctx)
+			if scTimeout {
+				return false
+			}
+			if blockFull() {
+				logging.Logger.Error("generate block (too big block size)",
+					zap.Int32("idx", idx),
+					zap.Int64("byte size", byteSize),
+					zap.Int32("count", count),
+					zap.Int("txns", len(b.Txns)))
+				return false
+			}
+			return true
+		}
 		if txnProcessor(ctx, txn) {
+			if scTimeout {
+				return false
+			}
 			if idx >= mc.BlockSize || byteSize >= mc.MaxByteSize {
 				logging.Logger.Error("generate block (too big block size)",
 					zap.Bool("idx >= block size", idx >= mc.BlockSize),
@@ -160,6 +240,7 @@ func (mc *Chain) GenerateBlock(ctx context.Context, b *block.Block,
 	collectionName := txn.GetCollectionName()
 	logging.Logger.Info("generate block starting iteration", zap.Int64("round", b.Round), zap.String("prev_block", b.PrevHash), zap.String("prev_state_hash", util.ToHex(b.PrevBlock.ClientStateHash)))
 	err := transactionEntityMetadata.GetStore().IterateCollection(ctx, transactionEntityMetadata, collectionName, txnIterHandler)
+	flushBatch(ctx)
 	if len(invalidTxns) > 0 {
 		logging.Logger.Info("generate block (found txns very old)", zap.Any("round", b.Round), zap.Int("num_invalid_txns", len(invalidTxns)))
 		go mc.deleteTxns(invalidTxns) // OK to do in background
@@ -180,7 +261,7 @@ func (mc *Chain) GenerateBlock(ctx context.Context, b *block.Block,
 	}
 	blockSize := idx
 	var reusedTxns int32
-	if blockSize < mc.BlockSize && byteSize < mc.MaxByteSize && mc.ReuseTransactions {
+	if blockSize < mc.BlockSize && byteSize < mc.MaxByteSize && mc.ReuseTransactions && !scTimeout {
 		blocks := mc.GetUnrelatedBlocks(10, b)
 		rcount := 0
 		for _, ub := range blocks {
@@ -194,12 +275,12 @@ func (mc *Chain) GenerateBlock(ctx context.Context, b *block.Block,
 					}
 				}
 				if txnProcessor(ctx, rtxn) {
-					if idx == mc.BlockSize || byteSize >= mc.MaxByteSize {
+					if scTimeout || idx == mc.BlockSize || byteSize >= mc.MaxByteSize {
 						break
 					}
 				}
 			}
-			if idx == mc.BlockSize || byteSize >= mc.MaxByteSize {
+			if scTimeout || idx == mc.BlockSize || byteSize >= mc.MaxByteSize {
 				break
 			}
 		}
@@ -240,7 +321,8 @@ func (mc *Chain) GenerateBlock(ctx context.Context, b *block.Block,
 	}
 
 	if mc.SmartContractSettingUpdatePeriod != 0 &&
-		b.Round%mc.SmartContractSettingUpdatePeriod == 0 {
+		b.Round%mc.SmartContractSettingUpdatePeriod == 0 ||
+		storagesc.IsSettingsActivationRound(b.ClientState, b.Round) {
 		err = mc.processTxn(ctx, mc.storageScCommitSettingChangesTx(b), b, clients)
 		if err != nil {
 			return err
@@ -60,15 +60,25 @@ func (mc *Chain) sendDKGShare(ctx context.Context, to string) (err error) {
 		return common.NewErrorf("send_dkg_share", "could not found sec share of node id: %s", to)
 	}
 
-	var state = crpc.Client().State()
+	var (
+		state = crpc.Client().State()
+		sent  string
+	)
 	switch nodeID := n.GetKey(); {
 	case state.Shares.IsGood(state, nodeID):
-		params.Add("secret_share", secShare.GetHexString())
+		sent = secShare.GetHexString()
 	case state.Shares.IsBad(state, nodeID):
-		params.Add("secret_share", revertString(secShare.GetHexString()))
+		sent = revertString(secShare.GetHexString())
 	default:
 		return common.NewError("failed to send DKG share", "skipped by tests")
 	}
+	params.Add("secret_share", sent)
+
+	var shareSign string
+	if shareSign, err = mc.signDKGShare(n.ID, sent); err != nil {
+		return common.NewErrorf("send_dkg_share", "signing share: %v", err)
+	}
+	params.Add("share_sign", shareSign)
 
 	var handler = func(ctx context.Context, entity datastore.Entity) (
 		_ interface{}, _ error) {
@@ -308,9 +318,10 @@ func SignShareRequestHandler(ctx context.Context, r *http.Request) (
 	resp interface{}, err error) {
 
 	var (
-		nodeID   = r.Header.Get(node.HeaderNodeID)
-		secShare = r.FormValue("secret_share")
-		mc       = GetMinerChain()
+		nodeID    = r.Header.Get(node.HeaderNodeID)
+		secShare  = r.FormValue("secret_share")
+		shareSign = r.FormValue("share_sign")
+		mc        = GetMinerChain()
 	)
 
 	mc.viewChangeProcess.Lock()
@@ -334,6 +345,9 @@ func SignShareRequestHandler(ctx context.Context, r *http.Request) (
 
 	if err = share.SetHexString(secShare); err != nil {
 		logging.Logger.Error("failed to set hex string", zap.Any("error", err))
+		if mpk, ok := mpks[nodeID]; ok {
+			mc.reportInvalidDKGShare(nodeID, mpk.Mpk, secShare, shareSign)
+		}
 		return nil, common.NewErrorf("sign_share",
 			"setting hex string: %v", err)
 	}
@@ -349,6 +363,7 @@ func SignShareRequestHandler(ctx context.Context, r *http.Request) (
 	if !mc.viewChangeProcess.viewChangeDKG.ValidateShare(mpk, share) {
 		logging.Logger.Error("failed to verify dkg share", zap.Any("share", secShare),
 			zap.Any("node_id", nodeID))
+		mc.reportInvalidDKGShare(nodeID, mpks[nodeID].Mpk, secShare, shareSign)
 		return nil, common.NewError("sign_share", "failed to verify DKG share")
 	}
 
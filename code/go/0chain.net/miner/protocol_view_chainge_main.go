@@ -52,6 +52,12 @@ func (mc *Chain) sendDKGShare(ctx context.Context, to string) (err error) {
 
 	params.Add("secret_share", secShare.GetHexString())
 
+	var shareSign string
+	if shareSign, err = mc.signDKGShare(n.ID, secShare.GetHexString()); err != nil {
+		return common.NewErrorf("send_dkg_share", "signing share: %v", err)
+	}
+	params.Add("share_sign", shareSign)
+
 	var handler = func(ctx context.Context, entity datastore.Entity) (
 		_ interface{}, _ error) {
 
@@ -240,9 +246,10 @@ func SignShareRequestHandler(ctx context.Context, r *http.Request) (
 	resp interface{}, err error) {
 
 	var (
-		nodeID   = r.Header.Get(node.HeaderNodeID)
-		secShare = r.FormValue("secret_share")
-		mc       = GetMinerChain()
+		nodeID    = r.Header.Get(node.HeaderNodeID)
+		secShare  = r.FormValue("secret_share")
+		shareSign = r.FormValue("share_sign")
+		mc        = GetMinerChain()
 	)
 
 	mc.viewChangeProcess.Lock()
@@ -270,6 +277,9 @@ func SignShareRequestHandler(ctx context.Context, r *http.Request) (
 
 	if err = share.SetHexString(secShare); err != nil {
 		logging.Logger.Error("failed to set hex string", zap.Any("error", err))
+		if mpk, ok := mpks[nodeID]; ok {
+			mc.reportInvalidDKGShare(nodeID, mpk.Mpk, secShare, shareSign)
+		}
 		return nil, common.NewErrorf("sign_share",
 			"setting hex string: %v", err)
 	}
@@ -279,6 +289,7 @@ func SignShareRequestHandler(ctx context.Context, r *http.Request) (
 	if !mc.viewChangeProcess.viewChangeDKG.ValidateShare(mpk, share) {
 		logging.Logger.Error("failed to verify dkg share", zap.Any("share", secShare),
 			zap.Any("node_id", nodeID))
+		mc.reportInvalidDKGShare(nodeID, mpks[nodeID].Mpk, secShare, shareSign)
 		return nil, common.NewError("sign_share", "failed to verify DKG share")
 	}
 
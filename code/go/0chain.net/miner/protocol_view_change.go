@@ -35,6 +35,7 @@ const (
 	scNameContributeMpk = "contributeMpk"
 	scNamePublishShares = "shareSignsOrShares"
 	scNameWait          = "wait"
+	scNameEvidence      = "submit_evidence"
 	// REST API requests
 	scRestAPIGetDKGMiners  = "/getDkgList"
 	scRestAPIGetMinersMPKS = "/getMpksList"
@@ -484,6 +485,73 @@ func (mc *Chain) getNodeSij(nodeID hbls.ID) (*hbls.SecretKey, bool) {
 	return &k, true
 }
 
+// getSelfMpk returns MPK of the node in hex
+func (mc *Chain) getSelfMpk() (mpk []string) {
+	mc.viewChangeProcess.Lock()
+	defer mc.viewChangeProcess.Unlock()
+
+	if mc.viewChangeProcess.viewChangeDKG == nil {
+		return
+	}
+	for _, pk := range mc.viewChangeProcess.viewChangeDKG.GetMPKs() {
+		mpk = append(mpk, pk.GetHexString())
+	}
+	return
+}
+
+// signDKGShare signs a DKG share sent to given node along with the MPK of
+// the node, the signature makes an invalid share provable
+func (mc *Chain) signDKGShare(to, share string) (string, error) {
+	return node.Self.Sign(minersc.DKGShareMessage(mc.getSelfMpk(), to, share))
+}
+
+// reportInvalidDKGShare submits evidence of an invalid DKG share received
+// from given node, if the share is signed by the node
+func (mc *Chain) reportInvalidDKGShare(sender string, mpk []string, share,
+	sign string) {
+
+	var n = node.GetNode(sender)
+	if n == nil || sign == "" {
+		return
+	}
+
+	var (
+		selfNode    = node.Self.Underlying()
+		selfNodeKey = selfNode.GetKey()
+		ss          = chain.GetServerChain().GetSignatureScheme()
+	)
+	if err := ss.SetPublicKey(n.PublicKey); err != nil {
+		return
+	}
+	var msg = minersc.DKGShareMessage(mpk, selfNodeKey, share)
+	if ok, err := ss.Verify(sign, msg); err != nil || !ok {
+		return
+	}
+
+	var data = new(httpclientutil.SmartContractTxnData)
+	data.Name = scNameEvidence
+	data.InputArgs = minersc.NewDKGShareEvidence(&minersc.DKGShare{
+		Sender:   sender,
+		Receiver: selfNodeKey,
+		Share:    share,
+		Sign:     sign,
+	})
+
+	var tx = httpclientutil.NewTransactionEntity(selfNodeKey, mc.ID,
+		selfNode.PublicKey)
+	tx.ToClientID = minersc.ADDRESS
+
+	var urls = mc.GetCurrentMagicBlock().Miners.N2NURLs()
+	go func() {
+		err := httpclientutil.SendSmartContractTxn(tx, minersc.ADDRESS, 0, 0,
+			data, urls)
+		if err != nil {
+			logging.Logger.Error("submitting DKG share evidence",
+				zap.String("sender", sender), zap.Error(err))
+		}
+	}()
+}
+
 func (mc *Chain) setSecretShares(shareOrSignSuccess map[string]*bls.DKGKeyShare) {
 
 	mc.viewChangeProcess.Lock()
@@ -15,6 +15,8 @@ import (
 	"0chain.net/chaincore/chain"
 	"0chain.net/chaincore/diagnostics"
 	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/persistencestore"
 )
 
 /* SetupHandlers sets up the necessary API end points */
@@ -22,6 +24,7 @@ func SetupHandlers() {
 	http.HandleFunc("/v1/block/get", common.UserRateLimit(common.ToJSONResponse(BlockHandler)))
 	http.HandleFunc("/v1/block/magic/get", common.UserRateLimit(common.ToJSONResponse(MagicBlockHandler)))
 	http.HandleFunc("/v1/transaction/get/confirmation", common.UserRateLimit(common.ToJSONResponse(TransactionConfirmationHandler)))
+	http.HandleFunc("/v1/transaction/trace", common.UserRateLimit(common.ToJSONResponse(TransactionTraceHandler)))
 	http.HandleFunc("/v1/chain/get/stats", common.UserRateLimit(common.ToJSONResponse(ChainStatsHandler)))
 	http.HandleFunc("/_chain_stats", common.UserRateLimit(ChainStatsWriter))
 	http.HandleFunc("/_health_check", common.UserRateLimit(HealthCheckWriter))
@@ -84,6 +87,19 @@ func BlockHandler(ctx context.Context, r *http.Request) (interface{}, error) {
 	return chain.GetBlockResponse(b, parts)
 }
 
+// TransactionTraceHandler - replays the transaction against the state of its
+// block recording the state operations of the smart contract
+func TransactionTraceHandler(ctx context.Context, r *http.Request) (interface{}, error) {
+	hash := r.FormValue("hash")
+	if hash == "" {
+		return nil, common.InvalidRequest("transaction hash (parameter hash) is required")
+	}
+	transactionConfirmationEntityMetadata := datastore.GetEntityMetadata("txn_confirmation")
+	ctx = persistencestore.WithEntityConnection(ctx, transactionConfirmationEntityMetadata)
+	defer persistencestore.Close(ctx)
+	return GetSharderChain().GetTransactionTrace(ctx, hash)
+}
+
 /*MagicBlockHandler - a handler to respond to magic block queries */
 func MagicBlockHandler(ctx context.Context, r *http.Request) (interface{}, error) {
 	magicBlockNumber := r.FormValue("magic_block_number")
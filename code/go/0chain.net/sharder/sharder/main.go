@@ -114,6 +114,7 @@ func main() {
 
 	config.Configuration.ChainID = viper.GetString("server_chain.id")
 	transaction.SetTxnTimeout(int64(viper.GetInt("server_chain.transaction.timeout")))
+	transaction.SetTxnFee(viper.GetInt64("server_chain.transaction.min_fee"))
 
 	reader, err = os.Open(*keysFile)
 	if err != nil {
@@ -240,12 +241,14 @@ func main() {
 		// No WriteTimeout setup to enable pprof
 		server = &http.Server{
 			Addr:           address,
+			Handler:        node.StreamServerHandler(http.DefaultServeMux),
 			ReadTimeout:    30 * time.Second,
 			MaxHeaderBytes: 1 << 20,
 		}
 	} else {
 		server = &http.Server{
 			Addr:           address,
+			Handler:        node.StreamServerHandler(http.DefaultServeMux),
 			ReadTimeout:    30 * time.Second,
 			WriteTimeout:   30 * time.Second,
 			MaxHeaderBytes: 1 << 20,
@@ -303,7 +306,7 @@ func main() {
 }
 
 func Listen(server *http.Server) {
-	var err = server.ListenAndServe()
+	var err = node.ListenAndServe(server)
 	if err != nil && err != http.ErrServerClosed {
 		log.Fatal(err) // fatal listening error
 	}
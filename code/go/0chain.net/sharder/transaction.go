@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"0chain.net/chaincore/block"
+	"0chain.net/chaincore/chain"
 	"0chain.net/core/common"
 	metrics "github.com/rcrowley/go-metrics"
 	"go.uber.org/zap"
@@ -95,6 +96,27 @@ func (sc *Chain) GetTransactionConfirmation(ctx context.Context, hash string) (*
 	return confirmation, nil
 }
 
+// GetTransactionTrace replays the transaction against the state of the
+// block it's included in, the previous block state must be available
+func (sc *Chain) GetTransactionTrace(ctx context.Context, hash string) (
+	*chain.TransactionTrace, error) {
+
+	confirmation, err := sc.GetTransactionConfirmation(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	b, err := sc.GetBlockFromHash(ctx, confirmation.BlockHash, confirmation.Round)
+	if err != nil {
+		return nil, err
+	}
+	prev, err := sc.GetBlockFromHash(ctx, b.PrevHash, b.Round-1)
+	if err != nil {
+		return nil, common.NewError("trace_transaction",
+			"previous block not available: "+err.Error())
+	}
+	return sc.TraceTransaction(ctx, b, prev, hash)
+}
+
 /*StoreTransactions - persists given list of transactions*/
 func (sc *Chain) StoreTransactions(b *block.Block) error {
 	var sTxns = make([]datastore.Entity, len(b.Txns))
@@ -255,6 +255,9 @@ func (sc *Chain) SharderHealthCheck(ctx context.Context) {
 			txn := httpclientutil.NewTransactionEntity(selfNode.GetKey(), sc.ID, selfNode.PublicKey)
 			scData := &httpclientutil.SmartContractTxnData{}
 			scData.Name = minerScSharderHealthCheck
+			scData.InputArgs = &minersc.SharderHealthCheckInput{
+				StoredRound: sc.GetLatestFinalizedBlock().Round,
+			}
 
 			txn.ToClientID = minersc.ADDRESS
 			txn.PublicKey = selfNode.PublicKey
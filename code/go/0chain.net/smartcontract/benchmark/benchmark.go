@@ -166,6 +166,11 @@ const (
 	InterestPoolMinLock       = SmartContract + InterestPoolSC + "min_lock"
 	InterestPoolMinLockPeriod = SmartContract + InterestPoolSC + "min_lock_period"
 	InterestPoolMaxMint       = SmartContract + InterestPoolSC + "max_mint"
+	InterestPoolApr           = SmartContract + InterestPoolSC + "apr"
+	InterestPoolAprCurve      = SmartContract + InterestPoolSC + "apr_curve"
+	InterestPoolAprEpoch      = SmartContract + InterestPoolSC + "apr_epoch"
+	InterestPoolTotalSupply   = SmartContract + InterestPoolSC + "total_supply"
+	InterestPoolEarlyPenalty  = SmartContract + InterestPoolSC + "early_unlock_penalty"
 
 	VestingMinLock         = SmartContract + VestingSc + "min_lock"
 	VestingMaxDestinations = SmartContract + VestingSc + "max_destinations"
@@ -178,6 +183,12 @@ const (
 	MinBurnAmount      = SmartContract + Zcn + "min_burn_amount"
 	MinStakeAmount     = SmartContract + Zcn + "min_stake_amount"
 	BurnAddress        = SmartContract + Zcn + "burn_address"
+	MintFee            = SmartContract + Zcn + "mint_fee"
+	BurnFee            = SmartContract + Zcn + "burn_fee"
+	UnbondingPeriod    = SmartContract + Zcn + "unbonding_period"
+	BurnTicketLifetime = SmartContract + Zcn + "burn_ticket_lifetime"
+	SlashRate          = SmartContract + Zcn + "slash_rate"
+	SlashReporterShare = SmartContract + Zcn + "slash_reporter_share"
 )
 
 func (s Source) String() string {
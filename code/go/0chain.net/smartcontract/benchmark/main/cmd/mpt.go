@@ -130,6 +130,10 @@ func setUpMpt(
 	log.Println("added allocations")
 	storagesc.SaveMockStakePools(stakePools, balances)
 	log.Println("added stake pools")
+	storagesc.AddMockBlobberAggregates(validators, balances)
+	log.Println("added blobber aggregates")
+	storagesc.AddMockShareOffers(balances)
+	log.Println("added share offers")
 	miners := minersc.AddMockNodes(clients, minersc.NodeTypeMiner, balances)
 	log.Println("added miners")
 	sharders := minersc.AddMockNodes(clients, minersc.NodeTypeSharder, balances)
@@ -0,0 +1,5 @@
+package smartcontract
+
+// BurnAddress is address tokens are burned to. Nobody has a key of the
+// address, thus tokens transferred to it are out of circulation.
+const BurnAddress = "0000000000000000000000000000000000000000000000000000000000000000"
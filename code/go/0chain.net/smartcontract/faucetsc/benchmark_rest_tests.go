@@ -64,6 +64,10 @@ func BenchmarkRestTests(
 			name:     "faucet_rest.getConfig",
 			endpoint: fsc.getConfigHandler,
 		},
+		{
+			name:     "faucet_rest.getAccessList",
+			endpoint: fsc.getAccessListHandler,
+		},
 	}
 	var testsI []bk.BenchTestI
 	for _, test := range tests {
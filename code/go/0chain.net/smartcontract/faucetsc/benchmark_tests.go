@@ -51,6 +51,8 @@ func (bt BenchTest) Run(balances cstate.StateContextI, b *testing.B) error {
 		_, err = fsc.pour(bt.Transaction(), bt.input, balances, gn)
 	case "refill":
 		_, err = fsc.refill(bt.Transaction(), balances, gn)
+	case "updateAccessList":
+		_, err = fsc.updateAccessList(bt.Transaction(), bt.input, balances, gn)
 	default:
 		b.Errorf("unknown endpoint" + bt.endpoint)
 	}
@@ -66,7 +68,7 @@ func BenchmarkTests(
 			name:     "faucet.update-settings",
 			endpoint: "updateSettings",
 			txn: &transaction.Transaction{
-				ClientID: owner,
+				ClientID: sc.GovernanceAddress,
 				Value:    3,
 			},
 			input: (&sc.StringMap{
@@ -89,6 +91,17 @@ func BenchmarkTests(
 			},
 			input: nil,
 		},
+		{
+			name:     "faucet.update-access-list",
+			endpoint: "updateAccessList",
+			txn: &transaction.Transaction{
+				ClientID: sc.GovernanceAddress,
+			},
+			input: (&accessListRequest{
+				Allow: []string{data.Clients[1]},
+				Deny:  []string{data.Clients[2]},
+			}).encode(),
+		},
 		{
 			name:     "faucet.refill",
 			endpoint: "refill",
@@ -16,6 +16,8 @@ const (
 	GlobalLimit
 	IndividualReset
 	GlobalReset
+	PowDifficulty
+	TokenIssuerKey
 )
 
 var (
@@ -26,6 +28,8 @@ var (
 		"global_limit",
 		"individual_reset",
 		"global_rest",
+		"pow_difficulty",
+		"token_issuer_key",
 	}
 )
 
@@ -36,6 +40,12 @@ type FaucetConfig struct {
 	GlobalLimit     state.Balance `json:"global_limit"`
 	IndividualReset time.Duration `json:"individual_reset"`
 	GlobalReset     time.Duration `json:"global_rest"`
+	// PowDifficulty is number of leading zero bits of the proof-of-work
+	// required to pour, zero disables the proof-of-work.
+	PowDifficulty int `json:"pow_difficulty"`
+	// TokenIssuerKey is public key of the off-chain issuer of the pour
+	// tokens, empty disables the tokens.
+	TokenIssuerKey string `json:"token_issuer_key"`
 }
 
 // configurations from sc.yaml
@@ -47,5 +57,7 @@ func getConfig() (conf *FaucetConfig) {
 	conf.GlobalLimit = state.Balance(config.SmartContractConfig.GetFloat64("smart_contracts.faucetsc.global_limit") * 1e10)
 	conf.IndividualReset = config.SmartContractConfig.GetDuration("smart_contracts.faucetsc.individual_reset")
 	conf.GlobalReset = config.SmartContractConfig.GetDuration("smart_contracts.faucetsc.global_reset")
+	conf.PowDifficulty = config.SmartContractConfig.GetInt("smart_contracts.faucetsc.pow_difficulty")
+	conf.TokenIssuerKey = config.SmartContractConfig.GetString("smart_contracts.faucetsc.token_issuer_key")
 	return
 }
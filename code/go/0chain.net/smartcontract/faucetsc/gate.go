@@ -0,0 +1,287 @@
+package faucetsc
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/bits"
+	"sort"
+
+	c_state "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+	sc "0chain.net/smartcontract"
+)
+
+// Number of rounds a proof-of-work is valid for.
+const powRoundWindow = 100
+
+// Maximal proof-of-work difficulty, number of leading zero bits of the hash.
+const maxPowDifficulty = 64
+
+// pourRequest is an optional input of the pour. When the gate is enabled it
+// carries either a proof-of-work or a token of the off-chain issuer.
+type pourRequest struct {
+	// Proof-of-work over (client ID, round, nonce).
+	Round int64 `json:"round,omitempty"`
+	Nonce int64 `json:"nonce,omitempty"`
+	// Token signed by the issuer.
+	Token *pourToken `json:"token,omitempty"`
+}
+
+func (pr *pourRequest) decode(input []byte) error {
+	if len(input) == 0 {
+		return nil
+	}
+	return json.Unmarshal(input, pr)
+}
+
+func (pr *pourRequest) encode() []byte {
+	buff, _ := json.Marshal(pr)
+	return buff
+}
+
+// pourToken is a permission to pour for a client issued off-chain, for
+// example after a captcha is solved. A token can be used once, the tokens
+// of a client are used in order of expiration.
+type pourToken struct {
+	Expiry    common.Timestamp `json:"expiry"`
+	Signature string           `json:"signature"`
+}
+
+// pourTokenHash is hash of the token signed by the issuer
+func pourTokenHash(clientID string, expiry common.Timestamp) string {
+	return encryption.Hash(fmt.Sprintf("%s:%d", clientID, expiry))
+}
+
+// powHash is hash of the proof-of-work
+func powHash(clientID string, round, nonce int64) []byte {
+	return encryption.RawHash(fmt.Sprintf("%s:%d:%d", clientID, round, nonce))
+}
+
+// leadingZeros returns number of leading zero bits of the hash
+func leadingZeros(hash []byte) (n int) {
+	for _, b := range hash {
+		if b != 0 {
+			return n + bits.LeadingZeros8(b)
+		}
+		n += 8
+	}
+	return
+}
+
+// gateEnabled returns true if the pours require a proof-of-work or a token
+func (gn *GlobalNode) gateEnabled() bool {
+	return gn.PowDifficulty > 0 || gn.TokenIssuerKey != ""
+}
+
+// checkGate verifies the pour request passes the enabled gate and marks
+// the proof as used by the client
+func (gn *GlobalNode) checkGate(
+	t *transaction.Transaction,
+	pr *pourRequest,
+	un *UserNode,
+	balances c_state.StateContextI,
+) error {
+	if pr.Token != nil && gn.TokenIssuerKey != "" {
+		return gn.checkToken(t, pr.Token, un, balances)
+	}
+	if pr.Token == nil && gn.PowDifficulty > 0 {
+		return gn.checkPow(t.ClientID, pr, un, balances)
+	}
+	return common.NewError("invalid_request", "proof-of-work or token required to pour")
+}
+
+func (gn *GlobalNode) checkPow(
+	clientID string,
+	pr *pourRequest,
+	un *UserNode,
+	balances c_state.StateContextI,
+) error {
+	var round = balances.GetBlock().Round
+	if pr.Round > round || pr.Round+powRoundWindow < round {
+		return common.NewError("invalid_request",
+			fmt.Sprintf("proof-of-work round %d is out of range (%d, %d]",
+				pr.Round, round-powRoundWindow, round))
+	}
+	if pr.Round <= un.PowRound {
+		return common.NewError("invalid_request",
+			"proof-of-work for the round is already used")
+	}
+	if leadingZeros(powHash(clientID, pr.Round, pr.Nonce)) < gn.PowDifficulty {
+		return common.NewError("invalid_request",
+			fmt.Sprintf("proof-of-work difficulty is less than %d", gn.PowDifficulty))
+	}
+	un.PowRound = pr.Round
+	return nil
+}
+
+func (gn *GlobalNode) checkToken(
+	t *transaction.Transaction,
+	token *pourToken,
+	un *UserNode,
+	balances c_state.StateContextI,
+) error {
+	if token.Expiry < t.CreationDate {
+		return common.NewError("invalid_request", "pour token is expired")
+	}
+	if token.Expiry <= un.TokenExpiry {
+		return common.NewError("invalid_request", "pour token is already used")
+	}
+	var scheme = balances.GetSignatureScheme()
+	if err := scheme.SetPublicKey(gn.TokenIssuerKey); err != nil {
+		return common.NewError("invalid_request",
+			"invalid token issuer key: "+err.Error())
+	}
+	ok, err := scheme.Verify(token.Signature, pourTokenHash(t.ClientID, token.Expiry))
+	if err != nil || !ok {
+		return common.NewError("invalid_request", "invalid pour token signature")
+	}
+	un.TokenExpiry = token.Expiry
+	return nil
+}
+
+// AccessList of the faucet managed by the owner. It's used only when the
+// gate is enabled: denied clients can't pour, allowed clients pour without
+// the proof-of-work or the token.
+type AccessList struct {
+	Allow map[datastore.Key]bool `json:"allow"`
+	Deny  map[datastore.Key]bool `json:"deny"`
+}
+
+func newAccessList() *AccessList {
+	return &AccessList{
+		Allow: make(map[datastore.Key]bool),
+		Deny:  make(map[datastore.Key]bool),
+	}
+}
+
+func accessListKey(globalKey string) datastore.Key {
+	return datastore.Key(globalKey + encryption.Hash("access_list"))
+}
+
+func (al *AccessList) Encode() []byte {
+	buff, _ := json.Marshal(al)
+	return buff
+}
+
+func (al *AccessList) Decode(input []byte) error {
+	err := json.Unmarshal(input, al)
+	if al.Allow == nil {
+		al.Allow = make(map[datastore.Key]bool)
+	}
+	if al.Deny == nil {
+		al.Deny = make(map[datastore.Key]bool)
+	}
+	return err
+}
+
+func (al *AccessList) isAllowed(clientID datastore.Key) bool {
+	return al.Allow[clientID]
+}
+
+func (al *AccessList) isDenied(clientID datastore.Key) bool {
+	return al.Deny[clientID]
+}
+
+// accessListRequest changes the access list, a client can't be both allowed
+// and denied: adding to one list removes from the other
+type accessListRequest struct {
+	Allow       []datastore.Key `json:"allow,omitempty"`
+	Deny        []datastore.Key `json:"deny,omitempty"`
+	RemoveAllow []datastore.Key `json:"remove_allow,omitempty"`
+	RemoveDeny  []datastore.Key `json:"remove_deny,omitempty"`
+}
+
+func (alr *accessListRequest) decode(input []byte) error {
+	return json.Unmarshal(input, alr)
+}
+
+func (alr *accessListRequest) encode() []byte {
+	buff, _ := json.Marshal(alr)
+	return buff
+}
+
+func (al *AccessList) update(alr *accessListRequest) {
+	for _, id := range alr.RemoveAllow {
+		delete(al.Allow, id)
+	}
+	for _, id := range alr.RemoveDeny {
+		delete(al.Deny, id)
+	}
+	for _, id := range alr.Allow {
+		delete(al.Deny, id)
+		al.Allow[id] = true
+	}
+	for _, id := range alr.Deny {
+		delete(al.Allow, id)
+		al.Deny[id] = true
+	}
+}
+
+// accessListResponse is sorted access list
+type accessListResponse struct {
+	Allow []datastore.Key `json:"allow"`
+	Deny  []datastore.Key `json:"deny"`
+}
+
+func (al *AccessList) response() *accessListResponse {
+	var resp = &accessListResponse{
+		Allow: make([]datastore.Key, 0, len(al.Allow)),
+		Deny:  make([]datastore.Key, 0, len(al.Deny)),
+	}
+	for id := range al.Allow {
+		resp.Allow = append(resp.Allow, id)
+	}
+	for id := range al.Deny {
+		resp.Deny = append(resp.Deny, id)
+	}
+	sort.Strings(resp.Allow)
+	sort.Strings(resp.Deny)
+	return resp
+}
+
+func (fc *FaucetSmartContract) getAccessList(globalKey string, balances c_state.StateContextI) (*AccessList, error) {
+	al := newAccessList()
+	val, err := balances.GetTrieNode(accessListKey(globalKey))
+	if err == util.ErrValueNotPresent {
+		return al, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := al.Decode(val.Encode()); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return al, nil
+}
+
+func (fc *FaucetSmartContract) updateAccessList(
+	t *transaction.Transaction,
+	inputData []byte,
+	balances c_state.StateContextI,
+	gn *GlobalNode,
+) (string, error) {
+	if !sc.CanUpdateSettings(t.ClientID) {
+		return "", common.NewError("update_access_list", "only the governance can update the access list")
+	}
+
+	var alr accessListRequest
+	if err := alr.decode(inputData); err != nil {
+		return "", common.NewError("update_access_list", "request not formated correctly")
+	}
+
+	al, err := fc.getAccessList(gn.ID, balances)
+	if err != nil {
+		return "", common.NewError("update_access_list", "getting access list: "+err.Error())
+	}
+	al.update(&alr)
+
+	_, err = balances.InsertTrieNode(accessListKey(gn.ID), al)
+	if err != nil {
+		return "", common.NewError("update_access_list", "saving access list: "+err.Error())
+	}
+	return string(al.Encode()), nil
+}
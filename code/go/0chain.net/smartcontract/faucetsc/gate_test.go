@@ -0,0 +1,205 @@
+package faucetsc
+
+import (
+	"testing"
+
+	"0chain.net/chaincore/block"
+	c_state "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+	sc "0chain.net/smartcontract"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testClientID = "client"
+
+// testBalances implements trie access of the state context only
+type testBalances struct {
+	c_state.StateContextI
+	round int64
+	tree  map[datastore.Key]util.Serializable
+	reads int
+}
+
+func newTestBalances(round int64) *testBalances {
+	return &testBalances{round: round, tree: make(map[datastore.Key]util.Serializable)}
+}
+
+func (tb *testBalances) GetBlock() *block.Block {
+	var b = new(block.Block)
+	b.Round = tb.round
+	return b
+}
+
+func (tb *testBalances) GetSignatureScheme() encryption.SignatureScheme {
+	return encryption.NewBLS0ChainScheme()
+}
+
+func (tb *testBalances) GetTrieNode(key datastore.Key) (util.Serializable, error) {
+	tb.reads++
+	if node, ok := tb.tree[key]; ok {
+		return node, nil
+	}
+	return nil, util.ErrValueNotPresent
+}
+
+func (tb *testBalances) InsertTrieNode(key datastore.Key, node util.Serializable) (datastore.Key, error) {
+	tb.tree[key] = node
+	return key, nil
+}
+
+func newTestFaucet() (*FaucetSmartContract, *GlobalNode) {
+	var fc = &FaucetSmartContract{}
+	return fc, &GlobalNode{ID: ADDRESS, FaucetConfig: &FaucetConfig{}}
+}
+
+func pourTxn(creationDate common.Timestamp) *transaction.Transaction {
+	return &transaction.Transaction{
+		ClientID:     testClientID,
+		ToClientID:   ADDRESS,
+		CreationDate: creationDate,
+	}
+}
+
+// findPow returns nonce of the proof-of-work with given number of leading
+// zero bits exactly
+func findPow(clientID string, round int64, zeros int) (nonce int64) {
+	for leadingZeros(powHash(clientID, round, nonce)) != zeros {
+		nonce++
+	}
+	return
+}
+
+func setAccessList(t *testing.T, fc *FaucetSmartContract, gn *GlobalNode,
+	balances *testBalances, alr *accessListRequest) {
+
+	_, err := fc.updateAccessList(&transaction.Transaction{
+		ClientID: sc.GovernanceAddress,
+	}, alr.encode(), balances, gn)
+	require.NoError(t, err)
+}
+
+func Test_leadingZeros(t *testing.T) {
+	require.Equal(t, 0, leadingZeros([]byte{0x80}))
+	require.Equal(t, 7, leadingZeros([]byte{0x01, 0xff}))
+	require.Equal(t, 12, leadingZeros([]byte{0x00, 0x0f}))
+	require.Equal(t, 16, leadingZeros([]byte{0x00, 0x00}))
+}
+
+func TestFaucetSmartContract_checkPourAccess_gateDisabled(t *testing.T) {
+	var (
+		fc, gn   = newTestFaucet()
+		balances = newTestBalances(100)
+	)
+	setAccessList(t, fc, gn, balances, &accessListRequest{
+		Deny: []datastore.Key{testClientID},
+	})
+
+	balances.reads = 0
+	require.NoError(t, fc.checkPourAccess(pourTxn(1), nil, &UserNode{}, balances, gn))
+	require.Zero(t, balances.reads, "the access list is read")
+}
+
+func TestFaucetSmartContract_checkPourAccess_pow(t *testing.T) {
+	var (
+		fc, gn   = newTestFaucet()
+		balances = newTestBalances(200)
+		un       = &UserNode{ID: testClientID}
+	)
+	gn.PowDifficulty = 4
+
+	var pour = func(round, nonce int64) error {
+		var pr = &pourRequest{Round: round, Nonce: nonce}
+		return fc.checkPourAccess(pourTxn(1), pr.encode(), un, balances, gn)
+	}
+
+	require.Error(t, fc.checkPourAccess(pourTxn(1), nil, un, balances, gn))
+	require.Error(t, pour(201, findPow(testClientID, 201, 4)), "future round")
+	require.Error(t, pour(99, findPow(testClientID, 99, 4)), "expired round")
+	require.Error(t, pour(150, findPow(testClientID, 150, 3)), "too easy")
+
+	require.NoError(t, pour(150, findPow(testClientID, 150, 4)))
+	require.EqualValues(t, 150, un.PowRound)
+	require.Error(t, pour(150, findPow(testClientID, 150, 5)), "reused round")
+	require.Error(t, pour(140, findPow(testClientID, 140, 4)), "earlier round")
+	require.NoError(t, pour(151, findPow(testClientID, 151, 6)))
+}
+
+func TestFaucetSmartContract_checkPourAccess_token(t *testing.T) {
+	var (
+		fc, gn   = newTestFaucet()
+		balances = newTestBalances(100)
+		un       = &UserNode{ID: testClientID}
+		issuer   = encryption.NewBLS0ChainScheme()
+		other    = encryption.NewBLS0ChainScheme()
+	)
+	require.NoError(t, issuer.GenerateKeys())
+	require.NoError(t, other.GenerateKeys())
+	gn.TokenIssuerKey = issuer.GetPublicKey()
+
+	var pour = func(now, expiry common.Timestamp,
+		signer encryption.SignatureScheme) error {
+
+		sig, err := signer.Sign(pourTokenHash(testClientID, expiry))
+		require.NoError(t, err)
+		var pr = &pourRequest{Token: &pourToken{Expiry: expiry, Signature: sig}}
+		return fc.checkPourAccess(pourTxn(now), pr.encode(), un, balances, gn)
+	}
+
+	require.Error(t, pour(10, 20, other), "not the issuer")
+	require.Error(t, pour(10, 5, issuer), "expired")
+	require.NoError(t, pour(10, 20, issuer))
+	require.EqualValues(t, 20, un.TokenExpiry)
+	require.Error(t, pour(10, 20, issuer), "reused")
+	require.Error(t, pour(10, 15, issuer), "expires before the used one")
+	require.NoError(t, pour(10, 30, issuer))
+
+	// proof-of-work is not enabled
+	var pr = &pourRequest{Round: 100, Nonce: findPow(testClientID, 100, 0)}
+	require.Error(t, fc.checkPourAccess(pourTxn(10), pr.encode(), un, balances, gn))
+}
+
+func TestFaucetSmartContract_checkPourAccess_accessList(t *testing.T) {
+	var (
+		fc, gn   = newTestFaucet()
+		balances = newTestBalances(100)
+		un       = &UserNode{ID: testClientID}
+	)
+	gn.PowDifficulty = 8
+
+	_, err := fc.updateAccessList(pourTxn(1), (&accessListRequest{
+		Allow: []datastore.Key{testClientID},
+	}).encode(), balances, gn)
+	require.Error(t, err, "only the governance updates the list")
+
+	// allowed clients pour without a proof
+	setAccessList(t, fc, gn, balances, &accessListRequest{
+		Allow: []datastore.Key{testClientID},
+	})
+	require.NoError(t, fc.checkPourAccess(pourTxn(1), nil, un, balances, gn))
+
+	// denying removes the client from the allowed
+	setAccessList(t, fc, gn, balances, &accessListRequest{
+		Deny: []datastore.Key{testClientID},
+	})
+	al, err := fc.getAccessList(gn.ID, balances)
+	require.NoError(t, err)
+	require.Equal(t, &accessListResponse{
+		Allow: []datastore.Key{},
+		Deny:  []datastore.Key{testClientID},
+	}, al.response())
+
+	var pr = &pourRequest{Round: 100, Nonce: findPow(testClientID, 100, 8)}
+	require.Error(t, fc.checkPourAccess(pourTxn(1), pr.encode(), un, balances, gn),
+		"denied even with a proof")
+
+	setAccessList(t, fc, gn, balances, &accessListRequest{
+		RemoveDeny: []datastore.Key{testClientID},
+	})
+	require.Error(t, fc.checkPourAccess(pourTxn(1), nil, un, balances, gn))
+	require.NoError(t, fc.checkPourAccess(pourTxn(1), pr.encode(), un, balances, gn))
+}
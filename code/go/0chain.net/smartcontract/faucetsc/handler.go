@@ -93,6 +93,20 @@ func (fc *FaucetSmartContract) getConfigHandler(
 			Settings[GlobalLimit]:     fmt.Sprintf("%v", float64(faucetConfig.GlobalLimit)/1e10),
 			Settings[IndividualReset]: fmt.Sprintf("%v", faucetConfig.IndividualReset),
 			Settings[GlobalReset]:     fmt.Sprintf("%v", faucetConfig.GlobalReset),
+			Settings[PowDifficulty]:   fmt.Sprintf("%v", faucetConfig.PowDifficulty),
+			Settings[TokenIssuerKey]:  faucetConfig.TokenIssuerKey,
 		},
 	}, nil
 }
+
+func (fc *FaucetSmartContract) getAccessListHandler(
+	_ context.Context,
+	_ url.Values,
+	balances c_state.StateContextI,
+) (interface{}, error) {
+	al, err := fc.getAccessList(fc.ID, balances)
+	if err != nil {
+		return nil, common.NewErrInternal("can't get access list", err.Error())
+	}
+	return al.response(), nil
+}
@@ -98,6 +98,13 @@ func (gn *GlobalNode) updateConfig(fields map[string]string) error {
 			if err != nil {
 				return fmt.Errorf("key %s, unable to convert %v to time.duration", key, value)
 			}
+		case Settings[PowDifficulty]:
+			gn.PowDifficulty, err = strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("key %s, unable to convert %v to int", key, value)
+			}
+		case Settings[TokenIssuerKey]:
+			gn.TokenIssuerKey = value
 		default:
 			return fmt.Errorf("key %s not recognised as setting", key)
 		}
@@ -119,6 +126,8 @@ func (gn *GlobalNode) validate() error {
 		return common.NewError("failed to validate global node", fmt.Sprintf("individual reset(%v) is too short", gn.IndividualReset))
 	case gn.GlobalReset < gn.IndividualReset:
 		return common.NewError("failed to validate global node", fmt.Sprintf("global reset(%v) is less than individual reset(%v)", gn.GlobalReset, gn.IndividualReset))
+	case gn.PowDifficulty < 0 || gn.PowDifficulty > maxPowDifficulty:
+		return common.NewError("failed to validate global node", fmt.Sprintf("pow difficulty(%v) is out of [0; %v] range", gn.PowDifficulty, maxPowDifficulty))
 	}
 	return nil
 }
@@ -127,6 +136,10 @@ type UserNode struct {
 	ID        string        `json:"id"`
 	StartTime time.Time     `json:"start_time"`
 	Used      state.Balance `json:"used"`
+	// PowRound is round of the last proof-of-work used.
+	PowRound int64 `json:"pow_round,omitempty"`
+	// TokenExpiry is expiration of the last pour token used.
+	TokenExpiry common.Timestamp `json:"token_expiry,omitempty"`
 }
 
 func (un *UserNode) GetKey(globalKey string) datastore.Key {
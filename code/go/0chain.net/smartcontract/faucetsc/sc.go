@@ -21,7 +21,6 @@ import (
 )
 
 const (
-	owner   = "1746b06bb09f55ee01b33b5e2e055d6cc7a900cb57c0a3a5eaabb8a0e7745802"
 	ADDRESS = "6dba10422e368813802877a85039d3985d96760ed844092319743fb3a76712d3"
 	name    = "faucet"
 )
@@ -64,8 +63,10 @@ func (fc *FaucetSmartContract) setSC(sc *smartcontractinterface.SmartContract, _
 	fc.SmartContract.RestHandlers["/globalPeriodicLimit"] = fc.globalPeriodicLimit
 	fc.SmartContract.RestHandlers["/pourAmount"] = fc.pourAmount
 	fc.SmartContract.RestHandlers["/getConfig"] = fc.getConfigHandler
+	fc.SmartContract.RestHandlers["/getAccessList"] = fc.getAccessListHandler
 	fc.SmartContractExecutionStats["update-settings"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", fc.ID, "update-settings"), nil)
 	fc.SmartContractExecutionStats["pour"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", fc.ID, "pour"), nil)
+	fc.SmartContractExecutionStats["update-access-list"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", fc.ID, "update-access-list"), nil)
 	fc.SmartContractExecutionStats["refill"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", fc.ID, "refill"), nil)
 	fc.SmartContractExecutionStats["tokens Poured"] = metrics.GetOrRegisterHistogram(fmt.Sprintf("sc:%v:func:%v", fc.ID, "tokens Poured"), nil, metrics.NewUniformSample(1024))
 	fc.SmartContractExecutionStats["token refills"] = metrics.GetOrRegisterHistogram(fmt.Sprintf("sc:%v:func:%v", fc.ID, "token refills"), nil, metrics.NewUniformSample(1024))
@@ -98,8 +99,8 @@ func (fc *FaucetSmartContract) updateSettings(
 	balances c_state.StateContextI,
 	gn *GlobalNode,
 ) (string, error) {
-	if t.ClientID != owner {
-		return "", common.NewError("update_settings", "only the owner can update the limits")
+	if !sc.CanUpdateSettings(t.ClientID) {
+		return "", common.NewError("update_settings", "only the governance can update the limits")
 	}
 
 	var input sc.StringMap
@@ -126,8 +127,11 @@ func toSeconds(dur time.Duration) common.Timestamp {
 	return common.Timestamp(dur / time.Second)
 }
 
-func (fc *FaucetSmartContract) pour(t *transaction.Transaction, _ []byte, balances c_state.StateContextI, gn *GlobalNode) (string, error) {
+func (fc *FaucetSmartContract) pour(t *transaction.Transaction, inputData []byte, balances c_state.StateContextI, gn *GlobalNode) (string, error) {
 	user := fc.getUserVariables(t, gn, balances)
+	if err := fc.checkPourAccess(t, inputData, user, balances, gn); err != nil {
+		return "", err
+	}
 	ok, err := user.validPourRequest(t, balances, gn)
 	if ok {
 		var pourAmount = gn.PourAmount
@@ -153,6 +157,36 @@ func (fc *FaucetSmartContract) pour(t *transaction.Transaction, _ []byte, balanc
 	return "", err
 }
 
+// checkPourAccess does nothing if the gate is disabled. Otherwise it rejects
+// denied clients and requires a proof-of-work or a token from the clients
+// not allowed.
+func (fc *FaucetSmartContract) checkPourAccess(
+	t *transaction.Transaction,
+	inputData []byte,
+	user *UserNode,
+	balances c_state.StateContextI,
+	gn *GlobalNode,
+) error {
+	if !gn.gateEnabled() {
+		return nil
+	}
+	al, err := fc.getAccessList(gn.ID, balances)
+	if err != nil {
+		return common.NewError("invalid_request", "getting access list: "+err.Error())
+	}
+	if al.isDenied(t.ClientID) {
+		return common.NewError("invalid_request", "the client is denied to pour")
+	}
+	if al.isAllowed(t.ClientID) {
+		return nil
+	}
+	var pr pourRequest
+	if err := pr.decode(inputData); err != nil {
+		return common.NewError("invalid_request", "pour request not formated correctly")
+	}
+	return gn.checkGate(t, &pr, user, balances)
+}
+
 func (fc *FaucetSmartContract) refill(t *transaction.Transaction, balances c_state.StateContextI, gn *GlobalNode) (string, error) {
 	clientBalance, err := balances.GetClientBalance(t.ClientID)
 	if err != nil {
@@ -246,6 +280,8 @@ func (fc *FaucetSmartContract) Execute(t *transaction.Transaction, funcName stri
 		return fc.pour(t, inputData, balances, gn)
 	case "refill":
 		return fc.refill(t, balances, gn)
+	case "update-access-list":
+		return fc.updateAccessList(t, inputData, balances, gn)
 	default:
 		return "", common.NewErrorf("failed execution", "no faucet smart contract method with name %s", funcName)
 	}
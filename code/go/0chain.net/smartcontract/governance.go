@@ -0,0 +1,12 @@
+package smartcontract
+
+// GovernanceAddress is address of the governance smart contract. The
+// contract executes settings changes accepted by stake holders.
+const GovernanceAddress = "6dba10422e368813802877a85039d3985d96760ed844092319743fb3a76712e5"
+
+// CanUpdateSettings returns true if given client is allowed to update
+// settings of a smart contract. Only accepted governance proposals can
+// change them, there is no owner key.
+func CanUpdateSettings(clientID string) bool {
+	return clientID == GovernanceAddress
+}
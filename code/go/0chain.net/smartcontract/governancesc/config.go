@@ -0,0 +1,261 @@
+package governancesc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/util"
+	"0chain.net/smartcontract"
+
+	chainstate "0chain.net/chaincore/chain/state"
+	configpkg "0chain.net/chaincore/config"
+	"0chain.net/chaincore/state"
+)
+
+type Setting int
+
+const (
+	VotingPeriod Setting = iota
+	Quorum
+	Threshold
+	MinProposerStake
+	MaxDescriptionLength
+)
+
+var (
+	Settings = []string{
+		"voting_period",
+		"quorum",
+		"threshold",
+		"min_proposer_stake",
+		"max_description_length",
+	}
+)
+
+func scConfigKey(scKey string) datastore.Key {
+	return datastore.Key(scKey + ":configurations")
+}
+
+type config struct {
+	// VotingPeriod is duration of voting for a proposal.
+	VotingPeriod time.Duration `json:"voting_period"`
+	// Quorum is minimal part of total stake should vote for a proposal
+	// to make the voting valid.
+	Quorum float64 `json:"quorum"`
+	// Threshold is minimal part of voted stake should approve a proposal
+	// to accept it.
+	Threshold float64 `json:"threshold"`
+	// MinProposerStake is minimal stake of a client to make proposals.
+	MinProposerStake state.Balance `json:"min_proposer_stake"`
+	// MaxDescriptionLength of a proposal.
+	MaxDescriptionLength int `json:"max_description_length"`
+}
+
+func (conf *config) validate() (err error) {
+	switch {
+	case toSeconds(conf.VotingPeriod) < 1:
+		return errors.New("invalid voting_period (< 1s)")
+	case conf.Quorum <= 0 || conf.Quorum > 1:
+		return errors.New("invalid quorum, should be in (0; 1]")
+	case conf.Threshold <= 0.5 || conf.Threshold > 1:
+		return errors.New("invalid threshold, should be in (0.5; 1]")
+	case conf.MinProposerStake < 0:
+		return errors.New("negative min_proposer_stake")
+	case conf.MaxDescriptionLength < 1:
+		return errors.New("invalid max_description_length (< 1)")
+	}
+	return
+}
+
+func (conf *config) Encode() (b []byte) {
+	var err error
+	if b, err = json.Marshal(conf); err != nil {
+		panic(err) // must not happens
+	}
+	return
+}
+
+func (conf *config) Decode(b []byte) error {
+	return json.Unmarshal(b, conf)
+}
+
+func (conf *config) update(changes *smartcontract.StringMap) error {
+	for key, value := range changes.Fields {
+		switch key {
+		case Settings[VotingPeriod]:
+			if dValue, err := time.ParseDuration(value); err != nil {
+				return fmt.Errorf("value %v cannot be converted to time.Duration, "+
+					"failing to set config key %s", value, key)
+			} else {
+				conf.VotingPeriod = dValue
+			}
+		case Settings[Quorum]:
+			if fValue, err := strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Errorf("value %v cannot be converted to float64, "+
+					"failing to set config key %s", value, key)
+			} else {
+				conf.Quorum = fValue
+			}
+		case Settings[Threshold]:
+			if fValue, err := strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Errorf("value %v cannot be converted to float64, "+
+					"failing to set config key %s", value, key)
+			} else {
+				conf.Threshold = fValue
+			}
+		case Settings[MinProposerStake]:
+			if sbValue, err := strconv.ParseFloat(value, 64); err != nil {
+				return fmt.Errorf("value %v cannot be converted to state.Balance, "+
+					"failing to set config key %s", value, key)
+			} else {
+				conf.MinProposerStake = state.Balance(sbValue * 1e10)
+			}
+		case Settings[MaxDescriptionLength]:
+			if iValue, err := strconv.Atoi(value); err != nil {
+				return fmt.Errorf("value %v cannot be converted to int, "+
+					"failing to set config key %s", value, key)
+			} else {
+				conf.MaxDescriptionLength = iValue
+			}
+		default:
+			return fmt.Errorf("config setting %s not found", key)
+		}
+	}
+	return nil
+}
+
+func (conf *config) getConfigMap() smartcontract.StringMap {
+	sMap := smartcontract.StringMap{
+		Fields: make(map[string]string),
+	}
+	sMap.Fields[Settings[VotingPeriod]] = fmt.Sprintf("%v", conf.VotingPeriod)
+	sMap.Fields[Settings[Quorum]] = fmt.Sprintf("%v", conf.Quorum)
+	sMap.Fields[Settings[Threshold]] = fmt.Sprintf("%v", conf.Threshold)
+	sMap.Fields[Settings[MinProposerStake]] = fmt.Sprintf("%v", float64(conf.MinProposerStake)/1e10)
+	sMap.Fields[Settings[MaxDescriptionLength]] = fmt.Sprintf("%v", conf.MaxDescriptionLength)
+	return sMap
+}
+
+// applyConfig validates and saves given changes of the configurations
+func (gsc *GovernanceSmartContract) applyConfig(
+	changes *smartcontract.StringMap, balances chainstate.StateContextI,
+) (conf *config, err error) {
+
+	if conf, err = gsc.getConfig(balances); err != nil {
+		return nil, fmt.Errorf("can't get config: %v", err)
+	}
+	if err = conf.update(changes); err != nil {
+		return
+	}
+	if err = conf.validate(); err != nil {
+		return
+	}
+	_, err = balances.InsertTrieNode(scConfigKey(gsc.ID), conf)
+	return
+}
+
+// updateConfig is used by the owner or by an accepted proposal
+func (gsc *GovernanceSmartContract) updateConfig(
+	txn *transaction.Transaction,
+	input []byte,
+	balances chainstate.StateContextI,
+) (resp string, err error) {
+	if !smartcontract.CanUpdateSettings(txn.ClientID) {
+		return "", common.NewError("update_config",
+			"unauthorized access - only the governance can update the variables")
+	}
+
+	update := &smartcontract.StringMap{}
+	if err = update.Decode(input); err != nil {
+		return "", common.NewError("update_config", err.Error())
+	}
+
+	if _, err = gsc.applyConfig(update, balances); err != nil {
+		return "", common.NewError("update_config", err.Error())
+	}
+
+	return "", nil
+}
+
+//
+// helpers
+//
+
+func toSeconds(dur time.Duration) common.Timestamp {
+	return common.Timestamp(dur / time.Second)
+}
+
+func (gsc *GovernanceSmartContract) getConfigBytes(
+	balances chainstate.StateContextI,
+) (b []byte, err error) {
+	var val util.Serializable
+	val, err = balances.GetTrieNode(scConfigKey(gsc.ID))
+	if err != nil {
+		return
+	}
+	return val.Encode(), nil
+}
+
+// configurations from sc.yaml
+func getConfiguredConfig() (conf *config, err error) {
+	const prefix = "smart_contracts.governancesc."
+
+	conf = new(config)
+
+	// short hand
+	var scconf = configpkg.SmartContractConfig
+	conf.VotingPeriod = scconf.GetDuration(prefix + "voting_period")
+	conf.Quorum = scconf.GetFloat64(prefix + "quorum")
+	conf.Threshold = scconf.GetFloat64(prefix + "threshold")
+	conf.MinProposerStake = state.Balance(
+		scconf.GetFloat64(prefix+"min_proposer_stake") * 1e10)
+	conf.MaxDescriptionLength = scconf.GetInt(prefix + "max_description_length")
+
+	err = conf.validate()
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (gsc *GovernanceSmartContract) getConfig(
+	balances chainstate.StateContextI,
+) (conf *config, err error) {
+	var confb []byte
+	confb, err = gsc.getConfigBytes(balances)
+	if err != nil {
+		if err != util.ErrValueNotPresent {
+			return nil, err
+		}
+		return getConfiguredConfig()
+	}
+	conf = new(config)
+	if err = conf.Decode(confb); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return conf, nil
+}
+
+//
+// REST-handler
+//
+
+func (gsc *GovernanceSmartContract) getConfigHandler(
+	ctx context.Context,
+	params url.Values,
+	balances chainstate.StateContextI,
+) (interface{}, error) {
+	res, err := gsc.getConfig(balances)
+	if err != nil {
+		return nil, common.NewErrInternal("can't get config", err.Error())
+	}
+	return res.getConfigMap(), nil
+}
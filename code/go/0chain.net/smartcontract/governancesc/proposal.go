@@ -0,0 +1,612 @@
+package governancesc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	chainstate "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/smartcontract"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/util"
+	sc "0chain.net/smartcontract"
+	"0chain.net/smartcontract/faucetsc"
+	"0chain.net/smartcontract/interestpoolsc"
+	"0chain.net/smartcontract/minersc"
+	"0chain.net/smartcontract/storagesc"
+	"0chain.net/smartcontract/vestingsc"
+)
+
+// A settingsTarget is a smart contract function used to update settings.
+type settingsTarget struct {
+	address  string
+	function string
+}
+
+// settingsTargets by smart contract names
+var settingsTargets = map[string]settingsTarget{
+	"miner":    {minersc.ADDRESS, "update_settings"},
+	"storage":  {storagesc.ADDRESS, "update_settings"},
+	"faucet":   {faucetsc.ADDRESS, "update-settings"},
+	"interest": {interestpoolsc.ADDRESS, "updateVariables"},
+	"vesting":  {vestingsc.ADDRESS, "vestingsc-update-settings"},
+	name:       {ADDRESS, "update_settings"},
+}
+
+// proposal statuses
+const (
+	statusVoting   = "voting"
+	statusRejected = "rejected"
+	statusExecuted = "executed"
+	statusFailed   = "failed" // accepted, but can't be executed
+)
+
+func proposalKey(scKey, proposalID string) datastore.Key {
+	return datastore.Key(scKey + ":proposal:" + proposalID)
+}
+
+func proposalsKey(scKey string) datastore.Key {
+	return datastore.Key(scKey + ":proposals")
+}
+
+// stakeOf returns tokens staked by given client in miners, sharders,
+// blobbers and validators before given round; stake moved and restaked
+// after a proposal has been created can't vote for it again
+func stakeOf(clientID string, before int64,
+	balances chainstate.StateContextI) (stake state.Balance, err error) {
+
+	var ms, ss state.Balance
+	if ms, err = minersc.GetClientStake(clientID, before, balances); err != nil {
+		return
+	}
+	if ss, err = storagesc.GetClientStake(clientID, before, balances); err != nil {
+		return
+	}
+	return ms + ss, nil
+}
+
+// totalStake returns tokens staked in all miners, sharders, blobbers
+// and validators using running totals of the contracts
+func totalStake(balances chainstate.StateContextI) (
+	stake state.Balance, err error) {
+
+	var ms, ss state.Balance
+	if ms, err = minersc.GetTotalStake(balances); err != nil {
+		return
+	}
+	if ss, err = storagesc.GetTotalStake(balances); err != nil {
+		return
+	}
+	return ms + ss, nil
+}
+
+// A vote of a stake holder weighted by its stake.
+type vote struct {
+	Approve bool             `json:"approve"`
+	Weight  state.Balance    `json:"weight"`
+	Time    common.Timestamp `json:"time"`
+}
+
+// A proposal of settings changes of a smart contract.
+type proposal struct {
+	ID          string           `json:"id"`
+	Proposer    string           `json:"proposer"`
+	Contract    string           `json:"contract"`
+	Changes     *sc.StringMap    `json:"changes"`
+	Description string           `json:"description"`
+	Created     common.Timestamp `json:"created"`
+	Round       int64            `json:"round"`
+	VotingEnd   common.Timestamp `json:"voting_end"`
+	// voting conditions at the moment of the proposal creation, only
+	// stake created before the Round is counted
+	TotalStake state.Balance `json:"total_stake"`
+	Quorum     float64       `json:"quorum"`
+	Threshold  float64       `json:"threshold"`
+	// votes by clients
+	Votes   map[string]*vote `json:"votes"`
+	For     state.Balance    `json:"for"`
+	Against state.Balance    `json:"against"`
+	// outcome
+	Status string           `json:"status"`
+	Closed common.Timestamp `json:"closed,omitempty"`
+	Error  string           `json:"error,omitempty"`
+}
+
+func (p *proposal) Encode() []byte {
+	var b, err = json.Marshal(p)
+	if err != nil {
+		panic(err) // must never happen
+	}
+	return b
+}
+
+func (p *proposal) Decode(b []byte) error {
+	return json.Unmarshal(b, p)
+}
+
+func (p *proposal) save(scKey string,
+	balances chainstate.StateContextI) (err error) {
+
+	_, err = balances.InsertTrieNode(proposalKey(scKey, p.ID), p)
+	return
+}
+
+func (p *proposal) addVote(clientID string, v *vote) error {
+	if _, ok := p.Votes[clientID]; ok {
+		return errors.New("already voted")
+	}
+	// votes can't exceed the total stake the proposal has been created with
+	if left := p.TotalStake - p.For - p.Against; v.Weight > left {
+		v.Weight = left
+	}
+	p.Votes[clientID] = v
+	if v.Approve {
+		p.For += v.Weight
+	} else {
+		p.Against += v.Weight
+	}
+	return nil
+}
+
+// decide returns true if the proposal is accepted, and false if it's
+// rejected; the decided is false if the voting should be continued;
+// before the end of the voting it decides only if rest of stake holders
+// can't change the outcome
+func (p *proposal) decide(end bool) (accepted, decided bool) {
+	var (
+		total   = float64(p.TotalStake)
+		pro     = float64(p.For)
+		contra  = float64(p.Against)
+		quorum  = p.Quorum * total
+		enough  = p.Threshold * total
+		blocked = (1 - p.Threshold) * total
+	)
+
+	if total > 0 {
+		if pro >= quorum && pro >= enough {
+			return true, true // no one can prevent the acceptance
+		}
+		if contra > blocked {
+			return false, true // no one can accept it anymore
+		}
+	}
+
+	if !end {
+		return false, false
+	}
+
+	var voted = pro + contra
+	return total > 0 && voted >= quorum && pro >= p.Threshold*voted, true
+}
+
+// proposals lists IDs of proposals in voting and closed ones
+type proposals struct {
+	Voting []string `json:"voting"`
+	Closed []string `json:"closed"`
+}
+
+func (ps *proposals) Encode() []byte {
+	var b, err = json.Marshal(ps)
+	if err != nil {
+		panic(err) // must never happen
+	}
+	return b
+}
+
+func (ps *proposals) Decode(b []byte) error {
+	return json.Unmarshal(b, ps)
+}
+
+func (ps *proposals) save(scKey string,
+	balances chainstate.StateContextI) (err error) {
+
+	_, err = balances.InsertTrieNode(proposalsKey(scKey), ps)
+	return
+}
+
+func (ps *proposals) close(proposalID string) {
+	for i, id := range ps.Voting {
+		if id == proposalID {
+			ps.Voting = append(ps.Voting[:i], ps.Voting[i+1:]...)
+			ps.Closed = append(ps.Closed, proposalID)
+			return
+		}
+	}
+}
+
+func (gsc *GovernanceSmartContract) getProposals(
+	balances chainstate.StateContextI) (ps *proposals, err error) {
+
+	var val util.Serializable
+	switch val, err = balances.GetTrieNode(proposalsKey(gsc.ID)); err {
+	case nil:
+	case util.ErrValueNotPresent:
+		return new(proposals), nil
+	default:
+		return
+	}
+	ps = new(proposals)
+	if err = ps.Decode(val.Encode()); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return
+}
+
+func (gsc *GovernanceSmartContract) getProposal(proposalID string,
+	balances chainstate.StateContextI) (p *proposal, err error) {
+
+	var val util.Serializable
+	if val, err = balances.GetTrieNode(proposalKey(gsc.ID, proposalID)); err != nil {
+		return
+	}
+	p = new(proposal)
+	if err = p.Decode(val.Encode()); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return
+}
+
+// execute changes of accepted proposal using settings function of the
+// target smart contract on behalf of the governance smart contract
+func (gsc *GovernanceSmartContract) execute(t *transaction.Transaction,
+	p *proposal, balances chainstate.StateContextI) {
+
+	var (
+		target = settingsTargets[p.Contract]
+		err    error
+	)
+	// a failed proposal doesn't change the state
+	err = chainstate.Atomic(balances, func() (err error) {
+		if target.address == gsc.ID {
+			_, err = gsc.applyConfig(p.Changes, balances)
+		} else if contract := smartcontract.GetSmartContract(target.address); contract == nil {
+			err = fmt.Errorf("smart contract %s is not available", p.Contract)
+		} else {
+			var tx = &transaction.Transaction{
+				HashIDField:  t.HashIDField,
+				ClientID:     gsc.ID,
+				ToClientID:   target.address,
+				CreationDate: t.CreationDate,
+			}
+			_, err = contract.Execute(tx, target.function, p.Changes.Encode(),
+				balances)
+		}
+		return
+	})
+
+	if err != nil {
+		p.Status, p.Error = statusFailed, err.Error()
+		return
+	}
+	p.Status = statusExecuted
+}
+
+// decideAndExecute closes the proposal executing it if it's accepted,
+// it returns true if the proposal has closed
+func (gsc *GovernanceSmartContract) decideAndExecute(
+	t *transaction.Transaction, p *proposal,
+	balances chainstate.StateContextI) (closed bool) {
+
+	var accepted, decided = p.decide(t.CreationDate >= p.VotingEnd)
+	if !decided {
+		return false
+	}
+	if accepted {
+		gsc.execute(t, p, balances)
+	} else {
+		p.Status = statusRejected
+	}
+	p.Closed = t.CreationDate
+	return true
+}
+
+// closeExpired closes proposals with ended voting
+func (gsc *GovernanceSmartContract) closeExpired(t *transaction.Transaction,
+	ps *proposals, balances chainstate.StateContextI) (
+	closed []*proposal, err error) {
+
+	for _, id := range append([]string{}, ps.Voting...) {
+		var p *proposal
+		if p, err = gsc.getProposal(id, balances); err != nil {
+			return nil, fmt.Errorf("can't get proposal %s: %v", id, err)
+		}
+		if t.CreationDate < p.VotingEnd {
+			continue
+		}
+		gsc.decideAndExecute(t, p, balances)
+		if err = p.save(gsc.ID, balances); err != nil {
+			return nil, fmt.Errorf("saving proposal %s: %v", id, err)
+		}
+		ps.close(id)
+		closed = append(closed, p)
+	}
+	return
+}
+
+//
+// SC functions
+//
+
+type proposalRequest struct {
+	Contract    string        `json:"contract"`
+	Changes     *sc.StringMap `json:"changes"`
+	Description string        `json:"description"`
+}
+
+func (pr *proposalRequest) decode(b []byte) error {
+	return json.Unmarshal(b, pr)
+}
+
+func (pr *proposalRequest) validate(conf *config) error {
+	if _, ok := settingsTargets[pr.Contract]; !ok {
+		return fmt.Errorf("unknown smart contract: %q", pr.Contract)
+	}
+	if pr.Changes == nil || len(pr.Changes.Fields) == 0 {
+		return errors.New("empty changes")
+	}
+	if len(pr.Description) > conf.MaxDescriptionLength {
+		return errors.New("description too long")
+	}
+	if pr.Contract == name {
+		// check out the changes of the governance configurations
+		var check = *conf
+		if err := check.update(pr.Changes); err != nil {
+			return err
+		}
+		return check.validate()
+	}
+	return nil
+}
+
+// addProposal creates new proposal to change settings of a smart contract
+func (gsc *GovernanceSmartContract) addProposal(t *transaction.Transaction,
+	input []byte, balances chainstate.StateContextI) (
+	resp string, err error) {
+
+	var conf *config
+	if conf, err = gsc.getConfig(balances); err != nil {
+		return "", common.NewError("add_proposal_failed",
+			"can't get config: "+err.Error())
+	}
+
+	var req proposalRequest
+	if err = req.decode(input); err != nil {
+		return "", common.NewError("add_proposal_failed",
+			"invalid request: "+err.Error())
+	}
+	if err = req.validate(conf); err != nil {
+		return "", common.NewError("add_proposal_failed",
+			"invalid request: "+err.Error())
+	}
+
+	var (
+		round = balances.GetBlock().Round
+		stake state.Balance
+	)
+	if stake, err = stakeOf(t.ClientID, round, balances); err != nil {
+		return "", common.NewError("add_proposal_failed",
+			"can't get proposer stake: "+err.Error())
+	}
+	if stake == 0 || stake < conf.MinProposerStake {
+		return "", common.NewErrorf("add_proposal_failed",
+			"not enough stake to make proposals: %d < %d", stake,
+			conf.MinProposerStake)
+	}
+
+	var ps *proposals
+	if ps, err = gsc.getProposals(balances); err != nil {
+		return "", common.NewError("add_proposal_failed",
+			"can't get proposals list: "+err.Error())
+	}
+	if _, err = gsc.closeExpired(t, ps, balances); err != nil {
+		return "", common.NewError("add_proposal_failed", err.Error())
+	}
+
+	var p = &proposal{
+		ID:          t.Hash,
+		Proposer:    t.ClientID,
+		Contract:    req.Contract,
+		Changes:     req.Changes,
+		Description: req.Description,
+		Created:     t.CreationDate,
+		Round:       round,
+		VotingEnd:   t.CreationDate + toSeconds(conf.VotingPeriod),
+		Quorum:      conf.Quorum,
+		Threshold:   conf.Threshold,
+		Votes:       make(map[string]*vote),
+		Status:      statusVoting,
+	}
+	if p.TotalStake, err = totalStake(balances); err != nil {
+		return "", common.NewError("add_proposal_failed",
+			"can't get total stake: "+err.Error())
+	}
+
+	// the proposer approves its proposal
+	if err = p.addVote(t.ClientID, &vote{
+		Approve: true,
+		Weight:  stake,
+		Time:    t.CreationDate,
+	}); err != nil {
+		return "", common.NewError("add_proposal_failed", err.Error())
+	}
+
+	ps.Voting = append(ps.Voting, p.ID)
+	if gsc.decideAndExecute(t, p, balances) {
+		ps.close(p.ID)
+	}
+
+	if err = p.save(gsc.ID, balances); err != nil {
+		return "", common.NewError("add_proposal_failed",
+			"saving proposal: "+err.Error())
+	}
+	if err = ps.save(gsc.ID, balances); err != nil {
+		return "", common.NewError("add_proposal_failed",
+			"saving proposals list: "+err.Error())
+	}
+
+	return string(p.Encode()), nil
+}
+
+type voteRequest struct {
+	ProposalID string `json:"proposal_id"`
+	Approve    bool   `json:"approve"`
+}
+
+func (vr *voteRequest) decode(b []byte) error {
+	return json.Unmarshal(b, vr)
+}
+
+// vote for or against a proposal with the stake the voter has had before
+// the proposal; the proposal is executed as soon as its outcome is decided
+func (gsc *GovernanceSmartContract) vote(t *transaction.Transaction,
+	input []byte, balances chainstate.StateContextI) (
+	resp string, err error) {
+
+	var req voteRequest
+	if err = req.decode(input); err != nil {
+		return "", common.NewError("vote_failed",
+			"invalid request: "+err.Error())
+	}
+
+	var ps *proposals
+	if ps, err = gsc.getProposals(balances); err != nil {
+		return "", common.NewError("vote_failed",
+			"can't get proposals list: "+err.Error())
+	}
+	if _, err = gsc.closeExpired(t, ps, balances); err != nil {
+		return "", common.NewError("vote_failed", err.Error())
+	}
+
+	var p *proposal
+	if p, err = gsc.getProposal(req.ProposalID, balances); err != nil {
+		return "", common.NewError("vote_failed",
+			"can't get proposal: "+err.Error())
+	}
+
+	// save expired proposals, since the voting is too late
+	if p.Status != statusVoting {
+		if err = ps.save(gsc.ID, balances); err != nil {
+			return "", common.NewError("vote_failed",
+				"saving proposals list: "+err.Error())
+		}
+		return fmt.Sprintf("voting is over, proposal %s", p.Status), nil
+	}
+
+	var stake state.Balance
+	if stake, err = stakeOf(t.ClientID, p.Round, balances); err != nil {
+		return "", common.NewError("vote_failed",
+			"can't get voter stake: "+err.Error())
+	}
+	if stake == 0 {
+		return "", common.NewError("vote_failed", "not a stake holder")
+	}
+
+	err = p.addVote(t.ClientID, &vote{
+		Approve: req.Approve,
+		Weight:  stake,
+		Time:    t.CreationDate,
+	})
+	if err != nil {
+		return "", common.NewError("vote_failed", err.Error())
+	}
+
+	if gsc.decideAndExecute(t, p, balances) {
+		ps.close(p.ID)
+	}
+
+	if err = p.save(gsc.ID, balances); err != nil {
+		return "", common.NewError("vote_failed",
+			"saving proposal: "+err.Error())
+	}
+	if err = ps.save(gsc.ID, balances); err != nil {
+		return "", common.NewError("vote_failed",
+			"saving proposals list: "+err.Error())
+	}
+
+	return string(p.Encode()), nil
+}
+
+// closeProposals closes all proposals with ended voting, executing accepted
+// ones; it can be called by anyone
+func (gsc *GovernanceSmartContract) closeProposals(t *transaction.Transaction,
+	_ []byte, balances chainstate.StateContextI) (resp string, err error) {
+
+	var ps *proposals
+	if ps, err = gsc.getProposals(balances); err != nil {
+		return "", common.NewError("close_proposals_failed",
+			"can't get proposals list: "+err.Error())
+	}
+
+	var closed []*proposal
+	if closed, err = gsc.closeExpired(t, ps, balances); err != nil {
+		return "", common.NewError("close_proposals_failed", err.Error())
+	}
+	if err = ps.save(gsc.ID, balances); err != nil {
+		return "", common.NewError("close_proposals_failed",
+			"saving proposals list: "+err.Error())
+	}
+
+	var b []byte
+	if b, err = json.Marshal(closed); err != nil {
+		return "", common.NewError("close_proposals_failed", err.Error())
+	}
+	return string(b), nil
+}
+
+//
+// REST handlers
+//
+
+// getProposalHandler returns proposal with its votes and outcome
+func (gsc *GovernanceSmartContract) getProposalHandler(ctx context.Context,
+	params url.Values, balances chainstate.StateContextI) (
+	interface{}, error) {
+
+	var p, err = gsc.getProposal(params.Get("id"), balances)
+	if err != nil {
+		return nil, sc.NewErrNoResourceOrErrInternal(err, true,
+			"can't get proposal")
+	}
+	return p, nil
+}
+
+// getProposalsHandler returns proposals in voting or closed ones, if
+// status provided, or all of them
+func (gsc *GovernanceSmartContract) getProposalsHandler(ctx context.Context,
+	params url.Values, balances chainstate.StateContextI) (
+	interface{}, error) {
+
+	var ps, err = gsc.getProposals(balances)
+	if err != nil {
+		return nil, common.NewErrInternal("can't get proposals list",
+			err.Error())
+	}
+
+	var ids []string
+	switch status := params.Get("status"); status {
+	case "":
+		ids = append(append(ids, ps.Voting...), ps.Closed...)
+	case statusVoting:
+		ids = ps.Voting
+	case "closed":
+		ids = ps.Closed
+	default:
+		return nil, common.NewErrBadRequest("unknown status: " + status)
+	}
+
+	var list = make([]*proposal, 0, len(ids))
+	for _, id := range ids {
+		var p *proposal
+		if p, err = gsc.getProposal(id, balances); err != nil {
+			return nil, sc.NewErrNoResourceOrErrInternal(err, true,
+				"can't get proposal "+id)
+		}
+		list = append(list, p)
+	}
+	return list, nil
+}
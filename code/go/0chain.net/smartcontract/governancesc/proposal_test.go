@@ -0,0 +1,113 @@
+package governancesc
+
+import (
+	"testing"
+	"time"
+
+	"0chain.net/chaincore/state"
+	sc "0chain.net/smartcontract"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestProposal(total int64) *proposal {
+	return &proposal{
+		TotalStake: state.Balance(total),
+		Quorum:     0.5,
+		Threshold:  0.66,
+		Votes:      make(map[string]*vote),
+	}
+}
+
+func Test_proposal_addVote(t *testing.T) {
+	var p = newTestProposal(100)
+	require.NoError(t, p.addVote("one", &vote{Approve: true, Weight: 10}))
+	require.NoError(t, p.addVote("two", &vote{Approve: false, Weight: 5}))
+	require.Error(t, p.addVote("one", &vote{Approve: false, Weight: 10}))
+	assert.EqualValues(t, 10, p.For)
+	assert.EqualValues(t, 5, p.Against)
+
+	// votes can't exceed the total stake
+	require.NoError(t, p.addVote("three", &vote{Approve: true, Weight: 90}))
+	assert.EqualValues(t, 95, p.For)
+	assert.EqualValues(t, 85, p.Votes["three"].Weight)
+}
+
+func Test_proposal_decide(t *testing.T) {
+	var p = newTestProposal(100)
+
+	var accepted, decided = p.decide(false)
+	assert.False(t, decided)
+
+	// enough stake approved it, accept before the end of voting
+	p.For = 70
+	accepted, decided = p.decide(false)
+	assert.True(t, accepted)
+	assert.True(t, decided)
+
+	// no one can accept it anymore
+	p.For, p.Against = 10, 35
+	accepted, decided = p.decide(false)
+	assert.False(t, accepted)
+	assert.True(t, decided)
+
+	// no quorum at the end
+	p.For, p.Against = 30, 10
+	accepted, decided = p.decide(true)
+	assert.False(t, accepted)
+	assert.True(t, decided)
+
+	// quorum, enough of voted stake approved it
+	p.For, p.Against = 45, 10
+	accepted, decided = p.decide(true)
+	assert.True(t, accepted)
+	assert.True(t, decided)
+
+	// quorum, but not enough of voted stake approved it
+	p.For, p.Against = 35, 20
+	accepted, decided = p.decide(true)
+	assert.False(t, accepted)
+	assert.True(t, decided)
+
+	// no stake at all
+	p = newTestProposal(0)
+	accepted, decided = p.decide(true)
+	assert.False(t, accepted)
+	assert.True(t, decided)
+}
+
+func Test_proposalRequest_validate(t *testing.T) {
+	var conf = &config{
+		VotingPeriod:         time.Hour,
+		Quorum:               0.5,
+		Threshold:            0.66,
+		MaxDescriptionLength: 10,
+	}
+	require.NoError(t, conf.validate())
+
+	var changes = func(key, value string) *sc.StringMap {
+		return &sc.StringMap{Fields: map[string]string{key: value}}
+	}
+
+	var pr = &proposalRequest{Contract: "unknown",
+		Changes: changes("max_mint", "10")}
+	require.Error(t, pr.validate(conf))
+
+	pr.Contract = "faucet"
+	require.NoError(t, pr.validate(conf))
+
+	pr.Description = "too long description"
+	require.Error(t, pr.validate(conf))
+
+	pr.Description, pr.Changes = "", nil
+	require.Error(t, pr.validate(conf))
+
+	pr.Contract, pr.Changes = name, changes("threshold", "0.4")
+	require.Error(t, pr.validate(conf))
+	assert.Equal(t, 0.66, conf.Threshold)
+
+	pr.Changes = changes("threshold", "0.75")
+	require.NoError(t, pr.validate(conf))
+	assert.Equal(t, 0.66, conf.Threshold)
+}
@@ -0,0 +1,97 @@
+package governancesc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	chainstate "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/smartcontract"
+	sci "0chain.net/chaincore/smartcontractinterface"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	sc "0chain.net/smartcontract"
+	metrics "github.com/rcrowley/go-metrics"
+)
+
+const (
+	ADDRESS = sc.GovernanceAddress
+	name    = "governance"
+)
+
+// GovernanceSmartContract lets stake holders of miners, sharders, blobbers
+// and validators to propose and vote for settings changes of other smart
+// contracts. Accepted proposals are executed on behalf of the contract.
+type GovernanceSmartContract struct {
+	*sci.SmartContract
+}
+
+func NewGovernanceSmartContract() sci.SmartContractInterface {
+	var gscCopy = &GovernanceSmartContract{
+		SmartContract: sci.NewSC(ADDRESS),
+	}
+	gscCopy.setSC(gscCopy.SmartContract, &smartcontract.BCContext{})
+	return gscCopy
+}
+
+func (gsc *GovernanceSmartContract) GetHandlerStats(ctx context.Context,
+	params url.Values) (interface{}, error) {
+
+	return gsc.SmartContract.HandlerStats(ctx, params)
+}
+
+func (gsc *GovernanceSmartContract) GetExecutionStats() map[string]interface{} {
+	return gsc.SmartContractExecutionStats
+}
+
+func (gsc *GovernanceSmartContract) GetName() string {
+	return name
+}
+
+func (gsc *GovernanceSmartContract) GetAddress() string {
+	return ADDRESS
+}
+
+func (gsc *GovernanceSmartContract) GetRestPoints() map[string]sci.SmartContractRestHandler {
+	return gsc.RestHandlers
+}
+
+func (gsc *GovernanceSmartContract) setSC(sc *sci.SmartContract,
+	_ sci.BCContextI) {
+
+	gsc.SmartContract = sc
+
+	// configurations
+	gsc.SmartContract.RestHandlers["/getConfig"] = gsc.getConfigHandler
+	gsc.SmartContractExecutionStats["update_settings"] = metrics.GetOrRegisterTimer(
+		fmt.Sprintf("sc:%v:func:%v", gsc.ID, "update_settings"), nil)
+
+	// proposals and votes
+	gsc.SmartContract.RestHandlers["/getProposal"] = gsc.getProposalHandler
+	gsc.SmartContract.RestHandlers["/getProposals"] = gsc.getProposalsHandler
+	gsc.SmartContractExecutionStats["add_proposal"] = metrics.GetOrRegisterTimer(
+		fmt.Sprintf("sc:%v:func:%v", gsc.ID, "add_proposal"), nil)
+	gsc.SmartContractExecutionStats["vote"] = metrics.GetOrRegisterTimer(
+		fmt.Sprintf("sc:%v:func:%v", gsc.ID, "vote"), nil)
+	gsc.SmartContractExecutionStats["close_proposals"] = metrics.GetOrRegisterTimer(
+		fmt.Sprintf("sc:%v:func:%v", gsc.ID, "close_proposals"), nil)
+}
+
+func (gsc *GovernanceSmartContract) Execute(t *transaction.Transaction,
+	funcName string, input []byte, balances chainstate.StateContextI) (
+	string, error) {
+
+	switch funcName {
+	case "add_proposal":
+		return gsc.addProposal(t, input, balances)
+	case "vote":
+		return gsc.vote(t, input, balances)
+	case "close_proposals":
+		return gsc.closeProposals(t, input, balances)
+	case "update_settings":
+		return gsc.updateConfig(t, input, balances)
+	}
+
+	return "", common.NewErrorf("failed execution",
+		"no governance smart contract method with name: %s", funcName)
+}
@@ -0,0 +1,186 @@
+package interestpoolsc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"0chain.net/chaincore/state"
+	"0chain.net/core/common"
+)
+
+// APRPoint is a point of the APR curve: the APR paid when given fraction
+// of the total supply is locked.
+type APRPoint struct {
+	Utilization float64 `json:"utilization"`
+	APR         float64 `json:"apr"`
+}
+
+// APRCurve is a piecewise linear function of the utilization, the points
+// are sorted by the utilization.
+type APRCurve []APRPoint
+
+// parseAPRCurve parses a curve in "utilization:apr,utilization:apr" format,
+// an empty string is an empty curve
+func parseAPRCurve(s string) (APRCurve, error) {
+	var curve APRCurve
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return curve, nil
+	}
+	for _, p := range strings.Split(s, ",") {
+		kv := strings.Split(strings.TrimSpace(p), ":")
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid apr curve point %q", p)
+		}
+		u, err := strconv.ParseFloat(kv[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid utilization %q: %v", kv[0], err)
+		}
+		apr, err := strconv.ParseFloat(kv[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid apr %q: %v", kv[1], err)
+		}
+		if u < 0 || u > 1 || apr < 0 {
+			return nil, fmt.Errorf("apr curve point %q out of range", p)
+		}
+		curve = append(curve, APRPoint{Utilization: u, APR: apr})
+	}
+	sort.Slice(curve, func(i, j int) bool {
+		return curve[i].Utilization < curve[j].Utilization
+	})
+	for i := 1; i < len(curve); i++ {
+		if curve[i].Utilization == curve[i-1].Utilization {
+			return nil, errors.New("duplicate utilization in apr curve")
+		}
+	}
+	return curve, nil
+}
+
+func (c APRCurve) String() string {
+	var points = make([]string, 0, len(c))
+	for _, p := range c {
+		points = append(points, strconv.FormatFloat(p.Utilization, 'f', -1, 64)+
+			":"+strconv.FormatFloat(p.APR, 'f', -1, 64))
+	}
+	return strings.Join(points, ",")
+}
+
+// apr for given utilization, the curve is flat before the first and after
+// the last point
+func (c APRCurve) apr(utilization float64) float64 {
+	if len(c) == 0 {
+		return 0
+	}
+	if utilization <= c[0].Utilization {
+		return c[0].APR
+	}
+	for i := 1; i < len(c); i++ {
+		if utilization == c[i].Utilization {
+			return c[i].APR
+		}
+		if utilization < c[i].Utilization {
+			var a, b = c[i-1], c[i]
+			return a.APR + (b.APR-a.APR)*
+				(utilization-a.Utilization)/(b.Utilization-a.Utilization)
+		}
+	}
+	return c[len(c)-1].APR
+}
+
+// utilization is fraction of the total supply locked in interest pools
+func (gn *GlobalNode) utilization() float64 {
+	if gn.TotalSupply <= 0 {
+		return 0
+	}
+	var u = float64(gn.TotalLocked) / float64(gn.TotalSupply)
+	if u > 1 {
+		return 1
+	}
+	return u
+}
+
+// updateAPR sets the APR from the curve once per epoch. Without a curve or
+// the total supply the configured APR is used as is.
+func (gn *GlobalNode) updateAPR(now common.Timestamp) {
+	if len(gn.APRCurve) == 0 || gn.TotalSupply <= 0 {
+		return
+	}
+	var epoch = common.Timestamp(gn.APREpoch / time.Second)
+	if gn.EpochStart > 0 && now-gn.EpochStart < epoch {
+		return
+	}
+	gn.APR = gn.APRCurve.apr(gn.utilization())
+	gn.EpochStart = now
+}
+
+// unlocked decreases the total locked, returns false if it's not changed
+func (gn *GlobalNode) unlocked(amount state.Balance) bool {
+	if gn.TotalLocked == 0 || amount == 0 {
+		return false
+	}
+	if amount > gn.TotalLocked {
+		amount = gn.TotalLocked
+	}
+	gn.TotalLocked -= amount
+	return true
+}
+
+// interest earned by given amount locked for given duration
+func interest(amount state.Balance, apr float64, duration time.Duration) state.Balance {
+	return state.Balance(float64(amount) * apr * float64(duration) / float64(YEAR))
+}
+
+// earlyUnlockStat is result of unlocking a pool before its lock expires
+type earlyUnlockStat struct {
+	// Returned to the owner.
+	Returned state.Balance `json:"returned"`
+	// Interest minted for the not served part of the lock period.
+	Forfeited state.Balance `json:"forfeited"`
+	// Penalty of the early unlock.
+	Penalty state.Balance `json:"penalty"`
+}
+
+func (ps *earlyUnlockStat) encode() []byte {
+	buff, _ := json.Marshal(ps)
+	return buff
+}
+
+// earlyUnlock returns the tokens returned to the owner unlocking the pool at
+// given time: the interest for the rest of the lock period and the penalty
+// are taken from the pool balance
+func (ip *interestPool) earlyUnlock(now common.Timestamp, penalty float64) (ps *earlyUnlockStat) {
+	ps = new(earlyUnlockStat)
+	var tl tokenLock
+	switch lock := ip.TokenLockInterface.(type) {
+	case *tokenLock:
+		tl = *lock
+	case tokenLock:
+		tl = lock
+	}
+	if tl.Duration <= 0 {
+		ps.Returned = ip.Balance
+		return
+	}
+	var left = tl.Duration - common.ToTime(now).Sub(common.ToTime(tl.StartTime))
+	if left < 0 {
+		left = 0
+	}
+	if left > tl.Duration {
+		left = tl.Duration
+	}
+	ps.Forfeited = state.Balance(float64(ip.TokensEarned) * float64(left) / float64(tl.Duration))
+	ps.Penalty = state.Balance(float64(ip.Balance) * penalty)
+	if ps.Forfeited+ps.Penalty > ip.Balance {
+		ps.Penalty = ip.Balance - ps.Forfeited
+		if ps.Penalty < 0 {
+			ps.Forfeited, ps.Penalty = ip.Balance, 0
+		}
+	}
+	ps.Returned = ip.Balance - ps.Forfeited - ps.Penalty
+	return
+}
@@ -0,0 +1,150 @@
+package interestpoolsc
+
+import (
+	"testing"
+	"time"
+
+	"0chain.net/chaincore/smartcontractinterface"
+	"0chain.net/chaincore/state"
+	"0chain.net/core/common"
+	"0chain.net/smartcontract"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseAPRCurve(t *testing.T) {
+	curve, err := parseAPRCurve("1:0.02, 0:0.2,0.5:0.1")
+	require.NoError(t, err)
+	require.Equal(t, APRCurve{{0, 0.2}, {0.5, 0.1}, {1, 0.02}}, curve)
+	require.Equal(t, "0:0.2,0.5:0.1,1:0.02", curve.String())
+
+	curve, err = parseAPRCurve("")
+	require.NoError(t, err)
+	require.Empty(t, curve)
+
+	for _, s := range []string{"0.5", "a:0.1", "0.5:b", "1.5:0.1", "0.5:-1", "0.5:0.1,0.5:0.2"} {
+		_, err = parseAPRCurve(s)
+		require.Error(t, err, s)
+	}
+}
+
+func TestAPRCurve_apr(t *testing.T) {
+	curve := APRCurve{{0.1, 0.2}, {0.5, 0.1}, {1, 0.02}}
+	require.Equal(t, 0.0, APRCurve{}.apr(0.5))
+	require.Equal(t, 0.2, curve.apr(0))
+	require.Equal(t, 0.2, curve.apr(0.1))
+	require.InDelta(t, 0.15, curve.apr(0.3), 1e-9)
+	require.InDelta(t, 0.06, curve.apr(0.75), 1e-9)
+	require.Equal(t, 0.02, curve.apr(1))
+}
+
+func TestGlobalNode_updateAPR(t *testing.T) {
+	gn := testGlobalNode(globalNode1Ok, 100, 0, 1, 0.1, time.Second)
+
+	// fixed APR without the curve
+	gn.updateAPR(10)
+	require.Equal(t, 0.1, gn.APR)
+
+	gn.APRCurve = APRCurve{{0, 0.2}, {1, 0}}
+	gn.APREpoch = time.Minute
+	gn.TotalSupply = 100
+	gn.TotalLocked = 25
+	gn.updateAPR(10)
+	require.InDelta(t, 0.15, gn.APR, 1e-9)
+	require.Equal(t, common.Timestamp(10), gn.EpochStart)
+
+	// the same epoch
+	gn.TotalLocked = 50
+	gn.updateAPR(69)
+	require.InDelta(t, 0.15, gn.APR, 1e-9)
+
+	// next epoch
+	gn.updateAPR(70)
+	require.InDelta(t, 0.1, gn.APR, 1e-9)
+}
+
+func TestGlobalNode_RateSettings_Encode_Decode(t *testing.T) {
+	gn := testGlobalNode(globalNode1Ok, 100, 0, 1, 0.1, time.Second)
+	gn.APRCurve = APRCurve{{0, 0.2}, {1, 0}}
+	gn.APREpoch = time.Hour
+	gn.TotalSupply = 1000
+	gn.EarlyUnlockPenalty = 0.1
+	gn.TotalLocked = 10
+	gn.EpochStart = 5
+
+	got := newGlobalNode()
+	require.NoError(t, got.Decode(gn.Encode()))
+	require.Equal(t, gn.RateSettings, got.RateSettings)
+}
+
+func Test_interestPool_earlyUnlock(t *testing.T) {
+	pool := newInterestPool()
+	pool.Balance = 1000
+	pool.TokensEarned = 100
+	pool.TokenLockInterface = &tokenLock{StartTime: 100, Duration: 10 * time.Second}
+
+	stat := pool.earlyUnlock(104, 0.1)
+	require.Equal(t, &earlyUnlockStat{Returned: 840, Forfeited: 60, Penalty: 100}, stat)
+
+	// the penalty doesn't exceed the balance
+	stat = pool.earlyUnlock(100, 0.95)
+	require.Equal(t, &earlyUnlockStat{Returned: 0, Forfeited: 100, Penalty: 900}, stat)
+
+	stat = pool.earlyUnlock(110, 0.1)
+	require.Equal(t, state.Balance(0), stat.Forfeited)
+}
+
+func TestInterestPoolSmartContract_earlyUnlock(t *testing.T) {
+	var (
+		ip = &InterestPoolSmartContract{}
+		sc = &smartcontractinterface.SmartContract{
+			ID:                          ADDRESS,
+			RestHandlers:                map[string]smartcontractinterface.SmartContractRestHandler{},
+			SmartContractExecutionStats: map[string]interface{}{},
+		}
+		balances = testBalance(clientID1, 1000)
+		un       = testUserNode(clientID1, nil)
+		gn       = testGlobalNode(globalNode1Ok, 1000, 0, 1, 0, time.Second)
+	)
+	ip.setSC(sc, nil)
+	gn.APRCurve = APRCurve{{0, float64(YEAR) / float64(10*time.Second)}, {1, 0}}
+	gn.TotalSupply = 10000
+	gn.EarlyUnlockPenalty = 0.1
+
+	tx := testTxn(clientID1, 1000)
+	tx.ToClientID = ADDRESS
+	balances.txn = tx
+	_, err := ip.lock(tx, un, gn, testPoolRequest(10*time.Second), balances)
+	require.NoError(t, err)
+	require.Len(t, un.Pools, 1)
+	require.Equal(t, state.Balance(1000), gn.TotalLocked)
+	require.Equal(t, state.Balance(1000), gn.TotalMinted)
+
+	var pool *interestPool
+	for _, p := range un.Pools {
+		pool = p
+	}
+	input := (&poolStat{ID: pool.ID}).encode()
+
+	// the lock is expired
+	late := testTxn(clientID1, 0)
+	late.ToClientID = ADDRESS
+	late.CreationDate = tx.CreationDate + 10
+	balances.txn = late
+	_, err = ip.earlyUnlock(late, un, gn, input, balances)
+	require.Error(t, err)
+
+	early := testTxn(clientID1, 0)
+	early.ToClientID = ADDRESS
+	early.CreationDate = tx.CreationDate + 4
+	balances.txn = early
+	resp, err := ip.earlyUnlock(early, un, gn, input, balances)
+	require.NoError(t, err)
+	require.Equal(t, string((&earlyUnlockStat{Returned: 300, Forfeited: 600, Penalty: 100}).encode()), resp)
+
+	require.Empty(t, un.Pools)
+	require.Equal(t, state.Balance(0), gn.TotalLocked)
+	require.Equal(t, state.Balance(1000), gn.TotalMinted, "no mint budget freed")
+	require.Equal(t, state.Balance(300+1000), balances.balances[clientID1])
+	require.Equal(t, state.Balance(600+100), balances.balances[smartcontract.BurnAddress])
+	require.Zero(t, balances.balances[ADDRESS])
+}
@@ -10,6 +10,16 @@ import (
 )
 
 func AddMockNodes(clients []string, balances cstate.StateContextI) {
+	gn := newGlobalNode()
+	gn.MinLock = state.Balance(viper.GetFloat64(benchmark.InterestPoolMinLock))
+	gn.MinLockPeriod = viper.GetDuration(benchmark.InterestPoolMinLockPeriod)
+	gn.MaxMint = state.Balance(viper.GetFloat64(benchmark.InterestPoolMaxMint))
+	gn.APR = viper.GetFloat64(benchmark.InterestPoolApr)
+	gn.APRCurve, _ = parseAPRCurve(viper.GetString(benchmark.InterestPoolAprCurve))
+	gn.APREpoch = viper.GetDuration(benchmark.InterestPoolAprEpoch)
+	gn.TotalSupply = state.Balance(viper.GetFloat64(benchmark.InterestPoolTotalSupply) * 1e10)
+	gn.EarlyUnlockPenalty = viper.GetFloat64(benchmark.InterestPoolEarlyPenalty)
+
 	for i, client := range clients {
 		un := newUserNode(client)
 		pool := newInterestPool()
@@ -19,6 +29,10 @@ func AddMockNodes(clients []string, balances cstate.StateContextI) {
 			Duration: viper.GetDuration(benchmark.InterestPoolMinLockPeriod),
 			Owner:    client,
 		}
+		pool.APR = gn.APR
+		pool.TokensEarned = interest(pool.Balance, pool.APR, gn.MinLockPeriod)
+		gn.TotalMinted += pool.TokensEarned
+		gn.TotalLocked += pool.Balance
 
 		_ = un.addPool(pool)
 
@@ -28,10 +42,8 @@ func AddMockNodes(clients []string, balances cstate.StateContextI) {
 		}
 	}
 
-	gn := newGlobalNode()
-	gn.MinLock = state.Balance(viper.GetFloat64(benchmark.InterestPoolMinLock))
-	gn.MinLockPeriod = viper.GetDuration(benchmark.InterestPoolMinLockPeriod)
-	gn.MaxMint = state.Balance(viper.GetFloat64(benchmark.InterestPoolMaxMint))
+	// the APR of the next epoch follows the utilization of the mock pools
+	gn.updateAPR(1)
 	_, err := balances.InsertTrieNode(gn.getKey(), gn)
 	if err != nil {
 		panic(err)
@@ -52,6 +52,8 @@ func (bt BenchTest) Run(balances cstate.StateContextI, _ *testing.B) error {
 		_, err = isc.lock(bt.Transaction(), un, gn, bt.input, balances)
 	case "unlock":
 		_, err = isc.unlock(bt.Transaction(), un, gn, bt.input, balances)
+	case "earlyUnlock":
+		_, err = isc.earlyUnlock(bt.Transaction(), un, gn, bt.input, balances)
 	case "updateVariables":
 		_, err = isc.updateVariables(bt.Transaction(), gn, bt.input, balances)
 	default:
@@ -88,18 +90,33 @@ func BenchmarkTests(
 				ID: getInterestPoolId(0),
 			}).encode(),
 		},
+		{
+			name:     "interest_pool.earlyUnlock",
+			endpoint: "earlyUnlock",
+			txn: &transaction.Transaction{
+				CreationDate: 1,
+				ClientID:     data.Clients[1],
+			},
+			input: (&poolStat{
+				ID: getInterestPoolId(1),
+			}).encode(),
+		},
 		{
 			name:     "interest_pool.updateVariables",
 			endpoint: "updateVariables",
 			txn: &transaction.Transaction{
-				ClientID: owner,
+				ClientID: sc.GovernanceAddress,
 			},
 			input: (&sc.StringMap{
 				Fields: map[string]string{
-					Settings[MinLock]:       "1",
-					Settings[Apr]:           "0.2",
-					Settings[MinLockPeriod]: "3m",
-					Settings[MaxMint]:       "5",
+					Settings[MinLock]:            "1",
+					Settings[Apr]:                "0.2",
+					Settings[MinLockPeriod]:      "3m",
+					Settings[MaxMint]:            "5",
+					Settings[AprCurve]:           "0:0.3,0.4:0.1,1:0.01",
+					Settings[AprEpoch]:           "1h",
+					Settings[TotalSupply]:        "400000000",
+					Settings[EarlyUnlockPenalty]: "0.1",
 				},
 			}).Encode(),
 		},
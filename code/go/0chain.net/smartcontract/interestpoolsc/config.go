@@ -14,6 +14,10 @@ const (
 	Apr
 	MinLockPeriod
 	MaxMint
+	AprCurve
+	AprEpoch
+	TotalSupply
+	EarlyUnlockPenalty
 )
 
 var (
@@ -22,6 +26,10 @@ var (
 		"apr",
 		"min_lock_period",
 		"max_mint",
+		"apr_curve",
+		"apr_epoch",
+		"total_supply",
+		"early_unlock_penalty",
 	}
 )
 
@@ -31,9 +39,9 @@ func (ip *InterestPoolSmartContract) updateVariables(
 	inputData []byte,
 	balances c_state.StateContextI,
 ) (string, error) {
-	if t.ClientID != owner {
+	if !smartcontract.CanUpdateSettings(t.ClientID) {
 		return "", common.NewError("failed to update variables",
-			"unauthorized access - only the owner can update the variables")
+			"unauthorized access - only the governance can update the variables")
 	}
 
 	changes := &smartcontract.StringMap{}
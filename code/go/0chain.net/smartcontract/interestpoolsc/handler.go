@@ -18,10 +18,14 @@ func (ip *InterestPoolSmartContract) getConfig(_ context.Context, _ url.Values,
 	const pfx = "smart_contracts.interestpoolsc."
 	return &smartcontract.StringMap{
 		Fields: map[string]string{
-			Settings[MinLock]:       fmt.Sprintf("%0v", gn.MinLock),
-			Settings[MaxMint]:       fmt.Sprintf("%0v", gn.MaxMint),
-			Settings[MinLockPeriod]: fmt.Sprintf("%0v", gn.MinLockPeriod),
-			Settings[Apr]:           fmt.Sprintf("%0v", gn.APR),
+			Settings[MinLock]:            fmt.Sprintf("%0v", gn.MinLock),
+			Settings[MaxMint]:            fmt.Sprintf("%0v", gn.MaxMint),
+			Settings[MinLockPeriod]:      fmt.Sprintf("%0v", gn.MinLockPeriod),
+			Settings[Apr]:                fmt.Sprintf("%0v", gn.APR),
+			Settings[AprCurve]:           gn.APRCurve.String(),
+			Settings[AprEpoch]:           fmt.Sprintf("%0v", gn.APREpoch),
+			Settings[TotalSupply]:        fmt.Sprintf("%0v", gn.TotalSupply),
+			Settings[EarlyUnlockPenalty]: fmt.Sprintf("%0v", gn.EarlyUnlockPenalty),
 		},
 	}, nil
 }
@@ -31,6 +35,7 @@ func (ip *InterestPoolSmartContract) getPoolsStats(_ context.Context, params url
 	if len(un.Pools) == 0 {
 		return nil, common.NewErrNoResource("can't find user node")
 	}
+	gn := ip.getGlobalNode(balances, "updateVariables")
 	t := time.Now()
 	stats := &poolStats{}
 	for _, pool := range un.Pools {
@@ -38,6 +43,7 @@ func (ip *InterestPoolSmartContract) getPoolsStats(_ context.Context, params url
 		if err != nil {
 			return nil, common.NewErrInternal("can't get pool stats", err.Error())
 		}
+		stat.project(pool, common.Timestamp(t.Unix()), gn)
 		stats.addStat(stat)
 	}
 	return stats, nil
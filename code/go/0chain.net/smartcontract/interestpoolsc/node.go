@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"0chain.net/chaincore/state"
+	"0chain.net/core/common"
 
 	"0chain.net/core/datastore"
 	"0chain.net/core/encryption"
@@ -17,6 +18,21 @@ type GlobalNode struct {
 	ID                datastore.Key
 	*SimpleGlobalNode `json:"simple_global_node"`
 	MinLockPeriod     time.Duration `json:"min_lock_period"`
+	RateSettings      `json:"rate_settings"`
+}
+
+// RateSettings of the variable APR. The APR follows the curve of the
+// utilization, the fraction of the total supply locked, and changes once
+// per epoch. Without the curve the APR is fixed.
+type RateSettings struct {
+	APRCurve           APRCurve      `json:"apr_curve,omitempty"`
+	APREpoch           time.Duration `json:"apr_epoch,omitempty"`
+	TotalSupply        state.Balance `json:"total_supply,omitempty"`
+	EarlyUnlockPenalty float64       `json:"early_unlock_penalty,omitempty"`
+	// TotalLocked in all interest pools.
+	TotalLocked state.Balance `json:"total_locked,omitempty"`
+	// EpochStart is time of the last APR change.
+	EpochStart common.Timestamp `json:"epoch_start,omitempty"`
 }
 
 func newGlobalNode() *GlobalNode {
@@ -36,6 +52,11 @@ func (gn *GlobalNode) Encode() []byte {
 	dur, _ := json.Marshal(gn.MinLockPeriod.String())
 	durEnc := json.RawMessage(dur)
 	rawMessage["min_lock_period"] = &durEnc
+	// rate settings are omitted while not used
+	if rs, _ := json.Marshal(gn.RateSettings); string(rs) != "{}" {
+		rsEnc := json.RawMessage(rs)
+		rawMessage["rate_settings"] = &rsEnc
+	}
 	b, _ := json.Marshal(rawMessage)
 	return b
 }
@@ -66,6 +87,13 @@ func (gn *GlobalNode) Decode(input []byte) error {
 		}
 		gn.MinLockPeriod = dur
 	}
+	rs, ok := objMap["rate_settings"]
+	if ok {
+		err = json.Unmarshal(*rs, &gn.RateSettings)
+		if err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -95,6 +123,31 @@ func (gn *GlobalNode) set(key string, value string) error {
 			return fmt.Errorf("cannot conver key %s, value %s into state.balane; %v", key, value, err)
 		}
 		gn.MaxMint = state.Balance(fValue * 1e10)
+	case Settings[AprCurve]:
+		gn.APRCurve, err = parseAPRCurve(value)
+		if err != nil {
+			return fmt.Errorf("cannot conver key %s, value %s into apr curve; %v", key, value, err)
+		}
+		gn.EpochStart = 0
+	case Settings[AprEpoch]:
+		gn.APREpoch, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("cannot conver key %s, value %s into time.duration; %v", key, value, err)
+		}
+	case Settings[TotalSupply]:
+		fValue, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("cannot conver key %s, value %s into state.balane; %v", key, value, err)
+		}
+		gn.TotalSupply = state.Balance(fValue * 1e10)
+	case Settings[EarlyUnlockPenalty]:
+		gn.EarlyUnlockPenalty, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("cannot conver key %s, value %s into float64; %v", key, value, err)
+		}
+		if gn.EarlyUnlockPenalty < 0 || gn.EarlyUnlockPenalty > 1 {
+			return fmt.Errorf("key %s, value %s is out of [0; 1] range", key, value)
+		}
 	default:
 		return fmt.Errorf("config setting %s not found", key)
 	}
@@ -5,7 +5,6 @@ import (
 	"time"
 
 	"0chain.net/chaincore/state"
-	// "0chain.net/chaincore/transaction"
 	"0chain.net/core/common"
 	"0chain.net/core/datastore"
 )
@@ -37,6 +36,13 @@ type poolStat struct {
 	APR          float64          `json:"apr"`
 	TokensEarned state.Balance    `json:"tokens_earned"`
 	Balance      state.Balance    `json:"balance"`
+	// InterestAccrued is part of the tokens earned for the served time.
+	InterestAccrued state.Balance `json:"interest_accrued,omitempty"`
+	// EarlyUnlockReturn is amount returned by unlocking the pool now.
+	EarlyUnlockReturn state.Balance `json:"early_unlock_return,omitempty"`
+	// ProjectedInterest is the interest for locking the balance for the
+	// same duration again at the current APR.
+	ProjectedInterest state.Balance `json:"projected_interest,omitempty"`
 }
 
 func (ps *poolStat) encode() []byte {
@@ -49,4 +55,16 @@ func (ps *poolStat) decode(input []byte) error {
 	return err
 }
 
-
+// project interest of the pool at given time with given global settings
+func (ps *poolStat) project(pool *interestPool, now common.Timestamp, gn *GlobalNode) {
+	var early = pool.earlyUnlock(now, gn.EarlyUnlockPenalty)
+	ps.InterestAccrued = pool.TokensEarned - early.Forfeited
+	if ps.Locked {
+		ps.EarlyUnlockReturn = early.Returned
+	}
+	var apr = gn.APR
+	if len(gn.APRCurve) > 0 && gn.TotalSupply > 0 {
+		apr = gn.APRCurve.apr(gn.utilization())
+	}
+	ps.ProjectedInterest = interest(ps.Balance, apr, ps.Duartion)
+}
@@ -16,13 +16,13 @@ import (
 	"0chain.net/core/common"
 	"0chain.net/core/datastore"
 	"0chain.net/core/util"
+	sc "0chain.net/smartcontract"
 
 	"github.com/rcrowley/go-metrics"
 )
 
 const (
 	Seperator = smartcontractinterface.Seperator
-	owner     = "1746b06bb09f55ee01b33b5e2e055d6cc7a900cb57c0a3a5eaabb8a0e7745802"
 	ADDRESS   = "cf8d0df9bd8cc637a4ff4e792ffe3686da6220c45f0e1103baa609f3f1751ef4"
 	name      = "interest"
 	YEAR      = time.Duration(time.Hour * 8784)
@@ -67,6 +67,7 @@ func (ipsc *InterestPoolSmartContract) setSC(sc *smartcontractinterface.SmartCon
 	ipsc.SmartContract.RestHandlers["/getConfig"] = ipsc.getConfig
 	ipsc.SmartContractExecutionStats["lock"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ipsc.ID, "lock"), nil)
 	ipsc.SmartContractExecutionStats["unlock"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ipsc.ID, "unlock"), nil)
+	ipsc.SmartContractExecutionStats["earlyUnlock"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ipsc.ID, "earlyUnlock"), nil)
 	ipsc.SmartContractExecutionStats["updateVariables"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ipsc.ID, "updateVariables"), nil)
 }
 
@@ -95,15 +96,15 @@ func (ip *InterestPoolSmartContract) lock(t *transaction.Transaction, un *UserNo
 	if !gn.canMint() {
 		return "", common.NewError("failed locking tokens", "can't mint anymore")
 	}
+	// the APR is fixed for the pool at the time of the lock
+	gn.updateAPR(t.CreationDate)
 	pool := newInterestPool()
 	pool.TokenLockInterface = &tokenLock{StartTime: t.CreationDate, Duration: npr.Duration, Owner: un.ClientID}
 	transfer, resp, err := pool.DigPool(t.Hash, t)
 	if err == nil {
 		balances.AddTransfer(transfer)
 		pool.APR = gn.APR
-		pool.TokensEarned = state.Balance(
-			float64(transfer.Amount) * gn.APR * float64(npr.Duration) / float64(YEAR),
-		)
+		pool.TokensEarned = interest(transfer.Amount, gn.APR, npr.Duration)
 		if err := balances.AddMint(&state.Mint{
 			Minter:     ip.ID,
 			ToClientID: transfer.ClientID,
@@ -111,8 +112,9 @@ func (ip *InterestPoolSmartContract) lock(t *transaction.Transaction, un *UserNo
 		}); err != nil {
 			return "", err
 		}
-		// add to total minted
+		// add to total minted and locked
 		gn.TotalMinted += pool.TokensEarned
+		gn.TotalLocked += transfer.Amount
 		balances.InsertTrieNode(gn.getKey(), gn)
 		// add to user pools
 		if err := un.addPool(pool); err != nil {
@@ -143,11 +145,57 @@ func (ip *InterestPoolSmartContract) unlock(t *transaction.Transaction, un *User
 		}
 		balances.AddTransfer(transfer)
 		balances.InsertTrieNode(un.getKey(gn.ID), un)
+		if gn.unlocked(transfer.Amount) {
+			balances.InsertTrieNode(gn.getKey(), gn)
+		}
 		return response, nil
 	}
 	return "", common.NewError("failed to unlock tokens", fmt.Sprintf("pool (%v) doesn't exist", ps.ID))
 }
 
+// earlyUnlock unlocks a pool before its lock expires. The interest minted
+// for the rest of the lock period is forfeited and the early unlock penalty
+// is charged, both are burned. The interest has been minted already, thus
+// the forfeited tokens don't return to the mint budget.
+func (ip *InterestPoolSmartContract) earlyUnlock(t *transaction.Transaction, un *UserNode, gn *GlobalNode, inputData []byte, balances c_state.StateContextI) (string, error) {
+	ps := &poolStat{}
+	err := ps.decode(inputData)
+	if err != nil {
+		return "", common.NewError("failed to unlock tokens early",
+			fmt.Sprintf("input not formatted correctly: %v\n", err.Error()))
+	}
+	pool, ok := un.Pools[ps.ID]
+	if !ok {
+		return "", common.NewError("failed to unlock tokens early", fmt.Sprintf("pool (%v) doesn't exist", ps.ID))
+	}
+	if !pool.IsLocked(common.ToTime(t.CreationDate)) {
+		return "", common.NewError("failed to unlock tokens early", "the pool is not locked, use unlock")
+	}
+	if pool.Balance == 0 {
+		return "", common.NewError("failed to unlock tokens early", "pool is empty")
+	}
+
+	stat := pool.earlyUnlock(t.CreationDate, gn.EarlyUnlockPenalty)
+	if stat.Returned > 0 {
+		if err := balances.AddTransfer(state.NewTransfer(ip.ID, t.ClientID, stat.Returned)); err != nil {
+			return "", common.NewError("failed to unlock tokens early", err.Error())
+		}
+	}
+	if burned := stat.Forfeited + stat.Penalty; burned > 0 {
+		if err := balances.AddTransfer(state.NewTransfer(ip.ID, sc.BurnAddress, burned)); err != nil {
+			return "", common.NewError("failed to unlock tokens early", err.Error())
+		}
+	}
+	if err := un.deletePool(pool.ID); err != nil {
+		return "", common.NewError("failed to unlock tokens early", fmt.Sprintf("error deleting pool from user node: %v", err.Error()))
+	}
+
+	gn.unlocked(pool.Balance)
+	balances.InsertTrieNode(un.getKey(gn.ID), un)
+	balances.InsertTrieNode(gn.getKey(), gn)
+	return string(stat.encode()), nil
+}
+
 func (ip *InterestPoolSmartContract) getUserNode(id datastore.Key, balances c_state.StateContextI) *UserNode {
 	un := newUserNode(id)
 	userBytes, err := balances.GetTrieNode(un.getKey(ip.ID))
@@ -174,6 +222,10 @@ func (ip *InterestPoolSmartContract) getGlobalNode(balances c_state.StateContext
 	gn.APR = conf.GetFloat64(pfx + "apr")
 	gn.MinLock = state.Balance(conf.GetInt64(pfx + "min_lock"))
 	gn.MaxMint = state.Balance(conf.GetFloat64(pfx+"max_mint") * 1e10)
+	gn.APRCurve, _ = parseAPRCurve(conf.GetString(pfx + "apr_curve"))
+	gn.APREpoch = conf.GetDuration(pfx + "apr_epoch")
+	gn.TotalSupply = state.Balance(conf.GetFloat64(pfx+"total_supply") * 1e10)
+	gn.EarlyUnlockPenalty = conf.GetFloat64(pfx + "early_unlock_penalty")
 	if err == util.ErrValueNotPresent && funcName != "updateVariables" {
 		balances.InsertTrieNode(gn.getKey(), gn)
 	}
@@ -188,6 +240,8 @@ func (ip *InterestPoolSmartContract) Execute(t *transaction.Transaction, funcNam
 		return ip.lock(t, un, gn, inputData, balances)
 	case "unlock":
 		return ip.unlock(t, un, gn, inputData, balances)
+	case "earlyUnlock":
+		return ip.earlyUnlock(t, un, gn, inputData, balances)
 	case "updateVariables":
 		return ip.updateVariables(t, gn, inputData, balances)
 	default:
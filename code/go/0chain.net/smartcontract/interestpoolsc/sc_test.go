@@ -466,7 +466,7 @@ func TestInterestPoolSmartContract_updateVariables(t *testing.T) {
 		{
 			name: "request not formatted correctly",
 			args: args{
-				t:         testTxn(owner, 100),
+				t:         testTxn(smartcontract.GovernanceAddress, 100),
 				gn:        nil,
 				inputData: []byte("{test}"),
 				balances:  nil,
@@ -477,7 +477,7 @@ func TestInterestPoolSmartContract_updateVariables(t *testing.T) {
 		{
 			name: "ok",
 			args: args{
-				t:  testTxn(owner, 100),
+				t:  testTxn(smartcontract.GovernanceAddress, 100),
 				gn: testGlobalNodeStringTime(globalNode1Ok, 10, 10, 0, 0.10, "5m"),
 				inputData: (&smartcontract.StringMap{
 					Fields: map[string]string{
@@ -729,7 +729,7 @@ func TestInterestPoolSmartContract_Execute(t *testing.T) {
 				SmartContractExecutionStats: map[string]interface{}{},
 			}},
 			args: args{
-				t:        testTxn(owner, 10),
+				t:        testTxn(smartcontract.GovernanceAddress, 10),
 				funcName: "updateVariables",
 				inputData: (&smartcontract.StringMap{
 					Fields: map[string]string{
@@ -29,7 +29,7 @@ func Test_tokenLock_IsLocked(t *testing.T) {
 			fields: fields{
 				StartTime: common.Now(),
 				Duration:  1 * time.Second,
-				Owner:     owner,
+				Owner:     clientID1,
 			},
 			args: args{
 				entity: "Second",
@@ -41,7 +41,7 @@ func Test_tokenLock_IsLocked(t *testing.T) {
 			fields: fields{
 				StartTime: common.Now(),
 				Duration:  1 * time.Second,
-				Owner:     owner,
+				Owner:     clientID1,
 			},
 			args: args{
 				entity: time.Now().Add(5 * time.Second),
@@ -53,7 +53,7 @@ func Test_tokenLock_IsLocked(t *testing.T) {
 			fields: fields{
 				StartTime: common.Now(),
 				Duration:  10 * time.Second,
-				Owner:     owner,
+				Owner:     clientID1,
 			},
 			args: args{
 				entity: time.Now(),
@@ -105,7 +105,7 @@ func Test_tokenLock_LockStats(t *testing.T) {
 			fields: fields{
 				StartTime: commonNow,
 				Duration:  5 * time.Second,
-				Owner:     owner,
+				Owner:     clientID1,
 			},
 			args: args{entity: timeNow},
 			want: (&poolStat{
@@ -120,7 +120,7 @@ func Test_tokenLock_LockStats(t *testing.T) {
 			fields: fields{
 				StartTime: commonNow,
 				Duration:  5 * time.Second,
-				Owner:     owner,
+				Owner:     clientID1,
 			},
 			args: args{entity: timeNow.Add(50 * time.Second)},
 			want: (&poolStat{
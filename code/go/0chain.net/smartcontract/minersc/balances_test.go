@@ -28,6 +28,7 @@ func newTestBalances() *testBalances {
 	return &testBalances{
 		balances: make(map[datastore.Key]state.Balance),
 		tree:     make(map[datastore.Key]util.Serializable),
+		block:    new(block.Block),
 	}
 }
 
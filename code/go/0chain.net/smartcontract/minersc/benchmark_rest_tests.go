@@ -117,6 +117,10 @@ func BenchmarkRestTests(
 				return values
 			}(),
 		},
+		{
+			name:     "miner_rest.nodeScores",
+			endpoint: msc.nodeScoresHandler,
+		},
 		{
 			name:     "miner_rest.configs",
 			endpoint: msc.configHandler,
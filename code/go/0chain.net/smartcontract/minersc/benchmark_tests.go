@@ -185,7 +185,7 @@ func BenchmarkTests(
 			name:     "miner.update_globals",
 			endpoint: msc.updateGlobals,
 			txn: &transaction.Transaction{
-				ClientID: owner,
+				ClientID: sc.GovernanceAddress,
 			},
 			input: (&sc.StringMap{
 				Fields: map[string]string{
@@ -216,7 +216,7 @@ func BenchmarkTests(
 			name:     "miner.update_settings",
 			endpoint: msc.updateSettings,
 			txn: &transaction.Transaction{
-				ClientID: owner,
+				ClientID: sc.GovernanceAddress,
 			},
 			input: (&sc.StringMap{
 				Fields: map[string]string{
@@ -16,7 +16,7 @@ func (msc *MinerSmartContract) addToDelegatePool(t *transaction.Transaction,
 	inputData []byte, gn *GlobalNode, balances cstate.StateContextI) (
 	resp string, err error) {
 
-	var dp deletePool
+	var dp delegatePoolRequest
 	if err = dp.Decode(inputData); err != nil {
 		return "", common.NewErrorf("delegate_pool_add",
 			"decoding request: %v", err)
@@ -75,6 +75,8 @@ func (msc *MinerSmartContract) addToDelegatePool(t *transaction.Transaction,
 	}
 	pool.DelegateID = t.ClientID
 	pool.Status = PENDING
+	pool.AutoCompound = dp.AutoCompound
+	pool.RoundCreated = balances.GetBlock().Round
 
 	Logger.Info("add delegate pool", zap.Any("pool", pool))
 
@@ -197,3 +199,45 @@ func (msc *MinerSmartContract) deleteFromDelegatePool(
 
 	return `{"action": "pool will be released next VC"}`, nil
 }
+
+// updateDelegatePool changes auto-compounding of rewards of a delegate pool;
+// only owner of the pool can change it
+func (msc *MinerSmartContract) updateDelegatePool(t *transaction.Transaction,
+	inputData []byte, _ *GlobalNode, balances cstate.StateContextI) (
+	resp string, err error) {
+
+	var dp delegatePoolRequest
+	if err = dp.Decode(inputData); err != nil {
+		return "", common.NewErrorf("delegate_pool_update",
+			"decoding request: %v", err)
+	}
+
+	var mn *MinerNode
+	if mn, err = getMinerNode(dp.MinerID, balances); err != nil {
+		return "", common.NewErrorf("delegate_pool_update",
+			"error getting miner node: %v", err)
+	}
+
+	var pool, ok = mn.Pending[dp.PoolID]
+	if !ok {
+		if pool, ok = mn.Active[dp.PoolID]; !ok {
+			return "", common.NewError("delegate_pool_update",
+				"pool does not exist or being deleted")
+		}
+	}
+
+	if pool.DelegateID != t.ClientID {
+		return "", common.NewErrorf("delegate_pool_update",
+			"you (%v) do not own the pool, it belongs to %v",
+			t.ClientID, pool.DelegateID)
+	}
+
+	pool.AutoCompound = dp.AutoCompound
+
+	if err = mn.save(balances); err != nil {
+		return "", common.NewErrorf("delegate_pool_update",
+			"saving miner node: %v", err)
+	}
+
+	return string(mn.Encode()), nil
+}
@@ -0,0 +1,421 @@
+package minersc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"0chain.net/chaincore/block"
+	cstate "0chain.net/chaincore/chain/state"
+	sci "0chain.net/chaincore/smartcontractinterface"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/threshold/bls"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+	"0chain.net/smartcontract"
+)
+
+// Types of provable misbehaviour of a miner or a sharder.
+const (
+	// CompetingBlocks is two different blocks of a round signed by
+	// the same generator.
+	CompetingBlocks = "competing_blocks"
+	// CompetingTickets is two verification tickets of the same verifier
+	// for two different blocks of a round proposed by the same generator.
+	CompetingTickets = "competing_tickets"
+	// WrongTicket is a verification ticket for a block not signed by
+	// a miner of the magic block.
+	WrongTicket = "wrong_ticket"
+	// InvalidDKGShare is a DKG share signed by its sender that doesn't
+	// match the MPK of the sender published in the SC.
+	InvalidDKGShare = "invalid_dkg_share"
+)
+
+// DKGShare is a secret share sent by a DKG miner to another one, the
+// sender signs the share along with its MPK and the receiver.
+type DKGShare struct {
+	Sender   string `json:"sender"`
+	Receiver string `json:"receiver"`
+	Share    string `json:"share"`
+	Sign     string `json:"sign"`
+}
+
+// DKGShareMessage returns message signed by sender of a DKG share.
+func DKGShareMessage(mpk []string, receiver, share string) string {
+	return encryption.Hash(strings.Join(mpk, ",") + ":" + receiver + ":" +
+		share)
+}
+
+// NewDKGShareEvidence returns input of the submit_evidence function for
+// given DKG share of the invalid_dkg_share type.
+func NewDKGShareEvidence(share *DKGShare) interface{} {
+	return &evidence{Type: InvalidDKGShare, Share: share}
+}
+
+// evidence of a misbehaviour submitted by a reporter
+type evidence struct {
+	Type    string                      `json:"type"`
+	Blocks  []*block.Header             `json:"blocks,omitempty"`
+	Tickets []*block.VerificationTicket `json:"tickets,omitempty"`
+	Share   *DKGShare                   `json:"share,omitempty"`
+}
+
+func (ev *evidence) decode(input []byte) error {
+	return json.Unmarshal(input, ev)
+}
+
+// round of the misbehaviour, it's the view change round for DKG shares
+func (ev *evidence) round(gn *GlobalNode) int64 {
+	if ev.Type == InvalidDKGShare {
+		return gn.ViewChange
+	}
+	return ev.Blocks[0].Round
+}
+
+// offender is ID of the misbehaving node
+func (ev *evidence) offender() string {
+	switch ev.Type {
+	case CompetingTickets, WrongTicket:
+		return ev.Tickets[0].VerifierID
+	case InvalidDKGShare:
+		return ev.Share.Sender
+	}
+	return ev.Blocks[0].MinerID
+}
+
+// validate the evidence without the signatures
+func (ev *evidence) validate() error {
+	switch ev.Type {
+	case CompetingBlocks, CompetingTickets:
+		return ev.validateCompeting()
+	case WrongTicket:
+		if len(ev.Blocks) != 1 || ev.Blocks[0] == nil {
+			return errors.New("one block header expected")
+		}
+		if len(ev.Tickets) != 1 || ev.Tickets[0] == nil {
+			return errors.New("one verification ticket expected")
+		}
+		if h := ev.Blocks[0]; h.Hash != h.ComputeHash() {
+			return fmt.Errorf("invalid hash of block %s", h.Hash)
+		}
+		return nil
+	case InvalidDKGShare:
+		var s = ev.Share
+		if s == nil || s.Sender == "" || s.Receiver == "" || s.Sign == "" {
+			return errors.New("signed DKG share expected")
+		}
+		if s.Sender == s.Receiver {
+			return errors.New("DKG share sent to itself")
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown evidence type: %q", ev.Type)
+}
+
+func (ev *evidence) validateCompeting() error {
+	if len(ev.Blocks) != 2 || ev.Blocks[0] == nil || ev.Blocks[1] == nil {
+		return errors.New("two block headers expected")
+	}
+
+	var a, b = ev.Blocks[0], ev.Blocks[1]
+	if a.Round != b.Round {
+		return errors.New("blocks of different rounds")
+	}
+	if a.MinerID != b.MinerID {
+		return errors.New("blocks of different generators")
+	}
+	for _, h := range ev.Blocks {
+		if h.Hash != h.ComputeHash() {
+			return fmt.Errorf("invalid hash of block %s", h.Hash)
+		}
+	}
+	if a.Hash == b.Hash {
+		return errors.New("the same block")
+	}
+
+	if ev.Type == CompetingTickets {
+		if len(ev.Tickets) != 2 || ev.Tickets[0] == nil ||
+			ev.Tickets[1] == nil {
+			return errors.New("two verification tickets expected")
+		}
+		if ev.Tickets[0].VerifierID != ev.Tickets[1].VerifierID {
+			return errors.New("tickets of different verifiers")
+		}
+	}
+	return nil
+}
+
+func verifySignature(publicKey, signature, hash string,
+	balances cstate.StateContextI) bool {
+
+	var ss = balances.GetSignatureScheme()
+	if err := ss.SetPublicKey(publicKey); err != nil {
+		return false
+	}
+	var ok, err = ss.Verify(signature, hash)
+	return err == nil && ok
+}
+
+// verify signatures of the evidence by given public key of the offender
+// and the misbehaviour itself
+func (ev *evidence) verify(msc *MinerSmartContract, publicKey string,
+	gn *GlobalNode, balances cstate.StateContextI) error {
+
+	switch ev.Type {
+	case WrongTicket:
+		return ev.verifyWrongTicket(msc, publicKey, gn, balances)
+	case InvalidDKGShare:
+		return ev.verifyDKGShare(publicKey, balances)
+	}
+
+	for i, h := range ev.Blocks {
+		var signature = h.Signature
+		if ev.Type == CompetingTickets {
+			signature = ev.Tickets[i].Signature
+		}
+		if !verifySignature(publicKey, signature, h.Hash, balances) {
+			return fmt.Errorf("invalid signature of block %s", h.Hash)
+		}
+	}
+	return nil
+}
+
+// verifyWrongTicket checks the ticket is signed by the offender and the
+// block is not signed by a miner of the current or the previous magic block
+func (ev *evidence) verifyWrongTicket(msc *MinerSmartContract,
+	publicKey string, gn *GlobalNode, balances cstate.StateContextI) error {
+
+	var h, ticket = ev.Blocks[0], ev.Tickets[0]
+	if pmb := gn.prevMagicBlock(balances); pmb != nil &&
+		h.Round < pmb.StartingRound {
+
+		return fmt.Errorf("block %s is older than the previous magic block",
+			h.Hash)
+	}
+	if !verifySignature(publicKey, ticket.Signature, h.Hash, balances) {
+		return fmt.Errorf("invalid ticket signature of block %s", h.Hash)
+	}
+
+	var generatorKey, err = msc.minerPublicKey(h.MinerID, gn, balances)
+	if err == nil && verifySignature(generatorKey, h.Signature, h.Hash,
+		balances) {
+
+		return fmt.Errorf("block %s is signed by miner %s", h.Hash, h.MinerID)
+	}
+	return nil
+}
+
+// verifyDKGShare checks the share is signed by the offender along with its
+// MPK published in the SC and the share doesn't match the MPK
+func (ev *evidence) verifyDKGShare(publicKey string,
+	balances cstate.StateContextI) error {
+
+	var mpks, err = getMinersMPKs(balances)
+	if err != nil {
+		return fmt.Errorf("can't get MPKs: %v", err)
+	}
+	var s = ev.Share
+	var mpk, ok = mpks.Mpks[s.Sender]
+	if !ok || mpk == nil {
+		return fmt.Errorf("no MPK of sender %s", s.Sender)
+	}
+
+	var msg = DKGShareMessage(mpk.Mpk, s.Receiver, s.Share)
+	if !verifySignature(publicKey, s.Sign, msg, balances) {
+		return errors.New("invalid signature of DKG share")
+	}
+
+	var share bls.Key
+	if err = share.SetHexString(s.Share); err != nil {
+		return nil // malformed share
+	}
+	if bls.ValidateShare(bls.ConvertStringToMpk(mpk.Mpk), share,
+		bls.ComputeIDdkg(s.Receiver)) {
+
+		return errors.New("the DKG share is valid")
+	}
+	return nil
+}
+
+func evidenceKey(tp, offender string, round int64) datastore.Key {
+	return datastore.Key(ADDRESS + ":evidence:" + tp + ":" + offender + ":" +
+		strconv.FormatInt(round, 10))
+}
+
+// slashing is result of a submitted evidence
+type slashing struct {
+	Type     string        `json:"type"`
+	Offender string        `json:"offender"`
+	Round    int64         `json:"round"`
+	Reporter string        `json:"reporter"`
+	Slashed  state.Balance `json:"slashed"`
+	Reward   state.Balance `json:"reward"`
+	Burned   state.Balance `json:"burned"`
+}
+
+func (s *slashing) Encode() []byte {
+	var b, err = json.Marshal(s)
+	if err != nil {
+		panic(err) // must never happen
+	}
+	return b
+}
+
+func (s *slashing) Decode(b []byte) error {
+	return json.Unmarshal(b, s)
+}
+
+// minerPublicKey returns public key of given miner from the current
+// magic block or the previous one
+func (msc *MinerSmartContract) minerPublicKey(id string, gn *GlobalNode,
+	balances cstate.StateContextI) (string, error) {
+
+	if mb, err := getMagicBlock(balances); err == nil {
+		if n := mb.Miners.GetNode(id); n != nil {
+			return n.PublicKey, nil
+		}
+	}
+	if pmb := gn.prevMagicBlock(balances); pmb != nil && pmb.Miners != nil {
+		if n := pmb.Miners.GetNode(id); n != nil {
+			return n.PublicKey, nil
+		}
+	}
+	return "", fmt.Errorf("miner %s not found in magic block", id)
+}
+
+// publicKeyOf returns public key of given miner or sharder from the current
+// magic block, the previous one or the DKG miners list
+func (msc *MinerSmartContract) publicKeyOf(id string, gn *GlobalNode,
+	balances cstate.StateContextI) (string, error) {
+
+	if pk, err := msc.minerPublicKey(id, gn, balances); err == nil {
+		return pk, nil
+	}
+	var mbs []*block.MagicBlock
+	if mb, err := getMagicBlock(balances); err == nil {
+		mbs = append(mbs, mb)
+	}
+	if pmb := gn.prevMagicBlock(balances); pmb != nil {
+		mbs = append(mbs, pmb)
+	}
+	for _, mb := range mbs {
+		if mb.Sharders == nil {
+			continue
+		}
+		if n := mb.Sharders.GetNode(id); n != nil {
+			return n.PublicKey, nil
+		}
+	}
+	if dmn, err := getDKGMinersList(balances); err == nil {
+		if n, ok := dmn.SimpleNodes[id]; ok {
+			return n.PublicKey, nil
+		}
+	}
+	return "", fmt.Errorf("node %s not found in magic block", id)
+}
+
+// slash given part of all delegate pools of given node
+func (mn *MinerNode) slash(rate float64) (slashed state.Balance) {
+	for _, pools := range []map[string]*sci.DelegatePool{mn.Pending, mn.Active} {
+		for _, pool := range pools {
+			var amount = state.Balance(float64(pool.Balance) * rate)
+			if amount <= 0 {
+				continue
+			}
+			pool.Balance -= amount
+			pool.Slashed += amount
+			slashed += amount
+		}
+	}
+	mn.TotalStaked -= int64(slashed)
+	mn.Stat.Slashed += slashed
+	return
+}
+
+// submitEvidence is SC function used by anyone to report a provable
+// misbehaviour of a miner or a sharder; the node is slashed, the reporter
+// gets a part of the slashed tokens and the rest is burned
+func (msc *MinerSmartContract) submitEvidence(
+	t *transaction.Transaction,
+	input []byte,
+	gn *GlobalNode,
+	balances cstate.StateContextI,
+) (resp string, err error) {
+
+	var ev evidence
+	if err = ev.decode(input); err != nil {
+		return "", common.NewError("submit_evidence", err.Error())
+	}
+	if err = ev.validate(); err != nil {
+		return "", common.NewError("submit_evidence",
+			"invalid evidence: "+err.Error())
+	}
+
+	var offender = ev.offender()
+	var publicKey string
+	if publicKey, err = msc.publicKeyOf(offender, gn, balances); err != nil {
+		return "", common.NewError("submit_evidence", err.Error())
+	}
+	if err = ev.verify(msc, publicKey, gn, balances); err != nil {
+		return "", common.NewError("submit_evidence",
+			"invalid evidence: "+err.Error())
+	}
+
+	var key = evidenceKey(ev.Type, offender, ev.round(gn))
+	switch _, err = balances.GetTrieNode(key); err {
+	case nil:
+		return "", common.NewError("submit_evidence",
+			"the misbehaviour is already slashed")
+	case util.ErrValueNotPresent:
+	default:
+		return "", common.NewError("submit_evidence", err.Error())
+	}
+
+	var mn *MinerNode
+	if mn, err = getMinerNode(offender, balances); err != nil {
+		return "", common.NewErrorf("submit_evidence",
+			"can't get node %s: %v", offender, err)
+	}
+
+	var s = &slashing{
+		Type:     ev.Type,
+		Offender: offender,
+		Round:    ev.round(gn),
+		Reporter: t.ClientID,
+		Slashed:  mn.slash(gn.SlashRate),
+	}
+	s.Reward = state.Balance(float64(s.Slashed) * gn.SlashReporterShare)
+	s.Burned = s.Slashed - s.Reward
+
+	if s.Reward > 0 {
+		err = balances.AddTransfer(state.NewTransfer(ADDRESS, t.ClientID,
+			s.Reward))
+		if err != nil {
+			return "", common.NewError("submit_evidence",
+				"paying reporter: "+err.Error())
+		}
+	}
+	if s.Burned > 0 {
+		err = balances.AddTransfer(state.NewTransfer(ADDRESS,
+			smartcontract.BurnAddress, s.Burned))
+		if err != nil {
+			return "", common.NewError("submit_evidence",
+				"burning slashed tokens: "+err.Error())
+		}
+	}
+
+	if err = mn.save(balances); err != nil {
+		return "", common.NewError("submit_evidence", err.Error())
+	}
+	if _, err = balances.InsertTrieNode(key, s); err != nil {
+		return "", common.NewError("submit_evidence",
+			"saving slashing: "+err.Error())
+	}
+
+	return string(s.Encode()), nil
+}
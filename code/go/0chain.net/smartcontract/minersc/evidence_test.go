@@ -0,0 +1,253 @@
+package minersc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"0chain.net/chaincore/block"
+	"0chain.net/chaincore/node"
+	sci "0chain.net/chaincore/smartcontractinterface"
+	"0chain.net/chaincore/threshold/bls"
+	"0chain.net/core/common"
+	"0chain.net/smartcontract"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSignedHeader(t *testing.T, generator *Client, round int64,
+	prevHash string) (h *block.Header) {
+
+	h = &block.Header{
+		MinerID:      generator.id,
+		PrevHash:     prevHash,
+		CreationDate: common.Timestamp(round),
+		Round:        round,
+	}
+	h.Hash = h.ComputeHash()
+	var err error
+	h.Signature, err = generator.scheme.Sign(h.Hash)
+	require.NoError(t, err)
+	return
+}
+
+func newVerificationTicket(t *testing.T, verifier *Client,
+	h *block.Header) *block.VerificationTicket {
+
+	var sig, err = verifier.scheme.Sign(h.Hash)
+	require.NoError(t, err)
+	return &block.VerificationTicket{VerifierID: verifier.id, Signature: sig}
+}
+
+func Test_submitEvidence(t *testing.T) {
+	var (
+		msc       = newTestMinerSC()
+		balances  = newTestBalances()
+		generator = newClient(0, balances)
+		verifier  = newClient(0, balances)
+		reporter  = newClient(0, balances)
+		gn        = &GlobalNode{SlashRate: 0.1, SlashReporterShare: 0.5}
+		lfmb      = new(block.Block)
+	)
+
+	lfmb.MagicBlock = block.NewMagicBlock()
+	lfmb.MagicBlock.Miners = node.NewPool(node.NodeTypeMiner)
+	for _, c := range []*Client{generator, verifier} {
+		var n = node.Provider()
+		n.ID, n.PublicKey = c.id, c.pk
+		lfmb.MagicBlock.Miners.NodesMap[c.id] = n
+
+		var mn = NewMinerNode()
+		mn.ID = c.id
+		var pool = sci.NewDelegatePool()
+		pool.ID = "pool" // the delegate pools are decoded by their IDs
+		pool.Balance = 100e10
+		mn.Active[pool.ID] = pool
+		mn.TotalStaked = 100e10
+		require.NoError(t, mn.save(balances))
+	}
+	balances.setLFMB(lfmb)
+
+	var submit = func(ev *evidence) (*slashing, error) {
+		var tx = newTransaction(reporter.id, ADDRESS, 0, 0)
+		balances.txn = tx
+		var resp, err = msc.submitEvidence(tx, mustEncode(t, ev), gn, balances)
+		if err != nil {
+			return nil, err
+		}
+		var s slashing
+		require.NoError(t, json.Unmarshal([]byte(resp), &s))
+		return &s, nil
+	}
+
+	var (
+		a = newSignedHeader(t, generator, 10, "prev_a")
+		b = newSignedHeader(t, generator, 10, "prev_b")
+		c = newSignedHeader(t, generator, 11, "prev_c")
+	)
+
+	// invalid evidences
+	var _, err = submit(&evidence{Type: CompetingBlocks,
+		Blocks: []*block.Header{a, a}})
+	require.Error(t, err)
+	_, err = submit(&evidence{Type: CompetingBlocks,
+		Blocks: []*block.Header{a, c}})
+	require.Error(t, err)
+	var forged = *b
+	forged.Signature = a.Signature
+	_, err = submit(&evidence{Type: CompetingBlocks,
+		Blocks: []*block.Header{a, &forged}})
+	require.Error(t, err)
+
+	// competing blocks
+	s, err := submit(&evidence{Type: CompetingBlocks,
+		Blocks: []*block.Header{a, b}})
+	require.NoError(t, err)
+	assert.Equal(t, generator.id, s.Offender)
+	assert.EqualValues(t, 10e10, s.Slashed)
+	assert.EqualValues(t, 5e10, s.Reward)
+	assert.EqualValues(t, 5e10, balances.balances[reporter.id])
+	assert.EqualValues(t, 5e10, s.Burned)
+	assert.EqualValues(t, 5e10, balances.balances[smartcontract.BurnAddress])
+
+	mn, err := getMinerNode(generator.id, balances)
+	require.NoError(t, err)
+	require.Contains(t, mn.Active, "pool")
+	assert.EqualValues(t, 90e10, mn.Active["pool"].Balance)
+	assert.EqualValues(t, 10e10, mn.Active["pool"].Slashed)
+	assert.EqualValues(t, 90e10, mn.TotalStaked)
+	assert.EqualValues(t, 10e10, mn.Stat.Slashed)
+
+	// the same misbehaviour can't be slashed twice
+	_, err = submit(&evidence{Type: CompetingBlocks,
+		Blocks: []*block.Header{b, a}})
+	require.Error(t, err)
+
+	// competing tickets
+	s, err = submit(&evidence{Type: CompetingTickets,
+		Blocks: []*block.Header{a, b},
+		Tickets: []*block.VerificationTicket{
+			newVerificationTicket(t, verifier, a),
+			newVerificationTicket(t, verifier, b),
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, verifier.id, s.Offender)
+	assert.EqualValues(t, 10e10, s.Slashed)
+
+	// wrong ticket, the block is signed by its generator
+	_, err = submit(&evidence{Type: WrongTicket,
+		Blocks:  []*block.Header{c},
+		Tickets: []*block.VerificationTicket{newVerificationTicket(t, verifier, c)},
+	})
+	require.Error(t, err)
+
+	var outsider = newClient(0, balances)
+	var d = newSignedHeader(t, outsider, 12, "prev_d")
+	d.MinerID = generator.id
+	d.Hash = d.ComputeHash()
+	d.Signature, err = outsider.scheme.Sign(d.Hash)
+	require.NoError(t, err)
+	s, err = submit(&evidence{Type: WrongTicket,
+		Blocks:  []*block.Header{d},
+		Tickets: []*block.VerificationTicket{newVerificationTicket(t, verifier, d)},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, verifier.id, s.Offender)
+	assert.EqualValues(t, 12, s.Round)
+}
+
+func Test_submitEvidence_invalidDKGShare(t *testing.T) {
+	var (
+		msc      = newTestMinerSC()
+		balances = newTestBalances()
+		sender   = newClient(0, balances)
+		receiver = newClient(0, balances)
+		reporter = newClient(0, balances)
+		sharder  = newClient(0, balances)
+		gn       = &GlobalNode{SlashRate: 0.1, SlashReporterShare: 0.5,
+			ViewChange: 100}
+		lfmb = new(block.Block)
+		dkg  = bls.MakeDKG(2, 2, sender.id)
+	)
+
+	lfmb.MagicBlock = block.NewMagicBlock()
+	lfmb.MagicBlock.Miners = node.NewPool(node.NodeTypeMiner)
+	lfmb.MagicBlock.Sharders = node.NewPool(node.NodeTypeSharder)
+	var sn = node.Provider()
+	sn.ID, sn.PublicKey = sharder.id, sharder.pk
+	lfmb.MagicBlock.Sharders.NodesMap[sharder.id] = sn
+	balances.setLFMB(lfmb)
+
+	// the sender is a new miner known by the DKG miners list only
+	var dmn = NewDKGMinerNodes()
+	dmn.SimpleNodes[sender.id] = &SimpleNode{ID: sender.id, PublicKey: sender.pk}
+	require.NoError(t, updateDKGMinersList(balances, dmn))
+
+	var mpk = &block.MPK{ID: sender.id}
+	for _, pk := range dkg.GetMPKs() {
+		mpk.Mpk = append(mpk.Mpk, pk.GetHexString())
+	}
+	var mpks = block.NewMpks()
+	mpks.Mpks[sender.id] = mpk
+	require.NoError(t, updateMinersMPKs(balances, mpks))
+
+	var mn = NewMinerNode()
+	mn.ID = sender.id
+	var pool = sci.NewDelegatePool()
+	pool.ID = "pool"
+	pool.Balance = 100e10
+	mn.Active[pool.ID] = pool
+	mn.TotalStaked = 100e10
+	require.NoError(t, mn.save(balances))
+
+	// sharders are known too
+	var pk, err = msc.publicKeyOf(sharder.id, gn, balances)
+	require.NoError(t, err)
+	assert.Equal(t, sharder.pk, pk)
+
+	var share = func(to string) *DKGShare {
+		var key, err = dkg.ComputeDKGKeyShare(bls.ComputeIDdkg(to))
+		require.NoError(t, err)
+		var s = &DKGShare{Sender: sender.id, Receiver: receiver.id,
+			Share: key.GetHexString()}
+		s.Sign, err = sender.scheme.Sign(DKGShareMessage(mpk.Mpk, s.Receiver,
+			s.Share))
+		require.NoError(t, err)
+		return s
+	}
+
+	var submit = func(s *DKGShare) (*slashing, error) {
+		var tx = newTransaction(reporter.id, ADDRESS, 0, 0)
+		balances.txn = tx
+		var input, err = json.Marshal(NewDKGShareEvidence(s))
+		require.NoError(t, err)
+		resp, err := msc.submitEvidence(tx, input, gn, balances)
+		if err != nil {
+			return nil, err
+		}
+		var sl slashing
+		require.NoError(t, json.Unmarshal([]byte(resp), &sl))
+		return &sl, nil
+	}
+
+	// valid share
+	_, err = submit(share(receiver.id))
+	require.Error(t, err)
+
+	// invalid share, not signed by the sender
+	var invalid = share(reporter.id)
+	var forged = *invalid
+	forged.Sign, err = reporter.scheme.Sign(DKGShareMessage(mpk.Mpk,
+		forged.Receiver, forged.Share))
+	require.NoError(t, err)
+	_, err = submit(&forged)
+	require.Error(t, err)
+
+	s, err := submit(invalid)
+	require.NoError(t, err)
+	assert.Equal(t, sender.id, s.Offender)
+	assert.EqualValues(t, 100, s.Round)
+	assert.EqualValues(t, 10e10, s.Slashed)
+	assert.EqualValues(t, 5e10, s.Burned)
+}
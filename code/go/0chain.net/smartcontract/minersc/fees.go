@@ -6,6 +6,7 @@ import (
 
 	"0chain.net/chaincore/block"
 	cstate "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/config"
 	sci "0chain.net/chaincore/smartcontractinterface"
 	"0chain.net/chaincore/state"
 	"0chain.net/chaincore/transaction"
@@ -206,6 +207,9 @@ func (msc *MinerSmartContract) viewChangePoolsWork(gn *GlobalNode,
 		}
 		if mn.Delete {
 			miners.Nodes = append(miners.Nodes[:i], miners.Nodes[i+1:]...)
+			if err = addTotalStake(-mn.staked, balances); err != nil {
+				return fmt.Errorf("deleting miner node stake: %v", err)
+			}
 			if _, err := balances.DeleteTrieNode(mn.GetKey()); err != nil {
 				return fmt.Errorf("deleting miner node: %v", err)
 			}
@@ -379,6 +383,15 @@ func (msc *MinerSmartContract) payFees(t *transaction.Transaction,
 		return "", common.NewError("pay_fee", "jumped back in time?")
 	}
 
+	if err = msc.activateGlobals(mb.Round, balances); err != nil {
+		return "", common.NewErrorf("pay_fees",
+			"activating global settings: %v", err)
+	}
+	if err = msc.activateSettings(gn, mb.Round, balances); err != nil {
+		return "", common.NewErrorf("pay_fees",
+			"activating settings: %v", err)
+	}
+
 	// the mb generator
 	var mn *MinerNode
 	if mn, err = getMinerNode(mb.MinerID, balances); err != nil {
@@ -391,6 +404,14 @@ func (msc *MinerSmartContract) payFees(t *transaction.Transaction,
 		zap.Int64("round", mb.Round),
 		zap.String("block", mb.Hash))
 
+	// the rewards are weighted by the performance from the fork only
+	var ep *epochPerformance
+	if config.IsActive(config.ForkPerformanceRewards, mb.Round) {
+		if ep, err = msc.updatePerformance(mb, balances); err != nil {
+			return "", common.NewError("pay_fees", err.Error())
+		}
+	}
+
 	var (
 		// mb reward -- mint for the mb
 		blockReward = state.Balance(
@@ -406,6 +427,16 @@ func (msc *MinerSmartContract) payFees(t *transaction.Transaction,
 		iresp string
 	)
 
+	// the generator's block reward depends on its performance
+	if ep != nil && gn.PerformanceWeight > 0 {
+		var weight float64
+		if weight, err = ep.weight(NodeTypeMiner, mn.ID, gn, balances); err != nil {
+			return "", common.NewError("pay_fees", err.Error())
+		}
+		minerr = state.Balance(float64(minerr) * weight)
+		charger, restr = mn.splitByServiceCharge(minerr)
+	}
+
 	if mn.numActiveDelegates() == 0 {
 		iresp, err = msc.payNode(charger+restr, chargef+restf, mn, gn, balances)
 		if err != nil {
@@ -430,7 +461,8 @@ func (msc *MinerSmartContract) payFees(t *transaction.Transaction,
 		resp += iresp
 	}
 	// pay and mint rest for mb sharders
-	iresp, err = msc.payShardersAndDelegates(sharderf, sharderr, mb, gn, balances)
+	iresp, err = msc.payShardersAndDelegates(sharderf, sharderr, mb, gn, ep,
+		balances)
 	if err != nil {
 		return "", err
 	}
@@ -515,15 +547,30 @@ func (msc *MinerSmartContract) mintStakeHolders(value state.Balance,
 			continue // avoid insufficient minting
 		}
 
-		var mint = state.NewMint(ADDRESS, pool.DelegateID, userMint)
-		if err = balances.AddMint(mint); err != nil {
-			resp += fmt.Sprintf("pay_fee/minting - adding mint: %v", err)
-			continue
-		}
-		msc.addMint(gn, mint.Amount)
 		pool.AddRewards(userMint)
 
-		resp += string(mint.Encode())
+		// reinvested tokens are minted to the SC and kept by the pool
+		var reinvest = node.compoundable(pool, userMint)
+		if reinvest > 0 {
+			var mint = state.NewMint(ADDRESS, ADDRESS, reinvest)
+			if err = balances.AddMint(mint); err != nil {
+				resp += fmt.Sprintf("pay_fee/minting - adding mint: %v", err)
+				continue
+			}
+			msc.addMint(gn, mint.Amount)
+			node.compound(pool, reinvest)
+			resp += string(mint.Encode())
+		}
+
+		if payout := userMint - reinvest; payout > 0 {
+			var mint = state.NewMint(ADDRESS, pool.DelegateID, payout)
+			if err = balances.AddMint(mint); err != nil {
+				resp += fmt.Sprintf("pay_fee/minting - adding mint: %v", err)
+				continue
+			}
+			msc.addMint(gn, mint.Amount)
+			resp += string(mint.Encode())
+		}
 	}
 
 	return resp, nil
@@ -559,13 +606,20 @@ func (msc *MinerSmartContract) payStakeHolders(value state.Balance,
 			continue // avoid insufficient transfer
 		}
 
-		var transfer = state.NewTransfer(ADDRESS, pool.DelegateID, userFee)
-		if err = balances.AddTransfer(transfer); err != nil {
-			return "", fmt.Errorf("adding transfer: %v", err)
+		// fees are already kept by the SC, reinvest them as is
+		var reinvest = node.compoundable(pool, userFee)
+		if reinvest > 0 {
+			node.compound(pool, reinvest)
 		}
 
+		if payout := userFee - reinvest; payout > 0 {
+			var transfer = state.NewTransfer(ADDRESS, pool.DelegateID, payout)
+			if err = balances.AddTransfer(transfer); err != nil {
+				return "", fmt.Errorf("adding transfer: %v", err)
+			}
+			resp += string(transfer.Encode())
+		}
 		pool.AddRewards(userFee)
-		resp += string(transfer.Encode())
 	}
 
 	return resp, nil
@@ -601,10 +655,11 @@ func (msc *MinerSmartContract) getBlockSharders(block *block.Block,
 	return
 }
 
-// pay fees and mint sharders
+// pay fees and mint sharders, the fees and the mint are split between
+// the sharders by their performance weights, if enabled
 func (msc *MinerSmartContract) payShardersAndDelegates(fee, mint state.Balance,
-	block *block.Block, gn *GlobalNode, balances cstate.StateContextI) (
-	resp string, err error) {
+	block *block.Block, gn *GlobalNode, ep *epochPerformance,
+	balances cstate.StateContextI) (resp string, err error) {
 
 	var sharders []*MinerNode
 	if sharders, err = msc.getBlockSharders(block, balances); err != nil {
@@ -612,13 +667,31 @@ func (msc *MinerSmartContract) payShardersAndDelegates(fee, mint state.Balance,
 	}
 
 	// fess and mint
-	var (
-		partf = state.Balance(float64(fee) / float64(len(sharders)))
-		partm = state.Balance(float64(mint) / float64(len(sharders)))
-	)
+	var partsf, partsm = make([]state.Balance, len(sharders)),
+		make([]state.Balance, len(sharders))
+	if ep != nil && gn.PerformanceWeight > 0 {
+		var weights = make([]float64, 0, len(sharders))
+		for _, sh := range sharders {
+			var weight float64
+			weight, err = ep.weight(NodeTypeSharder, sh.ID, gn, balances)
+			if err != nil {
+				return "", common.NewError("pay_fees/pay_sharders",
+					err.Error())
+			}
+			weights = append(weights, weight)
+		}
+		partsf, partsm = splitByWeights(fee, weights),
+			splitByWeights(mint, weights)
+	} else {
+		for i := range sharders {
+			partsf[i] = state.Balance(float64(fee) / float64(len(sharders)))
+			partsm[i] = state.Balance(float64(mint) / float64(len(sharders)))
+		}
+	}
 
 	// part for every sharder
-	for _, sh := range sharders {
+	for i, sh := range sharders {
+		var partf, partm = partsf[i], partsm[i]
 		var sresp string
 		if sh.numActiveDelegates() > 0 {
 			var delegateBr = state.Balance(float64(partm) * (1 - sh.ServiceCharge))
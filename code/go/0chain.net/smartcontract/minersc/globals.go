@@ -71,6 +71,8 @@ const (
 	StuckTimeThreshold // todo from chain
 	SmartContractTimeout
 	SmartContractSettingUpdatePeriod
+	SmartContractMaxGas
+	SmartContractGasPrice
 	LfbTicketRebroadcastTimeout              // todo restart worker
 	LfbTicketAhead                           // todo from chain
 	AsyncFetchingMaxSimultaneousFromMiners   // todo restart worker
@@ -150,6 +152,8 @@ var GlobalSettingName = []string{
 	"server_chain.stuck.time_threshold",
 	"server_chain.smart_contract.timeout",
 	"server_chain.smart_contract.setting_update_period",
+	"server_chain.smart_contract.max_gas",
+	"server_chain.smart_contract.gas_price",
 	"server_chain.lfb_ticket.rebroadcast_timeout",
 	"server_chain.lfb_ticket.ahead",
 	"server_chain.async_blocks_fetching.max_simultaneous_from_miners",
@@ -229,6 +233,8 @@ var GlobalSettingInfo = map[string]struct {
 	GlobalSettingName[StuckTimeThreshold]:                {smartcontract.Duration, false},
 	GlobalSettingName[SmartContractTimeout]:              {smartcontract.Duration, true},
 	GlobalSettingName[SmartContractSettingUpdatePeriod]:  {smartcontract.Int64, true},
+	GlobalSettingName[SmartContractMaxGas]:               {smartcontract.Int64, true},
+	GlobalSettingName[SmartContractGasPrice]:             {smartcontract.Int64, true},
 
 	GlobalSettingName[LfbTicketRebroadcastTimeout]:              {smartcontract.Duration, false},
 	GlobalSettingName[LfbTicketAhead]:                           {smartcontract.Int, false},
@@ -497,18 +503,19 @@ func getGlobalSettings(balances cstate.StateContextI) (*GlobalSettings, error) {
 func (msc *MinerSmartContract) updateGlobals(
 	txn *transaction.Transaction,
 	inputData []byte,
-	_ *GlobalNode,
+	gn *GlobalNode,
 	balances cstate.StateContextI,
 ) (resp string, err error) {
-	if txn.ClientID != owner {
+	if !smartcontract.CanUpdateSettings(txn.ClientID) {
 		return "", common.NewError("update_globals",
-			"unauthorized access - only the owner can update the variables")
+			"unauthorized access - only the governance can update the variables")
 	}
 
-	var changes smartcontract.StringMap
-	if err = changes.Decode(inputData); err != nil {
+	var req smartcontract.ScheduleRequest
+	if err = req.Decode(inputData); err != nil {
 		return "", common.NewError("update_globals", err.Error())
 	}
+	var changes = smartcontract.StringMap{Fields: req.Fields}
 
 	globals, err := getGlobalSettings(balances)
 
@@ -525,6 +532,10 @@ func (msc *MinerSmartContract) updateGlobals(
 		return "", common.NewErrorf("update_settings", "validation: %v", err.Error())
 	}
 
+	if req.ActivationRound != 0 || gn.SettingsTimeLock > 0 {
+		return msc.scheduleGlobals(txn, gn, &req, balances)
+	}
+
 	if err := globals.save(balances); err != nil {
 		return "", common.NewError("update_settings", err.Error())
 	}
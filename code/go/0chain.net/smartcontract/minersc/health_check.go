@@ -1,12 +1,20 @@
 package minersc
 
 import (
+	"encoding/json"
+
 	cstate "0chain.net/chaincore/chain/state"
 	"0chain.net/chaincore/transaction"
 	"0chain.net/core/common"
 	"0chain.net/core/util"
 )
 
+// SharderHealthCheckInput is optional input of the sharder health check,
+// the sharder reports the latest round it has finalized and stored.
+type SharderHealthCheckInput struct {
+	StoredRound int64 `json:"stored_round"`
+}
+
 func (msc *MinerSmartContract) minerHealthCheck(t *transaction.Transaction,
 	inputData []byte, gn *GlobalNode, balances cstate.StateContextI) (
 	resp string, err error) {
@@ -53,12 +61,27 @@ func (msc *MinerSmartContract) minerHealthCheck(t *transaction.Transaction,
 			"can't save miner: "+err.Error())
 	}
 
+	err = msc.addHealthCheck(NodeTypeMiner, t.ClientID, t.CreationDate, 0,
+		gn, balances)
+	if err != nil {
+		return "", common.NewError("miner_health_check_failed",
+			"can't update performance: "+err.Error())
+	}
+
 	return string(existingMiner.Encode()), nil
 }
 
 func (msc *MinerSmartContract) sharderHealthCheck(t *transaction.Transaction,
 	inputData []byte, gn *GlobalNode, balances cstate.StateContextI) (
 	resp string, err error) {
+	var input SharderHealthCheckInput
+	if len(inputData) > 0 {
+		if err = json.Unmarshal(inputData, &input); err != nil {
+			return "", common.NewError("sharder_health_check_failed",
+				"invalid input: "+err.Error())
+		}
+	}
+
 	all, err := getAllShardersList(balances)
 	if err != nil {
 		return "", common.NewError("sharder_health_check_failed",
@@ -100,5 +123,12 @@ func (msc *MinerSmartContract) sharderHealthCheck(t *transaction.Transaction,
 			"can't save sharder: "+err.Error())
 	}
 
+	err = msc.addHealthCheck(NodeTypeSharder, t.ClientID, t.CreationDate,
+		input.StoredRound, gn, balances)
+	if err != nil {
+		return "", common.NewError("sharder_health_check_failed",
+			"can't update performance: "+err.Error())
+	}
+
 	return string(existingSharder.Encode()), nil
 }
@@ -44,12 +44,15 @@ func (msc *MinerSmartContract) initSC() {
 	// as is
 	msc.smartContractFunctions["wait"] = msc.wait
 	msc.smartContractFunctions["update_globals"] = msc.updateGlobals
+	msc.smartContractFunctions["cancel_globals"] = msc.cancelGlobals
 	msc.smartContractFunctions["update_miner_settings"] = msc.UpdateMinerSettings
 	msc.smartContractFunctions["update_sharder_settings"] = msc.UpdateSharderSettings
 	msc.smartContractFunctions["update_settings"] = msc.updateSettings
 	msc.smartContractFunctions["addToDelegatePool"] = msc.addToDelegatePool
 	msc.smartContractFunctions["deleteFromDelegatePool"] = msc.deleteFromDelegatePool
+	msc.smartContractFunctions["updateDelegatePool"] = msc.updateDelegatePool
 	msc.smartContractFunctions["sharder_keep"] = msc.sharderKeep
+	msc.smartContractFunctions["submit_evidence"] = msc.submitEvidence
 }
 
 func (msc *MinerSmartContract) AddMinerIntegrationTests(
@@ -47,12 +47,19 @@ func (msc *MinerSmartContract) InitSmartContractFunctions() {
 	msc.smartContractFunctions["shareSignsOrShares"] = msc.shareSignsOrShares
 	msc.smartContractFunctions["wait"] = msc.wait
 	msc.smartContractFunctions["update_globals"] = msc.updateGlobals
+	msc.smartContractFunctions["cancel_globals"] = msc.cancelGlobals
+	msc.smartContractFunctions["cancel_settings"] = msc.cancelSettings
+	msc.smartContractFunctions["schedule_sc_version"] = msc.scheduleSCVersion
+	msc.smartContractFunctions["cancel_sc_version"] = msc.cancelSCVersion
 	msc.smartContractFunctions["update_settings"] = msc.updateSettings
 	msc.smartContractFunctions["update_miner_settings"] = msc.UpdateMinerSettings
 	msc.smartContractFunctions["update_sharder_settings"] = msc.UpdateSharderSettings
 
 	msc.smartContractFunctions["addToDelegatePool"] = msc.addToDelegatePool
 	msc.smartContractFunctions["deleteFromDelegatePool"] = msc.deleteFromDelegatePool
+	msc.smartContractFunctions["updateDelegatePool"] = msc.updateDelegatePool
 
 	msc.smartContractFunctions["sharder_keep"] = msc.sharderKeep
+
+	msc.smartContractFunctions["submit_evidence"] = msc.submitEvidence
 }
@@ -251,6 +251,23 @@ type GlobalNode struct {
 
 	// If viewchange is false then this will be used to pay interests and rewards to miner/sharders.
 	RewardRoundFrequency int64 `json:"reward_round_frequency"`
+
+	// SettingsTimeLock is min number of rounds between scheduling of
+	// global settings changes and their activation.
+	SettingsTimeLock int64 `json:"settings_time_lock"`
+
+	// SlashRate is part of stake of a node slashed for provable misbehaviour.
+	SlashRate float64 `json:"slash_rate"`
+	// SlashReporterShare is part of slashed tokens paid to the reporter
+	// of the misbehaviour, the rest is burned.
+	SlashReporterShare float64 `json:"slash_reporter_share"`
+
+	// HealthCheckPeriod is expected interval between health checks of
+	// a node, in seconds.
+	HealthCheckPeriod int64 `json:"health_check_period"`
+	// PerformanceWeight is part of block rewards of a node depending on
+	// its performance score over the view change epoch.
+	PerformanceWeight float64 `json:"performance_weight"`
 }
 
 func (gn *GlobalNode) readConfig() {
@@ -275,6 +292,11 @@ func (gn *GlobalNode) readConfig() {
 	gn.RewardDeclineRate = config.SmartContractConfig.GetFloat64(pfx + SettingName[RewardDeclineRate])
 	gn.InterestDeclineRate = config.SmartContractConfig.GetFloat64(pfx + SettingName[InterestDeclineRate])
 	gn.MaxMint = state.Balance(config.SmartContractConfig.GetFloat64(pfx+SettingName[MaxMint]) * 1e10)
+	gn.SettingsTimeLock = config.SmartContractConfig.GetInt64(pfx + SettingName[SettingsTimeLock])
+	gn.SlashRate = config.SmartContractConfig.GetFloat64(pfx + SettingName[SlashRate])
+	gn.SlashReporterShare = config.SmartContractConfig.GetFloat64(pfx + SettingName[SlashReporterShare])
+	gn.HealthCheckPeriod = config.SmartContractConfig.GetInt64(pfx + SettingName[HealthCheckPeriod])
+	gn.PerformanceWeight = config.SmartContractConfig.GetFloat64(pfx + SettingName[PerformanceWeight])
 }
 
 func (gn *GlobalNode) validate() error {
@@ -297,6 +319,30 @@ func (gn *GlobalNode) validate() error {
 	if gn.MaxDelegates <= 0 {
 		return fmt.Errorf("max_delegates is too small: %d", gn.MaxDelegates)
 	}
+
+	if gn.SettingsTimeLock < 0 {
+		return fmt.Errorf("negative settings_time_lock: %d",
+			gn.SettingsTimeLock)
+	}
+
+	if gn.SlashRate < 0 || gn.SlashRate > 1 {
+		return fmt.Errorf("slash_rate not in [0; 1] range: %v", gn.SlashRate)
+	}
+
+	if gn.SlashReporterShare < 0 || gn.SlashReporterShare > 1 {
+		return fmt.Errorf("slash_reporter_share not in [0; 1] range: %v",
+			gn.SlashReporterShare)
+	}
+
+	if gn.HealthCheckPeriod < 0 {
+		return fmt.Errorf("negative health_check_period: %d",
+			gn.HealthCheckPeriod)
+	}
+
+	if gn.PerformanceWeight < 0 || gn.PerformanceWeight > 1 {
+		return fmt.Errorf("performance_weight not in [0; 1] range: %v",
+			gn.PerformanceWeight)
+	}
 	return nil
 }
 
@@ -362,6 +408,16 @@ func (gn *GlobalNode) Get(key Setting) (interface{}, error) {
 		return gn.InterestDeclineRate, nil
 	case MaxMint:
 		return gn.MaxMint, nil
+	case SettingsTimeLock:
+		return gn.SettingsTimeLock, nil
+	case SlashRate:
+		return gn.SlashRate, nil
+	case SlashReporterShare:
+		return gn.SlashReporterShare, nil
+	case HealthCheckPeriod:
+		return gn.HealthCheckPeriod, nil
+	case PerformanceWeight:
+		return gn.PerformanceWeight, nil
 	default:
 		return nil, errors.New("Setting not implemented")
 	}
@@ -598,6 +654,10 @@ type MinerNode struct {
 	Pending     map[string]*sci.DelegatePool `json:"pending,omitempty"`
 	Active      map[string]*sci.DelegatePool `json:"active,omitempty"`
 	Deleting    map[string]*sci.DelegatePool `json:"deleting,omitempty"`
+
+	// staked is active stake of the node as it's stored, the difference
+	// goes to the running total on save
+	staked state.Balance
 }
 
 func NewMinerNode() *MinerNode {
@@ -637,7 +697,53 @@ func (mn *MinerNode) numActiveDelegates() int {
 	return len(mn.Active)
 }
 
+// compoundable returns part of given reward can be reinvested into given
+// delegate pool with regards to max_stake of the node, which limits balance
+// of every delegate pool; the rest of the reward should be payed to the
+// delegate wallet
+func (mn *MinerNode) compoundable(pool *sci.DelegatePool,
+	reward state.Balance) (reinvest state.Balance) {
+
+	if !pool.AutoCompound || pool.Status != ACTIVE || reward <= 0 {
+		return // no auto-compounding or the pool is not active
+	}
+
+	if mn.MaxStake <= 0 {
+		return reward // no boundary
+	}
+
+	if room := mn.MaxStake - pool.Balance; room < reward {
+		if room <= 0 {
+			return 0 // max_stake reached
+		}
+		return room
+	}
+	return reward
+}
+
+// compound given reward tokens to given delegate pool; the tokens should
+// already be kept by the miner SC
+func (mn *MinerNode) compound(pool *sci.DelegatePool, reinvest state.Balance) {
+	pool.Balance += reinvest
+	pool.Compounded += reinvest
+	mn.TotalStaked += int64(reinvest)
+}
+
+// activeStake returns tokens staked in active delegate pools of the node.
+func (mn *MinerNode) activeStake() (stake state.Balance) {
+	for _, pool := range mn.Active {
+		stake += pool.Balance
+	}
+	return
+}
+
 func (mn *MinerNode) save(balances cstate.StateContextI) error {
+	if diff := mn.activeStake() - mn.staked; diff != 0 {
+		if err := addTotalStake(diff, balances); err != nil {
+			return fmt.Errorf("saving total stake: %v", err)
+		}
+		mn.staked += diff
+	}
 	//var key datastore.Key
 	//if key, err = balances.InsertTrieNode(mn.getKey(), mn); err != nil {
 	if _, err := balances.InsertTrieNode(mn.GetKey(), mn); err != nil {
@@ -700,6 +806,7 @@ func (mn *MinerNode) Decode(input []byte) error {
 			return err
 		}
 	}
+	mn.staked = mn.activeStake()
 	return nil
 }
 
@@ -780,6 +887,8 @@ type Stat struct {
 	// for sharder (totals)
 	SharderRewards state.Balance `json:"sharder_rewards,omitempty"`
 	SharderFees    state.Balance `json:"sharder_fees,omitempty"`
+	// for both (totals)
+	Slashed state.Balance `json:"slashed,omitempty"`
 }
 
 type SimpleNode struct {
@@ -922,6 +1031,8 @@ type delegatePoolStat struct {
 	Status       string        `json:"status"`        //
 	High         state.Balance `json:"high"`          // }
 	Low          state.Balance `json:"low"`           // }
+	AutoCompound bool          `json:"auto_compound"` // reinvest rewards
+	Compounded   state.Balance `json:"compounded"`    // total reinvested
 }
 
 func newDelegatePoolStat(dp *sci.DelegatePool) (dps *delegatePoolStat) {
@@ -933,6 +1044,8 @@ func newDelegatePoolStat(dp *sci.DelegatePool) (dps *delegatePoolStat) {
 	dps.Status = dp.Status
 	dps.High = dp.High
 	dps.Low = dp.Low
+	dps.AutoCompound = dp.AutoCompound
+	dps.Compounded = dp.Compounded
 	return
 }
 
@@ -1023,6 +1136,23 @@ func (dp *deletePool) Decode(input []byte) error {
 	return json.Unmarshal(input, dp)
 }
 
+// delegatePoolRequest is request to add a delegate pool or to update
+// settings of an existing one
+type delegatePoolRequest struct {
+	MinerID      string `json:"id"`
+	PoolID       string `json:"pool_id,omitempty"`
+	AutoCompound bool   `json:"auto_compound,omitempty"`
+}
+
+func (dpr *delegatePoolRequest) Encode() []byte {
+	buff, _ := json.Marshal(dpr)
+	return buff
+}
+
+func (dpr *delegatePoolRequest) Decode(input []byte) error {
+	return json.Unmarshal(input, dpr)
+}
+
 type PhaseNode struct {
 	Phase        Phase `json:"phase"`
 	StartRound   int64 `json:"start_round"`
@@ -0,0 +1,443 @@
+package minersc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"0chain.net/chaincore/block"
+	cstate "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/state"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+	"0chain.net/smartcontract"
+)
+
+var performanceKey = datastore.Key(ADDRESS +
+	encryption.Hash("epoch_performance"))
+
+// nodePerformanceKey is key of performance statistic of given node, every
+// node has its own statistic not to make all health check transactions
+// and blocks conflicting
+func nodePerformanceKey(nt NodeType, id string) datastore.Key {
+	return datastore.Key(ADDRESS +
+		encryption.Hash("epoch_performance:"+nt.String()+":"+id))
+}
+
+// nodePerformance is statistic of a node collected over a view change epoch.
+type nodePerformance struct {
+	// StartRound is starting round of the epoch of the statistic.
+	StartRound int64 `json:"start_round"`
+
+	// for miners
+
+	// BlocksGenerated is number of finalized blocks generated.
+	BlocksGenerated int64 `json:"blocks_generated,omitempty"`
+	// Tickets is number of verification tickets of finalized blocks.
+	Tickets int64 `json:"tickets,omitempty"`
+
+	// for sharders
+
+	// BlocksStored is number of rounds of the epoch the sharder reported
+	// as finalized and stored by its health checks.
+	BlocksStored int64 `json:"blocks_stored,omitempty"`
+	// StoredRound is the latest round reported by the sharder.
+	StoredRound int64 `json:"stored_round,omitempty"`
+
+	// for both
+
+	// HealthChecks is number of health check transactions.
+	HealthChecks int64 `json:"health_checks"`
+	// HealthCheckGaps is number of intervals between health checks
+	// longer than configured health check period.
+	HealthCheckGaps int64 `json:"health_check_gaps"`
+	// LastHealthCheck of the epoch.
+	LastHealthCheck common.Timestamp `json:"last_health_check,omitempty"`
+}
+
+func (np *nodePerformance) Encode() []byte {
+	var b, err = json.Marshal(np)
+	if err != nil {
+		panic(err) // must never happen
+	}
+	return b
+}
+
+func (np *nodePerformance) Decode(b []byte) error {
+	return json.Unmarshal(b, np)
+}
+
+// getNodePerformance returns statistic of given node of the epoch started
+// at given round, statistic of a previous epoch is reset
+func getNodePerformance(nt NodeType, id string, startRound int64,
+	balances cstate.StateContextI) (*nodePerformance, error) {
+
+	var val, err = balances.GetTrieNode(nodePerformanceKey(nt, id))
+	if err != nil || val == nil {
+		if err != nil && err != util.ErrValueNotPresent {
+			return nil, err
+		}
+		return &nodePerformance{StartRound: startRound}, nil
+	}
+
+	var np = new(nodePerformance)
+	if err = np.Decode(val.Encode()); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	if np.StartRound != startRound {
+		return &nodePerformance{StartRound: startRound}, nil
+	}
+	return np, nil
+}
+
+func (np *nodePerformance) save(nt NodeType, id string,
+	balances cstate.StateContextI) (err error) {
+
+	_, err = balances.InsertTrieNode(nodePerformanceKey(nt, id), np)
+	return
+}
+
+// ratio in [0; 1] range, the ratio is 1 if nothing is expected
+func ratio(got, expected float64) float64 {
+	if expected <= 0 || got >= expected {
+		return 1
+	}
+	return got / expected
+}
+
+// health is ratio of health checks made to health checks expected
+// within given duration of the epoch
+func (np *nodePerformance) health(duration common.Timestamp,
+	period int64) float64 {
+
+	if period <= 0 {
+		return 1
+	}
+	return ratio(float64(np.HealthChecks), float64(int64(duration)/period))
+}
+
+// score of the node in [0; 1] range, the score is average of the
+// performance metrics relevant for the node type
+func (np *nodePerformance) score(nt NodeType, ep *epochPerformance,
+	period int64) float64 {
+
+	var health = np.health(ep.duration(), period)
+	if nt == NodeTypeSharder {
+		var stored = ratio(float64(np.BlocksStored), float64(ep.Rounds))
+		return (stored + health) / 2
+	}
+	var (
+		generated = ratio(float64(np.BlocksGenerated), ep.expectedBlocks())
+		tickets   = ratio(float64(np.Tickets), float64(ep.Rounds))
+	)
+	return (generated + tickets + health) / 3
+}
+
+// addHealthCheck of the node at given time
+func (np *nodePerformance) addHealthCheck(now common.Timestamp,
+	period int64) {
+
+	if np.LastHealthCheck != 0 && period > 0 &&
+		int64(now-np.LastHealthCheck) > period {
+
+		np.HealthCheckGaps++
+	}
+	np.HealthChecks++
+	np.LastHealthCheck = now
+}
+
+// addStored rounds of the epoch up to given round reported by the sharder
+func (np *nodePerformance) addStored(stored int64) {
+	var from = np.StoredRound
+	if from < np.StartRound-1 {
+		from = np.StartRound - 1
+	}
+	if stored > from {
+		np.BlocksStored += stored - from
+		np.StoredRound = stored
+	}
+}
+
+// epochPerformance is statistic of current view change epoch, the magic
+// block is the same over the epoch, thus all its nodes have the same
+// number of rounds
+type epochPerformance struct {
+	// StartRound is starting round of the magic block of the epoch.
+	StartRound int64 `json:"start_round"`
+	// Rounds is number of finalized blocks of the epoch.
+	Rounds int64 `json:"rounds"`
+	// StartTime and LastTime are creation dates of first and last
+	// blocks of the epoch.
+	StartTime common.Timestamp `json:"start_time"`
+	LastTime  common.Timestamp `json:"last_time"`
+	// Miners is number of miners of the magic block.
+	Miners int `json:"miners"`
+}
+
+func newEpochPerformance(startRound int64) *epochPerformance {
+	return &epochPerformance{StartRound: startRound}
+}
+
+func (ep *epochPerformance) Encode() []byte {
+	var b, err = json.Marshal(ep)
+	if err != nil {
+		panic(err) // must never happen
+	}
+	return b
+}
+
+func (ep *epochPerformance) Decode(b []byte) error {
+	return json.Unmarshal(b, ep)
+}
+
+func (ep *epochPerformance) duration() common.Timestamp {
+	return ep.LastTime - ep.StartTime
+}
+
+// expectedBlocks is expected number of finalized blocks of a miner
+func (ep *epochPerformance) expectedBlocks() float64 {
+	if ep.Miners == 0 {
+		return 0
+	}
+	return float64(ep.Rounds) / float64(ep.Miners)
+}
+
+// node returns statistic of given node of the epoch
+func (ep *epochPerformance) node(nt NodeType, id string,
+	balances cstate.StateContextI) (*nodePerformance, error) {
+
+	return getNodePerformance(nt, id, ep.StartRound, balances)
+}
+
+// weight of rewards of given node, it's 1 if the performance weighting
+// is disabled
+func (ep *epochPerformance) weight(nt NodeType, id string, gn *GlobalNode,
+	balances cstate.StateContextI) (float64, error) {
+
+	if gn.PerformanceWeight <= 0 {
+		return 1, nil
+	}
+	var np, err = ep.node(nt, id, balances)
+	if err != nil {
+		return 0, fmt.Errorf("getting performance of %s: %v", id, err)
+	}
+	var score = np.score(nt, ep, gn.HealthCheckPeriod)
+	return 1 - gn.PerformanceWeight + gn.PerformanceWeight*score, nil
+}
+
+// addBlock updates the statistic by given finalized block of given magic
+// block; the generator is scored by the block and the verifiers by the
+// tickets of the previous block, since the block has no tickets yet
+func (ep *epochPerformance) addBlock(b *block.Block, mb *block.MagicBlock,
+	balances cstate.StateContextI) (err error) {
+
+	if ep.Rounds == 0 {
+		ep.StartTime = b.CreationDate
+	}
+	ep.Rounds++
+	ep.LastTime = b.CreationDate
+	ep.Miners = mb.Miners.Size()
+
+	var np *nodePerformance
+	if np, err = ep.node(NodeTypeMiner, b.MinerID, balances); err != nil {
+		return
+	}
+	np.BlocksGenerated++
+	if err = np.save(NodeTypeMiner, b.MinerID, balances); err != nil {
+		return
+	}
+
+	if b.PrevBlock == nil {
+		return
+	}
+	var verifiers = make(map[string]struct{})
+	for _, vt := range b.PrevBlock.GetVerificationTickets() {
+		if _, ok := verifiers[vt.VerifierID]; ok ||
+			!mb.Miners.HasNode(vt.VerifierID) {
+			continue
+		}
+		verifiers[vt.VerifierID] = struct{}{}
+		if np, err = ep.node(NodeTypeMiner, vt.VerifierID, balances); err != nil {
+			return
+		}
+		np.Tickets++
+		if err = np.save(NodeTypeMiner, vt.VerifierID, balances); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func getEpochPerformance(balances cstate.StateContextI) (
+	*epochPerformance, error) {
+
+	var val, err = balances.GetTrieNode(performanceKey)
+	if err != nil || val == nil {
+		if err != nil && err != util.ErrValueNotPresent {
+			return nil, err
+		}
+		return newEpochPerformance(0), nil
+	}
+
+	var ep = newEpochPerformance(0)
+	if err = ep.Decode(val.Encode()); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return ep, nil
+}
+
+func (ep *epochPerformance) save(balances cstate.StateContextI) (err error) {
+	_, err = balances.InsertTrieNode(performanceKey, ep)
+	return
+}
+
+// updatePerformance adds given finalized block to performance statistic of
+// current epoch, the statistic is reset on view change
+func (msc *MinerSmartContract) updatePerformance(b *block.Block,
+	balances cstate.StateContextI) (ep *epochPerformance, err error) {
+
+	var lfmb = balances.GetLastestFinalizedMagicBlock()
+	if lfmb == nil || lfmb.MagicBlock == nil ||
+		lfmb.MagicBlock.Miners == nil || lfmb.MagicBlock.Sharders == nil {
+		return nil, nil // nothing to update by
+	}
+	var mb = lfmb.MagicBlock
+
+	if ep, err = getEpochPerformance(balances); err != nil {
+		return nil, fmt.Errorf("getting epoch performance: %v", err)
+	}
+	if ep.StartRound != mb.StartingRound {
+		ep = newEpochPerformance(mb.StartingRound)
+	}
+
+	if err = ep.addBlock(b, mb, balances); err != nil {
+		return nil, fmt.Errorf("updating node performance: %v", err)
+	}
+	if err = ep.save(balances); err != nil {
+		return nil, fmt.Errorf("saving epoch performance: %v", err)
+	}
+	return
+}
+
+// addHealthCheck to performance statistic of given node of current epoch,
+// a sharder reports the latest round it has stored
+func (msc *MinerSmartContract) addHealthCheck(nt NodeType, id string,
+	now common.Timestamp, stored int64, gn *GlobalNode,
+	balances cstate.StateContextI) (err error) {
+
+	var ep *epochPerformance
+	if ep, err = getEpochPerformance(balances); err != nil {
+		return fmt.Errorf("getting epoch performance: %v", err)
+	}
+	var np *nodePerformance
+	if np, err = ep.node(nt, id, balances); err != nil {
+		return fmt.Errorf("getting node performance: %v", err)
+	}
+	np.addHealthCheck(now, gn.HealthCheckPeriod)
+	if nt == NodeTypeSharder {
+		if round := balances.GetBlock().Round; stored > round {
+			stored = round // can't store future blocks
+		}
+		np.addStored(stored)
+	}
+	if err = np.save(nt, id, balances); err != nil {
+		return fmt.Errorf("saving node performance: %v", err)
+	}
+	return
+}
+
+// splitByWeights splits given value between sharders according to their
+// rewards weights
+func splitByWeights(value state.Balance, weights []float64) (
+	parts []state.Balance) {
+
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+	parts = make([]state.Balance, len(weights))
+	if total <= 0 {
+		return
+	}
+	for i, w := range weights {
+		parts[i] = state.Balance(float64(value) * w / total)
+	}
+	return
+}
+
+// NodeScore is performance score of a node of current epoch.
+type NodeScore struct {
+	ID          string           `json:"id"`
+	NodeType    NodeType         `json:"node_type"`
+	Score       float64          `json:"score"`
+	Performance *nodePerformance `json:"performance"`
+}
+
+// nodeScoresHandler returns performance scores of miners and sharders of
+// current view change epoch sorted by score descending, the optional 'id'
+// parameter filters the scores by node ID
+func (msc *MinerSmartContract) nodeScoresHandler(ctx context.Context,
+	params url.Values, balances cstate.StateContextI) (
+	resp interface{}, err error) {
+
+	var gn *GlobalNode
+	if gn, err = getGlobalNode(balances); err != nil {
+		return nil, smartcontract.NewErrNoResourceOrErrInternal(err, true,
+			"can't get global node")
+	}
+
+	var ep *epochPerformance
+	if ep, err = getEpochPerformance(balances); err != nil {
+		return nil, smartcontract.NewErrNoResourceOrErrInternal(err, true,
+			"can't get epoch performance")
+	}
+
+	var lfmb = balances.GetLastestFinalizedMagicBlock()
+	if lfmb == nil || lfmb.MagicBlock == nil {
+		return nil, common.NewErrNoResource("no magic block")
+	}
+
+	var (
+		id     = params.Get("id")
+		scores []*NodeScore
+	)
+	for _, nt := range []NodeType{NodeTypeMiner, NodeTypeSharder} {
+		var pool = lfmb.MagicBlock.Miners
+		if nt == NodeTypeSharder {
+			pool = lfmb.MagicBlock.Sharders
+		}
+		if pool == nil {
+			continue
+		}
+		for _, nid := range pool.Keys() {
+			if id != "" && nid != id {
+				continue
+			}
+			var np *nodePerformance
+			if np, err = ep.node(nt, nid, balances); err != nil {
+				return nil, common.NewErrInternal(err.Error())
+			}
+			scores = append(scores, &NodeScore{
+				ID:          nid,
+				NodeType:    nt,
+				Score:       np.score(nt, ep, gn.HealthCheckPeriod),
+				Performance: np,
+			})
+		}
+	}
+	if id != "" && len(scores) == 0 {
+		return nil, common.NewErrNoResource("no performance of node " + id)
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score == scores[j].Score {
+			return scores[i].ID < scores[j].ID
+		}
+		return scores[i].Score > scores[j].Score
+	})
+	return scores, nil
+}
@@ -0,0 +1,158 @@
+package minersc
+
+import (
+	"testing"
+
+	"0chain.net/chaincore/block"
+	"0chain.net/chaincore/node"
+	"0chain.net/chaincore/state"
+	"0chain.net/core/common"
+	"0chain.net/core/encryption"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newPerformanceMagicBlock(miners, sharders []*Client) *block.MagicBlock {
+	var mb = block.NewMagicBlock()
+	mb.Miners = node.NewPool(node.NodeTypeMiner)
+	mb.Sharders = node.NewPool(node.NodeTypeSharder)
+	for _, mn := range miners {
+		var n = node.Provider()
+		n.SetID(mn.id)
+		n.PublicKey = mn.pk
+		n.Type = node.NodeTypeMiner
+		n.SetSignatureSchemeType(encryption.SignatureSchemeBls0chain)
+		mb.Miners.AddNode(n)
+		mn.id = n.GetKey() // the node ID is set by its public key
+	}
+	for _, sh := range sharders {
+		var n = node.Provider()
+		n.SetID(sh.id)
+		n.PublicKey = sh.pk
+		n.Type = node.NodeTypeSharder
+		n.SetSignatureSchemeType(encryption.SignatureSchemeBls0chain)
+		mb.Sharders.AddNode(n)
+		sh.id = n.GetKey() // the node ID is set by its public key
+	}
+	return mb
+}
+
+func Test_epochPerformance_score(t *testing.T) {
+	var (
+		balances = newTestBalances()
+		m1, m2   = newClient(0, balances), newClient(0, balances)
+		s1, s2   = newClient(0, balances), newClient(0, balances)
+		mb       = newPerformanceMagicBlock([]*Client{m1, m2},
+			[]*Client{s1, s2})
+		ep   = newEpochPerformance(0)
+		gn   = &GlobalNode{HealthCheckPeriod: 100, PerformanceWeight: 0.5}
+		prev *block.Block
+	)
+
+	var healthCheck = func(nt NodeType, id string, now common.Timestamp,
+		stored int64) {
+
+		balances.block.Round = stored
+		require.NoError(t, (&MinerSmartContract{}).addHealthCheck(nt, id, now,
+			stored, gn, balances))
+	}
+
+	// m1 generates all blocks, m2 verifies nothing,
+	// s2 doesn't store blocks
+	for i := int64(0); i < 10; i++ {
+		var b = &block.Block{}
+		b.Round = i + 1
+		b.MinerID = m1.id
+		b.CreationDate = common.Timestamp(i * 100)
+		b.PrevBlock = prev
+		b.VerificationTickets = []*block.VerificationTicket{
+			{VerifierID: m1.id}, {VerifierID: m1.id}, {VerifierID: "unknown"},
+		}
+		require.NoError(t, ep.addBlock(b, mb, balances))
+		healthCheck(NodeTypeMiner, m1.id, b.CreationDate, 0)
+		healthCheck(NodeTypeSharder, s1.id, b.CreationDate, b.Round)
+		healthCheck(NodeTypeSharder, s2.id, b.CreationDate, -1)
+		prev = b
+	}
+	healthCheck(NodeTypeMiner, m2.id, 100, 0)
+	healthCheck(NodeTypeMiner, m2.id, 600, 0)
+
+	var node = func(nt NodeType, id string) *nodePerformance {
+		var np, err = ep.node(nt, id, balances)
+		require.NoError(t, err)
+		return np
+	}
+	var weight = func(nt NodeType, id string) float64 {
+		var w, err = ep.weight(nt, id, gn, balances)
+		require.NoError(t, err)
+		return w
+	}
+
+	assert.EqualValues(t, 10, ep.Rounds)
+	assert.EqualValues(t, 900, ep.duration())
+	assert.EqualValues(t, 5, ep.expectedBlocks())
+	assert.EqualValues(t, 10, node(NodeTypeMiner, m1.id).BlocksGenerated)
+	// tickets of the last block are not counted yet, duplicates are ignored
+	assert.EqualValues(t, 9, node(NodeTypeMiner, m1.id).Tickets)
+	assert.EqualValues(t, 1, node(NodeTypeMiner, m2.id).HealthCheckGaps)
+	assert.EqualValues(t, 10, node(NodeTypeSharder, s1.id).BlocksStored)
+
+	var score = func(nt NodeType, id string) float64 {
+		return node(nt, id).score(nt, ep, gn.HealthCheckPeriod)
+	}
+	assert.InDelta(t, (1+0.9+1)/3.0, score(NodeTypeMiner, m1.id), 1e-9)
+	assert.InDelta(t, (0+0+2.0/9)/3, score(NodeTypeMiner, m2.id), 1e-9)
+	assert.EqualValues(t, 1, score(NodeTypeSharder, s1.id))
+	assert.EqualValues(t, 0.5, score(NodeTypeSharder, s2.id))
+
+	assert.EqualValues(t, 0.75, weight(NodeTypeSharder, s2.id))
+	gn.PerformanceWeight = 0
+	assert.EqualValues(t, 1, weight(NodeTypeSharder, s2.id))
+}
+
+func Test_updatePerformance(t *testing.T) {
+	var (
+		msc      = newTestMinerSC()
+		balances = newTestBalances()
+		m1, s1   = newClient(0, balances), newClient(0, balances)
+		mb       = newPerformanceMagicBlock([]*Client{m1}, []*Client{s1})
+		b        = &block.Block{}
+	)
+	b.Round = 1
+	b.MinerID = m1.id
+
+	// no magic block
+	var ep, err = msc.updatePerformance(b, balances)
+	require.NoError(t, err)
+	require.Nil(t, ep)
+
+	mb.StartingRound = 1
+	balances.setLFMB(&block.Block{MagicBlock: mb})
+	_, err = msc.updatePerformance(b, balances)
+	require.NoError(t, err)
+	ep, err = msc.updatePerformance(b, balances)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, ep.Rounds)
+
+	// view change resets the statistic
+	mb.StartingRound = 2
+	ep, err = msc.updatePerformance(b, balances)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, ep.Rounds)
+	assert.EqualValues(t, 2, ep.StartRound)
+
+	ep, err = getEpochPerformance(balances)
+	require.NoError(t, err)
+	var np *nodePerformance
+	np, err = ep.node(NodeTypeMiner, m1.id, balances)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, np.BlocksGenerated)
+}
+
+func Test_splitByWeights(t *testing.T) {
+	assert.Equal(t, []state.Balance{25, 75},
+		splitByWeights(100, []float64{0.5, 1.5}))
+	assert.Equal(t, []state.Balance{0, 0},
+		splitByWeights(100, []float64{0, 0}))
+}
@@ -26,7 +26,6 @@ import (
 const (
 	//ADDRESS address of minersc
 	ADDRESS = "6dba10422e368813802877a85039d3985d96760ed844092319743fb3a76712d9"
-	owner   = "1746b06bb09f55ee01b33b5e2e055d6cc7a900cb57c0a3a5eaabb8a0e7745802"
 	name    = "miner"
 )
 
@@ -92,6 +91,10 @@ func (msc *MinerSmartContract) GetRestPoints() map[string]sci.SmartContractRestH
 func (msc *MinerSmartContract) setSC(sc *sci.SmartContract, bcContext sci.BCContextI) {
 	msc.SmartContract = sc
 	msc.SmartContract.RestHandlers["/globalSettings"] = msc.getGlobalsHandler
+	msc.SmartContract.RestHandlers["/pendingGlobalSettings"] = msc.getPendingGlobalsHandler
+	msc.SmartContract.RestHandlers["/pendingSettings"] = msc.getPendingSettingsHandler
+	msc.SmartContract.RestHandlers["/scVersions"] = msc.getSCVersionsHandler
+	msc.SmartContract.RestHandlers["/nodeScores"] = msc.nodeScoresHandler
 	msc.SmartContract.RestHandlers["/getNodepool"] = msc.GetNodepoolHandler
 	msc.SmartContract.RestHandlers["/getUserPools"] = msc.GetUserPoolsHandler
 	msc.SmartContract.RestHandlers["/getMinerList"] = msc.GetMinerListHandler
@@ -0,0 +1,109 @@
+package minersc
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+
+	cstate "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/smartcontract"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	sc "0chain.net/smartcontract"
+)
+
+// scheduleSCVersion schedules activation of a smart contract version,
+// the activation is time-locked as the global settings changes
+func (msc *MinerSmartContract) scheduleSCVersion(
+	txn *transaction.Transaction,
+	inputData []byte,
+	gn *GlobalNode,
+	balances cstate.StateContextI,
+) (resp string, err error) {
+	if !sc.CanUpdateSettings(txn.ClientID) {
+		return "", common.NewError("schedule_sc_version",
+			"unauthorized access - only the governance can update the variables")
+	}
+
+	var req smartcontract.ScheduleVersionRequest
+	if err = req.Decode(inputData); err != nil {
+		return "", common.NewError("schedule_sc_version", err.Error())
+	}
+
+	var activation int64
+	activation, err = req.Activation(balances.GetBlock().Round,
+		gn.SettingsTimeLock)
+	if err != nil {
+		return "", common.NewError("schedule_sc_version", err.Error())
+	}
+
+	var cv *smartcontract.ContractVersions
+	if cv, err = smartcontract.GetContractVersionsNode(balances); err != nil {
+		return "", common.NewError("schedule_sc_version",
+			"can't get smart contracts versions: "+err.Error())
+	}
+
+	if err = cv.Schedule(req.Address, req.Version, activation); err != nil {
+		return "", common.NewError("schedule_sc_version", err.Error())
+	}
+
+	if err = smartcontract.SaveContractVersionsNode(cv, balances); err != nil {
+		return "", common.NewError("schedule_sc_version", err.Error())
+	}
+
+	var b, _ = json.Marshal(&smartcontract.VersionActivation{
+		Version: req.Version,
+		Round:   activation,
+	})
+	return string(b), nil
+}
+
+// cancelSCVersion removes activations of a smart contract versions
+// scheduled after current round
+func (msc *MinerSmartContract) cancelSCVersion(
+	txn *transaction.Transaction,
+	inputData []byte,
+	_ *GlobalNode,
+	balances cstate.StateContextI,
+) (resp string, err error) {
+	if !sc.CanUpdateSettings(txn.ClientID) {
+		return "", common.NewError("cancel_sc_version",
+			"unauthorized access - only the governance can update the variables")
+	}
+
+	var req smartcontract.ScheduleVersionRequest
+	if err = req.Decode(inputData); err != nil {
+		return "", common.NewError("cancel_sc_version", err.Error())
+	}
+
+	var cv *smartcontract.ContractVersions
+	if cv, err = smartcontract.GetContractVersionsNode(balances); err != nil {
+		return "", common.NewError("cancel_sc_version",
+			"can't get smart contracts versions: "+err.Error())
+	}
+
+	if !cv.Cancel(req.Address, balances.GetBlock().Round) {
+		return "", common.NewError("cancel_sc_version",
+			"no scheduled versions found: "+req.Address)
+	}
+
+	if err = smartcontract.SaveContractVersionsNode(cv, balances); err != nil {
+		return "", common.NewError("cancel_sc_version", err.Error())
+	}
+
+	return "", nil
+}
+
+// getSCVersionsHandler returns activations of the smart contracts versions
+func (msc *MinerSmartContract) getSCVersionsHandler(
+	_ context.Context,
+	_ url.Values,
+	balances cstate.StateContextI,
+) (interface{}, error) {
+	cv, err := smartcontract.GetContractVersionsNode(balances)
+	if err != nil {
+		return nil, sc.NewErrNoResourceOrErrInternal(err, true,
+			"can't get smart contracts versions")
+	}
+	return cv, nil
+}
@@ -0,0 +1,63 @@
+package minersc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"0chain.net/chaincore/block"
+	"0chain.net/chaincore/smartcontract"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/datastore"
+	"0chain.net/core/util"
+	sc "0chain.net/smartcontract"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduleSCVersion(t *testing.T) {
+	var (
+		msc      = newTestMinerSC()
+		balances = &mockStateContext{
+			block: &block.Block{},
+			store: make(map[datastore.Key]util.Serializable),
+		}
+		gn = &GlobalNode{SettingsTimeLock: 10}
+	)
+	balances.block.Round = 100
+	smartcontract.RegisterContractVersion(2, msc)
+
+	var schedule = func(client string, version int, activation int64) error {
+		var input, err = json.Marshal(&smartcontract.ScheduleVersionRequest{
+			Address:         ADDRESS,
+			Version:         version,
+			ActivationRound: activation,
+		})
+		require.NoError(t, err)
+		_, err = msc.scheduleSCVersion(&transaction.Transaction{
+			ClientID: client,
+		}, input, gn, balances)
+		return err
+	}
+
+	require.Error(t, schedule("not_owner", 2, 0))            // unauthorized
+	require.Error(t, schedule(sc.GovernanceAddress, 2, 105)) // time lock
+	require.Error(t, schedule(sc.GovernanceAddress, 0, 0))   // invalid version
+	require.NoError(t, schedule(sc.GovernanceAddress, 2, 0)) // round 110
+
+	cv, err := smartcontract.GetContractVersionsNode(balances)
+	require.NoError(t, err)
+	require.Equal(t, smartcontract.DefaultContractVersion, cv.Active(ADDRESS, 109))
+	require.Equal(t, 2, cv.Active(ADDRESS, 110))
+
+	var input []byte
+	input, err = json.Marshal(&smartcontract.ScheduleVersionRequest{
+		Address: ADDRESS,
+	})
+	require.NoError(t, err)
+	_, err = msc.cancelSCVersion(&transaction.Transaction{ClientID: sc.GovernanceAddress},
+		input, gn, balances)
+	require.NoError(t, err)
+	_, err = msc.cancelSCVersion(&transaction.Transaction{ClientID: sc.GovernanceAddress},
+		input, gn, balances)
+	require.Error(t, err)
+}
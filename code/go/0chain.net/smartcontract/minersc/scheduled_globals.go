@@ -0,0 +1,191 @@
+package minersc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	cstate "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+	"0chain.net/smartcontract"
+)
+
+var scheduledGlobalsKey = datastore.Key(ADDRESS +
+	encryption.Hash("scheduled_global_settings"))
+
+func getScheduledGlobals(balances cstate.StateContextI) (
+	*smartcontract.ScheduledChanges, error) {
+
+	var val, err = balances.GetTrieNode(scheduledGlobalsKey)
+	if err != nil || val == nil {
+		if err != nil && err != util.ErrValueNotPresent {
+			return nil, err
+		}
+		return new(smartcontract.ScheduledChanges), nil
+	}
+
+	var scheduled = new(smartcontract.ScheduledChanges)
+	if err = scheduled.Decode(val.Encode()); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return scheduled, nil
+}
+
+func saveScheduledGlobals(scheduled *smartcontract.ScheduledChanges,
+	balances cstate.StateContextI) (err error) {
+
+	_, err = balances.InsertTrieNode(scheduledGlobalsKey, scheduled)
+	return
+}
+
+// scheduleGlobals queues time-locked changes of the global settings,
+// the changes should be validated already
+func (msc *MinerSmartContract) scheduleGlobals(
+	txn *transaction.Transaction,
+	gn *GlobalNode,
+	req *smartcontract.ScheduleRequest,
+	balances cstate.StateContextI,
+) (resp string, err error) {
+
+	var activation int64
+	activation, err = req.Activation(balances.GetBlock().Round,
+		gn.SettingsTimeLock)
+	if err != nil {
+		return "", common.NewError("update_globals", err.Error())
+	}
+
+	var scheduled *smartcontract.ScheduledChanges
+	if scheduled, err = getScheduledGlobals(balances); err != nil {
+		return "", common.NewError("update_globals",
+			"can't get scheduled changes: "+err.Error())
+	}
+
+	var sc = &smartcontract.ScheduledChange{
+		ID:              txn.Hash,
+		ActivationRound: activation,
+		Fields:          req.Fields,
+	}
+	if err = scheduled.Add(sc); err != nil {
+		return "", common.NewError("update_globals", err.Error())
+	}
+
+	if err = saveScheduledGlobals(scheduled, balances); err != nil {
+		return "", common.NewError("update_globals", err.Error())
+	}
+
+	var b, _ = json.Marshal(sc)
+	return string(b), nil
+}
+
+// activateGlobals applies time-locked changes of the global settings
+// should be activated at given round; changes became invalid since they
+// have been scheduled are dropped
+func (msc *MinerSmartContract) activateGlobals(round int64,
+	balances cstate.StateContextI) (err error) {
+
+	var scheduled *smartcontract.ScheduledChanges
+	if scheduled, err = getScheduledGlobals(balances); err != nil {
+		return fmt.Errorf("can't get scheduled changes: %v", err)
+	}
+	if !scheduled.IsDue(round) {
+		return
+	}
+
+	var globals *GlobalSettings
+	switch globals, err = getGlobalSettings(balances); err {
+	case nil:
+	case util.ErrValueNotPresent:
+		globals = &GlobalSettings{Fields: getStringMapFromViper()}
+	default:
+		return fmt.Errorf("can't get global settings: %v", err)
+	}
+
+	for _, sc := range scheduled.Due(round) {
+		var next = &GlobalSettings{Fields: make(map[string]string)}
+		for key, value := range globals.Fields {
+			next.Fields[key] = value
+		}
+		if next.update(smartcontract.StringMap{Fields: sc.Fields}) != nil {
+			continue // drop invalid changes
+		}
+		globals = next
+	}
+
+	if err = globals.save(balances); err != nil {
+		return fmt.Errorf("saving global settings: %v", err)
+	}
+	if err = saveScheduledGlobals(scheduled, balances); err != nil {
+		return fmt.Errorf("saving scheduled changes: %v", err)
+	}
+	return
+}
+
+// cancelGlobals removes time-locked changes of the global settings
+// before their activation
+func (msc *MinerSmartContract) cancelGlobals(
+	txn *transaction.Transaction,
+	inputData []byte,
+	_ *GlobalNode,
+	balances cstate.StateContextI,
+) (resp string, err error) {
+	if !smartcontract.CanUpdateSettings(txn.ClientID) {
+		return "", common.NewError("cancel_globals",
+			"unauthorized access - only the governance can update the variables")
+	}
+
+	var req smartcontract.CancelScheduleRequest
+	if err = req.Decode(inputData); err != nil {
+		return "", common.NewError("cancel_globals", err.Error())
+	}
+
+	var scheduled *smartcontract.ScheduledChanges
+	if scheduled, err = getScheduledGlobals(balances); err != nil {
+		return "", common.NewError("cancel_globals",
+			"can't get scheduled changes: "+err.Error())
+	}
+
+	if !scheduled.Cancel(req.ID) {
+		return "", common.NewError("cancel_globals",
+			"no scheduled changes found: "+req.ID)
+	}
+
+	if err = saveScheduledGlobals(scheduled, balances); err != nil {
+		return "", common.NewError("cancel_globals", err.Error())
+	}
+
+	return "", nil
+}
+
+// getPendingGlobalsHandler returns time-locked changes of the global
+// settings with diff against current values
+func (msc *MinerSmartContract) getPendingGlobalsHandler(
+	ctx context.Context,
+	params url.Values,
+	balances cstate.StateContextI,
+) (interface{}, error) {
+	var globals, err = msc.getGlobalsHandler(ctx, params, balances)
+	if err != nil {
+		return nil, err
+	}
+
+	var current map[string]string
+	switch gl := globals.(type) {
+	case *GlobalSettings:
+		current = gl.Fields
+	case GlobalSettings:
+		current = gl.Fields
+	}
+
+	scheduled, err := getScheduledGlobals(balances)
+	if err != nil {
+		return nil, smartcontract.NewErrNoResourceOrErrInternal(err, true,
+			"can't get scheduled changes")
+	}
+
+	return scheduled.Preview(current), nil
+}
@@ -0,0 +1,90 @@
+package minersc
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"0chain.net/chaincore/block"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/datastore"
+	"0chain.net/core/util"
+	"0chain.net/smartcontract"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledGlobals(t *testing.T) {
+	const maxBlockSize = "server_chain.block.max_block_size"
+
+	var (
+		msc      = newTestMinerSC()
+		balances = &mockStateContext{
+			block: &block.Block{},
+			store: make(map[datastore.Key]util.Serializable),
+		}
+		gn  = &GlobalNode{SettingsTimeLock: 10}
+		err error
+	)
+	balances.block.Round = 100
+	require.NoError(t, (&GlobalSettings{
+		Fields: map[string]string{maxBlockSize: "1"},
+	}).save(balances))
+
+	var current = func() string {
+		var globals, err = getGlobalSettings(balances)
+		require.NoError(t, err)
+		return globals.Fields[maxBlockSize]
+	}
+
+	var schedule = func(hash string, activation int64, value string) error {
+		var input, err = json.Marshal(&smartcontract.ScheduleRequest{
+			ActivationRound: activation,
+			Fields:          map[string]string{maxBlockSize: value},
+		})
+		require.NoError(t, err)
+		_, err = msc.updateGlobals(&transaction.Transaction{
+			HashIDField: datastore.HashIDField{Hash: hash},
+			ClientID:    smartcontract.GovernanceAddress,
+		}, input, gn, balances)
+		return err
+	}
+
+	require.Error(t, schedule("one", 105, "10"))   // time lock
+	require.Error(t, schedule("one", 0, "ten"))    // invalid value
+	require.NoError(t, schedule("one", 0, "10"))   // round 110
+	require.NoError(t, schedule("two", 120, "20")) // round 120
+
+	// not applied yet
+	assert.Equal(t, "1", current())
+
+	resp, err := msc.getPendingGlobalsHandler(context.Background(),
+		url.Values{}, balances)
+	require.NoError(t, err)
+	var pending = resp.([]*smartcontract.PendingChange)
+	require.Len(t, pending, 2)
+	assert.Equal(t, []smartcontract.SettingDiff{
+		{Name: maxBlockSize, Current: "1", Pending: "10"},
+	}, pending[0].Diff)
+
+	require.NoError(t, msc.activateGlobals(109, balances))
+	assert.Equal(t, "1", current())
+
+	require.NoError(t, msc.activateGlobals(110, balances))
+	assert.Equal(t, "10", current())
+
+	var input []byte
+	input, err = json.Marshal(&smartcontract.CancelScheduleRequest{ID: "two"})
+	require.NoError(t, err)
+	_, err = msc.cancelGlobals(&transaction.Transaction{ClientID: smartcontract.GovernanceAddress},
+		input, gn, balances)
+	require.NoError(t, err)
+	_, err = msc.cancelGlobals(&transaction.Transaction{ClientID: smartcontract.GovernanceAddress},
+		input, gn, balances)
+	require.Error(t, err)
+
+	require.NoError(t, msc.activateGlobals(120, balances))
+	assert.Equal(t, "10", current())
+}
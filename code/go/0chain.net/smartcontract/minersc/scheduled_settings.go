@@ -0,0 +1,193 @@
+package minersc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	cstate "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+	"0chain.net/smartcontract"
+)
+
+var scheduledSettingsKey = datastore.Key(ADDRESS +
+	encryption.Hash("scheduled_setting_changes"))
+
+func getScheduledSettings(balances cstate.StateContextI) (
+	*smartcontract.ScheduledChanges, error) {
+
+	var val, err = balances.GetTrieNode(scheduledSettingsKey)
+	if err != nil || val == nil {
+		if err != nil && err != util.ErrValueNotPresent {
+			return nil, err
+		}
+		return new(smartcontract.ScheduledChanges), nil
+	}
+
+	var scheduled = new(smartcontract.ScheduledChanges)
+	if err = scheduled.Decode(val.Encode()); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return scheduled, nil
+}
+
+func saveScheduledSettings(scheduled *smartcontract.ScheduledChanges,
+	balances cstate.StateContextI) (err error) {
+
+	_, err = balances.InsertTrieNode(scheduledSettingsKey, scheduled)
+	return
+}
+
+// updatedCopy returns copy of the global node with given changes applied
+// and validated, the global node itself is not changed
+func (gn *GlobalNode) updatedCopy(changes smartcontract.StringMap) (
+	next *GlobalNode, err error) {
+
+	next = new(GlobalNode)
+	if err = next.Decode(gn.Encode()); err != nil {
+		return
+	}
+	if err = next.update(changes); err != nil {
+		return
+	}
+	if err = next.validate(); err != nil {
+		return
+	}
+	return
+}
+
+// scheduleSettings queues time-locked changes of the settings, the
+// changes should be validated already; the settings_time_lock itself
+// is changed the same way
+func (msc *MinerSmartContract) scheduleSettings(
+	txn *transaction.Transaction,
+	gn *GlobalNode,
+	req *smartcontract.ScheduleRequest,
+	balances cstate.StateContextI,
+) (resp string, err error) {
+
+	var activation int64
+	activation, err = req.Activation(balances.GetBlock().Round,
+		gn.SettingsTimeLock)
+	if err != nil {
+		return "", common.NewError("update_settings", err.Error())
+	}
+
+	var scheduled *smartcontract.ScheduledChanges
+	if scheduled, err = getScheduledSettings(balances); err != nil {
+		return "", common.NewError("update_settings",
+			"can't get scheduled changes: "+err.Error())
+	}
+
+	var sc = &smartcontract.ScheduledChange{
+		ID:              txn.Hash,
+		ActivationRound: activation,
+		Fields:          req.Fields,
+	}
+	if err = scheduled.Add(sc); err != nil {
+		return "", common.NewError("update_settings", err.Error())
+	}
+
+	if err = saveScheduledSettings(scheduled, balances); err != nil {
+		return "", common.NewError("update_settings", err.Error())
+	}
+
+	var b, _ = json.Marshal(sc)
+	return string(b), nil
+}
+
+// activateSettings applies time-locked changes of the settings should be
+// activated at given round to the global node; changes became invalid
+// since they have been scheduled are dropped; the global node should be
+// saved by caller
+func (msc *MinerSmartContract) activateSettings(gn *GlobalNode, round int64,
+	balances cstate.StateContextI) (err error) {
+
+	var scheduled *smartcontract.ScheduledChanges
+	if scheduled, err = getScheduledSettings(balances); err != nil {
+		return fmt.Errorf("can't get scheduled changes: %v", err)
+	}
+	if !scheduled.IsDue(round) {
+		return
+	}
+
+	for _, sc := range scheduled.Due(round) {
+		var next *GlobalNode
+		next, err = gn.updatedCopy(smartcontract.StringMap{Fields: sc.Fields})
+		if err != nil {
+			continue // drop invalid changes
+		}
+		*gn = *next
+	}
+
+	if err = saveScheduledSettings(scheduled, balances); err != nil {
+		return fmt.Errorf("saving scheduled changes: %v", err)
+	}
+	return
+}
+
+// cancelSettings removes time-locked changes of the settings before
+// their activation
+func (msc *MinerSmartContract) cancelSettings(
+	txn *transaction.Transaction,
+	inputData []byte,
+	_ *GlobalNode,
+	balances cstate.StateContextI,
+) (resp string, err error) {
+	if !smartcontract.CanUpdateSettings(txn.ClientID) {
+		return "", common.NewError("cancel_settings",
+			"unauthorized access - only the governance can update the variables")
+	}
+
+	var req smartcontract.CancelScheduleRequest
+	if err = req.Decode(inputData); err != nil {
+		return "", common.NewError("cancel_settings", err.Error())
+	}
+
+	var scheduled *smartcontract.ScheduledChanges
+	if scheduled, err = getScheduledSettings(balances); err != nil {
+		return "", common.NewError("cancel_settings",
+			"can't get scheduled changes: "+err.Error())
+	}
+
+	if !scheduled.Cancel(req.ID) {
+		return "", common.NewError("cancel_settings",
+			"no scheduled changes found: "+req.ID)
+	}
+
+	if err = saveScheduledSettings(scheduled, balances); err != nil {
+		return "", common.NewError("cancel_settings", err.Error())
+	}
+
+	return "", nil
+}
+
+// getPendingSettingsHandler returns time-locked changes of the settings
+// with diff against current values
+func (msc *MinerSmartContract) getPendingSettingsHandler(
+	_ context.Context,
+	_ url.Values,
+	balances cstate.StateContextI,
+) (interface{}, error) {
+	gn, err := getGlobalNode(balances)
+	if err != nil {
+		return nil, common.NewErrInternal(err.Error())
+	}
+	current, err := gn.getConfigMap()
+	if err != nil {
+		return nil, common.NewErrInternal(err.Error())
+	}
+
+	scheduled, err := getScheduledSettings(balances)
+	if err != nil {
+		return nil, smartcontract.NewErrNoResourceOrErrInternal(err, true,
+			"can't get scheduled changes")
+	}
+
+	return scheduled.Preview(current.Fields), nil
+}
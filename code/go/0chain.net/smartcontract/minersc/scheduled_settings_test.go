@@ -0,0 +1,67 @@
+package minersc
+
+import (
+	"encoding/json"
+	"testing"
+
+	"0chain.net/chaincore/block"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/datastore"
+	"0chain.net/core/util"
+	"0chain.net/smartcontract"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheduledSettings(t *testing.T) {
+	var (
+		msc      = newTestMinerSC()
+		balances = &mockStateContext{
+			block: &block.Block{},
+			store: make(map[datastore.Key]util.Serializable),
+		}
+		gn = &GlobalNode{MinN: 1, MaxN: 1, MinS: 1, MaxS: 1, MaxDelegates: 1,
+			SettingsTimeLock: 10, SlashRate: 0.1}
+		err error
+	)
+	require.NoError(t, gn.validate())
+	balances.block.Round = 100
+
+	var update = func(hash string, activation int64, key, value string) error {
+		var input, err = json.Marshal(&smartcontract.ScheduleRequest{
+			ActivationRound: activation,
+			Fields:          map[string]string{key: value},
+		})
+		require.NoError(t, err)
+		_, err = msc.updateSettings(&transaction.Transaction{
+			HashIDField: datastore.HashIDField{Hash: hash},
+			ClientID:    smartcontract.GovernanceAddress,
+		}, input, gn, balances)
+		return err
+	}
+
+	require.Error(t, update("one", 105, "slash_rate", "0.5")) // time lock
+	require.Error(t, update("one", 0, "slash_rate", "half"))  // invalid value
+	require.NoError(t, update("one", 0, "slash_rate", "0.5")) // round 110
+	require.NoError(t, update("two", 0, "settings_time_lock", "0"))
+	assert.EqualValues(t, 10, gn.SettingsTimeLock, "the time lock is locked")
+
+	// not applied yet
+	var slashRate = gn.SlashRate
+	require.NoError(t, msc.activateSettings(gn, 109, balances))
+	assert.Equal(t, slashRate, gn.SlashRate)
+
+	require.NoError(t, msc.activateSettings(gn, 110, balances))
+	assert.Equal(t, 0.5, gn.SlashRate)
+	assert.Zero(t, gn.SettingsTimeLock)
+
+	// not time-locked anymore
+	require.NoError(t, update("three", 0, "slash_rate", "0.25"))
+	assert.Equal(t, 0.25, gn.SlashRate)
+
+	var scheduled *smartcontract.ScheduledChanges
+	scheduled, err = getScheduledSettings(balances)
+	require.NoError(t, err)
+	assert.Empty(t, scheduled.Changes)
+}
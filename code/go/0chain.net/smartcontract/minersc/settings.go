@@ -38,6 +38,11 @@ const (
 	RewardDeclineRate
 	InterestDeclineRate
 	MaxMint
+	SettingsTimeLock
+	SlashRate
+	SlashReporterShare
+	HealthCheckPeriod
+	PerformanceWeight
 	NumberOfSettings
 )
 
@@ -63,6 +68,11 @@ var (
 		"reward_decline_rate",
 		"interest_decline_rate",
 		"max_mint",
+		"settings_time_lock",
+		"slash_rate",
+		"slash_reporter_share",
+		"health_check_period",
+		"performance_weight",
 	}
 
 	Settings = map[string]struct {
@@ -89,6 +99,11 @@ var (
 		"reward_decline_rate":    {RewardDeclineRate, smartcontract.Float64},
 		"interest_decline_rate":  {InterestDeclineRate, smartcontract.Float64},
 		"max_mint":               {MaxMint, smartcontract.StateBalance},
+		"settings_time_lock":     {SettingsTimeLock, smartcontract.Int64},
+		"slash_rate":             {SlashRate, smartcontract.Float64},
+		"slash_reporter_share":   {SlashReporterShare, smartcontract.Float64},
+		"health_check_period":    {HealthCheckPeriod, smartcontract.Int64},
+		"performance_weight":     {PerformanceWeight, smartcontract.Float64},
 	}
 )
 
@@ -132,6 +147,10 @@ func (gn *GlobalNode) setInt64(key string, change int64) error {
 		gn.RewardRoundFrequency = change
 	case Epoch:
 		gn.Epoch = change
+	case SettingsTimeLock:
+		gn.SettingsTimeLock = change
+	case HealthCheckPeriod:
+		gn.HealthCheckPeriod = change
 	default:
 		return fmt.Errorf("key: %v not implemented as int64", key)
 	}
@@ -158,6 +177,12 @@ func (gn *GlobalNode) setFloat64(key string, change float64) error {
 		gn.RewardDeclineRate = change
 	case InterestDeclineRate:
 		gn.InterestDeclineRate = change
+	case SlashRate:
+		gn.SlashRate = change
+	case SlashReporterShare:
+		gn.SlashReporterShare = change
+	case PerformanceWeight:
+		gn.PerformanceWeight = change
 	default:
 		return fmt.Errorf("key: %v not implemented as float64", key)
 	}
@@ -221,24 +246,27 @@ func (msc *MinerSmartContract) updateSettings(
 	balances cstate.StateContextI,
 ) (resp string, err error) {
 
-	if t.ClientID != owner {
+	if !smartcontract.CanUpdateSettings(t.ClientID) {
 		return "", common.NewError("update_settings",
-			"unauthorized access - only the owner can update the variables")
+			"unauthorized access - only the governance can update the variables")
 	}
 
-	var changes smartcontract.StringMap
-	if err = changes.Decode(inputData); err != nil {
+	var req smartcontract.ScheduleRequest
+	if err = req.Decode(inputData); err != nil {
 		return "", common.NewError("update_settings", err.Error())
 	}
 
-	if err := gn.update(changes); err != nil {
+	var next *GlobalNode
+	next, err = gn.updatedCopy(smartcontract.StringMap{Fields: req.Fields})
+	if err != nil {
 		return "", common.NewError("update_settings", err.Error())
 	}
 
-	if err = gn.validate(); err != nil {
-		return "", common.NewError("update_settings", err.Error())
+	if req.ActivationRound != 0 || gn.SettingsTimeLock > 0 {
+		return msc.scheduleSettings(t, gn, &req, balances)
 	}
 
+	*gn = *next
 	if err := gn.save(balances); err != nil {
 		return "", common.NewError("update_settings", err.Error())
 	}
@@ -157,7 +157,7 @@ func TestUpdateSettings(t *testing.T) {
 		{
 			title: "all_settigns",
 			parameters: parameters{
-				client: "1746b06bb09f55ee01b33b5e2e055d6cc7a900cb57c0a3a5eaabb8a0e7745802",
+				client: smartcontract.GovernanceAddress,
 				inputMap: map[string]string{
 					"min_stake":              "0.0",
 					"max_stake":              "100",
@@ -0,0 +1,130 @@
+package minersc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	cstate "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/state"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/util"
+)
+
+// GetClientStake returns tokens of given client staked in active delegate
+// pools of miners and sharders created before given round.
+func GetClientStake(clientID string, before int64,
+	balances cstate.StateContextI) (stake state.Balance, err error) {
+
+	var un = NewUserNode()
+	un.ID = clientID
+
+	var val util.Serializable
+	switch val, err = balances.GetTrieNode(un.GetKey()); err {
+	case nil:
+	case util.ErrValueNotPresent:
+		return 0, nil // not a stake holder
+	default:
+		return
+	}
+	if err = un.Decode(val.Encode()); err != nil {
+		return 0, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+
+	for nodeID, poolIDs := range un.Pools {
+		var mn *MinerNode
+		if mn, err = getMinerNode(nodeID, balances); err != nil {
+			return 0, fmt.Errorf("can't get node %s: %v", nodeID, err)
+		}
+		for _, id := range poolIDs {
+			if dp, ok := mn.Active[id]; ok && dp.RoundCreated < before {
+				stake += dp.Balance
+			}
+		}
+	}
+	return
+}
+
+func totalStakeKey() datastore.Key {
+	return datastore.Key(ADDRESS + ":total_stake")
+}
+
+// totalStake is running total of tokens staked in active delegate pools of
+// all miners and sharders, it's updated on every save of a node
+type totalStake struct {
+	Stake state.Balance `json:"stake"`
+}
+
+func (ts *totalStake) Encode() []byte {
+	var b, err = json.Marshal(ts)
+	if err != nil {
+		panic(err) // must never happen
+	}
+	return b
+}
+
+func (ts *totalStake) Decode(b []byte) error {
+	return json.Unmarshal(b, ts)
+}
+
+// GetTotalStake returns tokens staked in active delegate pools of all
+// miners and sharders.
+func GetTotalStake(balances cstate.StateContextI) (
+	stake state.Balance, err error) {
+
+	var val util.Serializable
+	switch val, err = balances.GetTrieNode(totalStakeKey()); err {
+	case nil:
+	case util.ErrValueNotPresent:
+		return countTotalStake(balances) // not tracked yet
+	default:
+		return
+	}
+	var ts totalStake
+	if err = ts.Decode(val.Encode()); err != nil {
+		return 0, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return ts.Stake, nil
+}
+
+// addTotalStake adds given difference to the running total
+func addTotalStake(diff state.Balance, balances cstate.StateContextI) (
+	err error) {
+
+	var ts totalStake
+	if ts.Stake, err = GetTotalStake(balances); err != nil {
+		return
+	}
+	ts.Stake += diff
+	_, err = balances.InsertTrieNode(totalStakeKey(), &ts)
+	return
+}
+
+// countTotalStake goes through all miners and sharders, it's used once
+// to start the running total
+func countTotalStake(balances cstate.StateContextI) (
+	stake state.Balance, err error) {
+
+	var miners, sharders *MinerNodes
+	if miners, err = getMinersList(balances); err != nil {
+		return 0, fmt.Errorf("can't get miners list: %v", err)
+	}
+	if sharders, err = getAllShardersList(balances); err != nil {
+		return 0, fmt.Errorf("can't get sharders list: %v", err)
+	}
+
+	for _, nodes := range [][]*MinerNode{miners.Nodes, sharders.Nodes} {
+		for _, node := range nodes {
+			var mn *MinerNode
+			switch mn, err = getMinerNode(node.ID, balances); err {
+			case nil:
+				stake += mn.activeStake()
+			case util.ErrValueNotPresent:
+				err = nil // not saved yet
+			default:
+				return 0, fmt.Errorf("can't get node %s: %v", node.ID, err)
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,58 @@
+package minersc
+
+import (
+	"testing"
+
+	sci "0chain.net/chaincore/smartcontractinterface"
+	"0chain.net/chaincore/state"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_totalStake(t *testing.T) {
+	var (
+		balances = newTestBalances()
+		un       = NewUserNode()
+		mn       = NewMinerNode()
+	)
+	un.ID, mn.ID = "delegate", "miner"
+
+	var addPool = func(id string, value state.Balance, round int64) {
+		var pool = sci.NewDelegatePool()
+		pool.ID = id // the delegate pools are decoded by their IDs
+		pool.Balance = value
+		pool.DelegateID = un.ID
+		pool.RoundCreated = round
+		mn.Active[id] = pool
+		un.Pools[mn.ID] = append(un.Pools[mn.ID], id)
+	}
+
+	addPool("old", 10, 0)
+	addPool("new", 5, 100)
+	require.NoError(t, un.save(balances))
+	require.NoError(t, mn.save(balances))
+
+	var total, err = GetTotalStake(balances)
+	require.NoError(t, err)
+	require.EqualValues(t, 15, total)
+
+	// only stake created before the round is counted
+	var stake state.Balance
+	stake, err = GetClientStake(un.ID, 100, balances)
+	require.NoError(t, err)
+	require.EqualValues(t, 10, stake)
+	stake, err = GetClientStake(un.ID, 101, balances)
+	require.NoError(t, err)
+	require.EqualValues(t, 15, stake)
+
+	// the running total follows changes of the saved node
+	mn, err = getMinerNode(mn.ID, balances)
+	require.NoError(t, err)
+	delete(mn.Active, "new")
+	mn.Active["old"].Balance += 2
+	require.NoError(t, mn.save(balances))
+
+	total, err = GetTotalStake(balances)
+	require.NoError(t, err)
+	require.EqualValues(t, 12, total)
+}
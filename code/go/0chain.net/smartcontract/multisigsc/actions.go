@@ -0,0 +1,245 @@
+package multisigsc
+
+import (
+	"encoding/json"
+
+	"0chain.net/chaincore/config"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/encryption"
+)
+
+// Types of management proposals of a multi-sig wallet.
+const (
+	ActionAddSigner    = "add_signer"
+	ActionRemoveSigner = "remove_signer"
+	ActionSetThreshold = "set_threshold"
+	ActionSCCall       = "sc_call"
+)
+
+// Maximal size of transaction data of a smart contract call proposal.
+const MaxCallDataSize = 8 * 1024
+
+// SCCall is a smart contract transaction made on behalf of a multi-sig wallet.
+type SCCall struct {
+	ToClientID      string           `json:"to_client_id"`
+	Value           int64            `json:"transaction_value"`
+	TransactionData string           `json:"transaction_data"`
+	CreationDate    common.Timestamp `json:"creation_date"`
+	Fee             int64            `json:"transaction_fee"`
+}
+
+// Action is a management operation on a multi-sig wallet. Signer actions
+// carry the complete new signer set. The group key is re-split off-chain
+// using encryption.GenerateThresholdKeyShares, so the group public key and
+// the wallet's client ID never change.
+type Action struct {
+	Type string `json:"type"`
+
+	// Client ID of the multi-sig wallet, not the signer.
+	ClientID string `json:"client_id"`
+
+	// Version of the wallet the action is made for. The action can't be
+	// executed after the wallet changes.
+	WalletVersion int64 `json:"wallet_version"`
+
+	// New signers and threshold for signer actions.
+	SignerThresholdIDs []string `json:"signer_threshold_ids,omitempty"`
+	SignerPublicKeys   []string `json:"signer_public_keys,omitempty"`
+	NumRequired        int      `json:"num_required,omitempty"`
+
+	// Transaction for smart contract call action.
+	Call *SCCall `json:"call,omitempty"`
+}
+
+func (a Action) Encode() []byte {
+	buff, _ := json.Marshal(a)
+	return buff
+}
+
+func (a Action) notTooBig() bool {
+	if len(a.Type) > MaxFieldSize || len(a.ClientID) > MaxFieldSize {
+		return false
+	}
+	if len(a.SignerThresholdIDs) > MaxSigners ||
+		len(a.SignerPublicKeys) > MaxSigners {
+		return false
+	}
+	if a.Call != nil && (len(a.Call.ToClientID) > MaxFieldSize ||
+		len(a.Call.TransactionData) > MaxCallDataSize) {
+		return false
+	}
+	return true
+}
+
+// Check the action is well-formed. The new signer set is checked against the
+// wallet before the execution.
+func (a Action) validate() error {
+	switch a.Type {
+	case ActionAddSigner, ActionRemoveSigner, ActionSetThreshold:
+		if a.Call != nil {
+			return common.NewError("err_action_invalid", "unexpected transaction for signer action")
+		}
+	case ActionSCCall:
+		if a.Call == nil || a.Call.ToClientID == "" {
+			return common.NewError("err_action_invalid", "missing transaction for smart contract call")
+		}
+		if a.Call.Value < 0 || a.Call.Fee < 0 {
+			return common.NewError("err_action_invalid", "negative transaction value or fee")
+		}
+	default:
+		return common.NewError("err_action_invalid", "unknown action type: "+a.Type)
+	}
+	if a.ClientID == "" {
+		return common.NewError("err_action_invalid", "missing multi-sig wallet client ID")
+	}
+	return nil
+}
+
+// Make unsigned transaction of a smart contract call action.
+func (a Action) transaction(w Wallet) *transaction.Transaction {
+	t := &transaction.Transaction{
+		ClientID:        a.ClientID,
+		PublicKey:       w.PublicKey,
+		ToClientID:      a.Call.ToClientID,
+		ChainID:         config.GetServerChainID(),
+		TransactionData: a.Call.TransactionData,
+		Value:           a.Call.Value,
+		CreationDate:    a.Call.CreationDate,
+		Fee:             a.Call.Fee,
+		TransactionType: transaction.TxnTypeSmartContract,
+	}
+	t.Hash = t.ComputeHash()
+	return t
+}
+
+// Hash signed by the signers. For a smart contract call it's hash of the
+// transaction, so the reconstructed signature is the transaction signature.
+func (a Action) Hash() string {
+	if a.Type == ActionSCCall && a.Call != nil {
+		return a.transaction(Wallet{}).Hash
+	}
+	return encryption.Hash(a.Encode())
+}
+
+// Return the wallet with the action applied. The action must be made for the
+// current version of the wallet and result in a valid wallet.
+func (a Action) apply(w Wallet) (Wallet, error) {
+	if a.WalletVersion != w.Version {
+		return Wallet{}, common.NewError("err_action_stale", "the wallet has changed since the action was proposed")
+	}
+
+	if a.Type == ActionSCCall {
+		return w, nil
+	}
+
+	have, want := len(w.SignerThresholdIDs), len(a.SignerThresholdIDs)
+	switch a.Type {
+	case ActionAddSigner:
+		if want != have+1 {
+			return Wallet{}, common.NewError("err_action_invalid", "exactly one signer must be added")
+		}
+	case ActionRemoveSigner:
+		if want != have-1 {
+			return Wallet{}, common.NewError("err_action_invalid", "exactly one signer must be removed")
+		}
+	case ActionSetThreshold:
+		if want != have {
+			return Wallet{}, common.NewError("err_action_invalid", "number of signers must not change")
+		}
+		if a.NumRequired == w.NumRequired {
+			return Wallet{}, common.NewError("err_action_invalid", "threshold is not changed")
+		}
+	}
+
+	nw := w
+	nw.SignerThresholdIDs = a.SignerThresholdIDs
+	nw.SignerPublicKeys = a.SignerPublicKeys
+	nw.NumRequired = a.NumRequired
+	nw.Version++
+
+	if _, err := nw.valid(w.ClientID); err != nil {
+		return Wallet{}, err
+	}
+	return nw, nil
+}
+
+// ActionVote is a signer's vote for an action proposal.
+type ActionVote struct {
+	ProposalID string `json:"proposal_id"`
+	Action     Action `json:"action"`
+
+	// Signature share of Action.Hash().
+	Signature string `json:"signature"`
+}
+
+func (v ActionVote) notTooBig() bool {
+	return len(v.ProposalID) <= MaxFieldSize &&
+		len(v.Signature) <= MaxFieldSize &&
+		v.Action.notTooBig()
+}
+
+func (v ActionVote) getProposalRef() proposalRef {
+	return proposalRef{
+		ClientID:   v.Action.ClientID,
+		ProposalID: v.ProposalID,
+	}
+}
+
+func (v ActionVote) isCompatibleWithProposal(p proposal) bool {
+	return p.Action != nil && string(v.Action.Encode()) == string(p.Action.Encode())
+}
+
+// Verify the vote's signature share by the signer's public key.
+func (w Wallet) isActionVoteAuthorized(signingClientID string, v ActionVote) bool {
+	publicKey := w.publicKeyForSigner(signingClientID)
+	if publicKey == "" {
+		// Not a registered signer for this wallet.
+		return false
+	}
+	return w.verify(publicKey, v.Signature, v.Action.Hash())
+}
+
+func (w Wallet) verify(publicKey, signature, hash string) bool {
+	scheme := encryption.GetSignatureScheme(w.SignatureScheme)
+	if err := scheme.SetPublicKey(publicKey); err != nil {
+		return false
+	}
+	ok, err := scheme.Verify(signature, hash)
+	return err == nil && ok
+}
+
+// ProposalInfo is a proposal with its voting progress.
+type ProposalInfo struct {
+	ProposalID     string           `json:"proposal_id"`
+	ExpirationDate common.Timestamp `json:"expiration_date"`
+
+	Transfer *state.Transfer `json:"transfer,omitempty"`
+	Action   *Action         `json:"action,omitempty"`
+
+	SignerThresholdIDs []string `json:"signer_threshold_ids"`
+	Votes              int      `json:"votes"`
+	VotesRequired      int      `json:"votes_required"`
+
+	ExecutedInTxnHash string                   `json:"executed_in_txn_hash,omitempty"`
+	Transaction       *transaction.Transaction `json:"transaction,omitempty"`
+}
+
+func newProposalInfo(p proposal, w Wallet) *ProposalInfo {
+	info := &ProposalInfo{
+		ProposalID:         p.ProposalID,
+		ExpirationDate:     p.ExpirationDate,
+		Action:             p.Action,
+		SignerThresholdIDs: p.SignerThresholdIDs,
+		Votes:              len(p.SignerSignatures),
+		VotesRequired:      w.NumRequired,
+		ExecutedInTxnHash:  p.ExecutedInTxnHash,
+		Transaction:        p.Transaction,
+	}
+	if p.Action == nil {
+		transfer := p.Transfer
+		info.Transfer = &transfer
+	}
+	return info
+}
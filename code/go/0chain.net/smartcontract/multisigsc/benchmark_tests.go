@@ -59,6 +59,14 @@ func (bt BenchTest) Run(balances cstate.StateContextI, _ *testing.B) error {
 			bt.input,
 			balances,
 		)
+	case ActionVoteFuncName:
+		_, err = msc.voteAction(
+			bt.txn.Hash,
+			bt.txn.ClientID,
+			balances.GetBlock().CreationDate,
+			bt.input,
+			balances,
+		)
 	default:
 		panic("unknown endpoint: " + bt.endpoint)
 	}
@@ -117,6 +125,34 @@ func BenchmarkTests(
 				return bytes
 			}(),
 		},
+		{
+			name:     "multi_sig." + ActionVoteFuncName,
+			endpoint: ActionVoteFuncName,
+			txn: &transaction.Transaction{
+				ClientID: data.Clients[0],
+				HashIDField: datastore.HashIDField{
+					Hash: "my hash",
+				},
+			},
+			input: func() []byte {
+				action := Action{
+					Type:               ActionSetThreshold,
+					ClientID:           data.Clients[0],
+					SignerThresholdIDs: data.Clients[:MaxSigners],
+					SignerPublicKeys:   data.PublicKeys[:MaxSigners],
+					NumRequired:        MaxSigners - 1,
+				}
+				_ = sigScheme.SetPublicKey(data.PublicKeys[0])
+				sigScheme.SetPrivateKey(data.PrivateKeys[0])
+				signature, _ := sigScheme.Sign(action.Hash())
+				bytes, _ := json.Marshal(&ActionVote{
+					ProposalID: "my proposal",
+					Action:     action,
+					Signature:  signature,
+				})
+				return bytes
+			}(),
+		},
 	}
 	var testsI []bk.BenchTestI
 	for _, test := range tests {
@@ -0,0 +1,67 @@
+package multisigsc
+
+import (
+	"context"
+	"net/url"
+
+	c_state "0chain.net/chaincore/chain/state"
+	"0chain.net/core/common"
+)
+
+// Get not executed and not expired proposals of given multi-sig wallet.
+func (ms MultiSigSmartContract) getPendingProposalsHandler(ctx context.Context, params url.Values, balances c_state.StateContextI) (interface{}, error) {
+	clientID := params.Get("client_id")
+	if clientID == "" {
+		return nil, common.NewErrBadRequest("missing client_id")
+	}
+
+	w, err := ms.getWallet(clientID, balances)
+	if err != nil || w.isEmpty() {
+		return nil, common.NewErrNoResource("wallet not registered")
+	}
+
+	wp, err := ms.getWalletProposals(clientID, balances)
+	if err != nil {
+		return nil, common.NewErrInternal("can't get wallet proposals: " + err.Error())
+	}
+
+	now := common.Now()
+	pending := make([]*ProposalInfo, 0, len(wp.ProposalIDs))
+	for _, id := range wp.ProposalIDs {
+		p, err := ms.getProposal(proposalRef{ClientID: clientID, ProposalID: id}, balances)
+		if err != nil {
+			return nil, common.NewErrInternal("can't get proposal: " + err.Error())
+		}
+		if p.isEmpty() || p.isExpired(now) || p.ExecutedInTxnHash != "" {
+			continue
+		}
+		pending = append(pending, newProposalInfo(p, w))
+	}
+
+	return pending, nil
+}
+
+// Get a proposal of a multi-sig wallet. Executed smart contract call proposals
+// contain the signed transaction.
+func (ms MultiSigSmartContract) getProposalHandler(ctx context.Context, params url.Values, balances c_state.StateContextI) (interface{}, error) {
+	clientID := params.Get("client_id")
+	proposalID := params.Get("proposal_id")
+	if clientID == "" || proposalID == "" {
+		return nil, common.NewErrBadRequest("missing client_id or proposal_id")
+	}
+
+	w, err := ms.getWallet(clientID, balances)
+	if err != nil || w.isEmpty() {
+		return nil, common.NewErrNoResource("wallet not registered")
+	}
+
+	p, err := ms.getProposal(proposalRef{ClientID: clientID, ProposalID: proposalID}, balances)
+	if err != nil {
+		return nil, common.NewErrInternal("can't get proposal: " + err.Error())
+	}
+	if p.isEmpty() {
+		return nil, common.NewErrNoResource("proposal not found")
+	}
+
+	return newProposalInfo(p, w), nil
+}
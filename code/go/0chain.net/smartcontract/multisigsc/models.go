@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 
 	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
 	"0chain.net/core/common"
 	"0chain.net/core/datastore"
 	"0chain.net/core/encryption"
@@ -27,6 +28,9 @@ type Wallet struct {
 	SignerPublicKeys   []string `json:"signer_public_keys"`
 
 	NumRequired int `json:"num_required"`
+
+	// Incremented on every change of the signers or the threshold.
+	Version int64 `json:"version"`
 }
 
 func (w Wallet) Encode() []byte {
@@ -98,6 +102,10 @@ func (w Wallet) valid(forClientID string) (bool, error) {
 		}
 	}
 
+	if !w.signersMatchGroupKey() {
+		return false, common.NewError("signer_keys_group_key_no_match", "the signer public keys are not shares of the wallet public key")
+	}
+
 	if len(w.ClientID) > MaxFieldSize ||
 		len(w.SignatureScheme) > MaxFieldSize ||
 		len(w.PublicKey) > MaxFieldSize {
@@ -117,6 +125,37 @@ func (w Wallet) valid(forClientID string) (bool, error) {
 	return true, nil
 }
 
+// The signer public keys must be threshold shares of the group public key.
+// Shares are on the same polynomial of NumRequired-1 degree if every
+// NumRequired consecutive shares recover the group key, since neighbouring
+// windows have NumRequired-1 shares and the group key in common.
+func (w Wallet) signersMatchGroupKey() bool {
+	group := encryption.GetSignatureScheme(w.SignatureScheme)
+	if err := group.SetPublicKey(w.PublicKey); err != nil {
+		return false
+	}
+
+	shares := make([]encryption.ThresholdSignatureScheme, 0, len(w.SignerPublicKeys))
+	for i, key := range w.SignerPublicKeys {
+		tss := encryption.GetThresholdSignatureScheme(w.SignatureScheme)
+		if err := tss.SetPublicKey(key); err != nil {
+			return false
+		}
+		if err := tss.SetID(w.SignerThresholdIDs[i]); err != nil {
+			return false
+		}
+		shares = append(shares, tss)
+	}
+
+	for i := 0; i+w.NumRequired <= len(shares); i++ {
+		key, err := encryption.RecoverThresholdPublicKey(w.SignatureScheme, shares[i:i+w.NumRequired])
+		if err != nil || key != group.GetPublicKey() {
+			return false
+		}
+	}
+	return true
+}
+
 func isPublicKeyForClientID(publicKey, clientID string) bool {
 	publicKeyBytes, err := hex.DecodeString(publicKey)
 	if err != nil {
@@ -212,7 +251,7 @@ func (w Wallet) publicKeyForThresholdID(signerThresholdID string) string {
 // Compute the Lagrange polynomial of Wallet.NumRequired signature shares. The
 // y-intercept of this polynomial is the proposal's signature. (This process is
 // called reconstruction in the literature.)
-func (w Wallet) constructTransferSignature(p proposal) (string, error) {
+func (w Wallet) constructSignature(p proposal) (string, error) {
 	t := w.NumRequired
 	n := len(w.SignerThresholdIDs)
 	rec := encryption.GetReconstructSignatureScheme(w.SignatureScheme, t, n)
@@ -244,8 +283,8 @@ func (w Wallet) constructTransferSignature(p proposal) (string, error) {
 		}
 	}
 
-	// All of the SignerSignatures are signatures on the transfer, which means
-	// this reconstructed signature will be, too.
+	// All of the SignerSignatures are signatures on the transfer (or action),
+	// which means this reconstructed signature will be, too.
 	return rec.Reconstruct()
 }
 
@@ -300,8 +339,8 @@ func (pr *proposalRef) Decode(input []byte) error {
 	return err
 }
 
-// Proposal to transfer tokens out of the multi-sig wallet. Built up from T
-// different votes.
+// Proposal to transfer tokens out of the multi-sig wallet or to perform an
+// action on behalf of it. Built up from T different votes.
 type proposal struct {
 	// Proposal ID is unique only within a single multi-sig wallet. Globally, a
 	// proposal may be referred to by a wallet ID / proposal ID pair.
@@ -314,6 +353,9 @@ type proposal struct {
 
 	Transfer state.Transfer `json:"transfer"`
 
+	// Set for action proposals instead of the transfer.
+	Action *Action `json:"action,omitempty"`
+
 	// Pertinent data from votes.
 	SignerThresholdIDs []string `json:"signer_threshold_ids"`
 	SignerSignatures   []string `json:"signer_signatures"`
@@ -321,6 +363,9 @@ type proposal struct {
 	// Filled upon completing a proposal.
 	ClientSignature   string `json:"client_signature"`
 	ExecutedInTxnHash string `json:"executed_in_txn_hash"`
+
+	// Signed transaction of an executed smart contract call action.
+	Transaction *transaction.Transaction `json:"transaction,omitempty"`
 }
 
 func (p *proposal) Encode() []byte {
@@ -334,7 +379,15 @@ func (p *proposal) Decode(input []byte) error {
 }
 
 func (p proposal) isEmpty() bool {
-	return p.Transfer.ClientID == ""
+	return p.walletID() == ""
+}
+
+// Client ID of the multi-sig wallet of the proposal.
+func (p proposal) walletID() string {
+	if p.Action != nil {
+		return p.Action.ClientID
+	}
+	return p.Transfer.ClientID
 }
 
 func (p proposal) isExpired(now common.Timestamp) bool {
@@ -343,13 +396,13 @@ func (p proposal) isExpired(now common.Timestamp) bool {
 
 func (p proposal) ref() proposalRef {
 	return proposalRef{
-		ClientID:   p.Transfer.ClientID,
+		ClientID:   p.walletID(),
 		ProposalID: p.ProposalID,
 	}
 }
 
 func (p proposal) getKey() datastore.Key {
-	return getProposalKey(p.Transfer.ClientID, p.ProposalID)
+	return getProposalKey(p.walletID(), p.ProposalID)
 }
 
 func getProposalKey(clientID, proposalID string) datastore.Key {
@@ -375,3 +428,35 @@ func (q *expirationQueue) Decode(input []byte) error {
 func getExpirationQueueKey() datastore.Key {
 	return datastore.Key(Address + encryption.Hash("queue"))
 }
+
+// IDs of all proposals of a multi-sig wallet.
+type walletProposals struct {
+	ProposalIDs []string `json:"proposal_ids"`
+}
+
+func (wp *walletProposals) Encode() []byte {
+	buff, _ := json.Marshal(wp)
+	return buff
+}
+
+func (wp *walletProposals) Decode(input []byte) error {
+	err := json.Unmarshal(input, wp)
+	return err
+}
+
+func (wp *walletProposals) add(proposalID string) {
+	wp.ProposalIDs = append(wp.ProposalIDs, proposalID)
+}
+
+func (wp *walletProposals) remove(proposalID string) {
+	for i, id := range wp.ProposalIDs {
+		if id == proposalID {
+			wp.ProposalIDs = append(wp.ProposalIDs[:i], wp.ProposalIDs[i+1:]...)
+			return
+		}
+	}
+}
+
+func getWalletProposalsKey(clientID string) datastore.Key {
+	return datastore.Key(Address + encryption.Hash("proposals") + clientID)
+}
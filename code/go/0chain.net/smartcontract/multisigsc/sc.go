@@ -24,7 +24,10 @@ const (
 	Address          = "27b5ef7120252b79f9dd9c05505dd28f328c80f6863ee446daede08a84d651a7"
 	RegisterFuncName = "register"
 	VoteFuncName     = "vote"
-	LogTimingInfo    = false
+
+	ActionVoteFuncName = "vote_action"
+
+	LogTimingInfo = false
 )
 
 type MultiSigSmartContract struct {
@@ -61,6 +64,8 @@ func (ms *MultiSigSmartContract) GetRestPoints() map[string]smartcontractinterfa
 
 func (ms *MultiSigSmartContract) setSC(sc *smartcontractinterface.SmartContract, bc smartcontractinterface.BCContextI) {
 	ms.SmartContract = sc
+	ms.SmartContract.RestHandlers["/getPendingProposals"] = ms.getPendingProposalsHandler
+	ms.SmartContract.RestHandlers["/getProposal"] = ms.getProposalHandler
 }
 
 func (ms MultiSigSmartContract) Execute(t *transaction.Transaction, funcName string, inputData []byte, balances state.StateContextI) (string, error) {
@@ -74,6 +79,8 @@ func (ms MultiSigSmartContract) Execute(t *transaction.Transaction, funcName str
 		return ms.register(t.ClientID, inputData, balances)
 	case VoteFuncName:
 		return ms.vote(t.Hash, t.ClientID, balances.GetBlock().CreationDate, inputData, balances)
+	case ActionVoteFuncName:
+		return ms.voteAction(t.Hash, t.ClientID, balances.GetBlock().CreationDate, inputData, balances)
 	default:
 		return "err_execute_function_not_found: no multi sig smart contract function with that name: " + funcName, nil
 	}
@@ -159,7 +166,10 @@ func (ms MultiSigSmartContract) vote(currentTxnHash, signingClientID string, now
 
 	// Every vote is associated with a proposal. If an appropriate proposal does
 	// not exist yet, create one.
-	p, err := ms.findOrCreateProposal(now, v, balances)
+	p, err := ms.findOrCreateProposal(now, v.getProposalRef(), proposal{
+		ProposalID: v.ProposalID,
+		Transfer:   v.Transfer,
+	}, balances)
 	if err != nil {
 		// I/O error.
 		return "", err
@@ -225,7 +235,7 @@ func (ms MultiSigSmartContract) vote(currentTxnHash, signingClientID string, now
 
 	// Otherwise we can recover the threshold signature on the transfer and
 	// execute it.
-	thresholdSignature, err := w.constructTransferSignature(p)
+	thresholdSignature, err := w.constructSignature(p)
 	if err != nil {
 		return "", common.NewError("err_vote_recover", " in signature recovery: "+err.Error())
 	}
@@ -251,6 +261,155 @@ func (ms MultiSigSmartContract) vote(currentTxnHash, signingClientID string, now
 	return msg, nil
 }
 
+func (ms MultiSigSmartContract) voteAction(currentTxnHash, signingClientID string, now common.Timestamp, inputData []byte, balances state.StateContextI) (string, error) {
+	// Garbage collection of old proposals happens incrementally with every
+	// incoming vote.
+	err := ms.pruneExpirationQueue(now, balances)
+	if err != nil {
+		// I/O error.
+		if err != util.ErrValueNotPresent && err != util.ErrNodeNotFound {
+			return "", err
+		} //else there are no expiration queue.
+	}
+
+	var v ActionVote
+
+	err = json.Unmarshal(inputData, &v)
+	if err != nil {
+		return "", err
+	}
+
+	// Play nice.
+	if !v.notTooBig() {
+		return "", common.NewError("err_vote_too_big", "an input field exceeded allowable length")
+	}
+	if err = v.Action.validate(); err != nil {
+		return "", err
+	}
+	if v.Signature == "" {
+		return "", common.NewError("err_vote_no_signature", " must sign vote")
+	}
+
+	// Check that the multi-sig wallet is registered.
+	w, err := ms.getWallet(v.Action.ClientID, balances)
+	if err != nil {
+		// I/O error.
+		return "", err
+	}
+	if w.isEmpty() {
+		return "", common.NewError("err_vote_wallet_not_registered", " wallet not registered")
+	}
+
+	// Don't let votes for a stale action pile up.
+	if v.Action.WalletVersion != w.Version {
+		return "", common.NewError("err_action_stale", "the wallet has changed since the action was proposed")
+	}
+
+	p, err := ms.findOrCreateProposal(now, v.getProposalRef(), proposal{
+		ProposalID: v.ProposalID,
+		Action:     &v.Action,
+	}, balances)
+	if err != nil {
+		// I/O error.
+		return "", err
+	}
+
+	// Ensure all voters are on the same page.
+	if !v.isCompatibleWithProposal(p) {
+		return "", common.NewError("err_vote_not_compatible", " previous votes for same proposal differed")
+	}
+
+	// Check if the proposal was already finished, making this vote unnecessary.
+	if p.ExecutedInTxnHash != "" {
+		return "success 0: proposal previously executed in transaction hash " + p.ExecutedInTxnHash, nil
+	}
+
+	// Check that the voter is registered on the wallet and that the signature
+	// is valid.
+	signerThresholdID := w.thresholdIdForSigner(signingClientID)
+	if signerThresholdID == "" {
+		return "", common.NewError("err_vote_auth", " authorization failure")
+	}
+	if !w.isActionVoteAuthorized(signingClientID, v) {
+		return "", common.NewError("err_vote_auth", " authorization failure")
+	}
+
+	remaining := w.NumRequired - len(p.SignerSignatures)
+
+	// Check if this is a duplicate vote.
+	for _, id := range p.SignerThresholdIDs {
+		if id == signerThresholdID {
+			return fmt.Sprintf("success %d: already voted, still need %d other votes", remaining, remaining), nil
+		}
+	}
+
+	// Add the signature to the proposal. It is counted as a vote.
+	p.SignerThresholdIDs = append(p.SignerThresholdIDs, signerThresholdID)
+	p.SignerSignatures = append(p.SignerSignatures, v.Signature)
+
+	err = ms.putProposal(&p, balances)
+	if err != nil {
+		// I/O error.
+		return "", err
+	}
+
+	remaining--
+
+	// If more votes are still needed we must wait for them. Nothing more to do.
+	if remaining > 0 {
+		msg := fmt.Sprintf("success %d: need %d more votes", remaining, remaining)
+		return msg, nil
+	}
+
+	// Otherwise recover the threshold signature on the action. Unlike a
+	// transfer, nobody checks it later, so it's verified here.
+	thresholdSignature, err := w.constructSignature(p)
+	if err != nil {
+		return "", common.NewError("err_vote_recover", " in signature recovery: "+err.Error())
+	}
+	if !w.verify(w.PublicKey, thresholdSignature, p.Action.Hash()) {
+		return "", common.NewError("err_vote_recover", " invalid recovered signature")
+	}
+
+	p.ClientSignature = thresholdSignature
+
+	nw, err := p.Action.apply(w)
+	if err != nil {
+		return "", err
+	}
+
+	var msg string
+	if p.Action.Type == ActionSCCall {
+		// The signed transaction can be submitted by anyone.
+		t := p.Action.transaction(w)
+		t.Signature = p.ClientSignature
+		p.Transaction = t
+		txnBytes, err := json.Marshal(t)
+		if err != nil {
+			return "", err
+		}
+		msg = "success 0: transaction signed " + string(txnBytes)
+	} else {
+		err = ms.putWallet(nw, balances)
+		if err != nil {
+			// I/O error.
+			return "", err
+		}
+		msg = fmt.Sprintf("success 0: %s executed, wallet version %d", p.Action.Type, nw.Version)
+	}
+
+	// Save the proposal again.
+	p.ExecutedInTxnHash = currentTxnHash
+
+	err = ms.putProposal(&p, balances)
+	if err != nil {
+		// I/O error.
+		return "", err
+	}
+
+	return msg, nil
+}
+
 // Prune the oldest proposal if it has expired.
 func (ms MultiSigSmartContract) pruneExpirationQueue(now common.Timestamp, balances state.StateContextI) error {
 	q, err := ms.getOrCreateExpirationQueue(balances)
@@ -344,12 +503,26 @@ func (ms MultiSigSmartContract) prune(ref proposalRef, balances c_state.StateCon
 		return err
 	}
 
+	// And forget it in the wallet's proposals.
+	wp, err := ms.getWalletProposals(ref.ClientID, balances)
+	if err != nil {
+		return err
+	}
+
+	wp.remove(ref.ProposalID)
+
+	err = ms.putWalletProposals(ref.ClientID, &wp, balances)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
-func (ms MultiSigSmartContract) findOrCreateProposal(now common.Timestamp, v Vote, balances state.StateContextI) (proposal, error) {
+// Find the referenced proposal or create it from given template.
+func (ms MultiSigSmartContract) findOrCreateProposal(now common.Timestamp, ref proposalRef, template proposal, balances state.StateContextI) (proposal, error) {
 	// Start by trying to find an existing proposal.
-	p, err := ms.getProposal(v.getProposalRef(), balances)
+	p, err := ms.getProposal(ref, balances)
 	if err != nil {
 		//return proposal{}, nil
 	}
@@ -366,7 +539,7 @@ func (ms MultiSigSmartContract) findOrCreateProposal(now common.Timestamp, v Vot
 
 	// If it didn't exist or was expired, create it and update expiration queue.
 	if p.isEmpty() {
-		p, err = ms.createProposal(now, v, balances)
+		p, err = ms.createProposal(now, template, balances)
 		if err != nil {
 			return proposal{}, err
 		}
@@ -375,8 +548,9 @@ func (ms MultiSigSmartContract) findOrCreateProposal(now common.Timestamp, v Vot
 	return p, nil
 }
 
-// Create a proposal and add it to the expiration queue. Performs I/O.
-func (ms MultiSigSmartContract) createProposal(now common.Timestamp, v Vote, balances state.StateContextI) (proposal, error) {
+// Create a proposal from given template and add it to the expiration queue.
+// Performs I/O.
+func (ms MultiSigSmartContract) createProposal(now common.Timestamp, template proposal, balances state.StateContextI) (proposal, error) {
 	q, err := ms.getOrCreateExpirationQueue(balances)
 	if err != nil {
 		if err != util.ErrValueNotPresent && err != util.ErrNodeNotFound {
@@ -386,13 +560,14 @@ func (ms MultiSigSmartContract) createProposal(now common.Timestamp, v Vote, bal
 
 	// Create proposal.
 	p := proposal{
-		ProposalID:     v.ProposalID,
+		ProposalID:     template.ProposalID,
 		ExpirationDate: now + ExpirationTime,
 
 		Next: proposalRef{},
 		Prev: q.Tail,
 
-		Transfer: v.Transfer,
+		Transfer: template.Transfer,
+		Action:   template.Action,
 
 		SignerThresholdIDs: []string{},
 		SignerSignatures:   []string{},
@@ -435,6 +610,19 @@ func (ms MultiSigSmartContract) createProposal(now common.Timestamp, v Vote, bal
 		return proposal{}, err
 	}
 
+	// Add to the wallet's proposals.
+	wp, err := ms.getWalletProposals(p.walletID(), balances)
+	if err != nil {
+		return proposal{}, err
+	}
+
+	wp.add(p.ProposalID)
+
+	err = ms.putWalletProposals(p.walletID(), &wp, balances)
+	if err != nil {
+		return proposal{}, err
+	}
+
 	return p, nil
 }
 
@@ -545,3 +733,41 @@ func (ms MultiSigSmartContract) putExpirationQueue(q *expirationQueue, balances
 	_, err := balances.InsertTrieNode(getExpirationQueueKey(), q)
 	return err
 }
+
+func (ms MultiSigSmartContract) getWalletProposals(clientID string, balances c_state.StateContextI) (walletProposals, error) {
+	wpNode, err := balances.GetTrieNode(getWalletProposalsKey(clientID))
+
+	if err != nil {
+		// I/O error.
+		if err != util.ErrValueNotPresent && err != util.ErrNodeNotFound {
+			return walletProposals{}, err
+		} //else there are no proposals.
+		return walletProposals{}, nil
+	}
+
+	wp := walletProposals{}
+	if wpNode == nil {
+		return wp, nil
+	}
+	err = json.Unmarshal(wpNode.Encode(), &wp)
+	if err != nil {
+		// Decoding error.
+		return walletProposals{}, err
+	}
+
+	// Okay.
+	return wp, nil
+}
+
+func (ms MultiSigSmartContract) putWalletProposals(clientID string, wp *walletProposals, balances c_state.StateContextI) error {
+	if len(wp.ProposalIDs) == 0 {
+		_, err := balances.DeleteTrieNode(getWalletProposalsKey(clientID))
+		if err != nil && err != util.ErrValueNotPresent && err != util.ErrNodeNotFound {
+			return err
+		}
+		return nil
+	}
+
+	_, err := balances.InsertTrieNode(getWalletProposalsKey(clientID), wp)
+	return err
+}
@@ -0,0 +1,239 @@
+package multisigsc
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"0chain.net/chaincore/block"
+	cstate "0chain.net/chaincore/chain/state"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/logging"
+	"0chain.net/core/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func init() {
+	logging.Logger = zap.NewNop()
+}
+
+// testBalances implements trie access of the state context only
+type testBalances struct {
+	cstate.StateContextI
+	tree map[datastore.Key]util.Serializable
+}
+
+func newTestBalances() *testBalances {
+	return &testBalances{tree: make(map[datastore.Key]util.Serializable)}
+}
+
+func (tb *testBalances) GetBlock() *block.Block {
+	return new(block.Block)
+}
+
+func (tb *testBalances) GetTrieNode(key datastore.Key) (util.Serializable,
+	error) {
+
+	if node, ok := tb.tree[key]; ok {
+		return node, nil
+	}
+	return nil, util.ErrValueNotPresent
+}
+
+func (tb *testBalances) InsertTrieNode(key datastore.Key,
+	node util.Serializable) (datastore.Key, error) {
+
+	tb.tree[key] = node
+	return key, nil
+}
+
+func (tb *testBalances) DeleteTrieNode(key datastore.Key) (datastore.Key,
+	error) {
+
+	delete(tb.tree, key)
+	return key, nil
+}
+
+func clientIDOf(t *testing.T, publicKey string) string {
+	var b, err = hex.DecodeString(publicKey)
+	require.NoError(t, err)
+	return encryption.Hash(b)
+}
+
+// testGroup is a group key split between signers
+type testGroup struct {
+	key     encryption.SignatureScheme
+	signers []encryption.ThresholdSignatureScheme
+}
+
+func newTestGroup(t *testing.T) *testGroup {
+	var key = encryption.NewBLS0ChainScheme()
+	require.NoError(t, key.GenerateKeys())
+	return &testGroup{key: key}
+}
+
+// split the group key between n signers and return the wallet
+func (g *testGroup) split(t *testing.T, required, n int) Wallet {
+	var err error
+	g.signers, err = encryption.GenerateThresholdKeyShares(
+		encryption.SignatureSchemeBls0chain, required, n, g.key)
+	require.NoError(t, err)
+
+	var w = Wallet{
+		ClientID:        clientIDOf(t, g.key.GetPublicKey()),
+		SignatureScheme: encryption.SignatureSchemeBls0chain,
+		PublicKey:       g.key.GetPublicKey(),
+		NumRequired:     required,
+	}
+	for _, s := range g.signers {
+		w.SignerThresholdIDs = append(w.SignerThresholdIDs, s.GetID())
+		w.SignerPublicKeys = append(w.SignerPublicKeys, s.GetPublicKey())
+	}
+	return w
+}
+
+func (g *testGroup) vote(t *testing.T, ms MultiSigSmartContract,
+	balances *testBalances, signer int, proposalID string, a Action) (
+	string, error) {
+
+	var s = g.signers[signer]
+	var sig, err = s.Sign(a.Hash())
+	require.NoError(t, err)
+	input, err := json.Marshal(&ActionVote{
+		ProposalID: proposalID,
+		Action:     a,
+		Signature:  sig,
+	})
+	require.NoError(t, err)
+	return ms.voteAction("txn_"+proposalID, clientIDOf(t, s.GetPublicKey()),
+		common.Timestamp(1), input, balances)
+}
+
+func TestWallet_valid(t *testing.T) {
+	var (
+		g = newTestGroup(t)
+		w = g.split(t, 2, 3)
+	)
+	var ok, err = w.valid(w.ClientID)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// shares of another group key
+	var other = newTestGroup(t).split(t, 2, 3)
+	var forged = w
+	forged.SignerThresholdIDs = other.SignerThresholdIDs
+	forged.SignerPublicKeys = other.SignerPublicKeys
+	_, err = forged.valid(w.ClientID)
+	require.Error(t, err)
+
+	// one share replaced
+	forged = w
+	forged.SignerPublicKeys = append([]string{}, w.SignerPublicKeys...)
+	forged.SignerPublicKeys[2] = other.SignerPublicKeys[2]
+	_, err = forged.valid(w.ClientID)
+	require.Error(t, err)
+}
+
+func TestAction_apply(t *testing.T) {
+	var (
+		g    = newTestGroup(t)
+		w    = g.split(t, 2, 3)
+		next = g.split(t, 2, 4)
+		a    = Action{
+			Type:               ActionAddSigner,
+			ClientID:           w.ClientID,
+			SignerThresholdIDs: next.SignerThresholdIDs,
+			SignerPublicKeys:   next.SignerPublicKeys,
+			NumRequired:        2,
+		}
+	)
+
+	var nw, err = a.apply(w)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, nw.Version)
+	assert.Len(t, nw.SignerPublicKeys, 4)
+
+	// the action is stale after the wallet change
+	_, err = a.apply(nw)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "err_action_stale")
+
+	// not one signer added
+	var b = a
+	b.Type = ActionRemoveSigner
+	_, err = b.apply(w)
+	require.Error(t, err)
+
+	// the threshold is not changed
+	b = a
+	b.Type = ActionSetThreshold
+	b.SignerThresholdIDs = w.SignerThresholdIDs
+	b.SignerPublicKeys = w.SignerPublicKeys
+	_, err = b.apply(w)
+	require.Error(t, err)
+}
+
+func TestMultiSigSmartContract_voteAction(t *testing.T) {
+	var (
+		ms       = MultiSigSmartContract{}
+		balances = newTestBalances()
+		g        = newTestGroup(t)
+		w        = g.split(t, 2, 3)
+	)
+
+	var input, err = json.Marshal(&w)
+	require.NoError(t, err)
+	_, err = ms.register(w.ClientID, input, balances)
+	require.NoError(t, err)
+
+	var a = Action{
+		Type:               ActionSetThreshold,
+		ClientID:           w.ClientID,
+		SignerThresholdIDs: w.SignerThresholdIDs,
+		SignerPublicKeys:   w.SignerPublicKeys,
+		NumRequired:        3,
+	}
+
+	resp, err := g.vote(t, ms, balances, 0, "p1", a)
+	require.NoError(t, err)
+	assert.Contains(t, resp, "need 1 more votes")
+
+	// a vote for another action of the same proposal
+	var b = a
+	b.NumRequired = 2
+	_, err = g.vote(t, ms, balances, 1, "p1", b)
+	require.Error(t, err)
+
+	resp, err = g.vote(t, ms, balances, 1, "p1", a)
+	require.NoError(t, err)
+	assert.Contains(t, resp, "wallet version 1")
+
+	nw, err := ms.getWallet(w.ClientID, balances)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, nw.Version)
+	assert.Equal(t, 3, nw.NumRequired)
+
+	// votes for the previous version are rejected
+	_, err = g.vote(t, ms, balances, 2, "p2", a)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "err_action_stale")
+
+	// the new signer set must be shares of the group key
+	var other = newTestGroup(t).split(t, 2, 3)
+	a.WalletVersion = 1
+	a.NumRequired = 2
+	a.SignerThresholdIDs = other.SignerThresholdIDs
+	a.SignerPublicKeys = other.SignerPublicKeys
+	for i := 0; i < 2; i++ {
+		_, err = g.vote(t, ms, balances, i, "p3", a)
+		require.NoError(t, err)
+	}
+	_, err = g.vote(t, ms, balances, 2, "p3", a)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "signer_keys_group_key_no_match")
+}
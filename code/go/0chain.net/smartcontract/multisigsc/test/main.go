@@ -101,6 +101,13 @@ func main() {
 	Logger.Info("")
 	time.Sleep(10 * time.Second)
 
+	testAddSigner()
+
+	Logger.Info("")
+	Logger.Info("")
+	Logger.Info("")
+	time.Sleep(10 * time.Second)
+
 	for i := 0; i < c.numWallets; i++ {
 		go testStress(i)
 	}
@@ -234,6 +241,47 @@ func doProposalWithAllN(w testWallet) {
 	}
 }
 
+func testAddSigner() {
+	Logger.Info("Testing multi-sig signer rotation...")
+
+	// Generate a group key and associated sub-keys.
+	w := newTestWallet(0, c.signatureScheme, c.t, c.n)
+
+	// Register MPT wallets for everyone in our group and give them some tokens
+	// to play with.
+	w.registerMPTWallets()
+
+	output := w.registerSCWallet()
+	if !strings.HasPrefix(output, "success:") {
+		Logger.Fatal("Register failed: TxnOutput should have prefix 'success:'")
+	}
+
+	// Start the real test...
+	nw, action := w.resplit(multisigsc.ActionAddSigner, w.t, w.n+1)
+	p := w.newActionProposal("add signer", action)
+
+	for i, signer := range w.signerClientIDs[:w.t] {
+		output := w.registerActionVote(p, signer)
+
+		expectedOutput := fmt.Sprintf("success %d:", w.t-(i+1))
+		if !strings.HasPrefix(output, expectedOutput) {
+			Logger.Fatal("Vote failed: TxnOutput should have prefix '" + expectedOutput + "'")
+		}
+	}
+
+	// All the shares are new, so are the signers.
+	owner := getOwnerWallet(c.signatureScheme, c.ownerKeysFile)
+	for _, mptWallet := range nw.getSignerMPTWallets() {
+		registerMPTWallet(mptWallet)
+		airdrop(owner, mptWallet.ClientID)
+	}
+
+	doProposalWithAllN(nw)
+	printBalance(0, nw)
+
+	Logger.Info("Finished test")
+}
+
 func testStress(id int) {
 	Logger.Info("Stress testing multi-sig transfers...", zap.Int("worker#", id))
 
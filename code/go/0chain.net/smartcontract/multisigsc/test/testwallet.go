@@ -24,6 +24,9 @@ type testWallet struct {
 	signerKeys      []encryption.ThresholdSignatureScheme
 
 	t, n int
+
+	// Version of the registered wallet.
+	version int64
 }
 
 type testProposal struct {
@@ -231,3 +234,75 @@ func (t testWallet) toWallet() multisigsc.Wallet {
 		SignatureScheme:    t.signatureScheme,
 	}
 }
+
+type testActionProposal struct {
+	votes map[string]multisigsc.ActionVote
+}
+
+// Re-split the group key between n signers with threshold t. Returns the
+// wallet with the new signers and the action to propose. The wallet must not
+// be used before the action is executed.
+func (t testWallet) resplit(actionType string, newT, newN int) (testWallet, multisigsc.Action) {
+	signerKeys, err := encryption.GenerateThresholdKeyShares(t.signatureScheme, newT, newN, t.groupKey)
+	if err != nil {
+		panic(err)
+	}
+
+	var signerClientIDs []string
+	for _, key := range signerKeys {
+		signerClientIDs = append(signerClientIDs, clientIDForKey(key))
+	}
+
+	nt := t
+	nt.signerClientIDs = signerClientIDs
+	nt.signerKeys = signerKeys
+	nt.t, nt.n = newT, newN
+	nt.version = t.version + 1
+
+	w := nt.toWallet()
+
+	return nt, multisigsc.Action{
+		Type:               actionType,
+		ClientID:           t.groupClientID,
+		WalletVersion:      t.version,
+		SignerThresholdIDs: w.SignerThresholdIDs,
+		SignerPublicKeys:   w.SignerPublicKeys,
+		NumRequired:        newT,
+	}
+}
+
+func (t testWallet) newActionProposal(proposalID string, a multisigsc.Action) testActionProposal {
+	votes := make(map[string]multisigsc.ActionVote)
+
+	for i, signer := range t.signerKeys {
+		sig, err := signer.Sign(a.Hash())
+		if err != nil {
+			Logger.Fatal("Failed to sign action", zap.Error(err))
+		}
+
+		votes[t.signerClientIDs[i]] = multisigsc.ActionVote{
+			ProposalID: proposalID,
+			Action:     a,
+			Signature:  sig,
+		}
+	}
+
+	return testActionProposal{
+		votes: votes,
+	}
+}
+
+func (t testWallet) registerActionVote(p testActionProposal, signerClientID string) string {
+	data := httpclientutil.SmartContractTxnData{
+		Name:      multisigsc.ActionVoteFuncName,
+		InputArgs: p.votes[signerClientID],
+	}
+
+	Logger.Info("Requesting SC:VoteAction...", zap.Int("multi-sig wallet#", t.id), zap.Any("args", data.InputArgs))
+
+	txn := t.signerTransaction(signerClientID, 0, &data)
+
+	Logger.Info("Response received for SC:VoteAction", zap.Int("multi-sig wallet#", t.id), zap.String("txn hash", txn.Hash), zap.String("txn output", txn.TransactionOutput))
+
+	return txn.TransactionOutput
+}
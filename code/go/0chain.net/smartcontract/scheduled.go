@@ -0,0 +1,170 @@
+package smartcontract
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ScheduleRequest is input of SC functions changing settings. It's
+// compatible with the StringMap. The activation round is optional.
+type ScheduleRequest struct {
+	ActivationRound int64             `json:"activation_round,omitempty"`
+	Fields          map[string]string `json:"fields"`
+}
+
+func (sr *ScheduleRequest) Decode(input []byte) error {
+	return json.Unmarshal(input, sr)
+}
+
+// Activation returns round the requested changes should be activated at
+// for given current round and time lock (min number of rounds of notice).
+// Zero means the changes are not time-locked and can be applied right now.
+func (sr *ScheduleRequest) Activation(round, timeLock int64) (int64, error) {
+	if sr.ActivationRound == 0 {
+		if timeLock <= 0 {
+			return 0, nil
+		}
+		return round + timeLock, nil
+	}
+	if sr.ActivationRound <= round {
+		return 0, fmt.Errorf("activation round %d is not in future, "+
+			"current round %d", sr.ActivationRound, round)
+	}
+	if sr.ActivationRound < round+timeLock {
+		return 0, fmt.Errorf("activation round %d violates time lock, "+
+			"min activation round is %d", sr.ActivationRound, round+timeLock)
+	}
+	return sr.ActivationRound, nil
+}
+
+// CancelScheduleRequest is input of SC functions cancelling scheduled
+// settings changes.
+type CancelScheduleRequest struct {
+	ID string `json:"id"`
+}
+
+func (cr *CancelScheduleRequest) Decode(input []byte) error {
+	if err := json.Unmarshal(input, cr); err != nil {
+		return err
+	}
+	if cr.ID == "" {
+		return errors.New("missing scheduled changes id")
+	}
+	return nil
+}
+
+// ScheduledChange is settings changes waiting for its activation round.
+type ScheduledChange struct {
+	ID              string            `json:"id"`
+	ActivationRound int64             `json:"activation_round"`
+	Fields          map[string]string `json:"fields"`
+}
+
+// SettingDiff is pending change of a setting.
+type SettingDiff struct {
+	Name    string `json:"name"`
+	Current string `json:"current"`
+	Pending string `json:"pending"`
+}
+
+// Diff of the changes against given current settings, sorted by name.
+func (sc *ScheduledChange) Diff(current map[string]string) (diff []SettingDiff) {
+	diff = make([]SettingDiff, 0, len(sc.Fields))
+	for name, value := range sc.Fields {
+		diff = append(diff, SettingDiff{
+			Name:    name,
+			Current: current[name],
+			Pending: value,
+		})
+	}
+	sort.Slice(diff, func(i, j int) bool {
+		return diff[i].Name < diff[j].Name
+	})
+	return
+}
+
+// PendingChange is preview of scheduled changes.
+type PendingChange struct {
+	ID              string        `json:"id"`
+	ActivationRound int64         `json:"activation_round"`
+	Diff            []SettingDiff `json:"diff"`
+}
+
+// ScheduledChanges is queue of settings changes of a smart contract
+// ordered by activation rounds.
+type ScheduledChanges struct {
+	Changes []*ScheduledChange `json:"changes"`
+}
+
+func (scs *ScheduledChanges) Encode() []byte {
+	var b, err = json.Marshal(scs)
+	if err != nil {
+		panic(err) // must never happen
+	}
+	return b
+}
+
+func (scs *ScheduledChanges) Decode(input []byte) error {
+	return json.Unmarshal(input, scs)
+}
+
+// Add changes to the queue keeping the order, changes with the same
+// activation round are applied in order they have been added.
+func (scs *ScheduledChanges) Add(sc *ScheduledChange) error {
+	for _, c := range scs.Changes {
+		if c.ID == sc.ID {
+			return fmt.Errorf("scheduled changes %s already exist", sc.ID)
+		}
+	}
+	var i = sort.Search(len(scs.Changes), func(i int) bool {
+		return scs.Changes[i].ActivationRound > sc.ActivationRound
+	})
+	scs.Changes = append(scs.Changes, nil)
+	copy(scs.Changes[i+1:], scs.Changes[i:])
+	scs.Changes[i] = sc
+	return nil
+}
+
+// Cancel removes changes with given ID from the queue.
+func (scs *ScheduledChanges) Cancel(id string) bool {
+	for i, c := range scs.Changes {
+		if c.ID == id {
+			scs.Changes = append(scs.Changes[:i], scs.Changes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// IsDue returns true if there are changes should be activated at
+// given round.
+func (scs *ScheduledChanges) IsDue(round int64) bool {
+	return len(scs.Changes) > 0 && scs.Changes[0].ActivationRound <= round
+}
+
+// Due removes from the queue and returns changes should be activated
+// at given round.
+func (scs *ScheduledChanges) Due(round int64) (due []*ScheduledChange) {
+	var i = sort.Search(len(scs.Changes), func(i int) bool {
+		return scs.Changes[i].ActivationRound > round
+	})
+	due, scs.Changes = scs.Changes[:i:i], scs.Changes[i:]
+	return
+}
+
+// Preview of the scheduled changes against given current settings.
+func (scs *ScheduledChanges) Preview(current map[string]string) (
+	pending []*PendingChange) {
+
+	pending = make([]*PendingChange, 0, len(scs.Changes))
+	for _, c := range scs.Changes {
+		pending = append(pending, &PendingChange{
+			ID:              c.ID,
+			ActivationRound: c.ActivationRound,
+			Diff:            c.Diff(current),
+		})
+	}
+	return
+}
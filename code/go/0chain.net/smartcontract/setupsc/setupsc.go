@@ -2,11 +2,13 @@ package setupsc
 
 import (
 	"fmt"
+	"sort"
 
 	"0chain.net/chaincore/smartcontract"
 	sci "0chain.net/chaincore/smartcontractinterface"
 	"0chain.net/core/viper"
 	"0chain.net/smartcontract/faucetsc"
+	"0chain.net/smartcontract/governancesc"
 	"0chain.net/smartcontract/interestpoolsc"
 	"0chain.net/smartcontract/minersc"
 	"0chain.net/smartcontract/multisigsc"
@@ -15,71 +17,68 @@ import (
 	"0chain.net/smartcontract/zcnsc"
 )
 
-type SCName int
+// NewSmartContract creates implementation of a smart contract version
+type NewSmartContract func() sci.SmartContractInterface
 
-const (
-	Faucet SCName = iota
-	Storage
-	Interest
-	Multisig
-	Miner
-	Vesting
-	Zcn
-)
+// contracts - constructors of the smart contracts implementations by name
+// and version
+var contracts = map[string]map[int]NewSmartContract{}
+
+func init() {
+	var v1 = smartcontract.DefaultContractVersion
+	RegisterSmartContract("faucet", v1, func() sci.SmartContractInterface {
+		return faucetsc.NewFaucetSmartContract()
+	})
+	RegisterSmartContract("storage", v1, func() sci.SmartContractInterface {
+		return storagesc.NewStorageSmartContract()
+	})
+	RegisterSmartContract("interest", v1, func() sci.SmartContractInterface {
+		return interestpoolsc.NewInterestPoolSmartContract()
+	})
+	RegisterSmartContract("multisig", v1, func() sci.SmartContractInterface {
+		return multisigsc.NewMultiSigSmartContract()
+	})
+	RegisterSmartContract("miner", v1, func() sci.SmartContractInterface {
+		return minersc.NewMinerSmartContract()
+	})
+	RegisterSmartContract("vesting", v1, func() sci.SmartContractInterface {
+		return vestingsc.NewVestingSmartContract()
+	})
+	RegisterSmartContract("zcn", v1, func() sci.SmartContractInterface {
+		return zcnsc.NewZCNSmartContract()
+	})
+	RegisterSmartContract("governance", v1, func() sci.SmartContractInterface {
+		return governancesc.NewGovernanceSmartContract()
+	})
+}
 
-var (
-	SCNames = []string{
-		"faucet",
-		"storage",
-		"interest",
-		"multisig",
-		"miner",
-		"vesting",
-		"zcn",
+// RegisterSmartContract registers implementation of given version of the
+// smart contract. A new version of a contract is registered along with the
+// previous ones under the same name, the version active at a round is
+// scheduled in state.
+func RegisterSmartContract(name string, version int, newSC NewSmartContract) {
+	if _, ok := contracts[name]; !ok {
+		contracts[name] = make(map[int]NewSmartContract)
 	}
+	contracts[name][version] = newSC
+}
 
-	SCCode = map[string]SCName{
-		"faucet":   Faucet,
-		"storage":  Storage,
-		"interest": Interest,
-		"multisig": Multisig,
-		"miner":    Miner,
-		"vesting":  Vesting,
-		"zcn":      Zcn,
+// SCNames returns sorted names of the registered smart contracts.
+func SCNames() (names []string) {
+	for name := range contracts {
+		names = append(names, name)
 	}
-)
+	sort.Strings(names)
+	return
+}
 
 //SetupSmartContracts initializes smart contract addresses
 func SetupSmartContracts() {
-	for _, name := range SCNames {
+	for _, name := range SCNames() {
 		if viper.GetBool(fmt.Sprintf("development.smart_contract.%v", name)) {
-			var contract = newSmartContract(name)
-			smartcontract.ContractMap[contract.GetAddress()] = contract
+			for version, newSC := range contracts[name] {
+				smartcontract.RegisterContractVersion(version, newSC())
+			}
 		}
 	}
 }
-
-func newSmartContract(name string) sci.SmartContractInterface {
-	code, ok := SCCode[name]
-	if !ok {
-		return nil
-	}
-	switch code {
-	case Faucet:
-		return faucetsc.NewFaucetSmartContract()
-	case Storage:
-		return storagesc.NewStorageSmartContract()
-	case Interest:
-		return interestpoolsc.NewInterestPoolSmartContract()
-	case Multisig:
-		return multisigsc.NewMultiSigSmartContract()
-	case Miner:
-		return minersc.NewMinerSmartContract()
-	case Vesting:
-		return vestingsc.NewVestingSmartContract()
-	case Zcn:
-		return zcnsc.NewZCNSmartContract()
-	default:
-		return nil
-	}
-}
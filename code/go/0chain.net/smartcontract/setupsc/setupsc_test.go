@@ -0,0 +1,29 @@
+package setupsc
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"0chain.net/chaincore/smartcontract"
+	sci "0chain.net/chaincore/smartcontractinterface"
+	"0chain.net/core/viper"
+	"0chain.net/smartcontract/faucetsc"
+)
+
+func TestSetupSmartContracts(t *testing.T) {
+	require.Contains(t, SCNames(), "faucet")
+
+	// the second version of the faucet
+	var v2 = faucetsc.NewFaucetSmartContract()
+	RegisterSmartContract("faucet", 2, func() sci.SmartContractInterface {
+		return v2
+	})
+	viper.Set("development.smart_contract.faucet", true)
+	SetupSmartContracts()
+
+	require.Equal(t, []int{1, 2},
+		smartcontract.GetContractVersions(faucetsc.ADDRESS))
+	require.True(t, smartcontract.GetSmartContractVersion(faucetsc.ADDRESS, 2) == v2)
+	require.NotNil(t, smartcontract.GetSmartContract(faucetsc.ADDRESS))
+}
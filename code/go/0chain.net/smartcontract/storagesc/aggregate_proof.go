@@ -0,0 +1,811 @@
+package storagesc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/url"
+	"sort"
+	"strconv"
+
+	"0chain.net/smartcontract"
+
+	cstate "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+)
+
+//
+// Aggregate proofs. An optional proof-of-storage mode. A blobber commits
+// once an epoch a Merkle root over all its allocation roots. A challenge
+// samples some leaves of the commitment, and the blobber responds with
+// Merkle paths of the sampled leaves (verified by the SC) and validation
+// tickets of validators verified the sampled data. Thus, number of the
+// challenges depends on number of blobbers, not on number of allocations.
+// A passed challenge rewards the blobber and the validators for the sampled
+// allocations, a failed one penalizes the blobber like a failed challenge of
+// an allocation.
+//
+
+func blobberAggregateKey(scKey, blobberID string) datastore.Key {
+	return datastore.Key(scKey + ":aggregate:" + blobberID)
+}
+
+// An allocationLeaf is a leaf of an aggregate commitment Merkle tree. The
+// leaves are all allocations of a blobber sorted by allocation ID.
+type allocationLeaf struct {
+	AllocationID   string `json:"allocation_id"`
+	AllocationRoot string `json:"allocation_root"`
+}
+
+// GetHash implements util.Hashable interface.
+func (al *allocationLeaf) GetHash() string {
+	return encryption.Hash(al.AllocationID + ":" + al.AllocationRoot)
+}
+
+// GetHashBytes implements util.Hashable interface.
+func (al *allocationLeaf) GetHashBytes() []byte {
+	return encryption.RawHash(al.AllocationID + ":" + al.AllocationRoot)
+}
+
+// merkleDepth returns length of a Merkle path of a tree with given number
+// of leaves (see util.MerkleTree).
+func merkleDepth(leaves int) (depth int) {
+	if leaves <= 1 {
+		return 1
+	}
+	for ll := leaves; ll > 1; ll = (ll + 1) / 2 {
+		depth++
+	}
+	return
+}
+
+// An aggregateCommitment of a blobber for an epoch.
+type aggregateCommitment struct {
+	Epoch     int64            `json:"epoch"`
+	Root      string           `json:"root"`
+	NumLeaves int              `json:"num_leaves"`
+	Created   common.Timestamp `json:"created"`
+}
+
+// An aggregateChallenge samples leaves of a commitment.
+type aggregateChallenge struct {
+	ID         string            `json:"id"`
+	BlobberID  string            `json:"blobber_id"`
+	Epoch      int64             `json:"epoch"`
+	Root       string            `json:"root"`
+	NumLeaves  int               `json:"num_leaves"`
+	Samples    []int             `json:"samples"`
+	Validators []*ValidationNode `json:"validators"`
+	Created    common.Timestamp  `json:"created"`
+}
+
+func (ac *aggregateChallenge) isValidator(id string) bool {
+	for _, v := range ac.Validators {
+		if v.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+type aggregateProofsStats struct {
+	TotalChallenges   int64  `json:"total_challenges"`
+	SuccessChallenges int64  `json:"success_challenges"`
+	FailedChallenges  int64  `json:"failed_challenges"`
+	LastChallengeID   string `json:"last_challenge_id"`
+	LastChallenged    int64  `json:"last_challenged_epoch"`
+}
+
+// A blobberAggregate keeps latest commitment, open challenge and proofs
+// statistic of a blobber.
+type blobberAggregate struct {
+	BlobberID  string               `json:"blobber_id"`
+	Commitment *aggregateCommitment `json:"commitment"`
+	Challenge  *aggregateChallenge  `json:"challenge"` // open challenge
+	Stats      aggregateProofsStats `json:"stats"`
+	// Resolved is time of last resolved challenge per allocation.
+	Resolved map[string]common.Timestamp `json:"resolved,omitempty"`
+}
+
+// Encode implements util.Serializable interface.
+func (ba *blobberAggregate) Encode() []byte {
+	var b, err = json.Marshal(ba)
+	if err != nil {
+		panic(err) // must never happens
+	}
+	return b
+}
+
+// Decode implements util.Serializable interface.
+func (ba *blobberAggregate) Decode(p []byte) error {
+	return json.Unmarshal(p, ba)
+}
+
+func (ba *blobberAggregate) save(sscKey string,
+	balances cstate.StateContextI) (err error) {
+
+	_, err = balances.InsertTrieNode(blobberAggregateKey(sscKey, ba.BlobberID),
+		ba)
+	return
+}
+
+// resolve the open challenge
+func (ba *blobberAggregate) resolve(pass bool) {
+	if pass {
+		ba.Stats.SuccessChallenges++
+	} else {
+		ba.Stats.FailedChallenges++
+	}
+	ba.Stats.LastChallengeID = ba.Challenge.ID
+	ba.Challenge = nil
+}
+
+// previous returns time of previous resolved challenge of the blobber
+// regarding given allocation; rewards and penalties cover period from the
+// time to the open challenge
+func (ba *blobberAggregate) previous(alloc *StorageAllocation) (
+	prev common.Timestamp) {
+
+	if prev = alloc.StartTime; ba.Resolved[alloc.ID] > prev {
+		prev = ba.Resolved[alloc.ID]
+	}
+	return
+}
+
+// settle the period of given allocation covered by the open challenge
+func (ba *blobberAggregate) settle(allocID string) {
+	if ba.Resolved == nil {
+		ba.Resolved = make(map[string]common.Timestamp)
+	}
+	ba.Resolved[allocID] = ba.Challenge.Created
+}
+
+// prune resolution times of allocations the blobber doesn't store anymore
+func (ba *blobberAggregate) prune(ids []string) {
+	var keep = make(map[string]bool, len(ids))
+	for _, id := range ids {
+		keep[id] = true
+	}
+	for id := range ba.Resolved {
+		if !keep[id] {
+			delete(ba.Resolved, id)
+		}
+	}
+}
+
+// isAggregateBlobber returns true if the blobber proves its storage by
+// aggregate commitments; such blobbers don't get per allocation challenges
+func (sc *StorageSmartContract) isAggregateBlobber(blobberID string,
+	balances cstate.StateContextI) bool {
+
+	var ba, err = sc.getBlobberAggregate(blobberID, balances)
+	if err != nil || ba.Commitment == nil {
+		return false
+	}
+	var conf *scConfig
+	if conf, err = sc.getConfig(balances, false); err != nil {
+		return false
+	}
+	return conf.aggregateProofs().Enabled
+}
+
+// completed returns the open challenge as completed blobber challenge used
+// by rewards and penalties
+func (ba *blobberAggregate) completed() *BlobberChallenge {
+	return &BlobberChallenge{
+		BlobberID: ba.BlobberID,
+		LatestCompletedChallenge: &StorageChallenge{
+			ID:      ba.Challenge.ID,
+			Created: ba.Challenge.Created,
+		},
+	}
+}
+
+// blobberAllocations returns sorted IDs of allocations of a blobber, that
+// are allocations have open offers in its stake pool
+func blobberAllocations(sp *stakePool, now common.Timestamp) (ids []string) {
+	for allocID, off := range sp.Offers {
+		if off.Expire > now {
+			ids = append(ids, allocID)
+		}
+	}
+	sort.Strings(ids)
+	return
+}
+
+// sampleLeaves returns sorted k distinct indices of n leaves; it doesn't
+// depend on n (Floyd's algorithm)
+func sampleLeaves(r *rand.Rand, n, k int) (samples []int) {
+	if k > n {
+		k = n
+	}
+	var chosen = make(map[int]bool, k)
+	for j := n - k; j < n; j++ {
+		var i = r.Intn(j + 1)
+		if chosen[i] {
+			i = j
+		}
+		chosen[i] = true
+		samples = append(samples, i)
+	}
+	sort.Ints(samples)
+	return
+}
+
+// isExpired returns true if the open challenge can't be completed anymore
+func (ba *blobberAggregate) isExpired(conf *scConfig,
+	now common.Timestamp) bool {
+
+	return ba.Challenge != nil &&
+		ba.Challenge.Created+toSeconds(conf.MaxChallengeCompletionTime) < now
+}
+
+// aggregateEpoch returns epoch of given time
+func aggregateEpoch(conf *scConfig, now common.Timestamp) int64 {
+	var epoch = toSeconds(conf.aggregateProofs().Epoch)
+	if epoch <= 0 {
+		epoch = 1
+	}
+	return int64(now / epoch)
+}
+
+func (sc *StorageSmartContract) getBlobberAggregate(blobberID string,
+	balances cstate.StateContextI) (ba *blobberAggregate, err error) {
+
+	var val util.Serializable
+	val, err = balances.GetTrieNode(blobberAggregateKey(sc.ID, blobberID))
+	if err != nil {
+		return
+	}
+	ba = new(blobberAggregate)
+	if err = ba.Decode(val.Encode()); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return
+}
+
+func (sc *StorageSmartContract) getOrCreateBlobberAggregate(blobberID string,
+	balances cstate.StateContextI) (ba *blobberAggregate, err error) {
+
+	ba, err = sc.getBlobberAggregate(blobberID, balances)
+	if err == util.ErrValueNotPresent {
+		return &blobberAggregate{BlobberID: blobberID}, nil
+	}
+	return
+}
+
+//
+// SC functions
+//
+
+type aggregateCommitRequest struct {
+	Epoch     int64  `json:"epoch"`
+	Root      string `json:"root"`
+	NumLeaves int    `json:"num_leaves"`
+}
+
+func (acr *aggregateCommitRequest) decode(p []byte) error {
+	return json.Unmarshal(p, acr)
+}
+
+// commitBlobberAggregate saves aggregate commitment of a blobber for
+// current epoch; a blobber can commit once an epoch, and the commitment
+// should include all allocations of the blobber
+func (sc *StorageSmartContract) commitBlobberAggregate(
+	t *transaction.Transaction, input []byte,
+	balances cstate.StateContextI) (resp string, err error) {
+
+	var conf *scConfig
+	if conf, err = sc.getConfig(balances, false); err != nil {
+		return "", common.NewErrorf("commit_blobber_aggregate",
+			"can't get SC configurations: %v", err)
+	}
+
+	if !conf.aggregateProofs().Enabled {
+		return "", common.NewError("commit_blobber_aggregate",
+			"aggregate proofs are disabled")
+	}
+
+	var req aggregateCommitRequest
+	if err = req.decode(input); err != nil {
+		return "", common.NewErrorf("commit_blobber_aggregate",
+			"decoding request: %v", err)
+	}
+
+	if !encryption.IsHash(req.Root) {
+		return "", common.NewError("commit_blobber_aggregate",
+			"invalid commitment root")
+	}
+
+	if req.NumLeaves <= 0 {
+		return "", common.NewError("commit_blobber_aggregate",
+			"invalid number of leaves")
+	}
+
+	if epoch := aggregateEpoch(conf, t.CreationDate); req.Epoch != epoch {
+		return "", common.NewErrorf("commit_blobber_aggregate",
+			"invalid epoch %d, current epoch is %d", req.Epoch, epoch)
+	}
+
+	if _, err = sc.getBlobber(t.ClientID, balances); err != nil {
+		return "", common.NewErrorf("commit_blobber_aggregate",
+			"can't get blobber: %v", err)
+	}
+
+	var sp *stakePool
+	if sp, err = sc.getStakePool(t.ClientID, balances); err != nil {
+		return "", common.NewErrorf("commit_blobber_aggregate",
+			"can't get blobber's stake pool: %v", err)
+	}
+
+	var allocs = blobberAllocations(sp, t.CreationDate)
+	if req.NumLeaves != len(allocs) {
+		return "", common.NewErrorf("commit_blobber_aggregate",
+			"number of leaves %d doesn't match number of allocations of the"+
+				" blobber %d", req.NumLeaves, len(allocs))
+	}
+
+	var ba *blobberAggregate
+	if ba, err = sc.getOrCreateBlobberAggregate(t.ClientID, balances); err != nil {
+		return "", common.NewErrorf("commit_blobber_aggregate",
+			"can't get blobber aggregate: %v", err)
+	}
+
+	if ba.Commitment != nil && ba.Commitment.Epoch >= req.Epoch {
+		return "", common.NewErrorf("commit_blobber_aggregate",
+			"already committed for epoch %d", req.Epoch)
+	}
+
+	ba.Commitment = &aggregateCommitment{
+		Epoch:     req.Epoch,
+		Root:      req.Root,
+		NumLeaves: req.NumLeaves,
+		Created:   t.CreationDate,
+	}
+
+	if err = ba.save(sc.ID, balances); err != nil {
+		return "", common.NewErrorf("commit_blobber_aggregate",
+			"saving blobber aggregate: %v", err)
+	}
+
+	return string(ba.Encode()), nil
+}
+
+// selectAggregateValidators selects validators for an aggregate challenge
+func selectAggregateValidators(validators []*ValidationNode,
+	blobberID string, n int, r *rand.Rand) (selected []*ValidationNode) {
+
+	for _, i := range r.Perm(len(validators)) {
+		if len(selected) >= n {
+			break
+		}
+		if validators[i].ID != blobberID {
+			selected = append(selected, validators[i])
+		}
+	}
+	return
+}
+
+// generateAggregateChallenges generates at most one challenge per blobber
+// per commitment; an open challenge not completed in time is failed
+func (sc *StorageSmartContract) generateAggregateChallenges(
+	t *transaction.Transaction, _ []byte,
+	balances cstate.StateContextI) (resp string, err error) {
+
+	var conf *scConfig
+	if conf, err = sc.getConfig(balances, false); err != nil {
+		return "", common.NewErrorf("generate_aggregate_challenges",
+			"can't get SC configurations: %v", err)
+	}
+
+	var apc = conf.aggregateProofs()
+	if !apc.Enabled {
+		return "Aggregate proofs disabled in the config", nil
+	}
+
+	var b = balances.GetBlock()
+	if b == nil {
+		return "", common.NewError("generate_aggregate_challenges",
+			"missing current block")
+	}
+
+	var validators *ValidatorNodes
+	if validators, err = sc.getValidatorsList(balances); err != nil {
+		return "", common.NewErrorf("generate_aggregate_challenges",
+			"error getting the validators list: %v", err)
+	}
+
+	if len(validators.Nodes) == 0 {
+		return "", common.NewError("no_validators",
+			"not enough validators for the challenge")
+	}
+
+	var blobbers *StorageNodes
+	if blobbers, err = sc.getBlobbersList(balances); err != nil {
+		return "", common.NewErrorf("generate_aggregate_challenges",
+			"error getting the blobbers list: %v", err)
+	}
+
+	var (
+		epoch      = aggregateEpoch(conf, t.CreationDate)
+		hashString = encryption.Hash(t.Hash + b.PrevHash)
+		generated  int
+	)
+
+	for _, blobber := range blobbers.Nodes {
+		var ba *blobberAggregate
+		ba, err = sc.getBlobberAggregate(blobber.ID, balances)
+		if err == util.ErrValueNotPresent {
+			continue // no commitments
+		}
+		if err != nil {
+			return "", common.NewErrorf("generate_aggregate_challenges",
+				"can't get blobber aggregate: %v", err)
+		}
+
+		var changed bool
+		if ba.isExpired(conf, t.CreationDate) {
+			if err = sc.aggregatePenalty(t, ba, nil, balances); err != nil {
+				return "", common.NewErrorf("generate_aggregate_challenges",
+					"penalizing blobber %s: %v", blobber.ID, err)
+			}
+			ba.resolve(false)
+			sc.challengeResolved(balances, false)
+			changed = true
+		}
+
+		var cm = ba.Commitment
+		if ba.Challenge == nil && cm != nil && cm.Epoch >= epoch-1 &&
+			ba.Stats.LastChallenged < cm.Epoch {
+
+			var (
+				id         = encryption.Hash(hashString + ":" + blobber.ID)
+				seed int64 = 0
+			)
+			if seed, err = strconv.ParseInt(id[0:15], 16, 64); err != nil {
+				return "", common.NewErrorf("generate_aggregate_challenges",
+					"creating challenge seed: %v", err)
+			}
+			var (
+				r       = rand.New(rand.NewSource(seed))
+				samples = sampleLeaves(r, cm.NumLeaves, apc.NumSamples)
+			)
+			ba.Challenge = &aggregateChallenge{
+				ID:        id,
+				BlobberID: blobber.ID,
+				Epoch:     cm.Epoch,
+				Root:      cm.Root,
+				NumLeaves: cm.NumLeaves,
+				Samples:   samples,
+				Validators: selectAggregateValidators(validators.Nodes,
+					blobber.ID, apc.NumValidators, r),
+				Created: t.CreationDate,
+			}
+			ba.Stats.TotalChallenges++
+			ba.Stats.LastChallenged = cm.Epoch
+			sc.newChallenge(balances, t.CreationDate)
+			changed = true
+			generated++
+		}
+
+		if !changed {
+			continue
+		}
+		if err = ba.save(sc.ID, balances); err != nil {
+			return "", common.NewErrorf("generate_aggregate_challenges",
+				"saving blobber aggregate: %v", err)
+		}
+	}
+
+	return fmt.Sprintf("%d aggregate challenges generated", generated), nil
+}
+
+// An aggregateProof of a sampled leaf.
+type aggregateProof struct {
+	allocationLeaf
+	Path *util.MTPath `json:"path"`
+}
+
+type aggregateChallengeResponse struct {
+	ChallengeID       string              `json:"challenge_id"`
+	Proofs            []*aggregateProof   `json:"proofs"`
+	ValidationTickets []*ValidationTicket `json:"validation_tickets"`
+}
+
+func (acr *aggregateChallengeResponse) decode(p []byte) error {
+	return json.Unmarshal(p, acr)
+}
+
+// verifyAggregateProofs verifies Merkle paths of sampled leaves, and the
+// leaves are sorted and unique allocations of the blobber with the same
+// allocation roots the SC has; it returns the allocations
+func (sc *StorageSmartContract) verifyAggregateProofs(
+	ac *aggregateChallenge, proofs []*aggregateProof,
+	balances cstate.StateContextI) (allocs []*StorageAllocation, err error) {
+
+	if len(proofs) != len(ac.Samples) {
+		return nil, fmt.Errorf("expected %d proofs, got %d", len(ac.Samples),
+			len(proofs))
+	}
+
+	var depth = merkleDepth(ac.NumLeaves)
+	for i, proof := range proofs {
+		if proof == nil || proof.Path == nil {
+			return nil, fmt.Errorf("missing proof %d", i)
+		}
+		if proof.Path.LeafIndex != ac.Samples[i] {
+			return nil, fmt.Errorf("proof %d: unexpected leaf index %d, want %d",
+				i, proof.Path.LeafIndex, ac.Samples[i])
+		}
+		if i > 0 && proof.AllocationID <= proofs[i-1].AllocationID {
+			return nil, fmt.Errorf("proof %d: leaves are not sorted or not"+
+				" unique", i)
+		}
+		if len(proof.Path.Nodes) != depth {
+			return nil, fmt.Errorf("proof %d: invalid path length", i)
+		}
+		if !util.VerifyMerklePath(proof.GetHash(), proof.Path, ac.Root) {
+			return nil, fmt.Errorf("proof %d: invalid Merkle path", i)
+		}
+		var alloc *StorageAllocation
+		if alloc, err = sc.getAllocation(proof.AllocationID, balances); err != nil {
+			return nil, fmt.Errorf("proof %d: can't get allocation: %v", i, err)
+		}
+		var details, ok = alloc.BlobberMap[ac.BlobberID]
+		if !ok {
+			return nil, fmt.Errorf("proof %d: blobber is not part of"+
+				" allocation %s", i, alloc.ID)
+		}
+		if alloc.Finalized || alloc.Canceled {
+			return nil, fmt.Errorf("proof %d: allocation %s is finalized or"+
+				" canceled", i, alloc.ID)
+		}
+		if proof.AllocationRoot != details.AllocationRoot {
+			return nil, fmt.Errorf("proof %d: allocation root of %s doesn't"+
+				" match", i, alloc.ID)
+		}
+		allocs = append(allocs, alloc)
+	}
+	return
+}
+
+// isLate returns true if the open challenge is out of allocation's
+// challenge completion time
+func (ba *blobberAggregate) isLate(alloc *StorageAllocation,
+	details *BlobberAllocation) bool {
+
+	return ba.Challenge.Created > alloc.Expiration+
+		toSeconds(details.Terms.ChallengeCompletionTime)
+}
+
+// aggregateReward rewards the blobber and given validators for proven
+// allocations
+func (sc *StorageSmartContract) aggregateReward(t *transaction.Transaction,
+	ba *blobberAggregate, allocs []*StorageAllocation, validators []string,
+	balances cstate.StateContextI) (err error) {
+
+	// the proofs cover all allocations of the blobber
+	var ids = make([]string, 0, len(allocs))
+	for _, alloc := range allocs {
+		ids = append(ids, alloc.ID)
+	}
+	ba.prune(ids)
+
+	for _, alloc := range allocs {
+		var details = alloc.BlobberMap[ba.BlobberID]
+		if ba.isLate(alloc, details) {
+			continue // expired allocation
+		}
+		err = sc.blobberReward(t, alloc, ba.previous(alloc), ba.completed(),
+			details, validators, 1.0, balances)
+		if err != nil {
+			return fmt.Errorf("rewarding for allocation %s: %v", alloc.ID, err)
+		}
+		if _, err = balances.InsertTrieNode(alloc.GetKey(sc.ID), alloc); err != nil {
+			return fmt.Errorf("saving allocation %s: %v", alloc.ID, err)
+		}
+		ba.settle(alloc.ID)
+	}
+	return
+}
+
+// aggregatePenalty penalizes the blobber for sampled allocations; the
+// allocations are taken from allocations of the blobber sorted by ID, as
+// the leaves are, and don't depend on proofs given by the blobber
+func (sc *StorageSmartContract) aggregatePenalty(t *transaction.Transaction,
+	ba *blobberAggregate, validators []string,
+	balances cstate.StateContextI) (err error) {
+
+	var sp *stakePool
+	if sp, err = sc.getStakePool(ba.BlobberID, balances); err != nil {
+		return fmt.Errorf("can't get blobber's stake pool: %v", err)
+	}
+
+	var ids = blobberAllocations(sp, ba.Challenge.Created)
+	ba.prune(ids)
+	if len(ids) == 0 {
+		return // nothing to penalize for
+	}
+
+	var penalized = make(map[string]bool, len(ba.Challenge.Samples))
+	for _, i := range ba.Challenge.Samples {
+		var allocID = ids[i%len(ids)]
+		if penalized[allocID] {
+			continue
+		}
+		penalized[allocID] = true
+
+		var alloc *StorageAllocation
+		if alloc, err = sc.getAllocation(allocID, balances); err != nil {
+			return fmt.Errorf("can't get allocation %s: %v", allocID, err)
+		}
+		var details, ok = alloc.BlobberMap[ba.BlobberID]
+		if !ok || ba.isLate(alloc, details) {
+			continue // not an allocation of the blobber or expired one
+		}
+		err = sc.blobberPenalty(t, alloc, ba.previous(alloc), ba.completed(),
+			details, validators, balances)
+		if err != nil {
+			return fmt.Errorf("penalizing for allocation %s: %v", allocID, err)
+		}
+		if _, err = balances.InsertTrieNode(alloc.GetKey(sc.ID), alloc); err != nil {
+			return fmt.Errorf("saving allocation %s: %v", allocID, err)
+		}
+		ba.settle(allocID)
+	}
+	return
+}
+
+// aggregateChallengeResponse verifies a batch proof of a blobber
+func (sc *StorageSmartContract) aggregateChallengeResponse(
+	t *transaction.Transaction, input []byte,
+	balances cstate.StateContextI) (resp string, err error) {
+
+	var req aggregateChallengeResponse
+	if err = req.decode(input); err != nil {
+		return "", common.NewErrorf("aggregate_challenge_response",
+			"decoding request: %v", err)
+	}
+
+	if req.ChallengeID == "" || len(req.ValidationTickets) == 0 {
+		return "", common.NewError("aggregate_challenge_response",
+			"invalid parameters to challenge response")
+	}
+
+	var conf *scConfig
+	if conf, err = sc.getConfig(balances, false); err != nil {
+		return "", common.NewErrorf("aggregate_challenge_response",
+			"can't get SC configurations: %v", err)
+	}
+
+	var ba *blobberAggregate
+	if ba, err = sc.getBlobberAggregate(t.ClientID, balances); err != nil {
+		return "", common.NewErrorf("aggregate_challenge_response",
+			"can't get blobber aggregate: %v", err)
+	}
+
+	var ac = ba.Challenge
+	if ac == nil || ac.ID != req.ChallengeID {
+		if ba.Stats.LastChallengeID == req.ChallengeID {
+			return "Challenge Already redeemed by Blobber", nil
+		}
+		return "", common.NewErrorf("aggregate_challenge_response",
+			"cannot find the challenge with ID %s", req.ChallengeID)
+	}
+
+	var allocs, proofErr = sc.verifyAggregateProofs(ac, req.Proofs, balances)
+
+	var (
+		success, failure int
+		validators       []string // validators verified the proofs
+	)
+	for _, vt := range req.ValidationTickets {
+		if vt == nil || vt.ChallengeID != ac.ID || vt.BlobberID != t.ClientID ||
+			!ac.isValidator(vt.ValidatorID) {
+			continue
+		}
+		if ok, err := vt.VerifySign(balances); !ok || err != nil {
+			continue
+		}
+		validators = append(validators, vt.ValidatorID)
+		if vt.Result {
+			success++
+		} else {
+			failure++
+		}
+	}
+
+	var (
+		threshold   = len(ac.Validators) / 2
+		late        = ba.isExpired(conf, t.CreationDate)
+		enoughFails = failure > threshold ||
+			success+failure == len(ac.Validators)
+		passed bool
+	)
+
+	switch {
+	case proofErr != nil:
+		resp = "challenge failed by blobber: " + proofErr.Error()
+	case late:
+		resp = "late challenge (failed)"
+	case success > threshold:
+		passed, resp = true, "challenge passed by blobber"
+	case enoughFails:
+		resp = "Challenge Failed by Blobber"
+	default:
+		return "", common.NewError("not_enough_validations",
+			"Not enough validations, no successful validations")
+	}
+
+	if passed {
+		err = sc.aggregateReward(t, ba, allocs, validators, balances)
+		if err != nil {
+			return "", common.NewError("challenge_reward_error", err.Error())
+		}
+	} else {
+		err = sc.aggregatePenalty(t, ba, validators, balances)
+		if err != nil {
+			return "", common.NewError("challenge_penalty_error", err.Error())
+		}
+	}
+
+	ba.resolve(passed)
+	sc.challengeResolved(balances, passed)
+
+	if err = ba.save(sc.ID, balances); err != nil {
+		return "", common.NewErrorf("aggregate_challenge_response",
+			"saving blobber aggregate: %v", err)
+	}
+
+	return
+}
+
+//
+// REST handlers
+//
+
+const cantGetBlobberAggregateMsg = "can't get blobber aggregate"
+
+// getBlobberAggregateHandler returns latest commitment, open challenge and
+// statistic of a blobber
+func (sc *StorageSmartContract) getBlobberAggregateHandler(
+	ctx context.Context, params url.Values, balances cstate.StateContextI) (
+	resp interface{}, err error) {
+
+	var ba *blobberAggregate
+	ba, err = sc.getBlobberAggregate(params.Get("blobber_id"), balances)
+	if err != nil {
+		return nil, smartcontract.NewErrNoResourceOrErrInternal(err, true,
+			cantGetBlobberAggregateMsg)
+	}
+	return ba, nil
+}
+
+// getAggregateChallengeHandler returns open aggregate challenge of a
+// blobber; used by validators
+func (sc *StorageSmartContract) getAggregateChallengeHandler(
+	ctx context.Context, params url.Values, balances cstate.StateContextI) (
+	resp interface{}, err error) {
+
+	var (
+		blobberID   = params.Get("blobber_id")
+		challengeID = params.Get("challenge_id")
+		ba          *blobberAggregate
+	)
+	if ba, err = sc.getBlobberAggregate(blobberID, balances); err != nil {
+		return nil, smartcontract.NewErrNoResourceOrErrInternal(err, true,
+			cantGetBlobberAggregateMsg)
+	}
+
+	if ba.Challenge == nil ||
+		(challengeID != "" && ba.Challenge.ID != challengeID) {
+
+		return nil, common.NewErrNoResource("no open challenge of the blobber")
+	}
+	return ba.Challenge, nil
+}
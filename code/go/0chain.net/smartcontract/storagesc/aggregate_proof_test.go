@@ -0,0 +1,277 @@
+package storagesc
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+	"time"
+
+	"0chain.net/core/common"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_merkleDepth(t *testing.T) {
+	for leaves := 1; leaves < 20; leaves++ {
+		var (
+			hashes = make([]util.Hashable, 0, leaves)
+			tree   = new(util.MerkleTree)
+		)
+		for i := 0; i < leaves; i++ {
+			hashes = append(hashes, &allocationLeaf{
+				AllocationID:   encryption.Hash(randString(10)),
+				AllocationRoot: encryption.Hash(randString(10)),
+			})
+		}
+		tree.ComputeTree(hashes)
+		for i := 0; i < leaves; i++ {
+			var path = tree.GetPathByIndex(i)
+			assert.Len(t, path.Nodes, merkleDepth(leaves))
+			assert.True(t, util.VerifyMerklePath(hashes[i].GetHash(), path,
+				tree.GetRoot()))
+		}
+	}
+}
+
+func Test_sampleLeaves(t *testing.T) {
+	var r = rand.New(rand.NewSource(1))
+	for _, n := range []int{1, 2, 10, 1 << 40} {
+		var samples = sampleLeaves(r, n, 4)
+		require.Len(t, samples, minInt(n, 4))
+		assert.True(t, sort.IntsAreSorted(samples))
+		for i, idx := range samples {
+			assert.True(t, idx >= 0 && idx < n)
+			if i > 0 {
+				assert.NotEqual(t, samples[i-1], idx)
+			}
+		}
+	}
+}
+
+func Test_aggregateChallengeResponse(t *testing.T) {
+	const (
+		allocExpire = 3000
+		numValids   = 3
+	)
+
+	var (
+		ssc      = newTestStorageSC()
+		balances = newTestBalances(t, false)
+		client   = newClient(100*x10, balances)
+		now      = int64(10)
+		valids   []*ValidationNode
+		vclients []*Client
+		root     = encryption.Hash("root")
+	)
+
+	allocID, blobs := addAllocation(t, ssc, client, now, allocExpire, 0,
+		balances)
+
+	var conf, err = ssc.getConfig(balances, false)
+	require.NoError(t, err)
+	conf.AggregateProofs = &aggregateProofsConfig{
+		Enabled:       true,
+		Epoch:         1 * time.Minute,
+		NumSamples:    2,
+		NumValidators: numValids,
+	}
+	mustSave(t, scConfigKey(ADDRESS), conf, balances)
+
+	for i := 0; i < numValids; i++ {
+		var v = addValidator(t, ssc, now, balances)
+		vclients = append(vclients, v)
+		valids = append(valids, &ValidationNode{ID: v.id,
+			BaseURL: getValidatorURL(v.id)})
+
+		// stake the validator to get rewards
+		balances.balances[v.id] = 1 * x10
+		var tx = newTransaction(v.id, ADDRESS, 1*x10, now)
+		balances.setTransaction(t, tx)
+		_, err = ssc.stakePoolLock(tx, v.stakeLockRequest(t), balances)
+		require.NoError(t, err)
+	}
+
+	// a blobber of the allocation with tokens in challenge pool
+	var alloc *StorageAllocation
+	alloc, err = ssc.getAllocation(allocID, balances)
+	require.NoError(t, err)
+
+	var blob *Client
+	for _, b := range blobs {
+		if _, ok := alloc.BlobberMap[b.id]; ok {
+			blob = b
+			break
+		}
+	}
+	require.NotNil(t, blob)
+	alloc.BlobberMap[blob.id].AllocationRoot = root
+	alloc.BlobberMap[blob.id].ChallengePoolIntegralValue = 10 * x10
+	mustSave(t, alloc.GetKey(ssc.ID), alloc, balances)
+
+	var cp *challengePool
+	cp, err = ssc.getChallengePool(allocID, balances)
+	require.NoError(t, err)
+	cp.Balance = 10 * x10
+	require.NoError(t, cp.save(ssc.ID, allocID, balances))
+
+	// the blobber has one allocation only
+	var newTree = func(leaves ...*allocationLeaf) (tree *util.MerkleTree) {
+		var hashes []util.Hashable
+		for _, leaf := range leaves {
+			hashes = append(hashes, leaf)
+		}
+		tree = new(util.MerkleTree)
+		tree.ComputeTree(hashes)
+		return
+	}
+	var (
+		leaves = []*allocationLeaf{
+			{AllocationID: allocID, AllocationRoot: root},
+		}
+		tree = newTree(leaves...)
+	)
+
+	// commit
+	var tx = newTransaction(blob.id, ADDRESS, 0, now)
+	balances.setTransaction(t, tx)
+	_, err = ssc.commitBlobberAggregate(tx, mustEncode(t,
+		&aggregateCommitRequest{
+			Epoch:     aggregateEpoch(conf, tx.CreationDate) + 1,
+			Root:      tree.GetRoot(),
+			NumLeaves: len(leaves),
+		}), balances)
+	require.Error(t, err) // invalid epoch
+
+	_, err = ssc.commitBlobberAggregate(tx, mustEncode(t,
+		&aggregateCommitRequest{
+			Epoch:     aggregateEpoch(conf, tx.CreationDate),
+			Root:      tree.GetRoot(),
+			NumLeaves: 1 << 40,
+		}), balances)
+	require.Error(t, err) // more leaves than allocations
+
+	var commit = &aggregateCommitRequest{
+		Epoch:     aggregateEpoch(conf, tx.CreationDate),
+		Root:      tree.GetRoot(),
+		NumLeaves: len(leaves),
+	}
+	_, err = ssc.commitBlobberAggregate(tx, mustEncode(t, commit), balances)
+	require.NoError(t, err)
+	_, err = ssc.commitBlobberAggregate(tx, mustEncode(t, commit), balances)
+	require.Error(t, err) // already committed
+
+	// the blobber isn't challenged per allocation anymore, it's the only
+	// one with data of the allocation
+	require.True(t, ssc.isAggregateBlobber(blob.id, balances))
+	alloc, err = ssc.getAllocation(allocID, balances)
+	require.NoError(t, err)
+	_, err = ssc.addChallenge(alloc, &ValidatorNodes{Nodes: valids}, "chall",
+		common.Timestamp(now), rand.New(rand.NewSource(1)), 1, balances)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no_blobber_writes")
+
+	var challenge = func(id string, created int64, tree *util.MerkleTree,
+		numLeaves int, samples ...int) {
+
+		ba, err := ssc.getBlobberAggregate(blob.id, balances)
+		require.NoError(t, err)
+		ba.Challenge = &aggregateChallenge{
+			ID:         id,
+			BlobberID:  blob.id,
+			Epoch:      commit.Epoch,
+			Root:       tree.GetRoot(),
+			NumLeaves:  numLeaves,
+			Samples:    samples,
+			Validators: valids,
+			Created:    common.Timestamp(created),
+		}
+		require.NoError(t, ba.save(ssc.ID, balances))
+	}
+
+	var respond = func(id string, created int64, tree *util.MerkleTree,
+		leaves []*allocationLeaf, samples ...int) (string, error) {
+
+		var req = aggregateChallengeResponse{ChallengeID: id}
+		for _, i := range samples {
+			req.Proofs = append(req.Proofs, &aggregateProof{
+				allocationLeaf: *leaves[i],
+				Path:           tree.GetPathByIndex(i),
+			})
+		}
+		for _, v := range vclients {
+			req.ValidationTickets = append(req.ValidationTickets,
+				v.validTicket(t, id, blob.id, true, created))
+		}
+		var tx = newTransaction(blob.id, ADDRESS, 0, created+1)
+		balances.setTransaction(t, tx)
+		return ssc.aggregateChallengeResponse(tx, mustEncode(t, &req),
+			balances)
+	}
+
+	var details = func() *BlobberAllocation {
+		alloc, err := ssc.getAllocation(allocID, balances)
+		require.NoError(t, err)
+		return alloc.BlobberMap[blob.id]
+	}
+
+	t.Run("pass", func(t *testing.T) {
+		challenge("chall_1", 1000, tree, 1, 0)
+		resp, err := respond("chall_1", 1000, tree, leaves, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "challenge passed by blobber", resp)
+		assert.NotZero(t, details().ChallengeReward)
+
+		// already redeemed
+		resp, err = respond("chall_1", 1000, tree, leaves, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "Challenge Already redeemed by Blobber", resp)
+	})
+
+	t.Run("allocation root mismatch", func(t *testing.T) {
+		var (
+			leaves = []*allocationLeaf{
+				{AllocationID: allocID, AllocationRoot: encryption.Hash("x")},
+			}
+			tree = newTree(leaves...)
+		)
+		challenge("chall_2", 1500, tree, 1, 0)
+		resp, err := respond("chall_2", 1500, tree, leaves, 0)
+		require.NoError(t, err)
+		assert.Contains(t, resp, "allocation root")
+		assert.NotZero(t, details().Returned) // penalty
+	})
+
+	t.Run("leaves not unique", func(t *testing.T) {
+		var (
+			leaves = []*allocationLeaf{leaves[0], leaves[0]}
+			tree   = newTree(leaves...)
+		)
+		challenge("chall_3", 2000, tree, 2, 0, 1)
+		resp, err := respond("chall_3", 2000, tree, leaves, 0, 1)
+		require.NoError(t, err)
+		assert.Contains(t, resp, "not sorted or not unique")
+	})
+
+	t.Run("invalid leaf index", func(t *testing.T) {
+		var (
+			leaves = []*allocationLeaf{leaves[0],
+				{AllocationID: "zzz", AllocationRoot: root}}
+			tree = newTree(leaves...)
+		)
+		challenge("chall_4", 2500, tree, 2, 1)
+		resp, err := respond("chall_4", 2500, tree, leaves, 0)
+		require.NoError(t, err)
+		assert.Contains(t, resp, "unexpected leaf index")
+	})
+
+	var ba, errGet = ssc.getBlobberAggregate(blob.id, balances)
+	require.NoError(t, errGet)
+	assert.Nil(t, ba.Challenge)
+	assert.EqualValues(t, 1, ba.Stats.SuccessChallenges)
+	assert.EqualValues(t, 3, ba.Stats.FailedChallenges)
+	assert.Len(t, ba.Resolved, 1) // resolved per allocation
+	assert.EqualValues(t, 2500, ba.Resolved[allocID])
+}
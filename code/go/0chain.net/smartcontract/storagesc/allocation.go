@@ -674,6 +674,12 @@ func (sc *StorageSmartContract) adjustChallengePool(
 		return fmt.Errorf("adjust_challenge_pool: %v", err)
 	}
 
+	// the escrow gets back its share of the tokens moved back
+	var es *escrow
+	if es, err = sc.findEscrow(alloc, balances); err != nil {
+		return fmt.Errorf("adjust_challenge_pool: %v", err)
+	}
+
 	var changed bool
 
 	for i, ch := range changes {
@@ -691,7 +697,8 @@ func (sc *StorageSmartContract) adjustChallengePool(
 				if err != nil {
 					return fmt.Errorf("adjust_challenge_pool: %v", err)
 				}
-				err = cp.moveToWritePool(alloc, blobID, alloc.Until(), wp, -ch)
+				err = moveBackFromChallenge(alloc, es, cp, wp, blobID,
+					alloc.Until(), -ch)
 				changed = true
 			}
 		default:
@@ -702,11 +709,17 @@ func (sc *StorageSmartContract) adjustChallengePool(
 		}
 	}
 
-	if changed {
-		err = cp.save(sc.ID, alloc.ID, balances)
+	if !changed {
+		return
 	}
 
-	return
+	if es != nil {
+		if err = es.save(sc.ID, balances); err != nil {
+			return fmt.Errorf("adjust_challenge_pool: %v", err)
+		}
+	}
+
+	return cp.save(sc.ID, alloc.ID, balances)
 }
 
 // extendAllocation extends size or/and expiration (one of them can be reduced);
@@ -822,7 +835,13 @@ func (sc *StorageSmartContract) extendAllocation(
 	// pool has enough tokens
 	if diff > 0 {
 		if mldLeft := alloc.restMinLockDemand(); mldLeft > 0 {
-			if wps.allocUntil(alloc.ID, until) < mldLeft {
+			var inEscrow state.Balance
+			inEscrow, err = sc.escrowWriteBalance(alloc, balances)
+			if err != nil {
+				return common.NewErrorf("allocation_extending_failed",
+					"can't get allocation escrow: %v", err)
+			}
+			if wps.allocUntil(alloc.ID, until)+inEscrow < mldLeft {
 				return common.NewError("allocation_extending_failed",
 					"not enough tokens in write pool to extend allocation")
 			}
@@ -840,6 +859,13 @@ func (sc *StorageSmartContract) extendAllocation(
 		return common.NewErrorf("allocation_extending_failed", "%v", err)
 	}
 
+	var funds state.Balance
+	if funds, err = sc.allocationFunds(alloc, wps, balances); err != nil {
+		return common.NewErrorf("allocation_extending_failed",
+			"can't get allocation funds: %v", err)
+	}
+	emitAllocationUpdateEvent(allocationExtendedTag, alloc, funds, balances)
+
 	return nil
 }
 
@@ -906,6 +932,13 @@ func (sc *StorageSmartContract) reduceAllocation(t *transaction.Transaction,
 		return common.NewErrorf("allocation_reducing_failed", "%v", err)
 	}
 
+	var funds state.Balance
+	if funds, err = sc.allocationFunds(alloc, wps, balances); err != nil {
+		return common.NewErrorf("allocation_reducing_failed",
+			"can't get allocation funds: %v", err)
+	}
+	emitAllocationUpdateEvent(allocationReducedTag, alloc, funds, balances)
+
 	return nil
 
 }
@@ -1267,6 +1300,13 @@ func (sc *StorageSmartContract) cancelAllocationRequest(
 			"saving allocation: "+err.Error())
 	}
 
+	var funds state.Balance
+	if funds, err = sc.allocationFunds(alloc, nil, balances); err != nil {
+		return "", common.NewError("alloc_cancel_failed",
+			"can't get allocation funds: "+err.Error())
+	}
+	emitAllocationEvent(allocationCanceledTag, alloc, funds, balances)
+
 	return "canceled", nil
 }
 
@@ -1341,6 +1381,13 @@ func (sc *StorageSmartContract) finalizeAllocation(
 			"saving allocation: "+err.Error())
 	}
 
+	var funds state.Balance
+	if funds, err = sc.allocationFunds(alloc, nil, balances); err != nil {
+		return "", common.NewError("fini_alloc_failed",
+			"can't get allocation funds: "+err.Error())
+	}
+	emitAllocationEvent(allocationFinalizedTag, alloc, funds, balances)
+
 	return "finalized", nil
 }
 
@@ -1358,12 +1405,22 @@ func (sc *StorageSmartContract) finishAllocation(
 			"can't get SC configurations: "+err.Error())
 	}
 
+	// allocation escrow, if any
+	var es *escrow
+	if es, err = sc.findEscrow(alloc, balances); err != nil {
+		return common.NewError("fini_alloc_failed",
+			"can't get allocation escrow: "+err.Error())
+	}
+
 	wps, err := alloc.getAllocationPools(sc, balances)
+	if err == util.ErrValueNotPresent && es != nil {
+		wps, err = &allocationWritePools{ownerId: -1}, nil // escrow only
+	}
 	if err != nil {
 		return common.NewErrorf("allocation_extending_failed", "%v", err)
 	}
 	aps := wps.activeAllocationPools(alloc.ID, t.CreationDate)
-	if len(aps) == 0 {
+	if len(aps) == 0 && es == nil {
 		return common.NewError("fini_alloc_failed",
 			"no allocation pools to pay min lock demand")
 	}
@@ -1393,6 +1450,17 @@ func (sc *StorageSmartContract) finishAllocation(
 				paid += pay
 				lack -= pay
 			}
+			if lack > 0 && es != nil && es.Write.Balance > 0 {
+				var pay = minBalance(lack, es.Write.Balance)
+				_, err := es.payBlobber(sc.ID, escrowWrite, d.BlobberID,
+					sps[i], pay, balances)
+				if err != nil {
+					return fmt.Errorf("alloc_cancel_failed, paying min_lock lack %v for blobber "+
+						"%v from allocation escrow: %v", lack, d.BlobberID, err)
+				}
+				paid += pay
+				lack -= pay
+			}
 			if lack > 0 {
 				return fmt.Errorf("alloc_cancel_failed, paying min_lock for blobber %v"+
 					"ammount was short by %v", d.BlobberID, lack)
@@ -1464,19 +1532,33 @@ func (sc *StorageSmartContract) finishAllocation(
 		allb.Nodes.update(b)
 	}
 	cp.Balance -= passPayments
-	// move challenge pool rest to write pool
-	alloc.MovedBack += cp.Balance
-
-	// write pool
-	var wp *writePool
+	// move challenge pool rest to escrow and write pool, the escrow gets
+	// back its share of the rest
+	var (
+		rest = cp.Balance
+		wp   *writePool
+	)
 	if wp, err = sc.getWritePool(alloc.Owner, balances); err != nil {
 		return common.NewError("fini_alloc_failed",
 			"can't get user's write pools: "+err.Error())
 	}
-	err = cp.moveToWritePool(alloc, "", alloc.Until(), wp, cp.Balance)
+	err = moveBackFromChallenge(alloc, es, cp, wp, "", alloc.Until(), rest)
 	if err != nil {
 		return common.NewError("fini_alloc_failed",
-			"moving challenge pool rest back to write pool: "+err.Error())
+			"moving challenge pool rest back: "+err.Error())
+	}
+	alloc.MovedBack += rest
+
+	if es != nil {
+		// return all tokens left to the funders
+		if err = es.settle(sc.ID, balances); err != nil {
+			return common.NewError("fini_alloc_failed",
+				"settling allocation escrow: "+err.Error())
+		}
+		if err = es.save(sc.ID, balances); err != nil {
+			return common.NewError("fini_alloc_failed",
+				"saving allocation escrow: "+err.Error())
+		}
 	}
 
 	// save all blobbers list
@@ -0,0 +1,264 @@
+package storagesc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	cstate "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/state"
+	"0chain.net/core/common"
+	"0chain.net/core/logging"
+	"0chain.net/core/util"
+	"0chain.net/smartcontract"
+
+	"go.uber.org/zap"
+)
+
+// allocation lifecycle events
+const (
+	allocationEventType = "storage_allocation"
+
+	allocationFinalizedTag  = "finalized"
+	allocationCanceledTag   = "canceled"
+	allocationExtendedTag   = "extended"
+	allocationReducedTag    = "reduced"
+	allocationLowBalanceTag = "low_balance"
+)
+
+// allocationEvent is data of an allocation lifecycle event, it contains
+// everything required to notify allocation owner
+type allocationEvent struct {
+	AllocationID      string           `json:"allocation_id"`
+	Owner             string           `json:"owner"`
+	Size              int64            `json:"size"`
+	Expiration        common.Timestamp `json:"expiration"`
+	Until             common.Timestamp `json:"until"`
+	RestMinLockDemand state.Balance    `json:"rest_min_lock_demand"`
+	Funds             state.Balance    `json:"funds"`
+	Finalized         bool             `json:"finalized"`
+	Canceled          bool             `json:"canceled"`
+}
+
+func newAllocationEvent(alloc *StorageAllocation,
+	funds state.Balance) *allocationEvent {
+
+	return &allocationEvent{
+		AllocationID:      alloc.ID,
+		Owner:             alloc.Owner,
+		Size:              alloc.Size,
+		Expiration:        alloc.Expiration,
+		Until:             alloc.Until(),
+		RestMinLockDemand: alloc.restMinLockDemand(),
+		Funds:             funds,
+		Finalized:         alloc.Finalized,
+		Canceled:          alloc.Canceled,
+	}
+}
+
+// isLowBalance returns true if tokens left are not enough to pay
+// min lock demand rest of the allocation
+func (ae *allocationEvent) isLowBalance() bool {
+	return ae.Funds < ae.RestMinLockDemand
+}
+
+// emitAllocationEvent emits given lifecycle event of an allocation
+func emitAllocationEvent(tag string, alloc *StorageAllocation,
+	funds state.Balance, balances cstate.StateContextI) {
+
+	var data, err = json.Marshal(newAllocationEvent(alloc, funds))
+	if err != nil {
+		logging.Logger.Error("encoding allocation event",
+			zap.String("allocation_id", alloc.ID),
+			zap.String("tag", tag),
+			zap.Error(err))
+		return
+	}
+	balances.EmitEvent(allocationEventType, tag, string(data))
+}
+
+// emitAllocationUpdateEvent emits given lifecycle event of updated
+// allocation and low balance event if rest of tokens of the allocation
+// are not enough to pay its min lock demand
+func emitAllocationUpdateEvent(tag string, alloc *StorageAllocation,
+	funds state.Balance, balances cstate.StateContextI) {
+
+	emitAllocationEvent(tag, alloc, funds, balances)
+	if funds < alloc.restMinLockDemand() {
+		emitAllocationEvent(allocationLowBalanceTag, alloc, funds, balances)
+	}
+}
+
+// allocationFunds returns tokens of write pools and of escrow of given
+// allocation can be used to pay for it; the write pools already loaded by
+// caller are used, if given
+func (sc *StorageSmartContract) allocationFunds(alloc *StorageAllocation,
+	wps *allocationWritePools, balances cstate.StateContextI) (
+	funds state.Balance, err error) {
+
+	if wps == nil {
+		switch wps, err = alloc.getAllocationPools(sc, balances); err {
+		case nil:
+		case util.ErrValueNotPresent:
+			wps, err = &allocationWritePools{ownerId: -1}, nil // escrow only
+		default:
+			return
+		}
+	}
+	funds = wps.allocUntil(alloc.ID, alloc.Until())
+
+	var inEscrow state.Balance
+	if inEscrow, err = sc.escrowWriteBalance(alloc, balances); err != nil {
+		return
+	}
+	return funds + inEscrow, nil
+}
+
+// checkLowBalance emits low balance event if given move of tokens to
+// challenge pool makes the allocation funds less then min lock demand rest
+func (sc *StorageSmartContract) checkLowBalance(alloc *StorageAllocation,
+	moved, restBefore state.Balance, balances cstate.StateContextI) error {
+
+	if moved <= 0 {
+		return nil
+	}
+
+	var funds, err = sc.allocationFunds(alloc, nil, balances)
+	if err != nil {
+		return err
+	}
+
+	// report only crossing the threshold to don't repeat the event for
+	// every following write
+	if funds < alloc.restMinLockDemand() && funds+moved >= restBefore {
+		emitAllocationEvent(allocationLowBalanceTag, alloc, funds, balances)
+	}
+	return nil
+}
+
+//
+// REST
+//
+
+// expiringAllocation is an allocation expires soon or has not enough
+// tokens to pay its min lock demand
+type expiringAllocation struct {
+	*allocationEvent
+	Expiring   bool `json:"expiring"`
+	LowBalance bool `json:"low_balance"`
+}
+
+// limits of allocations scanned by one expiring allocations request
+const (
+	expiringAllocationsLimit    = 50
+	expiringAllocationsMaxLimit = 200
+)
+
+// parseIntParam parses optional non-negative integer URL query parameter
+func parseIntParam(params url.Values, name string, def int) (int, error) {
+	var s = params.Get(name)
+	if s == "" {
+		return def, nil
+	}
+	var v, err = strconv.Atoi(s)
+	if err != nil || v < 0 {
+		return 0, common.NewErrBadRequest(
+			fmt.Sprintf("invalid '%s' URL query parameter", name))
+	}
+	return v, nil
+}
+
+// getExpiringAllocationsHandler returns not finalized allocations expiring
+// within given number of time units or having not enough tokens to pay rest
+// of their min lock demand; the 'client' filters allocations by owner; the
+// 'offset' and 'limit' select allocations of the list to check
+func (ssc *StorageSmartContract) getExpiringAllocationsHandler(
+	ctx context.Context, params url.Values, balances cstate.StateContextI) (
+	resp interface{}, err error) {
+
+	var timeUnits float64
+	if tu := params.Get("time_units"); tu != "" {
+		if timeUnits, err = strconv.ParseFloat(tu, 64); err != nil ||
+			timeUnits < 0 {
+
+			return nil, common.NewErrBadRequest(
+				"invalid 'time_units' URL query parameter")
+		}
+	}
+
+	var offset, limit int
+	if offset, err = parseIntParam(params, "offset", 0); err != nil {
+		return
+	}
+	limit, err = parseIntParam(params, "limit", expiringAllocationsLimit)
+	if err != nil {
+		return
+	}
+	if limit == 0 {
+		limit = expiringAllocationsLimit
+	}
+	if limit > expiringAllocationsMaxLimit {
+		limit = expiringAllocationsMaxLimit
+	}
+
+	var (
+		clientID = params.Get("client")
+		now      = common.Timestamp(time.Now().Unix())
+		all      *Allocations
+	)
+
+	if clientID != "" {
+		all, err = ssc.getAllocationsList(clientID, balances)
+	} else {
+		all, err = ssc.getAllAllocationsList(balances)
+	}
+	if err != nil {
+		return nil, common.NewErrInternal("can't get allocation list",
+			err.Error())
+	}
+
+	var ids = all.List
+	if offset >= len(ids) {
+		ids = nil
+	} else {
+		ids = ids[offset:]
+	}
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	var list = make([]*expiringAllocation, 0)
+	for _, allocID := range ids {
+		var alloc *StorageAllocation
+		if alloc, err = ssc.getAllocation(allocID, balances); err != nil {
+			return nil, smartcontract.NewErrNoResourceOrErrInternal(err, true,
+				cantGetAllocation)
+		}
+		if alloc.Finalized {
+			continue
+		}
+
+		var funds state.Balance
+		if funds, err = ssc.allocationFunds(alloc, nil, balances); err != nil {
+			return nil, common.NewErrInternal("can't get allocation funds",
+				err.Error())
+		}
+
+		var (
+			ea = &expiringAllocation{
+				allocationEvent: newAllocationEvent(alloc, funds),
+			}
+			window = time.Duration(timeUnits * float64(alloc.TimeUnit))
+		)
+		ea.Expiring = alloc.Expiration <= now+toSeconds(window)
+		ea.LowBalance = ea.isLowBalance()
+		if ea.Expiring || ea.LowBalance {
+			list = append(list, ea)
+		}
+	}
+
+	return list, nil
+}
@@ -0,0 +1,96 @@
+package storagesc
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_emitAllocationUpdateEvent(t *testing.T) {
+	var (
+		balances = newTestBalances(t, false)
+		alloc    = &StorageAllocation{
+			ID:    "alloc_id",
+			Owner: "owner_id",
+			BlobberDetails: []*BlobberAllocation{
+				{MinLockDemand: 10, Spent: 2},
+				{MinLockDemand: 5, Spent: 5},
+			},
+		}
+	)
+
+	emitAllocationUpdateEvent(allocationExtendedTag, alloc, 8, balances)
+	require.Len(t, balances.events, 1)
+	assert.Equal(t, allocationEventType, balances.events[0].Type)
+	assert.Equal(t, allocationExtendedTag, balances.events[0].Tag)
+
+	emitAllocationUpdateEvent(allocationReducedTag, alloc, 7, balances)
+	require.Len(t, balances.events, 3)
+	assert.Equal(t, allocationReducedTag, balances.events[1].Tag)
+	assert.Equal(t, allocationLowBalanceTag, balances.events[2].Tag)
+
+	var ae allocationEvent
+	require.NoError(t, json.Unmarshal([]byte(balances.events[2].Data), &ae))
+	assert.Equal(t, "alloc_id", ae.AllocationID)
+	assert.Equal(t, "owner_id", ae.Owner)
+	assert.EqualValues(t, 8, ae.RestMinLockDemand)
+	assert.EqualValues(t, 7, ae.Funds)
+	assert.True(t, ae.isLowBalance())
+}
+
+func Test_getExpiringAllocationsHandler(t *testing.T) {
+	var (
+		ssc      = newTestStorageSC()
+		balances = newTestBalances(t, false)
+		client   = newClient(100*x10, balances)
+		now      = time.Now().Unix()
+	)
+
+	var allocID, _ = addAllocation(t, ssc, client, now, now+1800, 0,
+		balances)
+
+	var query = func(timeUnits string) (list []*expiringAllocation) {
+		var params = url.Values{}
+		params.Set("client", client.id)
+		params.Set("time_units", timeUnits)
+		var resp, err = ssc.getExpiringAllocationsHandler(context.Background(),
+			params, balances)
+		require.NoError(t, err)
+		return resp.([]*expiringAllocation)
+	}
+
+	// far from expiration
+	for _, ea := range query("0.001") {
+		assert.False(t, ea.Expiring)
+		assert.True(t, ea.LowBalance)
+	}
+
+	// expires within a half of time unit
+	var list = query("0.5")
+	require.Len(t, list, 1)
+	assert.Equal(t, allocID, list[0].AllocationID)
+	assert.True(t, list[0].Expiring)
+
+	// out of the scanned window
+	var params = url.Values{}
+	params.Set("client", client.id)
+	params.Set("time_units", "0.5")
+	params.Set("offset", "1")
+	var resp, err = ssc.getExpiringAllocationsHandler(context.Background(),
+		params, balances)
+	require.NoError(t, err)
+	assert.Empty(t, resp)
+
+	// invalid query
+	_, err = ssc.getExpiringAllocationsHandler(context.Background(),
+		url.Values{"time_units": []string{"-1"}}, balances)
+	require.Error(t, err)
+	_, err = ssc.getExpiringAllocationsHandler(context.Background(),
+		url.Values{"limit": []string{"x"}}, balances)
+	require.Error(t, err)
+}
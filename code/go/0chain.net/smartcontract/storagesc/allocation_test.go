@@ -420,6 +420,9 @@ func TestExtendAllocation(t *testing.T) {
 				return cp.Balance/10 == state.Balance(newFunds/10) // ignore type cast errors
 			}),
 		).Return("", nil).Once()
+		balances.On(
+			"EmitEvent", allocationEventType, allocationExtendedTag, mock.Anything,
+		).Return().Once()
 
 		return ssc, &txn, sa, blobbers, balances
 	}
@@ -21,8 +21,10 @@ import (
 type testBalances struct {
 	balances  map[datastore.Key]state.Balance
 	txn       *transaction.Transaction
+	block     *block.Block
 	transfers []*state.Transfer
 	tree      map[datastore.Key]util.Serializable
+	events    []event.Event
 
 	mpts      *mptStore // use for benchmarks
 	skipMerge bool      // don't merge for now
@@ -32,6 +34,7 @@ func newTestBalances(t testing.TB, mpts bool) (tb *testBalances) {
 	tb = &testBalances{
 		balances: make(map[datastore.Key]state.Balance),
 		tree:     make(map[datastore.Key]util.Serializable),
+		block:    new(block.Block),
 	}
 
 	if mpts {
@@ -56,7 +59,7 @@ func (tb *testBalances) setTransaction(t testing.TB,
 }
 
 // stubs
-func (tb *testBalances) GetBlock() *block.Block                       { return nil }
+func (tb *testBalances) GetBlock() *block.Block                       { return tb.block }
 func (tb *testBalances) GetState() util.MerklePatriciaTrieI           { return nil }
 func (tb *testBalances) GetTransaction() *transaction.Transaction     { return nil }
 func (tb *testBalances) GetBlockSharders(b *block.Block) []string     { return nil }
@@ -69,10 +72,20 @@ func (tb *testBalances) SetMagicBlock(block *block.MagicBlock)        {}
 func (tb *testBalances) AddSignedTransfer(st *state.SignedTransfer)   {}
 func (tb *testBalances) GetSignedTransfers() []*state.SignedTransfer  { return nil }
 func (tb *testBalances) GetEventDB() *event.EventDb                   { return nil }
-func (tb *testBalances) EmitEvent(string, string, string)             {}
 func (tb *testBalances) EmitError(error)                              {}
-func (tb *testBalances) GetEvents() []event.Event                     { return nil }
 func (tb *testBalances) GetChainCurrentMagicBlock() *block.MagicBlock { return nil }
+func (tb *testBalances) EmitEvent(eventType, tag, data string) {
+	tb.events = append(tb.events, event.Event{
+		Type: eventType,
+		Tag:  tag,
+		Data: data,
+	})
+}
+
+func (tb *testBalances) GetEvents() []event.Event {
+	return tb.events
+}
+
 func (tb *testBalances) DeleteTrieNode(key datastore.Key) (
 	datastore.Key, error) {
 
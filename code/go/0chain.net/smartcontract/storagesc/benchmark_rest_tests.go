@@ -113,6 +113,25 @@ func BenchmarkRestTests(
 				return values
 			}(),
 		},
+		{
+			name:     "storage_rest.expiring_allocations",
+			endpoint: ssc.getExpiringAllocationsHandler,
+			params: func() url.Values {
+				var values url.Values = make(map[string][]string)
+				values.Set("client", data.Clients[0])
+				values.Set("time_units", "1")
+				return values
+			}(),
+		},
+		{
+			name:     "storage_rest.get_share_offers",
+			endpoint: ssc.getShareOffersHandler,
+			params: func() url.Values {
+				var values url.Values = make(map[string][]string)
+				values.Set("allocation_id", getMockAllocationId(0))
+				return values
+			}(),
+		},
 		{
 			name:     "storage_rest.openchallenges",
 			endpoint: ssc.OpenChallengeHandler,
@@ -145,6 +164,24 @@ func BenchmarkRestTests(
 				return values
 			}(),
 		},
+		{
+			name:     "storage_rest.getBlobberAggregate",
+			endpoint: ssc.getBlobberAggregateHandler,
+			params: func() url.Values {
+				var values url.Values = make(map[string][]string)
+				values.Set("blobber_id", getMockBlobberId(0))
+				return values
+			}(),
+		},
+		{
+			name:     "storage_rest.getAggregateChallenge",
+			endpoint: ssc.getAggregateChallengeHandler,
+			params: func() url.Values {
+				var values url.Values = make(map[string][]string)
+				values.Set("blobber_id", getMockBlobberId(0))
+				return values
+			}(),
+		},
 		{
 			name:     "storage_rest.getReadPoolStat",
 			endpoint: ssc.getReadPoolStatHandler,
@@ -1,11 +1,13 @@
 package storagesc
 
 import (
+	"sort"
 	"strconv"
 	"time"
 
 	sci "0chain.net/chaincore/smartcontractinterface"
 	"0chain.net/core/encryption"
+	"0chain.net/core/util"
 	sc "0chain.net/smartcontract/benchmark"
 	"github.com/spf13/viper"
 
@@ -298,6 +300,127 @@ func AddMockBlobbers(
 	return rtvBlobbers
 }
 
+func AddMockBlobberAggregates(
+	validators []*ValidationNode,
+	balances cstate.StateContextI,
+) {
+	var (
+		sscId = StorageSmartContract{
+			SmartContract: sci.NewSC(ADDRESS),
+		}.ID
+		conf  = getMockAggregateProofsConfig()
+		now   = common.Timestamp(viper.GetInt64(sc.Now))
+		epoch = aggregateEpoch(&scConfig{AggregateProofs: conf}, now) - 1
+		tree  = getMockAggregateTree()
+	)
+	for i := 0; i < viper.GetInt(sc.NumBlobbers); i++ {
+		ba := &blobberAggregate{
+			BlobberID: getMockBlobberId(i),
+			Commitment: &aggregateCommitment{
+				Epoch:     epoch,
+				Root:      encryption.Hash("aggregate root" + strconv.Itoa(i)),
+				NumLeaves: viper.GetInt(sc.NumAllocations),
+				Created:   now,
+			},
+		}
+		if i == 0 {
+			ba.Commitment.Root = tree.GetRoot()
+			ba.Commitment.NumLeaves = len(getMockAggregateLeaves())
+			ba.Challenge = getMockAggregateChallenge(tree, validators, now)
+			ba.Stats.TotalChallenges++
+			ba.Stats.LastChallenged = epoch
+		}
+		if err := ba.save(sscId, balances); err != nil {
+			panic(err)
+		}
+	}
+}
+
+func AddMockShareOffers(
+	balances cstate.StateContextI,
+) {
+	var (
+		sscId = StorageSmartContract{
+			SmartContract: sci.NewSC(ADDRESS),
+		}.ID
+		now = common.Timestamp(viper.GetInt64(sc.Now))
+	)
+	for i := 0; i < viper.GetInt(sc.NumAllocations); i++ {
+		sos := newShareOffers(getMockAllocationId(i))
+		sos.set(&shareOffer{
+			PricePerGB: state.Balance(0.1 * 1e10),
+			FlatFee:    state.Balance(0.01 * 1e10),
+			Created:    now,
+		})
+		if err := sos.save(sscId, balances); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// allocation leaves of the first mock blobber
+func getMockAggregateLeaves() (leaves []*allocationLeaf) {
+	for i := 0; i < viper.GetInt(sc.NumAllocations); i++ {
+		if getMockBlobberBlockFromAllocationIndex(i) != 0 {
+			continue
+		}
+		leaves = append(leaves, &allocationLeaf{
+			AllocationID:   getMockAllocationId(i),
+			AllocationRoot: encryption.Hash("allocation root"),
+		})
+	}
+	sort.Slice(leaves, func(i, j int) bool {
+		return leaves[i].AllocationID < leaves[j].AllocationID
+	})
+	return
+}
+
+func getMockAggregateTree() *util.MerkleTree {
+	var (
+		leaves = getMockAggregateLeaves()
+		hashes = make([]util.Hashable, 0, len(leaves))
+		tree   = new(util.MerkleTree)
+	)
+	for _, leaf := range leaves {
+		hashes = append(hashes, leaf)
+	}
+	tree.ComputeTree(hashes)
+	return tree
+}
+
+func getMockAggregateChallenge(
+	tree *util.MerkleTree,
+	validators []*ValidationNode,
+	now common.Timestamp,
+) *aggregateChallenge {
+	var (
+		conf = getMockAggregateProofsConfig()
+		ac   = &aggregateChallenge{
+			ID:        getMockAggregateChallengeId(0),
+			BlobberID: getMockBlobberId(0),
+			Root:      tree.GetRoot(),
+			NumLeaves: len(getMockAggregateLeaves()),
+			Created:   now,
+		}
+	)
+	for i := 0; i < ac.NumLeaves && i < conf.NumSamples; i++ {
+		ac.Samples = append(ac.Samples, i)
+	}
+	for i := 0; i < len(validators) && i < conf.NumValidators; i++ {
+		ac.Validators = append(ac.Validators, validators[i])
+	}
+	return ac
+}
+
+func getMockAggregateProofsConfig() *aggregateProofsConfig {
+	return &aggregateProofsConfig{
+		Enabled:       true,
+		Epoch:         1 * time.Hour,
+		NumSamples:    4,
+		NumValidators: viper.GetInt(sc.NumBlobbersPerAllocation) / 2,
+	}
+}
+
 func AddMockValidators(
 	publicKeys []string,
 	balances cstate.StateContextI,
@@ -559,6 +682,10 @@ func getMockBlobberBlockFromAllocationIndex(i int) int {
 	return i % (viper.GetInt(sc.NumBlobbers) - viper.GetInt(sc.NumBlobbersPerAllocation))
 }
 
+func getMockAggregateChallengeId(blobber int) string {
+	return encryption.Hash("aggregate challenge" + strconv.Itoa(blobber))
+}
+
 func getMockChallengeId(blobber, index int) string {
 	return encryption.Hash("challenge" + strconv.Itoa(blobber) + strconv.Itoa(index))
 }
@@ -624,6 +751,7 @@ func SetMockConfig(
 		ReadPoolFraction:           viper.GetFloat64(sc.StorageFasReadPoolFraction),
 	}
 	conf.BlockReward = &blockReward{}
+	conf.AggregateProofs = getMockAggregateProofsConfig()
 	conf.ExposeMpt = true
 
 	var _, err = balances.InsertTrieNode(scConfigKey(ADDRESS), conf)
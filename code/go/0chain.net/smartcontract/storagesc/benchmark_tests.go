@@ -453,6 +453,45 @@ func BenchmarkTests(
 				return bytes
 			}(),
 		},
+		// priced share offers
+		{
+			name:     "storage.add_share_offer",
+			endpoint: ssc.addShareOffer,
+			txn: &transaction.Transaction{
+				HashIDField: datastore.HashIDField{
+					Hash: encryption.Hash("mock transaction hash"),
+				},
+				CreationDate: now,
+				ClientID:     data.Clients[0],
+				ToClientID:   ADDRESS,
+			},
+			input: func() []byte {
+				bytes, _ := json.Marshal(&shareOfferRequest{
+					AllocationID: getMockAllocationId(0),
+					FilePathHash: encryption.Hash("mock file path"),
+					PricePerGB:   state.Balance(0.1 * 1e10),
+				})
+				return bytes
+			}(),
+		},
+		{
+			name:     "storage.remove_share_offer",
+			endpoint: ssc.removeShareOffer,
+			txn: &transaction.Transaction{
+				HashIDField: datastore.HashIDField{
+					Hash: encryption.Hash("mock transaction hash"),
+				},
+				CreationDate: now,
+				ClientID:     data.Clients[0],
+				ToClientID:   ADDRESS,
+			},
+			input: func() []byte {
+				bytes, _ := json.Marshal(&shareOfferRequest{
+					AllocationID: getMockAllocationId(0),
+				})
+				return bytes
+			}(),
+		},
 		// add_curator
 		{
 			name:     "storage.curator_transfer_allocation",
@@ -666,7 +705,7 @@ func BenchmarkTests(
 			name:     "storage.update_settings",
 			endpoint: ssc.updateSettings,
 			txn: &transaction.Transaction{
-				ClientID: owner,
+				ClientID: sc.GovernanceAddress,
 			},
 			input: (&sc.StringMap{
 				Fields: map[string]string{
@@ -749,6 +788,83 @@ func BenchmarkTests(
 			},
 			input: nil,
 		},
+		// aggregate proofs
+		{
+			name:     "storage.commit_blobber_aggregate",
+			endpoint: ssc.commitBlobberAggregate,
+			txn: &transaction.Transaction{
+				ClientID:     getMockBlobberId(0),
+				ToClientID:   ADDRESS,
+				CreationDate: common.Timestamp(viper.GetInt64(bk.Now)),
+			},
+			input: func() []byte {
+				var conf = &scConfig{
+					AggregateProofs: getMockAggregateProofsConfig(),
+				}
+				bytes, _ := json.Marshal(&aggregateCommitRequest{
+					Epoch: aggregateEpoch(conf,
+						common.Timestamp(viper.GetInt64(bk.Now))),
+					Root:      getMockAggregateTree().GetRoot(),
+					NumLeaves: len(getMockAggregateLeaves()),
+				})
+				return bytes
+			}(),
+		},
+		{
+			name:     "storage.generate_aggregate_challenges",
+			endpoint: ssc.generateAggregateChallenges,
+			txn: &transaction.Transaction{
+				HashIDField: datastore.HashIDField{
+					Hash: encryption.Hash("mock transaction hash"),
+				},
+				CreationDate: common.Timestamp(viper.GetInt64(bk.Now)),
+			},
+			input: nil,
+		},
+		{
+			name:     "storage.aggregate_challenge_response",
+			endpoint: ssc.aggregateChallengeResponse,
+			txn: &transaction.Transaction{
+				ClientID:     getMockBlobberId(0),
+				CreationDate: common.Timestamp(viper.GetInt64(bk.Now)),
+			},
+			input: func() []byte {
+				var (
+					tree = getMockAggregateTree()
+					ac   = getMockAggregateChallenge(tree, nil,
+						common.Timestamp(viper.GetInt64(bk.Now)))
+					leaves = getMockAggregateLeaves()
+					req    = aggregateChallengeResponse{
+						ChallengeID: getMockAggregateChallengeId(0),
+					}
+				)
+				for _, idx := range ac.Samples {
+					req.Proofs = append(req.Proofs, &aggregateProof{
+						allocationLeaf: *leaves[idx],
+						Path:           tree.GetPathByIndex(idx),
+					})
+				}
+				var conf = getMockAggregateProofsConfig()
+				for i := 0; i < conf.NumValidators; i++ {
+					vt := &ValidationTicket{
+						ChallengeID:  req.ChallengeID,
+						BlobberID:    getMockBlobberId(0),
+						ValidatorID:  getMockValidatorId(i),
+						ValidatorKey: data.PublicKeys[i],
+						Result:       true,
+						Timestamp:    now,
+					}
+					hash := encryption.Hash(fmt.Sprintf("%v:%v:%v:%v:%v:%v", vt.ChallengeID, vt.BlobberID,
+						vt.ValidatorID, vt.ValidatorKey, vt.Result, vt.Timestamp))
+					_ = sigScheme.SetPublicKey(data.PublicKeys[i])
+					sigScheme.SetPrivateKey(data.PrivateKeys[i])
+					vt.Signature, _ = sigScheme.Sign(hash)
+					req.ValidationTickets = append(req.ValidationTickets, vt)
+				}
+				bytes, _ := json.Marshal(&req)
+				return bytes
+			}(),
+		},
 		// todo "update_config" waiting for PR489
 	}
 	var testsI []bk.BenchTestI
@@ -415,13 +415,6 @@ func (sc *StorageSmartContract) commitBlobberRead(t *transaction.Transaction,
 		return "", common.NewError("commit_blobber_read", err.Error())
 	}
 
-	// move tokens from read pool to blobber
-	var rp *readPool
-	if rp, err = sc.getReadPool(userID, balances); err != nil {
-		return "", common.NewErrorf("commit_blobber_read",
-			"can't get related read pool: %v", err)
-	}
-
 	var sp *stakePool
 	sp, err = sc.getStakePool(commitRead.ReadMarker.BlobberID, balances)
 	if err != nil {
@@ -429,11 +422,93 @@ func (sc *StorageSmartContract) commitBlobberRead(t *transaction.Transaction,
 			"can't get related stake pool: %v", err)
 	}
 
-	resp, err = rp.moveToBlobber(sc.ID, commitRead.ReadMarker.AllocationID,
-		commitRead.ReadMarker.BlobberID, sp, t.CreationDate, value, balances)
-	if err != nil {
+	// the allocation escrow pays first, the read pool pays the rest
+	var es *escrow
+	if es, err = sc.findEscrow(alloc, balances); err != nil {
 		return "", common.NewErrorf("commit_blobber_read",
-			"can't transfer tokens from read pool to stake pool: %v", err)
+			"can't get allocation escrow: %v", err)
+	}
+
+	// all redeems to response at the end
+	var (
+		redeems    []readPoolRedeem
+		fromEscrow state.Balance
+	)
+
+	if es != nil && es.paysFor(alloc, userID) {
+		fromEscrow = minBalance(value,
+			es.available(escrowRead, commitRead.ReadMarker.BlobberID))
+	}
+
+	if fromEscrow > 0 {
+		_, err = es.payBlobber(sc.ID, escrowRead,
+			commitRead.ReadMarker.BlobberID, sp, fromEscrow, balances)
+		if err != nil {
+			return "", common.NewErrorf("commit_blobber_read",
+				"can't transfer tokens from escrow to stake pool: %v", err)
+		}
+		if err = es.save(sc.ID, balances); err != nil {
+			return "", common.NewErrorf("commit_blobber_read",
+				"can't save escrow: %v", err)
+		}
+		redeems = append(redeems, readPoolRedeem{
+			PoolID:  es.Read.ID,
+			Balance: fromEscrow,
+		})
+	}
+
+	// the reader pays the owner for priced shared data with the read
+	var charge *shareCharge
+	charge, err = sc.shareOfferCharge(alloc, commitRead.ReadMarker, sizeRead,
+		balances)
+	if err != nil {
+		return "", common.NewError("commit_blobber_read", err.Error())
+	}
+
+	if rest := value - fromEscrow; rest > 0 || charge != nil {
+		// move tokens from read pool to blobber and owner
+		var rp *readPool
+		if rp, err = sc.getReadPool(userID, balances); err != nil {
+			return "", common.NewErrorf("commit_blobber_read",
+				"can't get related read pool: %v", err)
+		}
+
+		// the read and the share fee are paid together or not at all
+		if charge != nil {
+			var have = rp.blobberBalance(commitRead.ReadMarker.AllocationID,
+				commitRead.ReadMarker.BlobberID, t.CreationDate)
+			if have < rest+charge.fee {
+				return "", common.NewErrorf("commit_blobber_read",
+					"not enough tokens in read pool to pay for the read and "+
+						"the share offer: %d < %d", have, rest+charge.fee)
+			}
+		}
+
+		if rest > 0 {
+			var rpRedeems []readPoolRedeem
+			rpRedeems, err = rp.moveToBlobber(sc.ID,
+				commitRead.ReadMarker.AllocationID,
+				commitRead.ReadMarker.BlobberID, sp, t.CreationDate, rest,
+				balances)
+			if err != nil {
+				return "", common.NewErrorf("commit_blobber_read",
+					"can't transfer tokens from read pool to stake pool: %v", err)
+			}
+			redeems = append(redeems, rpRedeems...)
+		}
+
+		var shareRedeems []readPoolRedeem
+		shareRedeems, err = sc.payShareOffer(alloc, commitRead.ReadMarker, rp,
+			charge, t.CreationDate, balances)
+		if err != nil {
+			return "", common.NewError("commit_blobber_read", err.Error())
+		}
+		redeems = append(redeems, shareRedeems...)
+
+		if err = rp.save(sc.ID, userID, balances); err != nil {
+			return "", common.NewErrorf("commit_blobber_read",
+				"can't save read pool: %v", err)
+		}
 	}
 	details.ReadReward += value // stat
 	details.Spent += value      // reduce min lock demand left
@@ -445,11 +520,6 @@ func (sc *StorageSmartContract) commitBlobberRead(t *transaction.Transaction,
 			"can't save stake pool: %v", err)
 	}
 
-	if err = rp.save(sc.ID, userID, balances); err != nil {
-		return "", common.NewErrorf("commit_blobber_read",
-			"can't save read pool: %v", err)
-	}
-
 	// save allocation
 	_, err = balances.InsertTrieNode(alloc.GetKey(sc.ID), alloc)
 	if err != nil {
@@ -464,7 +534,7 @@ func (sc *StorageSmartContract) commitBlobberRead(t *transaction.Transaction,
 	}
 	sc.newRead(balances, numReads)
 
-	return // ok, the response and nil
+	return toJson(redeems), nil
 }
 
 func sizePrice(size int64, price state.Balance) float64 {
@@ -495,34 +565,60 @@ func (sc *StorageSmartContract) commitMoveTokens(alloc *StorageAllocation,
 	var (
 		until = alloc.Until()
 		move  state.Balance
+		wps   *allocationWritePools
 	)
 
-	// the details will be saved in caller with allocation object (the details
-	// is part of the allocation object)
-	wps, err := alloc.getAllocationPools(sc, balances)
+	// the allocation escrow is used first, if any, the write pools are
+	// used for the rest
+	es, err := sc.findEscrow(alloc, balances)
 	if err != nil {
-		return fmt.Errorf("can't move tokens to challenge pool: %v", err)
+		return fmt.Errorf("can't get allocation escrow: %v", err)
 	}
 
+	// the details will be saved in caller with allocation object (the details
+	// is part of the allocation object)
 	if size > 0 {
 		move = details.upload(size, wmTime,
 			alloc.restDurationInTimeUnits(wmTime))
 
-		err = wps.moveToChallenge(alloc.ID, details.BlobberID, cp, now, move)
-		if err != nil {
-			return fmt.Errorf("can't move tokens to challenge pool: %v", err)
+		var fromEscrow state.Balance
+		if es != nil {
+			fromEscrow = minBalance(move,
+				es.available(escrowWrite, details.BlobberID))
+		}
+		if fromEscrow > 0 {
+			err = es.moveToChallenge(cp, details.BlobberID, fromEscrow)
+			if err != nil {
+				return fmt.Errorf("can't move tokens to challenge pool: %v", err)
+			}
+		}
+		if rest := move - fromEscrow; rest > 0 {
+			wps, err = alloc.getAllocationPools(sc, balances)
+			if err != nil {
+				return fmt.Errorf("can't move tokens to challenge pool: %v", err)
+			}
+			err = wps.moveToChallenge(alloc.ID, details.BlobberID, cp, now, rest)
+			if err != nil {
+				return fmt.Errorf("can't move tokens to challenge pool: %v", err)
+			}
 		}
 
 		alloc.MovedToChallenge += move
 		details.Spent += move
 	} else {
-		// delete (challenge_pool -> write_pool)
+		// delete (challenge_pool -> escrow and write_pool); the escrow
+		// gets back its share of the tokens
 		move = details.delete(-size, wmTime, alloc.restDurationInTimeUnits(wmTime))
-		wp, err := wps.getOwnerWP()
+		wps, err = alloc.getAllocationPools(sc, balances)
 		if err != nil {
 			return fmt.Errorf("can't move tokens to challenge pool: %v", err)
 		}
-		err = cp.moveToWritePool(alloc, details.BlobberID, until, wp, move)
+		var wp *writePool
+		if wp, err = wps.getOwnerWP(); err != nil {
+			return fmt.Errorf("can't move tokens to challenge pool: %v", err)
+		}
+		err = moveBackFromChallenge(alloc, es, cp, wp, details.BlobberID,
+			until, move)
 		if err != nil {
 			return fmt.Errorf("can't move tokens to write pool: %v", err)
 		}
@@ -530,8 +626,16 @@ func (sc *StorageSmartContract) commitMoveTokens(alloc *StorageAllocation,
 		details.Returned += move
 	}
 
-	if err := wps.saveWritePools(sc.ID, balances); err != nil {
-		return fmt.Errorf("can't move tokens to challenge pool: %v", err)
+	if wps != nil {
+		if err := wps.saveWritePools(sc.ID, balances); err != nil {
+			return fmt.Errorf("can't move tokens to challenge pool: %v", err)
+		}
+	}
+
+	if es != nil {
+		if err = es.save(sc.ID, balances); err != nil {
+			return fmt.Errorf("can't save escrow: %v", err)
+		}
 	}
 
 	if err = cp.save(sc.ID, alloc.ID, balances); err != nil {
@@ -631,6 +735,10 @@ func (sc *StorageSmartContract) commitBlobberConnection(
 			"write marker time is after allocation expires")
 	}
 
+	var (
+		movedBefore = alloc.MovedToChallenge
+		restBefore  = alloc.restMinLockDemand()
+	)
 	err = sc.commitMoveTokens(alloc, commitConnection.WriteMarker.Size, details,
 		commitConnection.WriteMarker.Timestamp, t.CreationDate, balances)
 	if err != nil {
@@ -638,6 +746,13 @@ func (sc *StorageSmartContract) commitBlobberConnection(
 			"moving tokens: %v", err)
 	}
 
+	err = sc.checkLowBalance(alloc, alloc.MovedToChallenge-movedBefore,
+		restBefore, balances)
+	if err != nil {
+		return "", common.NewErrorf("commit_connection_failed",
+			"checking allocation funds: %v", err)
+	}
+
 	// save allocation object
 	_, err = balances.InsertTrieNode(alloc.GetKey(sc.ID), alloc)
 	if err != nil {
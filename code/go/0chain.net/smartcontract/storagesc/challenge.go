@@ -116,22 +116,32 @@ func (sc *StorageSmartContract) blobberReward(t *transaction.Transaction,
 	back = move - reward                            // return back to write pool
 
 	if back > 0 {
-		// move back to write pool
+		// move back to escrow and write pool
 		var wp *writePool
 		if wp, err = sc.getWritePool(alloc.Owner, balances); err != nil {
 			return fmt.Errorf("can't get allocation's write pool: %v", err)
 		}
+		var es *escrow
+		if es, err = sc.findEscrow(alloc, balances); err != nil {
+			return fmt.Errorf("can't get allocation's escrow: %v", err)
+		}
 		var until = alloc.Until()
-		err = cp.moveToWritePool(alloc, details.BlobberID, until, wp, back)
+		err = moveBackFromChallenge(alloc, es, cp, wp, details.BlobberID,
+			until, back)
 		if err != nil {
 			return fmt.Errorf("moving partial challenge to write pool: %v", err)
 		}
 		alloc.MovedBack += back
 		details.Returned += back
-		// save the write pool
+		// save the pools
 		if err = wp.save(sc.ID, alloc.Owner, balances); err != nil {
 			return fmt.Errorf("can't save allocation's write pool: %v", err)
 		}
+		if es != nil {
+			if err = es.save(sc.ID, balances); err != nil {
+				return fmt.Errorf("can't save allocation's escrow: %v", err)
+			}
+		}
 	}
 
 	var sp *stakePool
@@ -239,6 +249,11 @@ func (sc *StorageSmartContract) blobberPenalty(t *transaction.Transaction,
 		return fmt.Errorf("can't get allocation's write pool: %v", err)
 	}
 
+	var es *escrow
+	if es, err = sc.findEscrow(alloc, balances); err != nil {
+		return fmt.Errorf("can't get allocation's escrow: %v", err)
+	}
+
 	var (
 		rdtu = alloc.restDurationInTimeUnits(prev)
 		dtu  = alloc.durationInTimeUnits(tp - prev)
@@ -269,9 +284,10 @@ func (sc *StorageSmartContract) blobberPenalty(t *transaction.Transaction,
 		return
 	}
 
-	// move back to write pool
+	// move back to escrow and write pool
 	var until = alloc.Until()
-	err = cp.moveToWritePool(alloc, details.BlobberID, until, wp, move)
+	err = moveBackFromChallenge(alloc, es, cp, wp, details.BlobberID, until,
+		move)
 	if err != nil {
 		return fmt.Errorf("moving failed challenge to write pool: %v", err)
 	}
@@ -317,6 +333,12 @@ func (sc *StorageSmartContract) blobberPenalty(t *transaction.Transaction,
 		return fmt.Errorf("can't save allocation's write pool: %v", err)
 	}
 
+	if es != nil {
+		if err = es.save(sc.ID, balances); err != nil {
+			return fmt.Errorf("can't save allocation's escrow: %v", err)
+		}
+	}
+
 	if err = cp.save(sc.ID, alloc.ID, balances); err != nil {
 		return fmt.Errorf("can't save allocation's challenge pool: %v", err)
 	}
@@ -704,6 +726,10 @@ func (sc *StorageSmartContract) addChallenge(alloc *StorageAllocation,
 			return "", common.NewError("invalid_parameters",
 				"Blobber is not part of the allocation. Could not find blobber")
 		}
+		// blobbers proving by aggregate commitments are challenged by them
+		if sc.isAggregateBlobber(selectedBlobberObj.ID, balances) {
+			continue
+		}
 		blobberAllocation = alloc.BlobberMap[selectedBlobberObj.ID]
 		if blobberAllocation.AllocationRoot != "" {
 			break // found
@@ -51,6 +51,19 @@ type writePoolConfig struct {
 	MaxLockPeriod time.Duration `json:"max_lock_period"`
 }
 
+// aggregateProofsConfig of the optional proof-of-storage mode where
+// blobbers commit an aggregate commitment of all their allocations per
+// epoch and challenges sample against the commitment.
+type aggregateProofsConfig struct {
+	Enabled bool `json:"enabled"`
+	// Epoch is duration of an aggregate commitment.
+	Epoch time.Duration `json:"epoch"`
+	// NumSamples is number of allocation roots sampled by a challenge.
+	NumSamples int `json:"num_samples"`
+	// NumValidators is number of validators verifying a batch proof.
+	NumValidators int `json:"num_validators"`
+}
+
 type blockReward struct {
 	BlockReward           state.Balance `json:"block_reward"`
 	QualifyingStake       state.Balance `json:"qualifying_stake"`
@@ -159,8 +172,15 @@ type scConfig struct {
 
 	BlockReward *blockReward `json:"block_reward"`
 
+	// AggregateProofs related configurations.
+	AggregateProofs *aggregateProofsConfig `json:"aggregate_proofs"`
+
 	// Allow direct access to MPT
 	ExposeMpt bool `json:"expose_mpt"`
+
+	// SettingsTimeLock is min number of rounds between scheduling of
+	// settings changes and their activation.
+	SettingsTimeLock int64 `json:"settings_time_lock"`
 }
 
 func (sc *scConfig) validate() (err error) {
@@ -282,6 +302,10 @@ func (sc *scConfig) validate() (err error) {
 	if sc.MinStake < 0 {
 		return fmt.Errorf("negative min_stake: %v", sc.MinStake)
 	}
+	if sc.SettingsTimeLock < 0 {
+		return fmt.Errorf("negative settings_time_lock: %v",
+			sc.SettingsTimeLock)
+	}
 	if sc.MaxStake < sc.MinStake {
 		return fmt.Errorf("max_stake less than min_stake: %v < %v", sc.MinStake,
 			sc.MaxStake)
@@ -296,6 +320,19 @@ func (sc *scConfig) validate() (err error) {
 		return fmt.Errorf("max_change >= 1.0 (> 100%%, invalid): %v",
 			sc.MaxCharge)
 	}
+	if ap := sc.AggregateProofs; ap != nil && ap.Enabled {
+		if ap.Epoch <= 0 {
+			return fmt.Errorf("invalid aggregate_proofs.epoch: %v", ap.Epoch)
+		}
+		if ap.NumSamples < 1 {
+			return fmt.Errorf("invalid aggregate_proofs.num_samples: %v",
+				ap.NumSamples)
+		}
+		if ap.NumValidators < 1 {
+			return fmt.Errorf("invalid aggregate_proofs.num_validators: %v",
+				ap.NumValidators)
+		}
+	}
 	if sc.BlockReward.BlockReward < 0 {
 		return fmt.Errorf("negative block_reward.block_reward: %v",
 			sc.BlockReward.BlockReward)
@@ -323,6 +360,14 @@ func (sc *scConfig) validate() (err error) {
 	return
 }
 
+// aggregateProofs configurations, disabled ones if not set
+func (conf *scConfig) aggregateProofs() *aggregateProofsConfig {
+	if conf.AggregateProofs == nil {
+		return &aggregateProofsConfig{}
+	}
+	return conf.AggregateProofs
+}
+
 func (conf *scConfig) canMint() bool {
 	return conf.Minted < conf.MaxMint
 }
@@ -464,7 +509,19 @@ func getConfiguredConfig() (conf *scConfig, err error) {
 		scc.GetFloat64(pfx+"block_reward.blobber_capacity_ratio"),
 		scc.GetFloat64(pfx+"block_reward.blobber_usage_ratio"),
 	)
+	// aggregate proofs
+	conf.AggregateProofs = new(aggregateProofsConfig)
+	conf.AggregateProofs.Enabled = scc.GetBool(
+		pfx + "aggregate_proofs.enabled")
+	conf.AggregateProofs.Epoch = scc.GetDuration(
+		pfx + "aggregate_proofs.epoch")
+	conf.AggregateProofs.NumSamples = scc.GetInt(
+		pfx + "aggregate_proofs.num_samples")
+	conf.AggregateProofs.NumValidators = scc.GetInt(
+		pfx + "aggregate_proofs.num_validators")
+
 	conf.ExposeMpt = scc.GetBool(pfx + "expose_mpt")
+	conf.SettingsTimeLock = scc.GetInt64(pfx + "settings_time_lock")
 
 	err = conf.validate()
 	return
@@ -78,8 +78,15 @@ const (
 	BlockRewardBlobberCapacityWeight
 	BlockRewardBlobberUsageWeight
 
+	AggregateProofsEnabled
+	AggregateProofsEpoch
+	AggregateProofsNumSamples
+	AggregateProofsNumValidators
+
 	ExposeMpt
 
+	SettingsTimeLock
+
 	NumberOfSettings
 )
 
@@ -138,7 +145,14 @@ var (
 		"block_reward.blobber_capacity_ratio",
 		"block_reward.blobber_usage_ratio",
 
+		"aggregate_proofs.enabled",
+		"aggregate_proofs.epoch",
+		"aggregate_proofs.num_samples",
+		"aggregate_proofs.num_validators",
+
 		"expose_mpt",
+
+		"settings_time_lock",
 	}
 
 	Settings = map[string]struct {
@@ -198,7 +212,14 @@ var (
 		"block_reward.blobber_capacity_ratio": {BlockRewardBlobberCapacityWeight, smartcontract.Float64},
 		"block_reward.blobber_usage_ratio":    {BlockRewardBlobberUsageWeight, smartcontract.Float64},
 
+		"aggregate_proofs.enabled":        {AggregateProofsEnabled, smartcontract.Boolean},
+		"aggregate_proofs.epoch":          {AggregateProofsEpoch, smartcontract.Duration},
+		"aggregate_proofs.num_samples":    {AggregateProofsNumSamples, smartcontract.Int},
+		"aggregate_proofs.num_validators": {AggregateProofsNumValidators, smartcontract.Int},
+
 		"expose_mpt": {ExposeMpt, smartcontract.Boolean},
+
+		"settings_time_lock": {SettingsTimeLock, smartcontract.Int64},
 	}
 )
 
@@ -233,6 +254,16 @@ func (conf *scConfig) setInt(key string, change int) error {
 		conf.MaxChallengesPerGeneration = change
 	case MaxDelegates:
 		conf.MaxDelegates = change
+	case AggregateProofsNumSamples:
+		if conf.AggregateProofs == nil {
+			conf.AggregateProofs = &aggregateProofsConfig{}
+		}
+		conf.AggregateProofs.NumSamples = change
+	case AggregateProofsNumValidators:
+		if conf.AggregateProofs == nil {
+			conf.AggregateProofs = &aggregateProofsConfig{}
+		}
+		conf.AggregateProofs.NumValidators = change
 	default:
 		return fmt.Errorf("key: %v not implemented as int", key)
 	}
@@ -302,6 +333,8 @@ func (conf *scConfig) setInt64(key string, change int64) error {
 		conf.StakePool.MinLock = change
 	case FreeAllocationSize:
 		conf.FreeAllocationSettings.Size = change
+	case SettingsTimeLock:
+		conf.SettingsTimeLock = change
 	default:
 		return fmt.Errorf("key: %v not implemented as int64", key)
 	}
@@ -389,6 +422,11 @@ func (conf *scConfig) setDuration(key string, change time.Duration) error {
 		conf.FreeAllocationSettings.Duration = change
 	case FreeAllocationMaxChallengeCompletionTime:
 		conf.FreeAllocationSettings.MaxChallengeCompletionTime = change
+	case AggregateProofsEpoch:
+		if conf.AggregateProofs == nil {
+			conf.AggregateProofs = &aggregateProofsConfig{}
+		}
+		conf.AggregateProofs.Epoch = change
 	default:
 		return fmt.Errorf("key: %v not implemented as duration", key)
 	}
@@ -399,6 +437,11 @@ func (conf *scConfig) setBoolean(key string, change bool) error {
 	switch Settings[key].setting {
 	case ChallengeEnabled:
 		conf.ChallengeEnabled = change
+	case AggregateProofsEnabled:
+		if conf.AggregateProofs == nil {
+			conf.AggregateProofs = &aggregateProofsConfig{}
+		}
+		conf.AggregateProofs.Enabled = change
 	case ExposeMpt:
 		conf.ExposeMpt = change
 	default:
@@ -560,8 +603,18 @@ func (conf *scConfig) get(key Setting) interface{} {
 		return conf.BlockReward.BlobberCapacityWeight
 	case BlockRewardBlobberUsageWeight:
 		return conf.BlockReward.BlobberUsageWeight
+	case AggregateProofsEnabled:
+		return conf.aggregateProofs().Enabled
+	case AggregateProofsEpoch:
+		return conf.aggregateProofs().Epoch
+	case AggregateProofsNumSamples:
+		return conf.aggregateProofs().NumSamples
+	case AggregateProofsNumValidators:
+		return conf.aggregateProofs().NumValidators
 	case ExposeMpt:
 		return conf.ExposeMpt
+	case SettingsTimeLock:
+		return conf.SettingsTimeLock
 	default:
 		panic("Setting not implemented")
 	}
@@ -583,12 +636,12 @@ func (ssc *StorageSmartContract) updateSettings(
 	input []byte,
 	balances chainState.StateContextI,
 ) (resp string, err error) {
-	if t.ClientID != owner {
+	if !smartcontract.CanUpdateSettings(t.ClientID) {
 		return "", common.NewError("update_settings",
-			"unauthorized access - only the owner can update the variables")
+			"unauthorized access - only the governance can update the variables")
 	}
 
-	var newChanges smartcontract.StringMap
+	var newChanges smartcontract.ScheduleRequest
 	if err = newChanges.Decode(input); err != nil {
 		return "", common.NewError("update_settings", err.Error())
 	}
@@ -597,6 +650,16 @@ func (ssc *StorageSmartContract) updateSettings(
 		return "", nil
 	}
 
+	var conf *scConfig
+	if conf, err = ssc.getConfig(balances, true); err != nil {
+		return "", common.NewError("update_settings",
+			"can't get config: "+err.Error())
+	}
+
+	if newChanges.ActivationRound != 0 || conf.SettingsTimeLock > 0 {
+		return ssc.scheduleSettingChanges(t, conf, &newChanges, balances)
+	}
+
 	updateChanges, err := getSettingChanges(balances)
 	if err != nil {
 		return "", common.NewError("update_settings, getting setting changes", err.Error())
@@ -630,7 +693,17 @@ func (ssc *StorageSmartContract) commitSettingChanges(
 		return "", common.NewError("commitSettingChanges, getting setting changes", err.Error())
 	}
 
-	if len(changes.Fields) == 0 {
+	scheduled, err := getScheduledSettings(balances)
+	if err != nil {
+		return "", common.NewError("commitSettingChanges, getting scheduled changes", err.Error())
+	}
+
+	var due []*smartcontract.ScheduledChange
+	if len(scheduled.Changes) > 0 {
+		due = scheduled.Due(balances.GetBlock().Round)
+	}
+
+	if len(changes.Fields) == 0 && len(due) == 0 {
 		return "", nil
 	}
 
@@ -642,6 +715,20 @@ func (ssc *StorageSmartContract) commitSettingChanges(
 		return "", common.NewError("update_settings", err.Error())
 	}
 
+	if len(due) > 0 {
+		if err = activateScheduledSettings(conf, changes, due); err != nil {
+			return "", common.NewError("update_settings", err.Error())
+		}
+		_, err = balances.InsertTrieNode(scheduledSettingsKey, scheduled)
+		if err != nil {
+			return "", common.NewError("update_settings", err.Error())
+		}
+		_, err = balances.InsertTrieNode(settingChangesKey, changes)
+		if err != nil {
+			return "", common.NewError("update_settings", err.Error())
+		}
+	}
+
 	_, err = balances.InsertTrieNode(scConfigKey(ssc.ID), conf)
 	if err != nil {
 		return "", common.NewError("update_settings", err.Error())
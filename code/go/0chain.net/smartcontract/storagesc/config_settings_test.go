@@ -16,6 +16,7 @@ import (
 	"0chain.net/chaincore/mocks"
 	sci "0chain.net/chaincore/smartcontractinterface"
 	"0chain.net/chaincore/transaction"
+	"0chain.net/core/util"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
@@ -54,6 +55,7 @@ func TestUpdateSettings(t *testing.T) {
 
 		var oldChanges smartcontract.StringMap
 		oldChanges.Fields = p.previousMap
+		balances.On("GetTrieNode", scConfigKey(ssc.ID)).Return(&scConfig{}, nil).Once()
 		balances.On("GetTrieNode", settingChangesKey).Return(&oldChanges, nil).Once()
 
 		for key, value := range p.inputMap {
@@ -105,7 +107,7 @@ func TestUpdateSettings(t *testing.T) {
 		{
 			title: "all_settigns",
 			parameters: parameters{
-				client:      owner,
+				client:      smartcontract.GovernanceAddress,
 				previousMap: map[string]string{},
 				inputMap: map[string]string{
 					"max_mint":                      "1500000.02",
@@ -212,6 +214,7 @@ func TestCommitSettingChanges(t *testing.T) {
 		balances.On("GetTrieNode", settingChangesKey).Return(&smartcontract.StringMap{
 			Fields: p.inputMap,
 		}, nil).Once()
+		balances.On("GetTrieNode", scheduledSettingsKey).Return(nil, util.ErrValueNotPresent).Once()
 
 		balances.On(
 			"InsertTrieNode",
@@ -484,8 +487,19 @@ func getConfField(conf scConfig, field string) interface{} {
 	case BlockRewardBlobberUsageWeight:
 		return conf.BlockReward.BlobberUsageWeight
 
+	case AggregateProofsEnabled:
+		return conf.AggregateProofs.Enabled
+	case AggregateProofsEpoch:
+		return conf.AggregateProofs.Epoch
+	case AggregateProofsNumSamples:
+		return conf.AggregateProofs.NumSamples
+	case AggregateProofsNumValidators:
+		return conf.AggregateProofs.NumValidators
+
 	case ExposeMpt:
 		return conf.ExposeMpt
+	case SettingsTimeLock:
+		return conf.SettingsTimeLock
 	default:
 		panic("unknown field: " + field)
 	}
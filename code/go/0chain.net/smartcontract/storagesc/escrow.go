@@ -0,0 +1,826 @@
+package storagesc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"0chain.net/smartcontract"
+
+	cstate "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/tokenpool"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/util"
+)
+
+//
+// allocation escrow (read and write tokens of an allocation)
+//
+
+// escrow sub-balances
+const (
+	escrowRead  = "read"
+	escrowWrite = "write"
+	// tokens of write sub-balance moved to challenge pool
+	escrowChallenge = "challenge"
+)
+
+func escrowKey(scKey, allocID string) datastore.Key {
+	return datastore.Key(scKey + ":escrow:" + allocID)
+}
+
+// An escrowFunder represents tokens of a funder left in an escrow and
+// tokens of the funder moved to challenge pool of the allocation.
+type escrowFunder struct {
+	ClientID  datastore.Key `json:"client_id"`
+	Read      state.Balance `json:"read"`
+	Write     state.Balance `json:"write"`
+	Challenge state.Balance `json:"challenge,omitempty"`
+}
+
+func (ef *escrowFunder) share(kind string) *state.Balance {
+	switch kind {
+	case escrowRead:
+		return &ef.Read
+	case escrowChallenge:
+		return &ef.Challenge
+	}
+	return &ef.Write
+}
+
+// escrowFunders is list of funders sorted by client ID
+type escrowFunders []*escrowFunder
+
+func (efs escrowFunders) get(clientID datastore.Key) (ef *escrowFunder,
+	ok bool) {
+
+	var i = sort.Search(len(efs), func(i int) bool {
+		return efs[i].ClientID >= clientID
+	})
+	if i == len(efs) || efs[i].ClientID != clientID {
+		return // not found
+	}
+	return efs[i], true // found
+}
+
+func (efs *escrowFunders) getOrAdd(clientID datastore.Key) (
+	ef *escrowFunder) {
+
+	var ok bool
+	if ef, ok = efs.get(clientID); ok {
+		return
+	}
+	ef = &escrowFunder{ClientID: clientID}
+	var i = sort.Search(len(*efs), func(i int) bool {
+		return (*efs)[i].ClientID >= clientID
+	})
+	(*efs) = append((*efs)[:i], append([]*escrowFunder{ef}, (*efs)[i:]...)...)
+	return
+}
+
+// spend given value of given total reducing shares of all funders
+// proportionally; the division rest is spent in funders order
+func (efs escrowFunders) spend(kind string, value, total state.Balance) {
+	efs.move(kind, "", value, total)
+}
+
+// move given value of given total from one kind of shares of all funders
+// to another one proportionally; the division rest is moved in funders
+// order; empty 'to' means the value is spent
+func (efs escrowFunders) move(from, to string, value, total state.Balance) {
+	if value <= 0 || total <= 0 {
+		return
+	}
+	var (
+		left = value
+		cut  = func(ef *escrowFunder, value state.Balance) {
+			*ef.share(from) -= value
+			if to != "" {
+				*ef.share(to) += value
+			}
+			left -= value
+		}
+	)
+	for _, ef := range efs {
+		var share = *ef.share(from)
+		cut(ef, minBalance(state.Balance(float64(value)*float64(share)/
+			float64(total)), minBalance(share, left)))
+	}
+	for _, ef := range efs {
+		if left == 0 {
+			break
+		}
+		cut(ef, minBalance(*ef.share(from), left))
+	}
+}
+
+// An escrow keeps all tokens locked for an allocation by its funders (the
+// owner and sponsors). The read sub-balance pays for reads, the write one
+// pays for writes and min lock demand. The escrow and the read and write
+// pools of the allocation are funds of the same allocation: the escrow pays
+// first and the pools pay the rest. Tokens migrated from the pools keep
+// reserved for their blobbers. Tokens left are returned to the funders on
+// finalize_allocation or cancel_allocation.
+type escrow struct {
+	AllocationID  datastore.Key     `json:"allocation_id"`
+	Read          tokenpool.ZcnPool `json:"read"`
+	Write         tokenpool.ZcnPool `json:"write"`
+	ReadBlobbers  blobberPools      `json:"read_blobbers,omitempty"`
+	WriteBlobbers blobberPools      `json:"write_blobbers,omitempty"`
+	InChallenge   state.Balance     `json:"in_challenge,omitempty"`
+	Funders       escrowFunders     `json:"funders"`
+	Settled       bool              `json:"settled"`
+}
+
+func newEscrow(allocID datastore.Key) (es *escrow) {
+	es = new(escrow)
+	es.AllocationID = allocID
+	es.Read.ID = allocID + ":" + escrowRead
+	es.Write.ID = allocID + ":" + escrowWrite
+	return
+}
+
+// Encode implements util.Serializable interface.
+func (es *escrow) Encode() []byte {
+	var b, err = json.Marshal(es)
+	if err != nil {
+		panic(err) // must never happens
+	}
+	return b
+}
+
+// Decode implements util.Serializable interface.
+func (es *escrow) Decode(p []byte) error {
+	return json.Unmarshal(p, es)
+}
+
+// save the escrow in tree
+func (es *escrow) save(sscKey string, balances cstate.StateContextI) (
+	err error) {
+
+	_, err = balances.InsertTrieNode(escrowKey(sscKey, es.AllocationID), es)
+	return
+}
+
+func (es *escrow) pool(kind string) *tokenpool.ZcnPool {
+	if kind == escrowRead {
+		return &es.Read
+	}
+	return &es.Write
+}
+
+func (es *escrow) blobbers(kind string) *blobberPools {
+	if kind == escrowRead {
+		return &es.ReadBlobbers
+	}
+	return &es.WriteBlobbers
+}
+
+// fund the escrow by tokens already moved to the storage SC
+func (es *escrow) fund(kind string, clientID datastore.Key,
+	value state.Balance) {
+
+	es.pool(kind).Balance += value
+	*es.Funders.getOrAdd(clientID).share(kind) += value
+}
+
+// migrate moves tokens of given allocation pools of a client to a
+// sub-balance keeping the tokens reserved for blobbers of the pools
+func (es *escrow) migrate(kind string, clientID datastore.Key,
+	aps []*allocationPool) (err error) {
+
+	for _, ap := range aps {
+		var value = ap.Balance
+		if value == 0 {
+			continue
+		}
+		if _, _, err = ap.TransferTo(es.pool(kind), value, nil); err != nil {
+			return fmt.Errorf("allocation pool %s -> escrow: %v", ap.ID, err)
+		}
+		*es.Funders.getOrAdd(clientID).share(kind) += value
+		es.reserve(kind, ap.Blobbers)
+		ap.Blobbers = nil
+	}
+	return
+}
+
+// reserve tokens of a sub-balance for given blobbers
+func (es *escrow) reserve(kind string, bps blobberPools) {
+	var reserved = es.blobbers(kind)
+	for _, bp := range bps {
+		if bp.Balance <= 0 {
+			continue
+		}
+		if rp, ok := reserved.get(bp.BlobberID); ok {
+			rp.Balance += bp.Balance
+			continue
+		}
+		reserved.add(&blobberPool{BlobberID: bp.BlobberID, Balance: bp.Balance})
+	}
+	es.trimReserved(kind)
+}
+
+// trimReserved keeps reserved tokens of a sub-balance within its balance
+func (es *escrow) trimReserved(kind string) {
+	var (
+		left     = es.pool(kind).Balance
+		reserved = es.blobbers(kind)
+		i        int
+	)
+	for _, bp := range *reserved {
+		bp.Balance = minBalance(bp.Balance, left)
+		left -= bp.Balance
+		if bp.Balance > 0 {
+			(*reserved)[i], i = bp, i+1
+		}
+	}
+	(*reserved) = (*reserved)[:i]
+}
+
+// available returns tokens of a sub-balance can be used for given blobber,
+// that is tokens not reserved for other blobbers
+func (es *escrow) available(kind, blobberID string) (value state.Balance) {
+	var balance = es.pool(kind).Balance
+	value = balance
+	for _, bp := range *es.blobbers(kind) {
+		if bp.BlobberID != blobberID {
+			value -= bp.Balance
+		}
+	}
+	if value < 0 {
+		return 0
+	}
+	return minBalance(value, balance)
+}
+
+// useReserved reduces tokens reserved for a blobber by given spent value
+func (es *escrow) useReserved(kind, blobberID string, value state.Balance) {
+	var reserved = es.blobbers(kind)
+	if bp, ok := reserved.get(blobberID); ok {
+		if bp.Balance -= minBalance(bp.Balance, value); bp.Balance == 0 {
+			reserved.remove(blobberID)
+		}
+	}
+	es.trimReserved(kind)
+}
+
+// payBlobber moves tokens to stake pool of a blobber
+func (es *escrow) payBlobber(sscKey, kind, blobberID string, sp *stakePool,
+	value state.Balance, balances cstate.StateContextI) (
+	moved state.Balance, err error) {
+
+	var ep = es.pool(kind)
+	if ep.Balance < value {
+		return 0, fmt.Errorf("not enough tokens in escrow %s: %d < %d",
+			ep.ID, ep.Balance, value)
+	}
+	var before = ep.Balance
+	if moved, err = drainReward(sscKey, ep, sp, value, balances); err != nil {
+		return
+	}
+	es.Funders.spend(kind, before-ep.Balance, before)
+	es.useReserved(kind, blobberID, before-ep.Balance)
+	return
+}
+
+// moveToChallenge moves tokens of a blobber from write sub-balance to
+// challenge pool keeping shares of funders in the challenge pool
+func (es *escrow) moveToChallenge(cp *challengePool, blobberID string,
+	value state.Balance) (err error) {
+
+	var before = es.Write.Balance
+	if _, _, err = es.Write.TransferTo(cp, value, nil); err != nil {
+		return fmt.Errorf("escrow -> challenge pool: %v", err)
+	}
+	es.Funders.move(escrowWrite, escrowChallenge, value, before)
+	es.useReserved(escrowWrite, blobberID, value)
+	es.InChallenge += value
+	return
+}
+
+// challengeShare returns part of given value returned from challenge pool
+// of the allocation belongs to the escrow; it's proportional to tokens
+// moved to the challenge pool by the escrow, the rest belongs to write
+// pools of the allocation
+func (es *escrow) challengeShare(alloc *StorageAllocation,
+	value state.Balance) state.Balance {
+
+	var inChallenge = alloc.MovedToChallenge - alloc.MovedBack
+	if es.InChallenge <= 0 || value <= 0 {
+		return 0
+	}
+	if inChallenge <= es.InChallenge {
+		return minBalance(value, es.InChallenge) // escrow only
+	}
+	return minBalance(state.Balance(float64(value)*
+		float64(es.InChallenge)/float64(inChallenge)), es.InChallenge)
+}
+
+// moveFromChallenge moves tokens back from challenge pool to write
+// sub-balance crediting funders proportionally to their tokens in the
+// challenge pool
+func (es *escrow) moveFromChallenge(cp *challengePool,
+	value state.Balance) (err error) {
+
+	if value == 0 {
+		return // nothing to move
+	}
+	if _, _, err = cp.TransferTo(&es.Write, value, nil); err != nil {
+		return fmt.Errorf("challenge pool -> escrow: %v", err)
+	}
+	es.Funders.move(escrowChallenge, escrowWrite, value, es.InChallenge)
+	es.InChallenge -= minBalance(es.InChallenge, value)
+	return
+}
+
+// moveBackFromChallenge moves tokens from challenge pool back to funds of
+// the allocation; the escrow, if any, gets back its share, the write pool
+// gets the rest
+func moveBackFromChallenge(alloc *StorageAllocation, es *escrow,
+	cp *challengePool, wp *writePool, blobberID string,
+	until common.Timestamp, value state.Balance) (err error) {
+
+	var toEscrow state.Balance
+	if es != nil {
+		toEscrow = es.challengeShare(alloc, value)
+		if err = es.moveFromChallenge(cp, toEscrow); err != nil {
+			return
+		}
+	}
+	if rest := value - toEscrow; rest > 0 {
+		err = cp.moveToWritePool(alloc, blobberID, until, wp, rest)
+	}
+	return
+}
+
+// paysFor reports whether the escrow pays for reads of given client; only
+// the allocation owner and the funders are paid for
+func (es *escrow) paysFor(alloc *StorageAllocation,
+	clientID datastore.Key) bool {
+
+	if clientID == alloc.Owner {
+		return true
+	}
+	var _, ok = es.Funders.get(clientID)
+	return ok
+}
+
+// unlock tokens of a funder
+func (es *escrow) unlock(sscKey, kind string, clientID datastore.Key,
+	value state.Balance, balances cstate.StateContextI) (
+	resp string, err error) {
+
+	var ef, ok = es.Funders.get(clientID)
+	if !ok {
+		return "", errors.New("not a funder of the escrow")
+	}
+
+	var share = ef.share(kind)
+	if value == 0 {
+		value = *share // all
+	}
+	if value <= 0 || value > *share {
+		return "", fmt.Errorf("invalid amount to unlock: %d, funded: %d",
+			value, *share)
+	}
+
+	var transfer *state.Transfer
+	transfer, resp, err = es.pool(kind).DrainPool(sscKey, clientID, value, nil)
+	if err != nil {
+		return
+	}
+	if err = balances.AddTransfer(transfer); err != nil {
+		return
+	}
+	*share -= value
+	es.trimReserved(kind)
+	return
+}
+
+// settle returns all tokens left to funders
+func (es *escrow) settle(sscKey string, balances cstate.StateContextI) (
+	err error) {
+
+	for _, ef := range es.Funders {
+		for _, kind := range []string{escrowRead, escrowWrite} {
+			var share = ef.share(kind)
+			if *share == 0 {
+				continue
+			}
+			var transfer *state.Transfer
+			transfer, _, err = es.pool(kind).DrainPool(sscKey, ef.ClientID,
+				*share, nil)
+			if err != nil {
+				return fmt.Errorf("returning %s tokens to %s: %v", kind,
+					ef.ClientID, err)
+			}
+			if err = balances.AddTransfer(transfer); err != nil {
+				return fmt.Errorf("adding transfer: %v", err)
+			}
+			*share = 0
+		}
+		ef.Challenge = 0 // payed to blobbers and validators
+	}
+	es.ReadBlobbers, es.WriteBlobbers = nil, nil
+	es.InChallenge = 0
+	es.Settled = true
+	return
+}
+
+func (es *escrow) stat(alloc *StorageAllocation) (stat *escrowStat) {
+	stat = new(escrowStat)
+	stat.AllocationID = es.AllocationID
+	stat.Read = es.Read.Balance
+	stat.Write = es.Write.Balance
+	stat.ReadBlobbers = es.ReadBlobbers
+	stat.WriteBlobbers = es.WriteBlobbers
+	stat.InChallenge = es.InChallenge
+	stat.Funders = es.Funders
+	stat.Settled = es.Settled
+	if alloc != nil && !alloc.Finalized && !alloc.Canceled {
+		stat.MinLockDemandLeft = alloc.restMinLockDemand()
+	}
+	return
+}
+
+type escrowStat struct {
+	AllocationID      datastore.Key `json:"allocation_id"`
+	Read              state.Balance `json:"read"`
+	Write             state.Balance `json:"write"`
+	ReadBlobbers      blobberPools  `json:"read_blobbers"`
+	WriteBlobbers     blobberPools  `json:"write_blobbers"`
+	InChallenge       state.Balance `json:"in_challenge"`
+	MinLockDemandLeft state.Balance `json:"min_lock_demand_left"`
+	Funders           escrowFunders `json:"funders"`
+	Settled           bool          `json:"settled"`
+}
+
+//
+// SC / API requests
+//
+
+type escrowRequest struct {
+	AllocationID datastore.Key `json:"allocation_id"`
+	Kind         string        `json:"kind,omitempty"`   // read or write
+	Amount       state.Balance `json:"amount,omitempty"` // to unlock
+}
+
+func (er *escrowRequest) decode(input []byte) (err error) {
+	if err = json.Unmarshal(input, er); err != nil {
+		return
+	}
+	if er.AllocationID == "" {
+		return errors.New("missing allocation_id in request")
+	}
+	return // ok
+}
+
+func (er *escrowRequest) validateKind() error {
+	switch er.Kind {
+	case escrowRead, escrowWrite:
+		return nil
+	}
+	return fmt.Errorf("invalid escrow kind %q, expected %q or %q", er.Kind,
+		escrowRead, escrowWrite)
+}
+
+//
+// smart contract methods
+//
+
+// getEscrow of an allocation
+func (ssc *StorageSmartContract) getEscrow(allocID datastore.Key,
+	balances cstate.StateContextI) (es *escrow, err error) {
+
+	var val util.Serializable
+	if val, err = balances.GetTrieNode(escrowKey(ssc.ID, allocID)); err != nil {
+		return
+	}
+	es = new(escrow)
+	if err = es.Decode(val.Encode()); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return
+}
+
+// getOrCreateEscrow of an allocation, not saving it
+func (ssc *StorageSmartContract) getOrCreateEscrow(allocID datastore.Key,
+	balances cstate.StateContextI) (es *escrow, err error) {
+
+	es, err = ssc.getEscrow(allocID, balances)
+	if err == util.ErrValueNotPresent {
+		return newEscrow(allocID), nil
+	}
+	return
+}
+
+// findEscrow returns escrow of an allocation or nil, if the allocation
+// doesn't use an escrow
+func (ssc *StorageSmartContract) findEscrow(alloc *StorageAllocation,
+	balances cstate.StateContextI) (es *escrow, err error) {
+
+	if !alloc.HasEscrow {
+		return nil, nil
+	}
+	es, err = ssc.getEscrow(alloc.ID, balances)
+	if err == util.ErrValueNotPresent {
+		return nil, nil
+	}
+	return
+}
+
+// saveAllocationEscrow saves the escrow marking the allocation as funded
+// using it
+func (ssc *StorageSmartContract) saveAllocationEscrow(alloc *StorageAllocation,
+	es *escrow, balances cstate.StateContextI) (err error) {
+
+	if err = es.save(ssc.ID, balances); err != nil {
+		return fmt.Errorf("saving escrow: %v", err)
+	}
+	if alloc.HasEscrow {
+		return
+	}
+	alloc.HasEscrow = true
+	if _, err = balances.InsertTrieNode(alloc.GetKey(ssc.ID), alloc); err != nil {
+		return fmt.Errorf("saving allocation: %v", err)
+	}
+	return
+}
+
+// escrowLock locks tokens for read or write sub-balance of an allocation
+// escrow; anyone can fund an allocation becoming a funder of its escrow
+func (ssc *StorageSmartContract) escrowLock(t *transaction.Transaction,
+	input []byte, balances cstate.StateContextI) (resp string, err error) {
+
+	var req escrowRequest
+	if err = req.decode(input); err != nil {
+		return "", common.NewError("escrow_lock_failed", err.Error())
+	}
+	if err = req.validateKind(); err != nil {
+		return "", common.NewError("escrow_lock_failed", err.Error())
+	}
+
+	var conf *scConfig
+	if conf, err = ssc.getConfig(balances, true); err != nil {
+		return "", common.NewError("escrow_lock_failed",
+			"can't get configs: "+err.Error())
+	}
+
+	var minLock = conf.WritePool.MinLock
+	if req.Kind == escrowRead {
+		minLock = conf.ReadPool.MinLock
+	}
+	if t.Value < minLock {
+		return "", common.NewError("escrow_lock_failed",
+			"insufficient amount to lock")
+	}
+
+	if err = checkFill(t, balances); err != nil {
+		return "", common.NewError("escrow_lock_failed", err.Error())
+	}
+
+	var alloc *StorageAllocation
+	if alloc, err = ssc.getAllocation(req.AllocationID, balances); err != nil {
+		return "", common.NewError("escrow_lock_failed",
+			"can't get allocation: "+err.Error())
+	}
+
+	if alloc.Finalized || alloc.Canceled {
+		return "", common.NewError("escrow_lock_failed",
+			"allocation is finalized or canceled")
+	}
+
+	var es *escrow
+	if es, err = ssc.getOrCreateEscrow(alloc.ID, balances); err != nil {
+		return "", common.NewError("escrow_lock_failed",
+			"can't get escrow: "+err.Error())
+	}
+
+	var transfer = state.NewTransfer(t.ClientID, t.ToClientID,
+		state.Balance(t.Value))
+	if err = balances.AddTransfer(transfer); err != nil {
+		return "", common.NewError("escrow_lock_failed", err.Error())
+	}
+	es.fund(req.Kind, t.ClientID, state.Balance(t.Value))
+
+	if err = ssc.saveAllocationEscrow(alloc, es, balances); err != nil {
+		return "", common.NewError("escrow_lock_failed", err.Error())
+	}
+
+	return toJson(es.stat(alloc)), nil
+}
+
+// escrowUnlock returns tokens of a funder; tokens of write sub-balance can
+// be unlocked only if the rest covers min lock demand of the allocation
+func (ssc *StorageSmartContract) escrowUnlock(t *transaction.Transaction,
+	input []byte, balances cstate.StateContextI) (resp string, err error) {
+
+	var req escrowRequest
+	if err = req.decode(input); err != nil {
+		return "", common.NewError("escrow_unlock_failed", err.Error())
+	}
+	if err = req.validateKind(); err != nil {
+		return "", common.NewError("escrow_unlock_failed", err.Error())
+	}
+
+	var alloc *StorageAllocation
+	if alloc, err = ssc.getAllocation(req.AllocationID, balances); err != nil {
+		return "", common.NewError("escrow_unlock_failed",
+			"can't get allocation: "+err.Error())
+	}
+
+	var es *escrow
+	if es, err = ssc.getEscrow(alloc.ID, balances); err != nil {
+		return "", common.NewError("escrow_unlock_failed",
+			"can't get escrow: "+err.Error())
+	}
+
+	var before = es.Write.Balance
+	resp, err = es.unlock(ssc.ID, req.Kind, t.ClientID, req.Amount, balances)
+	if err != nil {
+		return "", common.NewError("escrow_unlock_failed", err.Error())
+	}
+
+	if req.Kind == escrowWrite && !alloc.Finalized && !alloc.Canceled {
+		// the write pools of the allocation cover min lock demand too
+		var leave = es.Write.Balance
+		if leave < before {
+			var inPools state.Balance
+			if inPools, err = ssc.writePoolsBalance(alloc, balances); err != nil {
+				return "", common.NewError("escrow_unlock_failed",
+					"can't get allocation write pools: "+err.Error())
+			}
+			if leave+inPools < alloc.restMinLockDemand() {
+				return "", common.NewError("escrow_unlock_failed",
+					"can't unlock, because min lock demand is not paid yet")
+			}
+		}
+	}
+
+	if err = es.save(ssc.ID, balances); err != nil {
+		return "", common.NewError("escrow_unlock_failed",
+			"saving escrow: "+err.Error())
+	}
+
+	return
+}
+
+// escrowMigrate moves all allocation pools of the transaction client
+// related to an allocation from client's read and write pools to the
+// allocation escrow, including expired ones
+func (ssc *StorageSmartContract) escrowMigrate(t *transaction.Transaction,
+	input []byte, balances cstate.StateContextI) (resp string, err error) {
+
+	var req escrowRequest
+	if err = req.decode(input); err != nil {
+		return "", common.NewError("escrow_migrate_failed", err.Error())
+	}
+
+	var alloc *StorageAllocation
+	if alloc, err = ssc.getAllocation(req.AllocationID, balances); err != nil {
+		return "", common.NewError("escrow_migrate_failed",
+			"can't get allocation: "+err.Error())
+	}
+
+	if alloc.Finalized || alloc.Canceled {
+		return "", common.NewError("escrow_migrate_failed",
+			"allocation is finalized or canceled")
+	}
+
+	var es *escrow
+	if es, err = ssc.getOrCreateEscrow(alloc.ID, balances); err != nil {
+		return "", common.NewError("escrow_migrate_failed",
+			"can't get escrow: "+err.Error())
+	}
+
+	var moved bool
+
+	// read pool
+	var rp *readPool
+	switch rp, err = ssc.getReadPool(t.ClientID, balances); err {
+	case nil:
+		var cut = rp.Pools.allocationCut(alloc.ID)
+		if err = es.migrate(escrowRead, t.ClientID, cut); err != nil {
+			return "", common.NewError("escrow_migrate_failed",
+				"migrating read pools: "+err.Error())
+		}
+		if len(cut) > 0 {
+			rp.removeEmpty(alloc.ID, cut)
+			if err = rp.save(ssc.ID, t.ClientID, balances); err != nil {
+				return "", common.NewError("escrow_migrate_failed",
+					"saving read pool: "+err.Error())
+			}
+			moved = true
+		}
+	case util.ErrValueNotPresent:
+	default:
+		return "", common.NewError("escrow_migrate_failed",
+			"can't get read pool: "+err.Error())
+	}
+
+	// write pool
+	var wp *writePool
+	switch wp, err = ssc.getWritePool(t.ClientID, balances); err {
+	case nil:
+		var cut = wp.Pools.allocationCut(alloc.ID)
+		if err = es.migrate(escrowWrite, t.ClientID, cut); err != nil {
+			return "", common.NewError("escrow_migrate_failed",
+				"migrating write pools: "+err.Error())
+		}
+		if len(cut) > 0 {
+			wp.removeEmpty(alloc.ID, cut)
+			if err = wp.save(ssc.ID, t.ClientID, balances); err != nil {
+				return "", common.NewError("escrow_migrate_failed",
+					"saving write pool: "+err.Error())
+			}
+			moved = true
+		}
+	case util.ErrValueNotPresent:
+	default:
+		return "", common.NewError("escrow_migrate_failed",
+			"can't get write pool: "+err.Error())
+	}
+
+	if !moved {
+		return "", common.NewError("escrow_migrate_failed",
+			"no read or write pools of the allocation to migrate")
+	}
+
+	if err = ssc.saveAllocationEscrow(alloc, es, balances); err != nil {
+		return "", common.NewError("escrow_migrate_failed", err.Error())
+	}
+
+	return toJson(es.stat(alloc)), nil
+}
+
+// writePoolsBalance returns tokens of write pools of an allocation can be
+// used until its end, if any
+func (ssc *StorageSmartContract) writePoolsBalance(alloc *StorageAllocation,
+	balances cstate.StateContextI) (value state.Balance, err error) {
+
+	var wps *allocationWritePools
+	switch wps, err = alloc.getAllocationPools(ssc, balances); err {
+	case nil:
+		return wps.allocUntil(alloc.ID, alloc.Until()), nil
+	case util.ErrValueNotPresent:
+		return 0, nil
+	}
+	return
+}
+
+// escrowWriteBalance returns write sub-balance of allocation escrow, if any
+func (ssc *StorageSmartContract) escrowWriteBalance(alloc *StorageAllocation,
+	balances cstate.StateContextI) (value state.Balance, err error) {
+
+	var es *escrow
+	if es, err = ssc.findEscrow(alloc, balances); err != nil || es == nil {
+		return
+	}
+	return es.Write.Balance, nil
+}
+
+//
+// stat
+//
+
+const cantGetEscrowMsg = "can't get escrow"
+
+// statistic of an allocation escrow; if client_id provided, then only
+// the client is listed in funders
+func (ssc *StorageSmartContract) getEscrowStatHandler(ctx context.Context,
+	params url.Values, balances cstate.StateContextI) (
+	resp interface{}, err error) {
+
+	var (
+		allocID  = params.Get("allocation_id")
+		clientID = params.Get("client_id")
+		alloc    *StorageAllocation
+		es       *escrow
+	)
+
+	if alloc, err = ssc.getAllocation(allocID, balances); err != nil {
+		return nil, smartcontract.NewErrNoResourceOrErrInternal(err, true,
+			cantGetAllocation)
+	}
+
+	if es, err = ssc.getEscrow(allocID, balances); err != nil {
+		return nil, smartcontract.NewErrNoResourceOrErrInternal(err, true,
+			cantGetEscrowMsg)
+	}
+
+	var stat = es.stat(alloc)
+	if clientID != "" {
+		stat.Funders = nil
+		if ef, ok := es.Funders.get(clientID); ok {
+			stat.Funders = escrowFunders{ef}
+		}
+	}
+
+	return stat, nil
+}
@@ -0,0 +1,243 @@
+package storagesc
+
+import (
+	"testing"
+
+	chainState "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/tokenpool"
+	"0chain.net/chaincore/transaction"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (es *escrow) funded(kind string) (total state.Balance) {
+	for _, ef := range es.Funders {
+		total += *ef.share(kind)
+	}
+	return
+}
+
+func Test_escrowFunders_spend(t *testing.T) {
+	var es = newEscrow("alloc_id")
+	es.fund(escrowWrite, "bob", 7)
+	es.fund(escrowWrite, "alice", 3)
+	es.fund(escrowRead, "bob", 5)
+
+	require.Len(t, es.Funders, 2)
+	assert.Equal(t, "alice", es.Funders[0].ClientID)
+	assert.Equal(t, "bob", es.Funders[1].ClientID)
+	assert.EqualValues(t, 10, es.Write.Balance)
+	assert.EqualValues(t, 5, es.Read.Balance)
+
+	// proportional spending, the rest is spent in funders order
+	es.Funders.spend(escrowWrite, 5, es.Write.Balance)
+	es.Write.Balance -= 5
+	assert.EqualValues(t, 1, es.Funders[0].Write)
+	assert.EqualValues(t, 4, es.Funders[1].Write)
+	assert.Equal(t, es.Write.Balance, es.funded(escrowWrite))
+	assert.Equal(t, es.Read.Balance, es.funded(escrowRead))
+}
+
+func Test_escrow_moveChallenge(t *testing.T) {
+	var (
+		es    = newEscrow("alloc_id")
+		cp    = newChallengePool()
+		alloc = new(StorageAllocation)
+	)
+	es.fund(escrowWrite, "alice", 4)
+	es.fund(escrowWrite, "bob", 4)
+
+	require.NoError(t, es.moveToChallenge(cp, "b1", 6))
+	assert.EqualValues(t, 2, es.Write.Balance)
+	assert.EqualValues(t, 6, cp.Balance)
+	assert.EqualValues(t, 6, es.InChallenge)
+	assert.Equal(t, es.Write.Balance, es.funded(escrowWrite))
+	assert.Equal(t, es.InChallenge, es.funded(escrowChallenge))
+
+	// a write pool moved the same amount to the challenge pool, the
+	// escrow gets back a half of the returned tokens
+	cp.Balance += 6
+	alloc.MovedToChallenge = 12
+	var share = es.challengeShare(alloc, 4)
+	assert.EqualValues(t, 2, share)
+
+	require.NoError(t, es.moveFromChallenge(cp, share))
+	assert.EqualValues(t, 4, es.Write.Balance)
+	assert.EqualValues(t, 10, cp.Balance)
+	assert.EqualValues(t, 4, es.InChallenge)
+	// the funders get back their tokens, not the owner
+	assert.EqualValues(t, 2, es.Funders[0].Write)
+	assert.EqualValues(t, 2, es.Funders[1].Write)
+	assert.Equal(t, es.Write.Balance, es.funded(escrowWrite))
+	assert.Equal(t, es.InChallenge, es.funded(escrowChallenge))
+
+	assert.Error(t, es.moveToChallenge(cp, "b1", 5))
+}
+
+func Test_moveBackFromChallenge(t *testing.T) {
+	var (
+		es    = newEscrow("alloc_id")
+		cp    = newChallengePool()
+		wp    = new(writePool)
+		alloc = &StorageAllocation{ID: "alloc_id", Owner: "alice"}
+	)
+	es.fund(escrowWrite, "bob", 4)
+	require.NoError(t, es.moveToChallenge(cp, "b1", 4))
+
+	// the owner's write pool moved the same amount
+	cp.Balance += 4
+	alloc.MovedToChallenge = 8
+
+	require.NoError(t, moveBackFromChallenge(alloc, es, cp, wp, "b1", 10, 6))
+	assert.EqualValues(t, 2, cp.Balance)
+	assert.EqualValues(t, 3, es.Write.Balance)
+	assert.EqualValues(t, 1, es.InChallenge)
+	assert.EqualValues(t, 3, es.Funders[0].Write)
+	require.Len(t, wp.Pools, 1)
+	assert.EqualValues(t, 3, wp.Pools[0].Balance)
+
+	// no escrow, all tokens go to the write pool
+	require.NoError(t, moveBackFromChallenge(alloc, nil, cp, wp, "b1", 10, 2))
+	assert.Zero(t, cp.Balance)
+	assert.EqualValues(t, 5, wp.Pools[0].Balance)
+}
+
+func Test_escrow_paysFor(t *testing.T) {
+	var (
+		es    = newEscrow("alloc_id")
+		alloc = &StorageAllocation{ID: "alloc_id", Owner: "alice"}
+	)
+	es.fund(escrowRead, "bob", 4)
+	assert.True(t, es.paysFor(alloc, "alice"))
+	assert.True(t, es.paysFor(alloc, "bob"))
+	assert.False(t, es.paysFor(alloc, "eve")) // auth ticket reader
+}
+
+func Test_escrow_reserved(t *testing.T) {
+	var (
+		es  = newEscrow("alloc_id")
+		aps = []*allocationPool{
+			{
+				ZcnPool: tokenpool.ZcnPool{TokenPool: tokenpool.TokenPool{
+					ID: "ap", Balance: 10}},
+				Blobbers: blobberPools{
+					{BlobberID: "b1", Balance: 4},
+					{BlobberID: "b2", Balance: 6},
+				},
+			},
+		}
+	)
+	es.fund(escrowWrite, "bob", 5)
+
+	require.NoError(t, es.migrate(escrowWrite, "alice", aps))
+	assert.Zero(t, aps[0].Balance)
+	assert.EqualValues(t, 15, es.Write.Balance)
+	assert.EqualValues(t, 10, es.Funders[0].Write)
+	assert.Len(t, es.WriteBlobbers, 2)
+
+	// tokens reserved for other blobbers can't be used
+	assert.EqualValues(t, 9, es.available(escrowWrite, "b1"))
+	assert.EqualValues(t, 11, es.available(escrowWrite, "b2"))
+	assert.EqualValues(t, 5, es.available(escrowWrite, "b3"))
+
+	var cp = newChallengePool()
+	require.NoError(t, es.moveToChallenge(cp, "b1", 6))
+	assert.EqualValues(t, 9, es.Write.Balance)
+	assert.Len(t, es.WriteBlobbers, 1) // b1 reserve is used
+	assert.EqualValues(t, 3, es.available(escrowWrite, "b3"))
+	assert.EqualValues(t, 9, es.available(escrowWrite, "b2"))
+}
+
+func Test_escrow_settle(t *testing.T) {
+	var (
+		balances = newTestBalances(t, false)
+		es       = newEscrow("alloc_id")
+	)
+	balances.setTransaction(t, &transaction.Transaction{
+		ClientID:   "alice",
+		ToClientID: ADDRESS,
+	})
+	es.fund(escrowRead, "alice", 3)
+	es.fund(escrowWrite, "alice", 5)
+	es.fund(escrowWrite, "bob", 2)
+
+	// only funders can unlock their tokens
+	var _, err = es.unlock(ADDRESS, escrowWrite, "carol", 1, balances)
+	require.Error(t, err)
+	_, err = es.unlock(ADDRESS, escrowWrite, "alice", 6, balances)
+	require.Error(t, err)
+	_, err = es.unlock(ADDRESS, escrowWrite, "alice", 1, balances)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, balances.balances["alice"])
+
+	require.NoError(t, es.settle(ADDRESS, balances))
+	assert.True(t, es.Settled)
+	assert.EqualValues(t, 8, balances.balances["alice"])
+	assert.EqualValues(t, 2, balances.balances["bob"])
+	assert.Zero(t, es.Read.Balance)
+	assert.Zero(t, es.Write.Balance)
+	assert.Zero(t, es.funded(escrowRead)+es.funded(escrowWrite))
+}
+
+func Test_escrow_Encode_Decode(t *testing.T) {
+	var es = newEscrow("alloc_id")
+	es.fund(escrowRead, "alice", 3)
+
+	var got = new(escrow)
+	require.NoError(t, got.Decode(es.Encode()))
+	assert.Equal(t, es, got)
+}
+
+func Test_escrow_writePools(t *testing.T) {
+	var (
+		ssc      = newTestStorageSC()
+		balances = newTestBalances(t, false)
+		client   = newClient(100*x10, balances)
+		now      = int64(10)
+		err      error
+	)
+
+	var allocID, _ = addAllocation(t, ssc, client, now, now+3600, 0,
+		balances)
+
+	var call = func(f func(*transaction.Transaction, []byte,
+		chainState.StateContextI) (string, error), value int64,
+		req *escrowRequest) (err error) {
+
+		var tx = newTransaction(client.id, ADDRESS, value, now)
+		balances.setTransaction(t, tx)
+		_, err = f(tx, mustEncode(t, req), balances)
+		return
+	}
+
+	var req = &escrowRequest{AllocationID: allocID, Kind: escrowWrite}
+	require.NoError(t, call(ssc.escrowLock, 1*x10, req))
+
+	var alloc *StorageAllocation
+	alloc, err = ssc.getAllocation(allocID, balances)
+	require.NoError(t, err)
+	require.True(t, alloc.HasEscrow)
+	require.NotZero(t, alloc.restMinLockDemand())
+
+	// the write pool covers min lock demand
+	require.NoError(t, call(ssc.escrowUnlock, 0, req))
+
+	// migrate the write pool keeping its tokens reserved for blobbers
+	require.NoError(t, call(ssc.escrowMigrate, 0, req))
+	var es *escrow
+	es, err = ssc.getEscrow(allocID, balances)
+	require.NoError(t, err)
+	assert.EqualValues(t, 15*x10, es.Write.Balance)
+	assert.EqualValues(t, 15*x10, es.funded(escrowWrite))
+	assert.Len(t, es.WriteBlobbers, len(alloc.BlobberDetails))
+
+	var wp *writePool
+	wp, err = ssc.getWritePool(client.id, balances)
+	require.NoError(t, err)
+	assert.Zero(t, wp.Pools.allocUntil(allocID, alloc.Until()))
+
+	// nothing covers min lock demand anymore
+	assert.Error(t, call(ssc.escrowUnlock, 0, req))
+}
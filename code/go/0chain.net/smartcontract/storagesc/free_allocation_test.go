@@ -745,6 +745,10 @@ func TestUpdateFreeStorageRequest(t *testing.T) {
 			}),
 		).Return("", nil).Once()
 
+		balances.On(
+			"EmitEvent", allocationEventType, allocationExtendedTag, mock.Anything,
+		).Return().Once()
+
 		return args{ssc, txn, input, balances}
 	}
 
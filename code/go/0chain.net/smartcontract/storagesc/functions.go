@@ -11,6 +11,7 @@ import (
 type payment struct {
 	to     datastore.Key
 	amount state.Balance
+	pool   *delegatePool // reinvest to the pool, if set
 }
 
 func transferReward(
@@ -25,11 +26,31 @@ func transferReward(
 			zcnPool.ID, zcnPool.Balance, value)
 	}
 
+	return drainReward(sscKey, &zcnPool, sp, value, balances)
+}
+
+// drainReward is the transferReward that drains given pool
+func drainReward(
+	sscKey string,
+	zcnPool *tokenpool.ZcnPool,
+	sp *stakePool,
+	value state.Balance,
+	balances cstate.StateContextI,
+) (state.Balance, error) {
 	payments, moved, err := getPayments(sp, float64(value))
 	if err != nil {
 		return 0, err
 	}
 	for _, payment := range payments {
+		if payment.pool != nil {
+			_, _, err = zcnPool.TransferTo(payment.pool, payment.amount, nil)
+			if err != nil {
+				return 0, fmt.Errorf("transferring tokens challenge_pool(%s) -> "+
+					"delegate_pool(%s): %v", zcnPool.ID, payment.pool.ID, err)
+			}
+			payment.pool.Compounded += payment.amount
+			continue
+		}
 		var transfer *state.Transfer
 		transfer, _, err = zcnPool.DrainPool(sscKey, payment.to, payment.amount, nil)
 		if err != nil {
@@ -53,13 +74,20 @@ func mintReward(
 		return err
 	}
 	for _, payment := range payments {
+		var to = payment.to // delegate wallet
+		if payment.pool != nil {
+			to = ADDRESS // reinvested tokens are kept by the storage SC
+		}
 		if err := balances.AddMint(&state.Mint{
 			Minter:     ADDRESS,        // storage SC
-			ToClientID: payment.to,     // delegate wallet
+			ToClientID: to,             // delegate wallet or storage SC
 			Amount:     payment.amount, // move total mints at once
 		}); err != nil {
 			return fmt.Errorf("minting rewards: %v", err)
 		}
+		if payment.pool != nil {
+			sp.compound(payment.pool, payment.amount)
+		}
 	}
 	return nil
 }
@@ -107,13 +135,26 @@ func getPayments(sp *stakePool, value float64) ([]payment, float64, error) {
 			continue
 		}
 
-		payments = append(payments, payment{
-			to:     dp.DelegateID,
-			amount: state.Balance(move),
-		})
+		var (
+			reward   = state.Balance(move)
+			reinvest = sp.compoundable(dp, reward)
+		)
+		if reinvest > 0 {
+			payments = append(payments, payment{
+				to:     dp.DelegateID,
+				amount: reinvest,
+				pool:   dp,
+			})
+		}
+		if reward > reinvest {
+			payments = append(payments, payment{
+				to:     dp.DelegateID,
+				amount: reward - reinvest,
+			})
+		}
 
 		// stat
-		dp.Rewards += state.Balance(move)
+		dp.Rewards += reward
 		moved += move
 	}
 	return payments, moved, nil
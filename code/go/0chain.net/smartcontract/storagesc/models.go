@@ -570,6 +570,9 @@ type StorageAllocation struct {
 
 	//AllocationPools allocationPools `json:"allocation_pools"`
 	WritePoolOwners []string `json:"write_pool_owners"`
+	// HasEscrow is true if tokens has been locked for the allocation in its
+	// escrow; allocations without escrow don't read it.
+	HasEscrow bool `json:"has_escrow,omitempty"`
 
 	// ChallengeCompletionTime is max challenge completion time of
 	// all blobbers of the allocation.
@@ -36,6 +36,18 @@ func (rp *readPool) blobberCut(allocID, blobberID string, now common.Timestamp,
 	return rp.Pools.blobberCut(allocID, blobberID, now)
 }
 
+// blobberBalance returns tokens of the read pool the blobber can be paid
+func (rp *readPool) blobberBalance(allocID, blobberID string,
+	now common.Timestamp) (value state.Balance) {
+
+	for _, ap := range rp.blobberCut(allocID, blobberID, now) {
+		if bp, ok := ap.Blobbers.get(blobberID); ok {
+			value += bp.Balance
+		}
+	}
+	return
+}
+
 func (rp *readPool) removeEmpty(allocID string, ap []*allocationPool) {
 	rp.Pools.removeEmpty(allocID, ap)
 }
@@ -116,15 +128,26 @@ func (rp *readPool) movePartToBlobber(sscKey string, ap *allocationPool,
 
 		var (
 			move     = state.Balance(float64(value) * ratio)
+			reinvest = sp.compoundable(dp, move)
 			transfer *state.Transfer
 		)
-		transfer, _, err = ap.DrainPool(sscKey, dp.DelegateID, move, nil)
-		if err != nil {
-			return fmt.Errorf("transferring tokens read_pool() -> "+
-				"stake_pool_holder(%s): %v", dp.DelegateID, err)
+		if reinvest > 0 {
+			if _, _, err = ap.TransferTo(dp, reinvest, nil); err != nil {
+				return fmt.Errorf("transferring tokens read_pool() -> "+
+					"delegate_pool(%s): %v", dp.ID, err)
+			}
+			dp.Compounded += reinvest
 		}
-		if err = balances.AddTransfer(transfer); err != nil {
-			return fmt.Errorf("adding transfer: %v", err)
+		if move > reinvest {
+			transfer, _, err = ap.DrainPool(sscKey, dp.DelegateID,
+				move-reinvest, nil)
+			if err != nil {
+				return fmt.Errorf("transferring tokens read_pool() -> "+
+					"stake_pool_holder(%s): %v", dp.DelegateID, err)
+			}
+			if err = balances.AddTransfer(transfer); err != nil {
+				return fmt.Errorf("adding transfer: %v", err)
+			}
 		}
 		// stat
 		dp.Rewards += move         // add to stake_pool_holder rewards
@@ -151,18 +174,15 @@ func toJson(val interface{}) string {
 
 func (rp *readPool) moveToBlobber(sscKey, allocID, blobID string,
 	sp *stakePool, now common.Timestamp, value state.Balance,
-	balances cstate.StateContextI) (resp string, err error) {
+	balances cstate.StateContextI) (redeems []readPoolRedeem, err error) {
 
 	var cut = rp.blobberCut(allocID, blobID, now)
 
 	if len(cut) == 0 {
-		return "", fmt.Errorf("no tokens in read pool for allocation: %s,"+
+		return nil, fmt.Errorf("no tokens in read pool for allocation: %s,"+
 			" blobber: %s", allocID, blobID)
 	}
 
-	// all redeems to response at the end
-	var redeems []readPoolRedeem
-
 	var torm []*allocationPool // to remove later (empty allocation pools)
 	for _, ap := range cut {
 		if value == 0 {
@@ -203,7 +223,7 @@ func (rp *readPool) moveToBlobber(sscKey, allocID, blobID string,
 	}
 
 	if value != 0 {
-		return "", fmt.Errorf("not enough tokens in read pool for "+
+		return nil, fmt.Errorf("not enough tokens in read pool for "+
 			"allocation: %s, blobber: %s", allocID, blobID)
 	}
 
@@ -211,7 +231,7 @@ func (rp *readPool) moveToBlobber(sscKey, allocID, blobID string,
 	rp.removeEmpty(allocID, torm)
 
 	// return the read redeems for blobbers read pools cache
-	return toJson(redeems), nil // ok
+	return redeems, nil // ok
 }
 
 // take read pool by ID to unlock (the take is get and remove)
@@ -50,7 +50,9 @@ func (ssc *StorageSmartContract) setSC(sc *sci.SmartContract, bcContext sci.BCCo
 	ssc.SmartContract.RestHandlers["/get_mpt_key"] = ssc.GetMptKey
 	// sc configurations
 	ssc.SmartContract.RestHandlers["/getConfig"] = ssc.getConfigHandler
+	ssc.SmartContract.RestHandlers["/getPendingSettings"] = ssc.getPendingSettingsHandler
 	ssc.SmartContractExecutionStats["update_settings"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "update_settings"), nil)
+	ssc.SmartContractExecutionStats["cancel_settings_changes"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "cancel_settings_changes"), nil)
 	// reading / writing
 	ssc.SmartContract.RestHandlers["/latestreadmarker"] = ssc.LatestReadMarkerHandler
 	ssc.SmartContractExecutionStats["read_redeem"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "read_redeem"), nil)
@@ -59,6 +61,7 @@ func (ssc *StorageSmartContract) setSC(sc *sci.SmartContract, bcContext sci.BCCo
 	ssc.SmartContract.RestHandlers["/allocation"] = ssc.AllocationStatsHandler
 	ssc.SmartContract.RestHandlers["/allocations"] = ssc.GetAllocationsHandler
 	ssc.SmartContract.RestHandlers["/allocation_min_lock"] = ssc.GetAllocationMinLockHandler
+	ssc.SmartContract.RestHandlers["/expiring_allocations"] = ssc.getExpiringAllocationsHandler
 	ssc.SmartContractExecutionStats["new_allocation_request"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "new_allocation_request"), nil)
 	ssc.SmartContractExecutionStats["update_allocation_request"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "update_allocation_request"), nil)
 	ssc.SmartContractExecutionStats["finalize_allocation"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "finalize_allocation"), nil)
@@ -73,6 +76,12 @@ func (ssc *StorageSmartContract) setSC(sc *sci.SmartContract, bcContext sci.BCCo
 	ssc.SmartContractExecutionStats["challenge_request"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "challenge_request"), nil)
 	ssc.SmartContractExecutionStats["challenge_response"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "challenge_response"), nil)
 	ssc.SmartContractExecutionStats["generate_challenges"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "generate_challenges"), nil)
+	// aggregate proofs
+	ssc.SmartContract.RestHandlers["/getBlobberAggregate"] = ssc.getBlobberAggregateHandler
+	ssc.SmartContract.RestHandlers["/getAggregateChallenge"] = ssc.getAggregateChallengeHandler
+	ssc.SmartContractExecutionStats["commit_blobber_aggregate"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "commit_blobber_aggregate"), nil)
+	ssc.SmartContractExecutionStats["generate_aggregate_challenges"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "generate_aggregate_challenges"), nil)
+	ssc.SmartContractExecutionStats["aggregate_challenge_response"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "aggregate_challenge_response"), nil)
 	// validator
 	ssc.SmartContractExecutionStats["add_validator"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "add_validator (add/update SC function)"), nil)
 	// validators stat (not function calls)
@@ -101,12 +110,22 @@ func (ssc *StorageSmartContract) setSC(sc *sci.SmartContract, bcContext sci.BCCo
 	ssc.SmartContract.RestHandlers["/getWritePoolAllocBlobberStat"] = ssc.getWritePoolAllocBlobberStatHandler
 	ssc.SmartContractExecutionStats["write_pool_lock"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "write_pool_lock"), nil)
 	ssc.SmartContractExecutionStats["write_pool_unlock"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "write_pool_unlock"), nil)
+	// allocation escrow
+	ssc.SmartContract.RestHandlers["/getEscrowStat"] = ssc.getEscrowStatHandler
+	ssc.SmartContractExecutionStats["escrow_lock"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "escrow_lock"), nil)
+	ssc.SmartContractExecutionStats["escrow_unlock"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "escrow_unlock"), nil)
+	ssc.SmartContractExecutionStats["escrow_migrate"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "escrow_migrate"), nil)
+	// priced share offers
+	ssc.SmartContract.RestHandlers["/getShareOffers"] = ssc.getShareOffersHandler
+	ssc.SmartContractExecutionStats["add_share_offer"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "add_share_offer"), nil)
+	ssc.SmartContractExecutionStats["remove_share_offer"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "remove_share_offer"), nil)
 	// stake pool
 	ssc.SmartContract.RestHandlers["/getStakePoolStat"] = ssc.getStakePoolStatHandler
 	ssc.SmartContract.RestHandlers["/getUserStakePoolStat"] = ssc.getUserStakePoolStatHandler
 	ssc.SmartContractExecutionStats["stake_pool_lock"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "stake_pool_lock"), nil)
 	ssc.SmartContractExecutionStats["stake_pool_unlock"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "stake_pool_unlock"), nil)
 	ssc.SmartContractExecutionStats["stake_pool_pay_interests"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "stake_pool_pay_interests"), nil)
+	ssc.SmartContractExecutionStats["stake_pool_set_auto_compound"] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", ssc.ID, "stake_pool_set_auto_compound"), nil)
 	// challenge pool
 	ssc.SmartContract.RestHandlers["/getChallengePoolStat"] = ssc.getChallengePoolStatHandler
 }
@@ -260,6 +279,22 @@ func (sc *StorageSmartContract) Execute(t *transaction.Transaction,
 	case "write_pool_unlock":
 		resp, err = sc.writePoolUnlock(t, input, balances)
 
+	// allocation escrow
+
+	case "escrow_lock":
+		resp, err = sc.escrowLock(t, input, balances)
+	case "escrow_unlock":
+		resp, err = sc.escrowUnlock(t, input, balances)
+	case "escrow_migrate":
+		resp, err = sc.escrowMigrate(t, input, balances)
+
+	// priced share offers
+
+	case "add_share_offer":
+		resp, err = sc.addShareOffer(t, input, balances)
+	case "remove_share_offer":
+		resp, err = sc.removeShareOffer(t, input, balances)
+
 		// stake pool
 
 	case "stake_pool_lock":
@@ -268,6 +303,8 @@ func (sc *StorageSmartContract) Execute(t *transaction.Transaction,
 		resp, err = sc.stakePoolUnlock(t, input, balances)
 	case "stake_pool_pay_interests":
 		resp, err = sc.stakePoolPayInterests(t, input, balances)
+	case "stake_pool_set_auto_compound":
+		resp, err = sc.stakePoolSetAutoCompound(t, input, balances)
 
 	case "generate_challenges":
 		challengesEnabled := config.SmartContractConfig.GetBool(
@@ -285,6 +322,15 @@ func (sc *StorageSmartContract) Execute(t *transaction.Transaction,
 	case "challenge_response":
 		resp, err = sc.verifyChallenge(t, input, balances)
 
+	// aggregate proofs
+
+	case "commit_blobber_aggregate":
+		resp, err = sc.commitBlobberAggregate(t, input, balances)
+	case "generate_aggregate_challenges":
+		resp, err = sc.generateAggregateChallenges(t, input, balances)
+	case "aggregate_challenge_response":
+		resp, err = sc.aggregateChallengeResponse(t, input, balances)
+
 	// configurations
 
 	case "update_settings":
@@ -293,6 +339,9 @@ func (sc *StorageSmartContract) Execute(t *transaction.Transaction,
 	case "commit_settings_changes":
 		resp, err = sc.commitSettingChanges(t, input, balances)
 
+	case "cancel_settings_changes":
+		resp, err = sc.cancelSettingChanges(t, input, balances)
+
 	default:
 		err = common.NewErrorf("invalid_storage_function_name",
 			"Invalid storage function '%s' called", funcName)
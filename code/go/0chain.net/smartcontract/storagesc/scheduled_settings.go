@@ -0,0 +1,191 @@
+package storagesc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	chainState "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+	"0chain.net/smartcontract"
+)
+
+var scheduledSettingsKey = datastore.Key(ADDRESS +
+	encryption.Hash("scheduled_setting_changes"))
+
+func getScheduledSettings(balances chainState.StateContextI) (
+	scheduled *smartcontract.ScheduledChanges, err error) {
+
+	var val util.Serializable
+	val, err = balances.GetTrieNode(scheduledSettingsKey)
+	if err == util.ErrValueNotPresent {
+		return new(smartcontract.ScheduledChanges), nil
+	}
+	if err != nil {
+		return
+	}
+
+	scheduled = new(smartcontract.ScheduledChanges)
+	if err = scheduled.Decode(val.Encode()); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return
+}
+
+// IsSettingsActivationRound returns true if there are time-locked settings
+// changes of the storage SC should be activated at given round. The
+// changes are activated by the commit_settings_changes SC function.
+func IsSettingsActivationRound(clientState util.MerklePatriciaTrieI,
+	round int64) bool {
+
+	if clientState == nil {
+		return false
+	}
+
+	var val, err = clientState.GetNodeValue(
+		util.Path(encryption.Hash(scheduledSettingsKey)))
+	if err != nil {
+		return false
+	}
+
+	var scheduled smartcontract.ScheduledChanges
+	if err = scheduled.Decode(val.Encode()); err != nil {
+		return false
+	}
+	return scheduled.IsDue(round)
+}
+
+// scheduleSettingChanges validates and queues time-locked changes
+func (ssc *StorageSmartContract) scheduleSettingChanges(
+	t *transaction.Transaction,
+	conf *scConfig,
+	req *smartcontract.ScheduleRequest,
+	balances chainState.StateContextI,
+) (resp string, err error) {
+
+	var round = balances.GetBlock().Round
+	var activation int64
+	activation, err = req.Activation(round, conf.SettingsTimeLock)
+	if err != nil {
+		return "", common.NewError("update_settings", err.Error())
+	}
+
+	var check = new(scConfig)
+	if err = check.Decode(conf.Encode()); err != nil {
+		return "", common.NewError("update_settings", err.Error())
+	}
+	var changes = smartcontract.StringMap{Fields: req.Fields}
+	if err = check.update(changes); err != nil {
+		return "", common.NewError("update_settings", err.Error())
+	}
+	if err = check.validate(); err != nil {
+		return "", common.NewError("update_settings", err.Error())
+	}
+
+	var scheduled *smartcontract.ScheduledChanges
+	if scheduled, err = getScheduledSettings(balances); err != nil {
+		return "", common.NewError("update_settings",
+			"can't get scheduled changes: "+err.Error())
+	}
+
+	var sc = &smartcontract.ScheduledChange{
+		ID:              t.Hash,
+		ActivationRound: activation,
+		Fields:          req.Fields,
+	}
+	if err = scheduled.Add(sc); err != nil {
+		return "", common.NewError("update_settings", err.Error())
+	}
+
+	_, err = balances.InsertTrieNode(scheduledSettingsKey, scheduled)
+	if err != nil {
+		return "", common.NewError("update_settings", err.Error())
+	}
+
+	return toJson(sc), nil
+}
+
+// activateScheduledSettings applies given due changes to the configurations,
+// changes became invalid since they have been scheduled are skipped; the
+// activated settings are removed from the not time-locked changes
+func activateScheduledSettings(conf *scConfig,
+	changes *smartcontract.StringMap,
+	due []*smartcontract.ScheduledChange) (err error) {
+
+	for _, sc := range due {
+		var next = new(scConfig)
+		if err = next.Decode(conf.Encode()); err != nil {
+			return
+		}
+		var fields = smartcontract.StringMap{Fields: sc.Fields}
+		if next.update(fields) != nil || next.validate() != nil {
+			continue // drop invalid changes
+		}
+		*conf = *next
+		for key := range sc.Fields {
+			delete(changes.Fields, key)
+		}
+	}
+	return
+}
+
+// cancelSettingChanges removes time-locked changes before their activation
+func (ssc *StorageSmartContract) cancelSettingChanges(
+	t *transaction.Transaction,
+	input []byte,
+	balances chainState.StateContextI,
+) (resp string, err error) {
+	if !smartcontract.CanUpdateSettings(t.ClientID) {
+		return "", common.NewError("cancel_settings_changes",
+			"unauthorized access - only the governance can update the variables")
+	}
+
+	var req smartcontract.CancelScheduleRequest
+	if err = req.Decode(input); err != nil {
+		return "", common.NewError("cancel_settings_changes", err.Error())
+	}
+
+	var scheduled *smartcontract.ScheduledChanges
+	if scheduled, err = getScheduledSettings(balances); err != nil {
+		return "", common.NewError("cancel_settings_changes",
+			"can't get scheduled changes: "+err.Error())
+	}
+
+	if !scheduled.Cancel(req.ID) {
+		return "", common.NewError("cancel_settings_changes",
+			"no scheduled changes found: "+req.ID)
+	}
+
+	_, err = balances.InsertTrieNode(scheduledSettingsKey, scheduled)
+	if err != nil {
+		return "", common.NewError("cancel_settings_changes", err.Error())
+	}
+
+	return "", nil
+}
+
+// getPendingSettingsHandler returns time-locked settings changes with
+// diff against current configurations
+func (ssc *StorageSmartContract) getPendingSettingsHandler(
+	ctx context.Context,
+	params url.Values,
+	balances chainState.StateContextI,
+) (resp interface{}, err error) {
+	var conf interface{}
+	if conf, err = ssc.getConfigHandler(ctx, params, balances); err != nil {
+		return
+	}
+	var current = conf.(smartcontract.StringMap)
+
+	var scheduled *smartcontract.ScheduledChanges
+	if scheduled, err = getScheduledSettings(balances); err != nil {
+		return nil, smartcontract.NewErrNoResourceOrErrInternal(err, true,
+			"can't get scheduled changes")
+	}
+
+	return scheduled.Preview(current.Fields), nil
+}
@@ -0,0 +1,111 @@
+package storagesc
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+
+	"0chain.net/chaincore/block"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/datastore"
+	"0chain.net/smartcontract"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_scheduledSettings(t *testing.T) {
+	var (
+		ssc      = newTestStorageSC()
+		balances = newTestBalances(t, false)
+		conf     = setConfig(t, balances)
+		err      error
+	)
+
+	conf.FreeAllocationSettings.Duration = time.Hour
+	conf.BlockReward = new(blockReward)
+	conf.SettingsTimeLock = 10
+	mustSave(t, scConfigKey(ADDRESS), conf, balances)
+
+	var setRound = func(round int64) {
+		balances.block = new(block.Block)
+		balances.block.Round = round
+	}
+
+	var schedule = func(hash string, activation int64, fields map[string]string) (
+		string, error) {
+
+		var input, err = json.Marshal(&smartcontract.ScheduleRequest{
+			ActivationRound: activation,
+			Fields:          fields,
+		})
+		require.NoError(t, err)
+		return ssc.updateSettings(&transaction.Transaction{
+			HashIDField: datastore.HashIDField{Hash: hash},
+			ClientID:    smartcontract.GovernanceAddress,
+		}, input, balances)
+	}
+
+	setRound(100)
+
+	// violates time lock
+	_, err = schedule("one", 105, map[string]string{"max_delegates": "50"})
+	require.Error(t, err)
+	// invalid changes
+	_, err = schedule("one", 0, map[string]string{"max_delegates": "0"})
+	require.Error(t, err)
+
+	_, err = schedule("one", 0, map[string]string{"max_delegates": "50"})
+	require.NoError(t, err)
+	_, err = schedule("two", 120, map[string]string{"blobber_slash": "0.2"})
+	require.NoError(t, err)
+
+	var resp interface{}
+	resp, err = ssc.getPendingSettingsHandler(context.Background(),
+		url.Values{}, balances)
+	require.NoError(t, err)
+	var pending = resp.([]*smartcontract.PendingChange)
+	require.Len(t, pending, 2)
+	assert.Equal(t, "one", pending[0].ID)
+	assert.EqualValues(t, 110, pending[0].ActivationRound)
+	assert.Equal(t, []smartcontract.SettingDiff{
+		{Name: "max_delegates", Current: "200", Pending: "50"},
+	}, pending[0].Diff)
+
+	var commit = func(round int64) *scConfig {
+		setRound(round)
+		_, err := ssc.commitSettingChanges(&transaction.Transaction{}, nil,
+			balances)
+		require.NoError(t, err)
+		conf, err := ssc.getConfig(balances, false)
+		require.NoError(t, err)
+		return conf
+	}
+
+	conf = commit(109)
+	assert.Equal(t, 200, conf.MaxDelegates)
+	assert.False(t, IsSettingsActivationRound(nil, 110))
+
+	conf = commit(110)
+	assert.Equal(t, 50, conf.MaxDelegates)
+	assert.Equal(t, 0.1, conf.BlobberSlash)
+
+	// cancel
+	var cancel = func(id string) error {
+		var input, err = json.Marshal(&smartcontract.CancelScheduleRequest{
+			ID: id,
+		})
+		require.NoError(t, err)
+		_, err = ssc.cancelSettingChanges(&transaction.Transaction{
+			ClientID: smartcontract.GovernanceAddress,
+		}, input, balances)
+		return err
+	}
+	require.Error(t, cancel("one"))
+	require.NoError(t, cancel("two"))
+
+	conf = commit(120)
+	assert.Equal(t, 0.1, conf.BlobberSlash)
+}
@@ -0,0 +1,497 @@
+package storagesc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+
+	"0chain.net/smartcontract"
+
+	cstate "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+)
+
+//
+// priced share offers (paid auth tickets)
+//
+
+func shareOffersKey(scKey, allocID string) datastore.Key {
+	return datastore.Key(scKey + ":share_offers:" + allocID)
+}
+
+func sharePurchaseKey(scKey, allocID, filePathHash,
+	clientID string) datastore.Key {
+
+	return datastore.Key(scKey + ":share_purchase:" +
+		encryption.Hash(allocID+":"+filePathHash+":"+clientID))
+}
+
+// A shareOffer is price of reading shared data of an allocation. An offer
+// with empty file path hash covers entire allocation. A reader pays the
+// flat fee once and the per-GB price for every read redeemed.
+type shareOffer struct {
+	FilePathHash string           `json:"file_path_hash"`
+	PricePerGB   state.Balance    `json:"price_per_gb"`
+	FlatFee      state.Balance    `json:"flat_fee"`
+	Created      common.Timestamp `json:"created"`
+	Revenue      state.Balance    `json:"revenue"` // stat
+}
+
+// fee for given size read, in GB; the flat fee is included for readers
+// not paid it yet
+func (so *shareOffer) fee(sizeRead float64, purchased bool) (
+	fee state.Balance) {
+
+	fee = state.Balance(float64(so.PricePerGB) * sizeRead)
+	if !purchased {
+		fee += so.FlatFee
+	}
+	return
+}
+
+// shareOffers of an allocation sorted by file path hash
+type shareOffers struct {
+	AllocationID string        `json:"allocation_id"`
+	Offers       []*shareOffer `json:"offers"`
+}
+
+func newShareOffers(allocID string) *shareOffers {
+	return &shareOffers{AllocationID: allocID}
+}
+
+func (sos *shareOffers) Encode() []byte {
+	var b, err = json.Marshal(sos)
+	if err != nil {
+		panic(err) // must not happen
+	}
+	return b
+}
+
+func (sos *shareOffers) Decode(p []byte) error {
+	return json.Unmarshal(p, sos)
+}
+
+func (sos *shareOffers) save(sscKey string,
+	balances cstate.StateContextI) (err error) {
+
+	_, err = balances.InsertTrieNode(shareOffersKey(sscKey, sos.AllocationID),
+		sos)
+	return
+}
+
+func (sos *shareOffers) getIndex(filePathHash string) (i int, ok bool) {
+	i = sort.Search(len(sos.Offers), func(i int) bool {
+		return sos.Offers[i].FilePathHash >= filePathHash
+	})
+	ok = i < len(sos.Offers) && sos.Offers[i].FilePathHash == filePathHash
+	return
+}
+
+// set adds or replaces offer for its file path hash
+func (sos *shareOffers) set(so *shareOffer) {
+	var i, ok = sos.getIndex(so.FilePathHash)
+	if ok {
+		sos.Offers[i] = so
+		return
+	}
+	sos.Offers = append(sos.Offers, nil)
+	copy(sos.Offers[i+1:], sos.Offers[i:])
+	sos.Offers[i] = so
+}
+
+func (sos *shareOffers) remove(filePathHash string) (ok bool) {
+	var i int
+	if i, ok = sos.getIndex(filePathHash); ok {
+		sos.Offers = append(sos.Offers[:i], sos.Offers[i+1:]...)
+	}
+	return
+}
+
+// match returns offer of given file path hash or offer of entire
+// allocation, if any
+func (sos *shareOffers) match(filePathHash string) *shareOffer {
+	if i, ok := sos.getIndex(filePathHash); ok {
+		return sos.Offers[i]
+	}
+	if i, ok := sos.getIndex(""); ok {
+		return sos.Offers[i]
+	}
+	return nil
+}
+
+// A sharePurchase is payments of a reader for a share offer.
+type sharePurchase struct {
+	AllocationID string           `json:"allocation_id"`
+	FilePathHash string           `json:"file_path_hash"`
+	ClientID     string           `json:"client_id"`
+	Paid         state.Balance    `json:"paid"`
+	Created      common.Timestamp `json:"created"`
+}
+
+func (sp *sharePurchase) Encode() []byte {
+	var b, err = json.Marshal(sp)
+	if err != nil {
+		panic(err) // must not happen
+	}
+	return b
+}
+
+func (sp *sharePurchase) Decode(p []byte) error {
+	return json.Unmarshal(p, sp)
+}
+
+func (sp *sharePurchase) save(sscKey string,
+	balances cstate.StateContextI) (err error) {
+
+	_, err = balances.InsertTrieNode(sharePurchaseKey(sscKey,
+		sp.AllocationID, sp.FilePathHash, sp.ClientID), sp)
+	return
+}
+
+// moveToOwner moves given value from read pool of an allocation blobber
+// to the allocation owner
+func (rp *readPool) moveToOwner(sscKey, allocID, blobID, ownerID string,
+	now common.Timestamp, value state.Balance,
+	balances cstate.StateContextI) (redeems []readPoolRedeem, err error) {
+
+	var cut = rp.blobberCut(allocID, blobID, now)
+
+	var torm []*allocationPool // to remove later (empty allocation pools)
+	for _, ap := range cut {
+		if value == 0 {
+			break // all required tokens has moved to the owner
+		}
+		var bi, ok = ap.Blobbers.getIndex(blobID)
+		if !ok {
+			continue // impossible case, but leave the check here
+		}
+		var (
+			bp       = ap.Blobbers[bi]
+			move     state.Balance
+			transfer *state.Transfer
+		)
+		if value >= bp.Balance {
+			move, bp.Balance = bp.Balance, 0
+		} else {
+			move, bp.Balance = value, bp.Balance-value
+		}
+
+		if transfer, _, err = ap.DrainPool(sscKey, ownerID, move, nil); err != nil {
+			return nil, fmt.Errorf("transferring tokens read_pool() -> "+
+				"allocation_owner(%s): %v", ownerID, err)
+		}
+		if err = balances.AddTransfer(transfer); err != nil {
+			return nil, fmt.Errorf("adding transfer: %v", err)
+		}
+
+		redeems = append(redeems, readPoolRedeem{
+			PoolID:  ap.ID,
+			Balance: move,
+		})
+
+		value -= move
+		if bp.Balance == 0 {
+			ap.Blobbers.removeByIndex(bi)
+		}
+		if ap.Balance == 0 {
+			torm = append(torm, ap) // remove the allocation pool later
+		}
+	}
+
+	if value != 0 {
+		return nil, fmt.Errorf("not enough tokens in read pool for "+
+			"allocation: %s, blobber: %s", allocID, blobID)
+	}
+
+	rp.removeEmpty(allocID, torm)
+	return
+}
+
+func (ssc *StorageSmartContract) getShareOffers(allocID string,
+	balances cstate.StateContextI) (sos *shareOffers, err error) {
+
+	var val util.Serializable
+	if val, err = balances.GetTrieNode(shareOffersKey(ssc.ID, allocID)); err != nil {
+		return
+	}
+	sos = newShareOffers(allocID)
+	if err = sos.Decode(val.Encode()); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return
+}
+
+// findShareOffers returns nil, if an allocation has no share offers
+func (ssc *StorageSmartContract) findShareOffers(allocID string,
+	balances cstate.StateContextI) (sos *shareOffers, err error) {
+
+	sos, err = ssc.getShareOffers(allocID, balances)
+	if err == util.ErrValueNotPresent {
+		return nil, nil
+	}
+	return
+}
+
+func (ssc *StorageSmartContract) getSharePurchase(allocID, filePathHash,
+	clientID string, balances cstate.StateContextI) (
+	sp *sharePurchase, err error) {
+
+	var val util.Serializable
+	val, err = balances.GetTrieNode(sharePurchaseKey(ssc.ID, allocID,
+		filePathHash, clientID))
+	if err != nil {
+		return
+	}
+	sp = new(sharePurchase)
+	if err = sp.Decode(val.Encode()); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return
+}
+
+// A shareCharge is fee of a share offer a reader pays with a read.
+type shareCharge struct {
+	offers   *shareOffers
+	offer    *shareOffer
+	purchase *sharePurchase // nil for first purchase
+	fee      state.Balance
+}
+
+// shareOfferCharge returns fee of a share offer related to auth ticket of
+// given read marker or nil, if the read is free
+func (ssc *StorageSmartContract) shareOfferCharge(alloc *StorageAllocation,
+	rm *ReadMarker, sizeRead float64, balances cstate.StateContextI) (
+	charge *shareCharge, err error) {
+
+	if rm.PayerID == alloc.Owner || rm.AuthTicket == nil {
+		return // owner reads its data for free
+	}
+
+	var sos *shareOffers
+	if sos, err = ssc.findShareOffers(alloc.ID, balances); err != nil {
+		return nil, fmt.Errorf("can't get share offers: %v", err)
+	}
+	if sos == nil {
+		return // not priced
+	}
+
+	var so = sos.match(rm.AuthTicket.FilePathHash)
+	if so == nil {
+		return // not priced
+	}
+
+	var sp *sharePurchase
+	sp, err = ssc.getSharePurchase(alloc.ID, so.FilePathHash, rm.PayerID,
+		balances)
+	switch err {
+	case nil:
+	case util.ErrValueNotPresent:
+		sp, err = nil, nil
+	default:
+		return nil, fmt.Errorf("can't get share purchase: %v", err)
+	}
+
+	var fee = so.fee(sizeRead, sp != nil)
+	if fee == 0 {
+		return
+	}
+	return &shareCharge{offers: sos, offer: so, purchase: sp, fee: fee}, nil
+}
+
+// payShareOffer moves fee of a share offer from given read pool of the
+// reader to the allocation owner; the fee is charged with the read, the
+// caller saves the read pool
+func (ssc *StorageSmartContract) payShareOffer(alloc *StorageAllocation,
+	rm *ReadMarker, rp *readPool, charge *shareCharge, now common.Timestamp,
+	balances cstate.StateContextI) (redeems []readPoolRedeem, err error) {
+
+	if charge == nil {
+		return // not priced
+	}
+
+	redeems, err = rp.moveToOwner(ssc.ID, alloc.ID, rm.BlobberID, alloc.Owner,
+		now, charge.fee, balances)
+	if err != nil {
+		return nil, fmt.Errorf("can't pay share offer: %v", err)
+	}
+
+	var sp = charge.purchase
+	if sp == nil {
+		sp = &sharePurchase{
+			AllocationID: alloc.ID,
+			FilePathHash: charge.offer.FilePathHash,
+			ClientID:     rm.PayerID,
+			Created:      now,
+		}
+	}
+	sp.Paid += charge.fee
+	if err = sp.save(ssc.ID, balances); err != nil {
+		return nil, fmt.Errorf("can't save share purchase: %v", err)
+	}
+
+	charge.offer.Revenue += charge.fee
+	if err = charge.offers.save(ssc.ID, balances); err != nil {
+		return nil, fmt.Errorf("can't save share offers: %v", err)
+	}
+	return
+}
+
+//
+// add / remove share offer
+//
+
+type shareOfferRequest struct {
+	AllocationID string        `json:"allocation_id"`
+	FilePathHash string        `json:"file_path_hash"`
+	PricePerGB   state.Balance `json:"price_per_gb"`
+	FlatFee      state.Balance `json:"flat_fee"`
+}
+
+func (req *shareOfferRequest) decode(input []byte) error {
+	return json.Unmarshal(input, req)
+}
+
+func (req *shareOfferRequest) validate() error {
+	switch {
+	case req.AllocationID == "":
+		return errors.New("missing allocation_id")
+	case req.PricePerGB < 0 || req.FlatFee < 0:
+		return errors.New("negative price")
+	case req.PricePerGB == 0 && req.FlatFee == 0:
+		return errors.New("zero price, remove the offer instead")
+	}
+	return nil
+}
+
+// owner's allocation that can be shared
+func (ssc *StorageSmartContract) getOwnAllocation(t *transaction.Transaction,
+	allocID string, balances cstate.StateContextI) (
+	alloc *StorageAllocation, err error) {
+
+	if alloc, err = ssc.getAllocation(allocID, balances); err != nil {
+		return nil, fmt.Errorf("can't get allocation: %v", err)
+	}
+	if alloc.Owner != t.ClientID {
+		return nil, errors.New("only owner can manage share offers")
+	}
+	if alloc.Finalized || alloc.Canceled {
+		return nil, errors.New("allocation is finalized or canceled")
+	}
+	return
+}
+
+// addShareOffer adds or updates priced share offer of an allocation
+// or a file of it
+func (ssc *StorageSmartContract) addShareOffer(t *transaction.Transaction,
+	input []byte, balances cstate.StateContextI) (resp string, err error) {
+
+	var req shareOfferRequest
+	if err = req.decode(input); err != nil {
+		return "", common.NewError("add_share_offer_failed", err.Error())
+	}
+	if err = req.validate(); err != nil {
+		return "", common.NewError("add_share_offer_failed", err.Error())
+	}
+
+	if _, err = ssc.getOwnAllocation(t, req.AllocationID, balances); err != nil {
+		return "", common.NewError("add_share_offer_failed", err.Error())
+	}
+
+	var sos *shareOffers
+	if sos, err = ssc.findShareOffers(req.AllocationID, balances); err != nil {
+		return "", common.NewError("add_share_offer_failed",
+			"can't get share offers: "+err.Error())
+	}
+	if sos == nil {
+		sos = newShareOffers(req.AllocationID)
+	}
+
+	var so = &shareOffer{
+		FilePathHash: req.FilePathHash,
+		PricePerGB:   req.PricePerGB,
+		FlatFee:      req.FlatFee,
+		Created:      t.CreationDate,
+	}
+	if i, ok := sos.getIndex(req.FilePathHash); ok {
+		so.Revenue = sos.Offers[i].Revenue // keep stat
+	}
+	sos.set(so)
+
+	if err = sos.save(ssc.ID, balances); err != nil {
+		return "", common.NewError("add_share_offer_failed",
+			"saving share offers: "+err.Error())
+	}
+
+	return toJson(so), nil
+}
+
+// removeShareOffer makes shared data of an allocation free for reading
+func (ssc *StorageSmartContract) removeShareOffer(t *transaction.Transaction,
+	input []byte, balances cstate.StateContextI) (resp string, err error) {
+
+	var req shareOfferRequest
+	if err = req.decode(input); err != nil {
+		return "", common.NewError("remove_share_offer_failed", err.Error())
+	}
+
+	if _, err = ssc.getOwnAllocation(t, req.AllocationID, balances); err != nil {
+		return "", common.NewError("remove_share_offer_failed", err.Error())
+	}
+
+	var sos *shareOffers
+	if sos, err = ssc.getShareOffers(req.AllocationID, balances); err != nil {
+		return "", common.NewError("remove_share_offer_failed",
+			"can't get share offers: "+err.Error())
+	}
+	if !sos.remove(req.FilePathHash) {
+		return "", common.NewError("remove_share_offer_failed",
+			"no such share offer")
+	}
+
+	if err = sos.save(ssc.ID, balances); err != nil {
+		return "", common.NewError("remove_share_offer_failed",
+			"saving share offers: "+err.Error())
+	}
+
+	return "share offer removed", nil
+}
+
+//
+// stat
+//
+
+const cantGetShareOffersMsg = "can't get share offers"
+
+// getShareOffersHandler returns share offers of an allocation; if
+// file_path_hash provided, then only the offer applied to the file is
+// returned
+func (ssc *StorageSmartContract) getShareOffersHandler(ctx context.Context,
+	params url.Values, balances cstate.StateContextI) (
+	resp interface{}, err error) {
+
+	var allocID = params.Get("allocation_id")
+
+	var sos *shareOffers
+	if sos, err = ssc.getShareOffers(allocID, balances); err != nil {
+		return nil, smartcontract.NewErrNoResourceOrErrInternal(err, true,
+			cantGetShareOffersMsg)
+	}
+
+	if _, ok := params["file_path_hash"]; !ok {
+		return sos, nil
+	}
+
+	var so = sos.match(params.Get("file_path_hash"))
+	if so == nil {
+		return nil, common.NewErrNoResource("no share offer for the file")
+	}
+	return so, nil
+}
@@ -0,0 +1,126 @@
+package storagesc
+
+import (
+	"testing"
+
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_shareOffers(t *testing.T) {
+	var sos = newShareOffers("alloc_id")
+	assert.Nil(t, sos.match("file"))
+
+	sos.set(&shareOffer{FilePathHash: "file", FlatFee: 1})
+	sos.set(&shareOffer{FilePathHash: "", PricePerGB: 2})
+	sos.set(&shareOffer{FilePathHash: "another", FlatFee: 3})
+	require.Len(t, sos.Offers, 3)
+	assert.Equal(t, "", sos.Offers[0].FilePathHash)
+	assert.Equal(t, "another", sos.Offers[1].FilePathHash)
+	assert.Equal(t, "file", sos.Offers[2].FilePathHash)
+
+	assert.EqualValues(t, 1, sos.match("file").FlatFee)
+	assert.EqualValues(t, 2, sos.match("unknown").PricePerGB)
+
+	sos.set(&shareOffer{FilePathHash: "file", FlatFee: 4})
+	require.Len(t, sos.Offers, 3)
+	assert.EqualValues(t, 4, sos.match("file").FlatFee)
+
+	assert.True(t, sos.remove(""))
+	assert.False(t, sos.remove(""))
+	assert.Nil(t, sos.match("unknown"))
+}
+
+func Test_payShareOffer(t *testing.T) {
+	const (
+		allocID  = "alloc_id"
+		blobID   = "blobber_id"
+		ownerID  = "owner_id"
+		readerID = "reader_id"
+		now      = 10
+	)
+
+	var (
+		ssc      = newTestStorageSC()
+		balances = newTestBalances(t, false)
+		alloc    = &StorageAllocation{ID: allocID, Owner: ownerID}
+		rp       = new(readPool)
+		ap       = &allocationPool{AllocationID: allocID, ExpireAt: 100}
+	)
+	balances.setTransaction(t, &transaction.Transaction{
+		ClientID:   blobID,
+		ToClientID: ADDRESS,
+	})
+
+	ap.ID, ap.Balance = "read_pool_id", 100
+	ap.Blobbers.add(&blobberPool{BlobberID: blobID, Balance: 100})
+	rp.Pools.add(ap)
+	require.NoError(t, rp.save(ssc.ID, readerID, balances))
+
+	var sos = newShareOffers(allocID)
+	sos.set(&shareOffer{FlatFee: 10})
+	sos.set(&shareOffer{FilePathHash: "file", PricePerGB: 10, FlatFee: 5})
+	require.NoError(t, sos.save(ssc.ID, balances))
+
+	var pay = func(rm *ReadMarker, sizeRead float64) error {
+		var charge, err = ssc.shareOfferCharge(alloc, rm, sizeRead, balances)
+		require.NoError(t, err)
+		var rp, errRP = ssc.getReadPool(readerID, balances)
+		require.NoError(t, errRP)
+		if _, err = ssc.payShareOffer(alloc, rm, rp, charge, now,
+			balances); err != nil {
+			return err
+		}
+		return rp.save(ssc.ID, readerID, balances)
+	}
+
+	var read = func(payerID, filePathHash string, sizeRead float64) {
+		var rm = &ReadMarker{
+			BlobberID:  blobID,
+			PayerID:    payerID,
+			AuthTicket: &AuthTicket{FilePathHash: filePathHash},
+		}
+		require.NoError(t, pay(rm, sizeRead))
+	}
+
+	// the owner doesn't pay
+	read(ownerID, "file", 1)
+	assert.Zero(t, balances.balances[ownerID])
+
+	// entire allocation offer, the flat fee is paid once
+	read(readerID, "other", 2)
+	assert.EqualValues(t, 10, balances.balances[ownerID])
+	read(readerID, "other", 2)
+	assert.EqualValues(t, 10, balances.balances[ownerID])
+
+	// file offer, the flat fee and per GB price
+	read(readerID, "file", 2)
+	assert.EqualValues(t, 35, balances.balances[ownerID])
+
+	var err error
+	rp, err = ssc.getReadPool(readerID, balances)
+	require.NoError(t, err)
+	assert.EqualValues(t, 65, rp.Pools[0].Balance)
+	assert.EqualValues(t, 65, rp.blobberBalance(allocID, blobID, now))
+	assert.Zero(t, rp.blobberBalance(allocID, "other", now))
+
+	sp, err := ssc.getSharePurchase(allocID, "file", readerID, balances)
+	require.NoError(t, err)
+	assert.EqualValues(t, 25, sp.Paid)
+
+	sos, err = ssc.getShareOffers(allocID, balances)
+	require.NoError(t, err)
+	assert.Equal(t, []state.Balance{10, 25},
+		[]state.Balance{sos.Offers[0].Revenue, sos.Offers[1].Revenue})
+
+	// not enough tokens
+	var rm = &ReadMarker{
+		BlobberID:  blobID,
+		PayerID:    readerID,
+		AuthTicket: &AuthTicket{FilePathHash: "file"},
+	}
+	require.Error(t, pay(rm, 10))
+}
@@ -0,0 +1,142 @@
+package storagesc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	chainstate "0chain.net/chaincore/chain/state"
+	sci "0chain.net/chaincore/smartcontractinterface"
+	"0chain.net/chaincore/state"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/util"
+)
+
+func newStakeSC() *StorageSmartContract {
+	return &StorageSmartContract{SmartContract: sci.NewSC(ADDRESS)}
+}
+
+// GetClientStake returns tokens of given client staked in stake pools of
+// blobbers and validators created before given round.
+func GetClientStake(clientID string, before int64,
+	balances chainstate.StateContextI) (stake state.Balance, err error) {
+
+	var (
+		ssc = newStakeSC()
+		usp *userStakePools
+	)
+	switch usp, err = ssc.getUserStakePool(clientID, balances); err {
+	case nil:
+	case util.ErrValueNotPresent:
+		return 0, nil // not a stake holder
+	default:
+		return
+	}
+
+	for providerID, poolIDs := range usp.Pools {
+		var sp *stakePool
+		if sp, err = ssc.getStakePool(providerID, balances); err != nil {
+			return 0, fmt.Errorf("can't get stake pool of %s: %v",
+				providerID, err)
+		}
+		for _, id := range poolIDs {
+			if dp, ok := sp.Pools[id]; ok && dp.RoundCreated < before {
+				stake += dp.Balance
+			}
+		}
+	}
+	return
+}
+
+func totalStakeKey() datastore.Key {
+	return datastore.Key(ADDRESS + ":total_stake")
+}
+
+// totalStake is running total of tokens staked in stake pools of all
+// blobbers and validators, it's updated on every save of a stake pool
+type totalStake struct {
+	Stake state.Balance `json:"stake"`
+}
+
+func (ts *totalStake) Encode() []byte {
+	var b, err = json.Marshal(ts)
+	if err != nil {
+		panic(err) // must never happen
+	}
+	return b
+}
+
+func (ts *totalStake) Decode(b []byte) error {
+	return json.Unmarshal(b, ts)
+}
+
+// GetTotalStake returns tokens staked in stake pools of all blobbers
+// and validators.
+func GetTotalStake(balances chainstate.StateContextI) (
+	stake state.Balance, err error) {
+
+	var val util.Serializable
+	switch val, err = balances.GetTrieNode(totalStakeKey()); err {
+	case nil:
+	case util.ErrValueNotPresent:
+		return countTotalStake(balances) // not tracked yet
+	default:
+		return
+	}
+	var ts totalStake
+	if err = ts.Decode(val.Encode()); err != nil {
+		return 0, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return ts.Stake, nil
+}
+
+// addTotalStake adds given difference to the running total
+func addTotalStake(diff state.Balance, balances chainstate.StateContextI) (
+	err error) {
+
+	var ts totalStake
+	if ts.Stake, err = GetTotalStake(balances); err != nil {
+		return
+	}
+	ts.Stake += diff
+	_, err = balances.InsertTrieNode(totalStakeKey(), &ts)
+	return
+}
+
+// countTotalStake goes through stake pools of all blobbers and validators,
+// it's used once to start the running total
+func countTotalStake(balances chainstate.StateContextI) (
+	stake state.Balance, err error) {
+
+	var (
+		ssc        = newStakeSC()
+		blobbers   *StorageNodes
+		validators *ValidatorNodes
+		ids        []string
+	)
+	if blobbers, err = ssc.getBlobbersList(balances); err != nil {
+		return 0, fmt.Errorf("can't get blobbers list: %v", err)
+	}
+	if validators, err = ssc.getValidatorsList(balances); err != nil {
+		return 0, fmt.Errorf("can't get validators list: %v", err)
+	}
+	for _, b := range blobbers.Nodes {
+		ids = append(ids, b.ID)
+	}
+	for _, v := range validators.Nodes {
+		ids = append(ids, v.ID)
+	}
+
+	for _, id := range ids {
+		var sp *stakePool
+		switch sp, err = ssc.getStakePool(id, balances); err {
+		case nil:
+			stake += sp.stake()
+		case util.ErrValueNotPresent:
+			err = nil // no stake pool
+		default:
+			return 0, fmt.Errorf("can't get stake pool of %s: %v", id, err)
+		}
+	}
+	return
+}
@@ -109,12 +109,15 @@ type stakePoolRewards struct {
 // delegate pool
 type delegatePool struct {
 	tokenpool.ZcnPool `json:"pool"`    // the pool
-	MintAt            common.Timestamp `json:"mint_at"`     // last mint time
-	DelegateID        datastore.Key    `json:"delegate_id"` // user
-	Interests         state.Balance    `json:"interests"`   // total
-	Rewards           state.Balance    `json:"rewards"`     // total
-	Penalty           state.Balance    `json:"penalty"`     // total
-	Unstake           common.Timestamp `json:"unstake"`     // want to unstake
+	MintAt            common.Timestamp `json:"mint_at"`       // last mint time
+	DelegateID        datastore.Key    `json:"delegate_id"`   // user
+	Interests         state.Balance    `json:"interests"`     // total
+	Rewards           state.Balance    `json:"rewards"`       // total
+	Penalty           state.Balance    `json:"penalty"`       // total
+	Unstake           common.Timestamp `json:"unstake"`       // want to unstake
+	AutoCompound      bool             `json:"auto_compound"` // reinvest rewards
+	Compounded        state.Balance    `json:"compounded"`    // total reinvested
+	RoundCreated      int64            `json:"round_created,omitempty"`
 }
 
 // stake pool settings
@@ -165,6 +168,10 @@ type stakePool struct {
 	Rewards stakePoolRewards `json:"rewards"`
 	// Settings of the stake pool.
 	Settings stakePoolSettings `json:"settings"`
+
+	// staked is stake of the pool as it's stored, the difference goes
+	// to the running total on save
+	staked state.Balance
 }
 
 func newStakePool() *stakePool {
@@ -194,7 +201,11 @@ func (sp *stakePool) Encode() (b []byte) {
 
 // Decode from []byte
 func (sp *stakePool) Decode(input []byte) error {
-	return json.Unmarshal(input, sp)
+	if err := json.Unmarshal(input, sp); err != nil {
+		return err
+	}
+	sp.staked = sp.stake()
+	return nil
 }
 
 // offersStake returns stake required by currently open offers;
@@ -218,6 +229,12 @@ func (sp *stakePool) offersStake(now common.Timestamp, dry bool) (
 func (sp *stakePool) save(sscKey, blobberID string,
 	balances chainstate.StateContextI) (err error) {
 
+	if diff := sp.stake() - sp.staked; diff != 0 {
+		if err = addTotalStake(diff, balances); err != nil {
+			return fmt.Errorf("saving total stake: %v", err)
+		}
+		sp.staked += diff
+	}
 	_, err = balances.InsertTrieNode(stakePoolKey(sscKey, blobberID), sp)
 	return
 }
@@ -269,6 +286,7 @@ func (sp *stakePool) dig(t *transaction.Transaction,
 
 	dp.DelegateID = t.ClientID
 	dp.MintAt = t.CreationDate
+	dp.RoundCreated = balances.GetBlock().Round
 
 	sp.Pools[t.Hash] = dp
 
@@ -609,6 +627,35 @@ func (sp *stakePool) slash(
 	return
 }
 
+// The compoundable returns part of given reward can be reinvested into given
+// delegate pool with regards to the max_stake, which limits balance of every
+// delegate pool. The rest of the reward should be payed to the delegate
+// wallet.
+func (sp *stakePool) compoundable(dp *delegatePool,
+	reward state.Balance) (reinvest state.Balance) {
+
+	if !dp.AutoCompound || dp.Unstake > 0 || reward <= 0 {
+		return // no auto-compounding or the pool wants to unstake
+	}
+
+	if sp.Settings.MaxStake <= 0 {
+		return reward // no boundary
+	}
+
+	var room = sp.Settings.MaxStake - dp.Balance
+	if room <= 0 {
+		return // max_stake reached
+	}
+	return minBalance(room, reward)
+}
+
+// compound given reward tokens to given delegate pool, the tokens should
+// already be moved to the storage SC
+func (sp *stakePool) compound(dp *delegatePool, reinvest state.Balance) {
+	dp.Balance += reinvest
+	dp.Compounded += reinvest
+}
+
 // free staked capacity of related blobber, excluding delegate pools want to
 // unstake.
 func (sp *stakePool) cleanCapacity(now common.Timestamp,
@@ -682,9 +729,13 @@ func (sp *stakePool) stat(conf *scConfig, sscKey string,
 			Rewards:    dp.Rewards,
 			Penalty:    dp.Penalty,
 			Unstake:    dp.Unstake,
+
+			AutoCompound: dp.AutoCompound,
+			Compounded:   dp.Compounded,
 		}
 		stat.Interests += dp.Rewards
 		stat.Penalty += dp.Penalty
+		stat.Compounded += dp.Compounded
 		if conf.canMint() {
 			dps.PendingInterests = sp.interests(dp, now, rate, period)
 		}
@@ -724,6 +775,8 @@ type delegatePoolStat struct {
 	Penalty          state.Balance    `json:"penalty"`           // total for all time
 	PendingInterests state.Balance    `json:"pending_interests"` // not payed yet
 	Unstake          common.Timestamp `json:"unstake"`           // want to unstake
+	AutoCompound     bool             `json:"auto_compound"`     // reinvest rewards
+	Compounded       state.Balance    `json:"compounded"`        // total reinvested
 }
 
 type stakePoolStat struct {
@@ -738,9 +791,10 @@ type stakePoolStat struct {
 	Offers      []offerPoolStat `json:"offers"`       //
 	OffersTotal state.Balance   `json:"offers_total"` //
 	// delegate pools
-	Delegate  []delegatePoolStat `json:"delegate"`
-	Interests state.Balance      `json:"interests"` // total for all (TO REMOVE)
-	Penalty   state.Balance      `json:"penalty"`   // total for all
+	Delegate   []delegatePoolStat `json:"delegate"`
+	Interests  state.Balance      `json:"interests"`  // total for all (TO REMOVE)
+	Penalty    state.Balance      `json:"penalty"`    // total for all
+	Compounded state.Balance      `json:"compounded"` // total for all
 	// rewards
 	Rewards rewardsStat `json:"rewards"`
 
@@ -897,8 +951,9 @@ func (ssc *StorageSmartContract) updateSakePoolOffer(ba *BlobberAllocation,
 }
 
 type stakePoolRequest struct {
-	BlobberID datastore.Key `json:"blobber_id,omitempty"`
-	PoolID    datastore.Key `json:"pool_id,omitempty"`
+	BlobberID    datastore.Key `json:"blobber_id,omitempty"`
+	PoolID       datastore.Key `json:"pool_id,omitempty"`
+	AutoCompound bool          `json:"auto_compound,omitempty"`
 }
 
 func (spr *stakePoolRequest) decode(p []byte) (err error) {
@@ -973,6 +1028,7 @@ func (ssc *StorageSmartContract) stakePoolLock(t *transaction.Transaction,
 		return "", common.NewErrorf("stake_pool_lock_failed",
 			"stake pool digging error: %v", err)
 	}
+	dp.AutoCompound = spr.AutoCompound
 
 	// add to user pools
 	var usp *userStakePools
@@ -1082,6 +1138,45 @@ func (ssc *StorageSmartContract) stakePoolUnlock(t *transaction.Transaction,
 	return
 }
 
+// stakePoolSetAutoCompound enables or disables auto-compounding of rewards
+// of a delegate pool; only owner of the delegate pool can change it
+func (ssc *StorageSmartContract) stakePoolSetAutoCompound(
+	t *transaction.Transaction, input []byte,
+	balances chainstate.StateContextI) (resp string, err error) {
+
+	var spr stakePoolRequest
+	if err = spr.decode(input); err != nil {
+		return "", common.NewErrorf("stake_pool_set_auto_compound_failed",
+			"can't decode request: %v", err)
+	}
+
+	var sp *stakePool
+	if sp, err = ssc.getStakePool(spr.BlobberID, balances); err != nil {
+		return "", common.NewErrorf("stake_pool_set_auto_compound_failed",
+			"can't get related stake pool: %v", err)
+	}
+
+	var dp, ok = sp.Pools[spr.PoolID]
+	if !ok {
+		return "", common.NewErrorf("stake_pool_set_auto_compound_failed",
+			"no such delegate pool: %q", spr.PoolID)
+	}
+
+	if dp.DelegateID != t.ClientID {
+		return "", common.NewError("stake_pool_set_auto_compound_failed",
+			"only owner of the delegate pool can change it")
+	}
+
+	dp.AutoCompound = spr.AutoCompound
+
+	if err = sp.save(ssc.ID, spr.BlobberID, balances); err != nil {
+		return "", common.NewErrorf("stake_pool_set_auto_compound_failed",
+			"saving stake pool: %v", err)
+	}
+
+	return toJson(&spr), nil
+}
+
 // pay interests not payed for now
 func (ssc *StorageSmartContract) stakePoolPayInterests(
 	t *transaction.Transaction, input []byte,
@@ -1217,6 +1312,10 @@ func (ssc *StorageSmartContract) getUserStakePoolStatHandler(ctx context.Context
 				Interests:  dp.Interests,
 				Rewards:    dp.Rewards,
 				Penalty:    dp.Penalty,
+				Unstake:    dp.Unstake,
+
+				AutoCompound: dp.AutoCompound,
+				Compounded:   dp.Compounded,
 			}
 			if conf.canMint() {
 				dps.PendingInterests = sp.interests(dp, now, rate, period)
@@ -85,6 +85,62 @@ func Test_stakePool_fill(t *testing.T) {
 	assert.Equal(t, state.Balance(90), sp.stake())
 }
 
+func Test_stakePool_compoundable(t *testing.T) {
+	var (
+		sp = newStakePool()
+		dp = new(delegatePool)
+	)
+	dp.ID = "pool_id"
+	dp.Balance = 80
+	sp.Pools[dp.ID] = dp
+	sp.Settings.MaxStake = 100
+
+	assert.Zero(t, sp.compoundable(dp, 10)) // disabled
+
+	dp.AutoCompound = true
+	assert.Equal(t, state.Balance(10), sp.compoundable(dp, 10))
+	assert.Equal(t, state.Balance(20), sp.compoundable(dp, 30))
+
+	// max_stake limits the delegate pool, not the whole stake pool
+	var other = &delegatePool{}
+	other.ID, other.Balance = "other_pool_id", 500
+	sp.Pools[other.ID] = other
+	assert.Equal(t, state.Balance(20), sp.compoundable(dp, 30))
+
+	dp.Balance = 100
+	assert.Zero(t, sp.compoundable(dp, 30)) // max_stake reached
+
+	sp.Settings.MaxStake = 0 // no boundary
+	assert.Equal(t, state.Balance(30), sp.compoundable(dp, 30))
+
+	dp.Unstake = common.Now() // wants to unstake
+	assert.Zero(t, sp.compoundable(dp, 10))
+}
+
+func Test_getPayments_autoCompound(t *testing.T) {
+	var (
+		sp       = newStakePool()
+		dpa, dpb = new(delegatePool), new(delegatePool)
+	)
+	dpa.ID, dpa.DelegateID, dpa.Balance = "pool_a", "delegate_a", 50
+	dpb.ID, dpb.DelegateID, dpb.Balance = "pool_b", "delegate_b", 50
+	dpa.AutoCompound = true
+	sp.Pools[dpa.ID], sp.Pools[dpb.ID] = dpa, dpb
+	sp.Settings.MaxStake = 70
+
+	payments, moved, err := getPayments(sp, 100)
+	require.NoError(t, err)
+	assert.Equal(t, 100.0, moved)
+	require.Len(t, payments, 3)
+
+	// delegate_a: 20 reinvested (max_stake), 30 payed to the wallet
+	assert.Equal(t, payment{to: "delegate_a", amount: 20, pool: dpa},
+		payments[0])
+	assert.Equal(t, payment{to: "delegate_a", amount: 30}, payments[1])
+	assert.Equal(t, payment{to: "delegate_b", amount: 50}, payments[2])
+	assert.Equal(t, state.Balance(50), dpa.Rewards)
+}
+
 type mockStakePool struct {
 	zcnAmount float64
 	MintAt    common.Timestamp
@@ -71,7 +71,7 @@ func BenchmarkTests(
 			name:     "vesting.updateConfig",
 			endpoint: vsc.updateConfig,
 			txn: &transaction.Transaction{
-				ClientID:     owner,
+				ClientID:     sc.GovernanceAddress,
 				CreationDate: common.Timestamp(viper.GetInt64(bk.Now)),
 			},
 			input: (&sc.StringMap{
@@ -134,6 +134,21 @@ func BenchmarkTests(
 				return bytes
 			}(),
 		},
+		{
+			name:     "vesting.revoke",
+			endpoint: vsc.revoke,
+			txn: &transaction.Transaction{
+				ClientID:     data.Clients[0],
+				CreationDate: common.Timestamp(viper.GetInt64(bk.Now)),
+			},
+			input: func() []byte {
+				bytes, _ := json.Marshal(&stopRequest{
+					PoolID:      geMockVestingPoolId(0),
+					Destination: getMockDestinationId(0, 0),
+				})
+				return bytes
+			}(),
+		},
 		{
 			name:     "vesting.delete",
 			endpoint: vsc.delete,
@@ -142,9 +142,9 @@ func (vsc *VestingSmartContract) updateConfig(
 	input []byte,
 	balances chainstate.StateContextI,
 ) (resp string, err error) {
-	if txn.ClientID != owner {
+	if !smartcontract.CanUpdateSettings(txn.ClientID) {
 		return "", common.NewError("update_config",
-			"unauthorized access - only the owner can update the variables")
+			"unauthorized access - only the governance can update the variables")
 	}
 
 	var conf *config
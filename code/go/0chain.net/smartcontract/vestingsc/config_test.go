@@ -168,7 +168,7 @@ func TestUpdateConfig(t *testing.T) {
 		{
 			title: "ok_all",
 			parameters: parameters{
-				client: owner,
+				client: smartcontract.GovernanceAddress,
 				input: map[string]string{
 					Settings[MinLock]:              "5",
 					Settings[MinDuration]:          "1s",
@@ -188,13 +188,13 @@ func TestUpdateConfig(t *testing.T) {
 			},
 			want: want{
 				error: true,
-				msg:   "update_config: unauthorized access - only the owner can update the variables",
+				msg:   "update_config: unauthorized access - only the governance can update the variables",
 			},
 		},
 		{
 			title: "bad_data",
 			parameters: parameters{
-				client: owner,
+				client: smartcontract.GovernanceAddress,
 				input: map[string]string{
 					Settings[MinDuration]: mockBadData,
 				},
@@ -207,7 +207,7 @@ func TestUpdateConfig(t *testing.T) {
 		{
 			title: "bad_key",
 			parameters: parameters{
-				client: owner,
+				client: smartcontract.GovernanceAddress,
 				input: map[string]string{
 					mockBadKey: "1",
 				},
@@ -166,3 +166,17 @@ func (c *Client) delete(t *testing.T, vsc *VestingSmartContract,
 	dr.PoolID = poolID
 	return vsc.unlock(tx, mustEncode(t, &dr), balances)
 }
+
+func (c *Client) revoke(t *testing.T, vsc *VestingSmartContract,
+	poolID, dest datastore.Key, now common.Timestamp,
+	balances chainstate.StateContextI) (resp string, err error) {
+
+	var (
+		tx = newTransaction(c.id, ADDRESS, 0, now)
+		sr stopRequest
+	)
+	balances.(*testBalances).txn = tx
+	sr.PoolID = poolID
+	sr.Destination = dest
+	return vsc.revoke(tx, mustEncode(t, &sr), balances)
+}
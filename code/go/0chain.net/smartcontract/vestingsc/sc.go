@@ -15,7 +15,6 @@ import (
 
 const (
 	ADDRESS = "2bba5b05949ea59c80aed3ac3474d7379d3be737e8eb5a968c52295e48333ead"
-	owner   = "1746b06bb09f55ee01b33b5e2e055d6cc7a900cb57c0a3a5eaabb8a0e7745802"
 )
 
 type RestPoints = map[string]smartcontractinterface.SmartContractRestHandler
@@ -72,6 +71,10 @@ func (vsc *VestingSmartContract) setSC(sc *smartcontractinterface.SmartContract,
 	vsc.SmartContractExecutionStats["stop"] = metrics.GetOrRegisterTimer(
 		fmt.Sprintf("sc:%v:func:%v", vsc.ID, "stop"), nil)
 
+	// revoke vesting for a destination, returning unvested tokens to owner
+	vsc.SmartContractExecutionStats["revoke"] = metrics.GetOrRegisterTimer(
+		fmt.Sprintf("sc:%v:func:%v", vsc.ID, "revoke"), nil)
+
 	// tokens unlock for an existing pool (as owner, as a destination)
 	vsc.SmartContractExecutionStats["unlock"] = metrics.GetOrRegisterTimer(
 		fmt.Sprintf("sc:%v:func:%v", vsc.ID, "unlock"), nil)
@@ -96,6 +99,8 @@ func (vsc *VestingSmartContract) Execute(t *transaction.Transaction,
 		resp, err = vsc.add(t, input, balances)
 	case "stop":
 		resp, err = vsc.stop(t, input, balances)
+	case "revoke":
+		resp, err = vsc.revoke(t, input, balances)
 	case "delete":
 		resp, err = vsc.delete(t, input, balances)
 	case "vestingsc-update-settings":
@@ -66,6 +66,20 @@ type destination struct {
 	// can produce zero tokens transfer (resolution is a second). The move
 	// will be updated only if a triggering really moves tokens (non zero).
 	Move common.Timestamp `json:"move"`
+
+	// StartTime of vesting for the destination, the start time of the
+	// pool is used if it's zero.
+	StartTime common.Timestamp `json:"start_time,omitempty"`
+	// Cliff is time range from the start nothing is vested within. The
+	// tokens accumulated by the cliff are vested at once after it.
+	Cliff time.Duration `json:"cliff,omitempty"`
+	// Step is interval of vesting tranches, for example, a month. Tokens
+	// are vested continuously if it's zero.
+	Step time.Duration `json:"step,omitempty"`
+	// Revoked is time the destination has been revoked by the pool owner.
+	// The Amount is reduced to tokens vested by the time and the rest is
+	// returned to the owner.
+	Revoked common.Timestamp `json:"revoked,omitempty"`
 }
 
 // tokens left for this destination
@@ -93,14 +107,69 @@ func (d *destination) move(now common.Timestamp, moved state.Balance) {
 	}
 }
 
+// scheduled returns true if the destination has custom vesting schedule
+// or it has been revoked
+func (d *destination) scheduled() bool {
+	return d.StartTime != 0 || d.Cliff != 0 || d.Step != 0 || d.Revoked != 0
+}
+
+// begin is start time of vesting for the destination
+func (d *destination) begin(start common.Timestamp) common.Timestamp {
+	if d.StartTime != 0 {
+		return d.StartTime
+	}
+	return start
+}
+
+// cliffEnd is time the cliff of the destination ends
+func (d *destination) cliffEnd(start common.Timestamp) common.Timestamp {
+	return d.begin(start) + toSeconds(d.Cliff)
+}
+
+// vestedAt returns total amount of tokens should be vested by given time
+// by the destination's schedule for given start and end of related
+// vesting pool
+func (d *destination) vestedAt(now, start, end common.Timestamp) (
+	vested state.Balance) {
+
+	if d.Revoked != 0 && now >= d.Revoked {
+		return d.Amount // reduced on revocation
+	}
+
+	var begin = d.begin(start)
+	if now >= end || begin >= end {
+		return d.Amount
+	}
+	if now < d.cliffEnd(start) || now <= begin {
+		return 0
+	}
+
+	var elapsed = now - begin
+	if step := toSeconds(d.Step); step > 0 {
+		elapsed -= elapsed % step // whole tranches only
+	}
+	return state.Balance(float64(d.Amount) *
+		(float64(elapsed) / float64(end-begin)))
+}
+
 // The unlock returns amount of tokens to vest for current period.
 // The dry argument leave all inside the destination as it was and
 // used to obtain pool statistic. The now must not be later than the
 // end. Also, the now must be greater or equal to start time of related
 // vesting pool.
-func (d *destination) unlock(now, end common.Timestamp, dry bool) (
+func (d *destination) unlock(now, start, end common.Timestamp, dry bool) (
 	amount state.Balance) {
 
+	if d.scheduled() {
+		if amount = d.vestedAt(now, start, end) - d.Vested; amount < 0 {
+			amount = 0
+		}
+		if !dry {
+			d.move(now, amount)
+		}
+		return
+	}
+
 	var (
 		full   = d.full(end)   // full time range left
 		period = d.period(now) // current vesting period
@@ -133,9 +202,10 @@ type destinations []*destination
 // start sets start time (the Last and the Move)
 func (ds destinations) start(now common.Timestamp) {
 	for _, d := range ds {
-		d.Last = now // } setup start time
-		d.Move = now // }
-		d.Vested = 0 // clean possible request injection
+		d.Last = now  // } setup start time
+		d.Move = now  // }
+		d.Vested = 0  // clean possible request injection
+		d.Revoked = 0 // }
 	}
 }
 
@@ -148,6 +218,7 @@ type addRequest struct {
 	StartTime    common.Timestamp `json:"start_time"`            //
 	Duration     time.Duration    `json:"duration"`              //
 	Destinations destinations     `json:"destinations"`          //
+	Irrevocable  bool             `json:"irrevocable,omitempty"` // grant policy
 }
 
 func (ar *addRequest) decode(b []byte) error {
@@ -178,14 +249,38 @@ func (ar *addRequest) validate(now common.Timestamp, conf *config) (err error) {
 		return errors.New("too many destinations")
 	}
 
+	var end = ar.StartTime + toSeconds(ar.Duration)
 	for _, d := range ar.Destinations {
 		if d.Amount < 0 {
 			return fmt.Errorf("negative amount for %q: %d", d.ID, d.Amount)
 		}
+		if err = d.validate(ar.StartTime, end); err != nil {
+			return fmt.Errorf("invalid schedule for %q: %v", d.ID, err)
+		}
 	}
 	return
 }
 
+// validate vesting schedule of the destination for given vesting pool
+// start and end
+func (d *destination) validate(start, end common.Timestamp) error {
+	switch {
+	case d.StartTime != 0 && d.StartTime < start:
+		return errors.New("vesting starts before the pool starts")
+	case d.StartTime != 0 && d.StartTime >= end:
+		return errors.New("vesting starts after the pool expires")
+	case d.Cliff < 0:
+		return errors.New("negative cliff")
+	case d.cliffEnd(start) > end:
+		return errors.New("cliff ends after the pool expires")
+	case d.Step < 0:
+		return errors.New("negative step")
+	case toSeconds(d.Step) > end-d.begin(start):
+		return errors.New("step is longer than vesting")
+	}
+	return nil
+}
+
 //
 // vesting pool
 //
@@ -202,6 +297,9 @@ type vestingPool struct {
 	ExpireAt     common.Timestamp `json:"expire_at"`    //
 	Destinations destinations     `json:"destinations"` //
 	ClientID     datastore.Key    `json:"client_id"`    // the pool owner
+	// Irrevocable pool can't be stopped, revoked or deleted by the
+	// owner before it expires.
+	Irrevocable bool `json:"irrevocable,omitempty"`
 }
 
 // newVestingPool returns new empty uninitialized vesting pool.
@@ -223,6 +321,7 @@ func newVestingPoolFromReqeust(clientID datastore.Key, ar *addRequest) (
 	vp.ExpireAt = ar.StartTime + toSeconds(ar.Duration)
 	vp.Destinations = ar.Destinations
 	vp.Destinations.start(vp.StartTime)
+	vp.Irrevocable = ar.Irrevocable
 	return
 }
 
@@ -332,7 +431,7 @@ func (vp *vestingPool) trigger(t *transaction.Transaction,
 	)
 	sb.WriteByte('[')
 	for _, d := range vp.Destinations {
-		var value = d.unlock(now, end, false)
+		var value = d.unlock(now, vp.StartTime, end, false)
 		if value == 0 {
 			continue
 		}
@@ -411,7 +510,7 @@ func (vp *vestingPool) vest(vscID, destID datastore.Key, now common.Timestamp,
 		return
 	}
 
-	var value = d.unlock(now, end, false)
+	var value = d.unlock(now, vp.StartTime, end, false)
 	if value == 0 {
 		return "", errZeroVesting
 	}
@@ -423,6 +522,45 @@ func (vp *vestingPool) vest(vscID, destID datastore.Key, now common.Timestamp,
 	return
 }
 
+// revoke the destination at given time, the destination keeps tokens
+// vested by the time and the unvested tokens are returned to the owner
+func (vp *vestingPool) revoke(vscID, destID datastore.Key,
+	now common.Timestamp, balances chainstate.StateContextI) (
+	resp string, err error) {
+
+	if now > vp.ExpireAt {
+		now = vp.ExpireAt
+	} else if now < vp.StartTime {
+		now = vp.StartTime
+	}
+
+	var d *destination
+	if d, err = vp.find(destID); err != nil {
+		return
+	}
+	if d.Revoked != 0 {
+		return "", fmt.Errorf("destination %s already revoked", destID)
+	}
+
+	var vested = d.Vested + d.unlock(now, vp.StartTime, vp.ExpireAt, true)
+	var unvested = d.Amount - vested
+	d.Amount, d.Revoked = vested, now
+
+	if unvested <= 0 {
+		return
+	}
+
+	var transfer *state.Transfer
+	transfer, resp, err = vp.DrainPool(vscID, vp.ClientID, unvested, nil)
+	if err != nil {
+		return "", fmt.Errorf("draining vesting pool: %v", err)
+	}
+	if err = balances.AddTransfer(transfer); err != nil {
+		return "", fmt.Errorf("adding transfer vesting_pool->owner: %v", err)
+	}
+	return
+}
+
 func (vp *vestingPool) drain(t *transaction.Transaction,
 	balances chainstate.StateContextI) (resp string, err error) {
 
@@ -466,6 +604,7 @@ func (vp *vestingPool) info(now common.Timestamp) (i *info) {
 	i.Description = vp.Description
 	i.StartTime = vp.StartTime
 	i.ExpireAt = vp.ExpireAt
+	i.Irrevocable = vp.Irrevocable
 
 	var end = i.ExpireAt
 
@@ -479,13 +618,17 @@ func (vp *vestingPool) info(now common.Timestamp) (i *info) {
 
 	var dinfos = make([]*destInfo, 0, len(vp.Destinations))
 	for _, d := range vp.Destinations {
-		var value = d.unlock(now, end, true)
+		var value = d.unlock(now, vp.StartTime, end, true)
 		dinfos = append(dinfos, &destInfo{
-			ID:     d.ID,
-			Wanted: d.Amount,
-			Earned: value,
-			Vested: d.Vested,
-			Last:   d.Last,
+			ID:        d.ID,
+			Wanted:    d.Amount,
+			Earned:    value,
+			Vested:    d.Vested,
+			Last:      d.Last,
+			StartTime: d.StartTime,
+			Cliff:     d.Cliff,
+			Step:      d.Step,
+			Revoked:   d.Revoked,
 		})
 	}
 
@@ -495,11 +638,15 @@ func (vp *vestingPool) info(now common.Timestamp) (i *info) {
 }
 
 type destInfo struct {
-	ID     datastore.Key    `json:"id"`     // identifier
-	Wanted state.Balance    `json:"wanted"` // wanted amount for entire period
-	Earned state.Balance    `json:"earned"` // can unlock
-	Vested state.Balance    `json:"vested"` // tokens already vested
-	Last   common.Timestamp `json:"last"`   // last time unlocked
+	ID        datastore.Key    `json:"id"`                   // identifier
+	Wanted    state.Balance    `json:"wanted"`               // wanted amount for entire period
+	Earned    state.Balance    `json:"earned"`               // can unlock
+	Vested    state.Balance    `json:"vested"`               // tokens already vested
+	Last      common.Timestamp `json:"last"`                 // last time unlocked
+	StartTime common.Timestamp `json:"start_time,omitempty"` // own vesting start
+	Cliff     time.Duration    `json:"cliff,omitempty"`      // nothing vested within
+	Step      time.Duration    `json:"step,omitempty"`       // tranches interval
+	Revoked   common.Timestamp `json:"revoked,omitempty"`    // revocation time
 }
 
 type info struct {
@@ -511,6 +658,7 @@ type info struct {
 	ExpireAt     common.Timestamp `json:"expire_at"`    // until
 	Destinations []*destInfo      `json:"destinations"` // receivers
 	ClientID     datastore.Key    `json:"client_id"`    // owner
+	Irrevocable  bool             `json:"irrevocable"`  // grant policy
 }
 
 //
@@ -635,6 +783,11 @@ func (vsc *VestingSmartContract) stop(t *transaction.Transaction,
 			"only owner can stop a vesting")
 	}
 
+	if vp.Irrevocable {
+		return "", common.NewError("stop_vesting_failed",
+			"can't stop vesting of irrevocable pool")
+	}
+
 	if t.CreationDate > vp.ExpireAt {
 		return "", common.NewError("stop_vesting_failed", "expired pool")
 	}
@@ -657,6 +810,55 @@ func (vsc *VestingSmartContract) stop(t *transaction.Transaction,
 	return sr.Destination + " has deleted from the vesting pool", nil
 }
 
+// revoke vesting for a destination, the destination keeps vested tokens
+// and can unlock them, unvested tokens are returned to the owner
+func (vsc *VestingSmartContract) revoke(t *transaction.Transaction,
+	input []byte, balances chainstate.StateContextI) (resp string, err error) {
+
+	var sr stopRequest
+	if err = sr.decode(input); err != nil {
+		return "", common.NewError("revoke_vesting_failed",
+			"malformed request: "+err.Error())
+	}
+
+	if sr.Destination == "" {
+		return "", common.NewError("revoke_vesting_failed",
+			"missing destination to revoke vesting")
+	}
+
+	var vp *vestingPool
+	if vp, err = vsc.getPool(sr.PoolID, balances); err != nil {
+		return "", common.NewError("revoke_vesting_failed",
+			"can't get vesting pool: "+err.Error())
+	}
+
+	if vp.ClientID != t.ClientID {
+		return "", common.NewError("revoke_vesting_failed",
+			"only owner can revoke a vesting")
+	}
+
+	if vp.Irrevocable {
+		return "", common.NewError("revoke_vesting_failed",
+			"can't revoke vesting of irrevocable pool")
+	}
+
+	if t.CreationDate > vp.ExpireAt {
+		return "", common.NewError("revoke_vesting_failed", "expired pool")
+	}
+
+	_, err = vp.revoke(t.ToClientID, sr.Destination, t.CreationDate, balances)
+	if err != nil {
+		return "", common.NewError("revoke_vesting_failed", err.Error())
+	}
+
+	if err = vp.save(balances); err != nil {
+		return "", common.NewError("revoke_vesting_failed",
+			"saving pool: "+err.Error())
+	}
+
+	return sr.Destination + " has revoked from the vesting pool", nil
+}
+
 func (vsc *VestingSmartContract) delete(t *transaction.Transaction,
 	input []byte, balances chainstate.StateContextI) (resp string, err error) {
 
@@ -687,6 +889,11 @@ func (vsc *VestingSmartContract) delete(t *transaction.Transaction,
 			"only pool owner can delete the pool")
 	}
 
+	if vp.Irrevocable && t.CreationDate < vp.ExpireAt {
+		return "", common.NewError("delete_vesting_pool_failed",
+			"can't delete irrevocable pool before it expires")
+	}
+
 	// move tokens to destinations
 	if vp.Balance > 0 {
 		if _, err = vp.trigger(t, balances); err != nil {
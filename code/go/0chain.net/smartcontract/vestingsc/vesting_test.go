@@ -528,3 +528,129 @@ func TestVestingSmartContract_getPoolInfoHandler(t *testing.T) {
 	require.NoError(t, err)
 	require.IsType(t, &info{}, resp)
 }
+
+func Test_destination_schedule(t *testing.T) {
+	const start, end = 10, 110
+
+	// step
+	var d = &destination{ID: "one", Amount: 100, Step: 20 * time.Second}
+	assert.True(t, d.scheduled())
+	assert.EqualValues(t, 0, d.vestedAt(29, start, end))
+	assert.EqualValues(t, 20, d.vestedAt(30, start, end))
+	assert.EqualValues(t, 40, d.vestedAt(55, start, end))
+	assert.EqualValues(t, 100, d.vestedAt(end, start, end))
+
+	// cliff
+	d = &destination{ID: "two", Amount: 100, Cliff: 50 * time.Second}
+	assert.EqualValues(t, 0, d.vestedAt(59, start, end))
+	assert.EqualValues(t, 50, d.vestedAt(60, start, end))
+	assert.EqualValues(t, 60, d.unlock(70, start, end, false))
+	assert.EqualValues(t, 60, d.Vested)
+	assert.EqualValues(t, 0, d.unlock(70, start, end, false))
+
+	// own start time
+	d = &destination{ID: "three", Amount: 100, StartTime: 60}
+	assert.EqualValues(t, 0, d.vestedAt(60, start, end))
+	assert.EqualValues(t, 50, d.vestedAt(85, start, end))
+
+	// revoked
+	d = &destination{ID: "four", Amount: 30, Vested: 10, Revoked: 50}
+	assert.EqualValues(t, 20, d.unlock(50, start, end, true))
+
+	// validation
+	assert.NoError(t, (&destination{Cliff: 100 * time.Second}).
+		validate(start, end))
+	requireErrMsg(t, (&destination{StartTime: 5}).validate(start, end),
+		"vesting starts before the pool starts")
+	requireErrMsg(t, (&destination{StartTime: end}).validate(start, end),
+		"vesting starts after the pool expires")
+	requireErrMsg(t, (&destination{Cliff: 101 * time.Second}).
+		validate(start, end), "cliff ends after the pool expires")
+	requireErrMsg(t, (&destination{StartTime: 90, Step: 30 * time.Second}).
+		validate(start, end), "step is longer than vesting")
+}
+
+func TestVestingSmartContract_revoke(t *testing.T) {
+	var (
+		vsc      = newTestVestingSC()
+		balances = newTestBalances()
+		client   = newClient(1200e10, balances)
+		one      = newClient(0, balances)
+		two      = newClient(0, balances)
+		ar       = &addRequest{
+			Description: "for something",
+			StartTime:   10,
+			Duration:    100 * time.Second,
+			Destinations: destinations{
+				&destination{ID: one.id, Amount: 100e10,
+					Step: 20 * time.Second},
+				&destination{ID: two.id, Amount: 200e10,
+					Cliff: 50 * time.Second},
+			},
+		}
+		resp string
+		err  error
+	)
+	configureConfig()
+
+	// irrevocable
+	ar.Irrevocable = true
+	resp, err = client.add(t, vsc, ar, 300e10, 0, balances)
+	require.NoError(t, err)
+	var irr vestingPool
+	require.NoError(t, irr.Decode([]byte(resp)))
+	assert.True(t, irr.Irrevocable)
+
+	_, err = client.revoke(t, vsc, irr.ID, one.id, 20, balances)
+	requireErrMsg(t, err, "revoke_vesting_failed: "+
+		"can't revoke vesting of irrevocable pool")
+	_, err = client.stop(t, vsc, irr.ID, one.id, 20, balances)
+	requireErrMsg(t, err, "stop_vesting_failed: "+
+		"can't stop vesting of irrevocable pool")
+	var tx = newTransaction(client.id, ADDRESS, 0, 20)
+	balances.txn = tx
+	_, err = vsc.delete(tx, mustEncode(t, &poolRequest{PoolID: irr.ID}),
+		balances)
+	requireErrMsg(t, err, "delete_vesting_pool_failed: "+
+		"can't delete irrevocable pool before it expires")
+
+	// revocable
+	ar.Irrevocable = false
+	resp, err = client.add(t, vsc, ar, 300e10, 0, balances)
+	require.NoError(t, err)
+	var set vestingPool
+	require.NoError(t, set.Decode([]byte(resp)))
+
+	// the cliff
+	_, err = two.unlock(t, vsc, set.ID, 55, balances)
+	requireErrMsg(t, err, "unlock_vesting_pool_failed: "+
+		"vesting pool: "+errZeroVesting.Error())
+
+	var before = balances.balances[client.id]
+	_, err = client.revoke(t, vsc, set.ID, two.id, 55, balances)
+	require.NoError(t, err)
+	_, err = client.revoke(t, vsc, set.ID, one.id, 55, balances)
+	require.NoError(t, err)
+	assert.EqualValues(t, 260e10, balances.balances[client.id]-before)
+
+	_, err = client.revoke(t, vsc, set.ID, one.id, 56, balances)
+	requireErrMsg(t, err, "revoke_vesting_failed: "+
+		"destination "+one.id+" already revoked")
+
+	// vested tokens are still claimable
+	_, err = one.unlock(t, vsc, set.ID, 90, balances)
+	require.NoError(t, err)
+	assert.EqualValues(t, 40e10, balances.balances[one.id])
+	_, err = two.unlock(t, vsc, set.ID, 90, balances)
+	require.Error(t, err)
+
+	var got *vestingPool
+	got, err = vsc.getPool(set.ID, balances)
+	require.NoError(t, err)
+	assert.Zero(t, got.Balance)
+
+	var inf = got.info(90)
+	assert.EqualValues(t, 55, inf.Destinations[0].Revoked)
+	assert.EqualValues(t, 40e10, inf.Destinations[0].Wanted)
+	assert.EqualValues(t, 50*time.Second, inf.Destinations[1].Cliff)
+}
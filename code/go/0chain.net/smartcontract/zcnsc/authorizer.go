@@ -121,11 +121,24 @@ func (zcn *ZCNSmartContract) DeleteAuthorizer(tran *transaction.Transaction, _ [
 
 	//empty the authorizer's pool
 	var transfer *state.Transfer
-	pool := ans.NodeMap[tran.ClientID].Staking
+	node := ans.NodeMap[tran.ClientID]
+	pool := node.Staking
 	if pool == nil {
 		return "", common.NewError("failed to delete authorizer", "pool is not created")
 	}
 
+	// the stake is locked for the unbonding period first, the authorizer
+	// can still be slashed, the second call releases the stake
+	if gn.UnbondingPeriod > 0 && node.UnbondingStart == 0 {
+		node.startUnbonding(tran.CreationDate, gn)
+		err = ans.Save(balances)
+		if err != nil {
+			return "", common.NewError("failed to delete authorizer", "saving authorizers: "+err.Error())
+		}
+		logging.Logger.Info("authorizer unbonding", zap.String("hash", tran.Hash), zap.String("authorizer_id", tran.ClientID))
+		return string(pool.LockStats(tran)), nil
+	}
+
 	transfer, resp, err = pool.EmptyPool(gn.ID, tran.ClientID, tran)
 	if err != nil {
 		err = common.NewError("failed to delete authorizer", fmt.Sprintf("error emptying pool(%v)", err.Error()))
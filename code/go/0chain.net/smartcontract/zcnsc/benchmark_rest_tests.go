@@ -38,6 +38,10 @@ func BenchmarkRestTests(_ benchmark.BenchData, _ benchmark.SignatureScheme) benc
 				name:     "zcnsc_rest.getAuthorizerNodes",
 				endpoint: sc.getAuthorizerNodes,
 			},
+			{
+				name:     "zcnsc_rest.getPendingBurnTickets",
+				endpoint: sc.getPendingBurnTickets,
+			},
 		},
 	)
 }
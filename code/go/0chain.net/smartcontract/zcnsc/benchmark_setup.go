@@ -54,6 +54,12 @@ func addMockGlobalNode(balances cstate.StateContextI) {
 	gn.MinBurnAmount = config.SmartContractConfig.GetInt64(benchmark.MinBurnAmount)
 	gn.MinStakeAmount = config.SmartContractConfig.GetInt64(benchmark.MinStakeAmount)
 	gn.BurnAddress = config.SmartContractConfig.GetString(benchmark.BurnAddress)
+	gn.MintFee = state.Balance(config.SmartContractConfig.GetInt64(benchmark.MintFee))
+	gn.BurnFee = state.Balance(config.SmartContractConfig.GetInt64(benchmark.BurnFee))
+	gn.UnbondingPeriod = config.SmartContractConfig.GetDuration(benchmark.UnbondingPeriod)
+	gn.BurnTicketLifetime = config.SmartContractConfig.GetDuration(benchmark.BurnTicketLifetime)
+	gn.SlashRate = config.SmartContractConfig.GetFloat64(benchmark.SlashRate)
+	gn.SlashReporterShare = config.SmartContractConfig.GetFloat64(benchmark.SlashReporterShare)
 
 	_, _ = balances.InsertTrieNode(gn.GetKey(), gn)
 }
@@ -4,6 +4,7 @@ import (
 	"0chain.net/chaincore/chain"
 	cstate "0chain.net/chaincore/chain/state"
 	"0chain.net/chaincore/config"
+	"0chain.net/chaincore/state"
 	"0chain.net/chaincore/transaction"
 	"0chain.net/core/common"
 	"0chain.net/core/datastore"
@@ -91,6 +92,12 @@ func BenchmarkTests(data benchmark.BenchData, _ benchmark.SignatureScheme) bench
 				txn:      createRandomTransaction(data.Clients, data.PublicKeys),
 				input:    createMintPayload(data, 10, 110),
 			},
+			{
+				name:     benchmark.Zcn + SlashAuthorizerFunc,
+				endpoint: sc.SlashAuthorizer,
+				txn:      createTransaction(data.Clients[0], data.PublicKeys[0]),
+				input:    createSlashPayload(data, removableAuthorizer+1),
+			},
 		},
 	)
 }
@@ -152,7 +159,8 @@ func createMintPayload(data benchmark.BenchData, from, to int) []byte {
 	client := data.Clients[1]
 
 	for i := from; i < to; i++ {
-		index := randomIndex(len(data.PublicKeys))
+		// every authorizer signs once, the first client is not an authorizer
+		index := 1 + i%(len(data.PublicKeys)-1)
 
 		pb := proofOfBurn{
 			TxnID:             encryption.Hash(strconv.Itoa(i)),
@@ -190,6 +198,41 @@ func createMintPayload(data benchmark.BenchData, from, to int) []byte {
 	return payload.Encode()
 }
 
+// two different signed mint payloads for the same Ethereum transaction
+func createSlashPayload(data benchmark.BenchData, index int) []byte {
+	var payloads []*MintPayload
+
+	for _, amount := range []int64{100, 200} {
+		pb := proofOfBurn{
+			TxnID:             "0xc8285f5304b1B7aAB09a7d26721D6F585448D0ed",
+			Amount:            amount,
+			ReceivingClientID: data.Clients[0],
+			Nonce:             1,
+		}
+
+		err := pb.sign(data.PrivateKeys[index])
+		if err != nil {
+			panic(err)
+		}
+
+		payloads = append(payloads, &MintPayload{
+			EthereumTxnID:     pb.TxnID,
+			Amount:            state.Balance(pb.Amount),
+			Nonce:             pb.Nonce,
+			ReceivingClientID: pb.ReceivingClientID,
+			Signatures: []*AuthorizerSignature{
+				{ID: data.Clients[index], Signature: pb.Signature},
+			},
+		})
+	}
+
+	payload := SlashPayload{
+		AuthorizerID: data.Clients[index],
+		Payloads:     payloads,
+	}
+	return payload.Encode()
+}
+
 func createBurnPayload() []byte {
 	burnNonce = burnNonce + 1
 	payload := BurnPayload{
@@ -57,15 +57,61 @@ func (zcn *ZCNSmartContract) Burn(trans *transaction.Transaction, inputData []by
 		return
 	}
 
+	// the fee is escrowed by the SC and paid to the authorizers signed
+	// the burn ticket
+	fee := gn.BurnFee
+	if int64(fee) >= trans.Value {
+		err = common.NewError("failed to burn", fmt.Sprintf("amount requested(%v) is not greater than burn fee (%v)", trans.Value, fee))
+		return
+	}
+	if fee > 0 {
+		err = balances.AddTransfer(state.NewTransfer(trans.ClientID, ADDRESS, fee))
+		if err != nil {
+			return "", err
+		}
+	}
+
 	// burn the tokens
-	err = balances.AddTransfer(state.NewTransfer(trans.ClientID, gn.BurnAddress, state.Balance(trans.Value)))
+	amount := trans.Value - int64(fee)
+	err = balances.AddTransfer(state.NewTransfer(trans.ClientID, gn.BurnAddress, state.Balance(amount)))
 	if err != nil {
 		return "", err
 	}
 
+	// register the burn ticket for the authorizers to sign
+	ticket := &BurnTicket{
+		TxnID:           trans.Hash,
+		ClientID:        trans.ClientID,
+		Nonce:           payload.Nonce,
+		Amount:          amount,
+		EthereumAddress: payload.EthereumAddress,
+		CreationDate:    trans.CreationDate,
+		Fee:             fee,
+	}
+	err = ticket.Save(balances)
+	if err != nil {
+		return "", common.NewError("failed to burn", "saving burn ticket: "+err.Error())
+	}
+
+	var pending *BurnTickets
+	pending, err = GetPendingBurnTickets(balances)
+	if err != nil {
+		return "", common.NewError("failed to burn", "can't get pending burn tickets: "+err.Error())
+	}
+	pending.add(trans.Hash)
+	err = pending.Save(balances)
+	if err != nil {
+		return "", common.NewError("failed to burn", "saving pending burn tickets: "+err.Error())
+	}
+
+	err = pruneBurnTickets(trans.CreationDate, gn, balances)
+	if err != nil {
+		return "", common.NewError("failed to burn", "pruning burn tickets: "+err.Error())
+	}
+
 	response := &BurnPayloadResponse{
 		TxnID:           trans.Hash,
-		Amount:          trans.Value,
+		Amount:          amount,
 		Nonce:           payload.Nonce,
 		EthereumAddress: payload.EthereumAddress,
 	}
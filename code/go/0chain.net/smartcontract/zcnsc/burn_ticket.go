@@ -0,0 +1,390 @@
+package zcnsc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"0chain.net/chaincore/chain"
+	cstate "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+)
+
+var (
+	PendingBurnTicketsKey = ADDRESS + encryption.Hash("pending_burn_tickets")
+	ProvedBurnTicketsKey  = ADDRESS + encryption.Hash("proved_burn_tickets")
+)
+
+// BurnTicket is a registered burn the authorizers sign, the signed ticket is
+// the proof of the burn for the Ethereum side.
+type BurnTicket struct {
+	TxnID           string                 `json:"0chain_txn_id"`
+	ClientID        string                 `json:"client_id"`
+	Nonce           int64                  `json:"nonce"`
+	Amount          int64                  `json:"amount"`
+	EthereumAddress string                 `json:"ethereum_address"`
+	CreationDate    common.Timestamp       `json:"creation_date"`
+	Signatures      []*AuthorizerSignature `json:"signatures"`
+	// Proved is set when the ticket has enough signatures.
+	Proved bool `json:"proved"`
+	// Fee escrowed by the SC, it's paid to the authorizers signed the
+	// ticket when it's proved.
+	Fee state.Balance `json:"fee"`
+}
+
+func getBurnTicketKey(txnID string) datastore.Key {
+	return ADDRESS + "burn_ticket" + txnID
+}
+
+func (bt *BurnTicket) GetKey() datastore.Key {
+	return getBurnTicketKey(bt.TxnID)
+}
+
+func (bt *BurnTicket) Encode() []byte {
+	buff, _ := json.Marshal(bt)
+	return buff
+}
+
+func (bt *BurnTicket) Decode(input []byte) error {
+	err := json.Unmarshal(input, bt)
+	return err
+}
+
+// GetStringToSign returns hash of the ticket signed by the authorizers
+func (bt *BurnTicket) GetStringToSign() string {
+	return encryption.Hash(fmt.Sprintf("%v:%v:%v:%v", bt.TxnID, bt.Amount, bt.Nonce, bt.EthereumAddress))
+}
+
+// isExpired returns true if the ticket is older than given lifetime, zero
+// lifetime means the tickets never expire
+func (bt *BurnTicket) isExpired(now common.Timestamp, lifetime time.Duration) bool {
+	return lifetime > 0 && now >= bt.CreationDate+common.Timestamp(lifetime/time.Second)
+}
+
+func (bt *BurnTicket) isSignedBy(id string) bool {
+	for _, sig := range bt.Signatures {
+		if sig.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// payFee pays the escrowed fee to the authorizers signed the ticket, the
+// rest of the division goes to the first one
+func (bt *BurnTicket) payFee(ans *AuthorizerNodes, balances cstate.StateContextI) (err error) {
+	share, total := splitFee(bt.Fee, len(bt.Signatures))
+	for i, sig := range bt.Signatures {
+		amount := share
+		if i == 0 {
+			amount += bt.Fee - total
+		}
+		if amount <= 0 {
+			continue
+		}
+		err = balances.AddTransfer(state.NewTransfer(ADDRESS, sig.ID, amount))
+		if err != nil {
+			return
+		}
+		if an := ans.NodeMap[sig.ID]; an != nil {
+			an.Rewards += amount
+		}
+	}
+	return ans.Save(balances)
+}
+
+func (bt *BurnTicket) Save(balances cstate.StateContextI) (err error) {
+	_, err = balances.InsertTrieNode(bt.GetKey(), bt)
+	return
+}
+
+func GetBurnTicket(txnID string, balances cstate.StateContextI) (*BurnTicket, error) {
+	val, err := balances.GetTrieNode(getBurnTicketKey(txnID))
+	if err != nil {
+		return nil, err
+	}
+	bt := &BurnTicket{}
+	if err := bt.Decode(val.Encode()); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return bt, nil
+}
+
+// BurnTickets is list of transaction hashes of pending or proved burn
+// tickets in order of their registration.
+type BurnTickets struct {
+	TxnIDs []string `json:"txn_ids"`
+
+	key datastore.Key
+}
+
+func (bts *BurnTickets) Encode() []byte {
+	buff, _ := json.Marshal(bts)
+	return buff
+}
+
+func (bts *BurnTickets) Decode(input []byte) error {
+	err := json.Unmarshal(input, bts)
+	return err
+}
+
+func (bts *BurnTickets) add(txnID string) {
+	bts.TxnIDs = append(bts.TxnIDs, txnID)
+}
+
+func (bts *BurnTickets) remove(txnID string) {
+	for i, id := range bts.TxnIDs {
+		if id == txnID {
+			bts.TxnIDs = append(bts.TxnIDs[:i], bts.TxnIDs[i+1:]...)
+			return
+		}
+	}
+}
+
+// prune removes expired tickets from the head of the list and deletes them,
+// the escrowed fee of an expired not proved ticket is returned to its client
+func (bts *BurnTickets) prune(now common.Timestamp, gn *GlobalNode, balances cstate.StateContextI) (err error) {
+	if gn.BurnTicketLifetime <= 0 {
+		return
+	}
+	for len(bts.TxnIDs) > 0 {
+		var bt *BurnTicket
+		switch bt, err = GetBurnTicket(bts.TxnIDs[0], balances); {
+		case err == util.ErrValueNotPresent:
+			// already removed
+		case err != nil:
+			return
+		case !bt.isExpired(now, gn.BurnTicketLifetime):
+			return nil
+		default:
+			if !bt.Proved && bt.Fee > 0 {
+				err = balances.AddTransfer(state.NewTransfer(ADDRESS, bt.ClientID, bt.Fee))
+				if err != nil {
+					return
+				}
+			}
+			if _, err = balances.DeleteTrieNode(bt.GetKey()); err != nil {
+				return
+			}
+		}
+		bts.TxnIDs = bts.TxnIDs[1:]
+	}
+	return nil
+}
+
+func (bts *BurnTickets) Save(balances cstate.StateContextI) (err error) {
+	key := bts.key
+	if key == "" {
+		key = PendingBurnTicketsKey
+	}
+	_, err = balances.InsertTrieNode(key, bts)
+	return
+}
+
+func getBurnTickets(key datastore.Key, balances cstate.StateContextI) (*BurnTickets, error) {
+	bts := &BurnTickets{key: key}
+	val, err := balances.GetTrieNode(key)
+	if err == util.ErrValueNotPresent || (err == nil && val == nil) {
+		return bts, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := bts.Decode(val.Encode()); err != nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrDecoding, err)
+	}
+	return bts, nil
+}
+
+// GetPendingBurnTickets returns list of not proved burn tickets
+func GetPendingBurnTickets(balances cstate.StateContextI) (*BurnTickets, error) {
+	return getBurnTickets(PendingBurnTicketsKey, balances)
+}
+
+// GetProvedBurnTickets returns list of proved burn tickets kept until
+// they expire
+func GetProvedBurnTickets(balances cstate.StateContextI) (*BurnTickets, error) {
+	return getBurnTickets(ProvedBurnTicketsKey, balances)
+}
+
+// pruneBurnTickets removes expired pending and proved burn tickets
+func pruneBurnTickets(now common.Timestamp, gn *GlobalNode, balances cstate.StateContextI) error {
+	for _, get := range []func(cstate.StateContextI) (*BurnTickets, error){
+		GetPendingBurnTickets,
+		GetProvedBurnTickets,
+	} {
+		bts, err := get(balances)
+		if err != nil {
+			return err
+		}
+		before := len(bts.TxnIDs)
+		if err = bts.prune(now, gn, balances); err != nil {
+			return err
+		}
+		if len(bts.TxnIDs) == before {
+			continue
+		}
+		if err = bts.Save(balances); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MintRecord is a processed mint, the Ethereum transaction can be minted
+// only once regardless of the client.
+type MintRecord struct {
+	EthereumTxnID string `json:"ethereum_txn_id"`
+	ClientID      string `json:"client_id"`
+	Amount        int64  `json:"amount"`
+	Nonce         int64  `json:"nonce"`
+	TxnID         string `json:"0chain_txn_id"`
+}
+
+func getMintRecordKey(ethereumTxnID string) datastore.Key {
+	return ADDRESS + "mint" + encryption.Hash(ethereumTxnID)
+}
+
+func (mr *MintRecord) Encode() []byte {
+	buff, _ := json.Marshal(mr)
+	return buff
+}
+
+func (mr *MintRecord) Decode(input []byte) error {
+	err := json.Unmarshal(input, mr)
+	return err
+}
+
+func (mr *MintRecord) Save(balances cstate.StateContextI) (err error) {
+	_, err = balances.InsertTrieNode(getMintRecordKey(mr.EthereumTxnID), mr)
+	return
+}
+
+// isMinted returns true if the Ethereum transaction is already minted
+func isMinted(ethereumTxnID string, balances cstate.StateContextI) (bool, error) {
+	switch _, err := balances.GetTrieNode(getMintRecordKey(ethereumTxnID)); err {
+	case nil:
+		return true, nil
+	case util.ErrValueNotPresent:
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// BurnTicketSignature is a signature of a burn ticket by an authorizer.
+type BurnTicketSignature struct {
+	TxnID     string `json:"0chain_txn_id"`
+	Signature string `json:"signature"`
+}
+
+func (bs *BurnTicketSignature) Encode() []byte {
+	buff, _ := json.Marshal(bs)
+	return buff
+}
+
+func (bs *BurnTicketSignature) Decode(input []byte) error {
+	err := json.Unmarshal(input, bs)
+	return err
+}
+
+// SignBurnTicket sc API function
+// inputData is a BurnTicketSignature.
+// Transaction must be sent by an active authorizer. The ticket is proved
+// when it has enough signatures and moved from the pending tickets to the
+// proved ones. Expired tickets are pruned.
+func (zcn *ZCNSmartContract) SignBurnTicket(trans *transaction.Transaction, inputData []byte, balances cstate.StateContextI) (resp string, err error) {
+	gn, err := GetGlobalNode(balances)
+	if err != nil {
+		return "", common.NewError("failed to sign burn ticket", fmt.Sprintf("failed to get global node error: %s", err.Error()))
+	}
+
+	payload := &BurnTicketSignature{}
+	err = payload.Decode(inputData)
+	if err != nil {
+		return "", common.NewError("failed to sign burn ticket", "malformed payload: "+err.Error())
+	}
+
+	ans, err := GetAuthorizerNodes(balances)
+	if err != nil {
+		return "", common.NewError("failed to sign burn ticket", err.Error())
+	}
+
+	an := ans.NodeMap[trans.ClientID]
+	if an == nil || !an.IsActive(gn) {
+		return "", common.NewError("failed to sign burn ticket", fmt.Sprintf("authorizer (%v) doesn't exist or not active", trans.ClientID))
+	}
+
+	bt, err := GetBurnTicket(payload.TxnID, balances)
+	if err != nil {
+		return "", common.NewError("failed to sign burn ticket", fmt.Sprintf("can't get burn ticket %s: %v", payload.TxnID, err))
+	}
+
+	if bt.isExpired(trans.CreationDate, gn.BurnTicketLifetime) {
+		return "", common.NewError("failed to sign burn ticket", "the ticket is expired")
+	}
+
+	if bt.isSignedBy(an.ID) {
+		return "", common.NewError("failed to sign burn ticket", "the ticket is already signed by the authorizer")
+	}
+
+	signatureScheme := chain.GetServerChain().GetSignatureScheme()
+	if err = signatureScheme.SetPublicKey(an.PublicKey); err != nil {
+		return "", common.NewError("failed to sign burn ticket", "invalid public key: "+err.Error())
+	}
+	ok, err := signatureScheme.Verify(payload.Signature, bt.GetStringToSign())
+	if err != nil || !ok {
+		return "", common.NewError("failed to sign burn ticket", "invalid signature")
+	}
+
+	bt.Signatures = append(bt.Signatures, &AuthorizerSignature{
+		ID:        an.ID,
+		Signature: payload.Signature,
+	})
+
+	signaturesNeeded := int(gn.PercentAuthorizers * float64(len(ans.GetActiveAuthorizers(gn))))
+	if signaturesNeeded < 1 {
+		signaturesNeeded = 1
+	}
+
+	if !bt.Proved && len(bt.Signatures) >= signaturesNeeded {
+		bt.Proved = true
+
+		if err = bt.payFee(ans, balances); err != nil {
+			return "", common.NewError("failed to sign burn ticket", "paying fee: "+err.Error())
+		}
+
+		var pending *BurnTickets
+		if pending, err = GetPendingBurnTickets(balances); err != nil {
+			return "", common.NewError("failed to sign burn ticket", "can't get pending burn tickets: "+err.Error())
+		}
+		pending.remove(bt.TxnID)
+		if err = pending.Save(balances); err != nil {
+			return "", common.NewError("failed to sign burn ticket", "saving pending burn tickets: "+err.Error())
+		}
+
+		var proved *BurnTickets
+		if proved, err = GetProvedBurnTickets(balances); err != nil {
+			return "", common.NewError("failed to sign burn ticket", "can't get proved burn tickets: "+err.Error())
+		}
+		proved.add(bt.TxnID)
+		if err = proved.Save(balances); err != nil {
+			return "", common.NewError("failed to sign burn ticket", "saving proved burn tickets: "+err.Error())
+		}
+	}
+
+	if err = bt.Save(balances); err != nil {
+		return "", common.NewError("failed to sign burn ticket", "saving burn ticket: "+err.Error())
+	}
+
+	if err = pruneBurnTickets(trans.CreationDate, gn, balances); err != nil {
+		return "", common.NewError("failed to sign burn ticket", "pruning burn tickets: "+err.Error())
+	}
+
+	return string(bt.Encode()), nil
+}
@@ -0,0 +1,197 @@
+package zcnsc_test
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"0chain.net/chaincore/chain"
+	"0chain.net/core/common"
+	"0chain.net/core/encryption"
+	. "0chain.net/smartcontract/zcnsc"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Mint_SameEthereumTxnForOtherClient_Fails(t *testing.T) {
+	ctx := MakeMockStateContext()
+	contract := CreateZCNSmartContract()
+
+	payload, _, err := CreateMintPayload(clientId, authorizers)
+	require.NoError(t, err)
+
+	_, err = contract.Mint(CreateTransactionToZcnsc(authorizers[0], tokens), payload.Encode(), ctx)
+	require.NoError(t, err)
+
+	_, err = contract.Mint(CreateTransactionToZcnsc(authorizers[1], tokens), payload.Encode(), ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already minted")
+}
+
+func Test_Burn_RegistersTicket(t *testing.T) {
+	ctx := MakeMockStateContext()
+	contract := CreateZCNSmartContract()
+
+	tr := CreateDefaultTransactionToZcnsc()
+	payload := createBurnPayload()
+	_, err := contract.Burn(tr, payload.Encode(), ctx)
+	require.NoError(t, err)
+
+	bt, err := GetBurnTicket(tr.Hash, ctx)
+	require.NoError(t, err)
+	require.Equal(t, tr.ClientID, bt.ClientID)
+	require.Equal(t, payload.Nonce, bt.Nonce)
+	require.Equal(t, tr.Value, bt.Amount)
+	require.Equal(t, payload.EthereumAddress, bt.EthereumAddress)
+	require.False(t, bt.Proved)
+
+	pending, err := GetPendingBurnTickets(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{tr.Hash}, pending.TxnIDs)
+}
+
+func Test_SignBurnTicket(t *testing.T) {
+	ctx := MakeMockStateContext()
+	sc := CreateZCNSmartContract()
+
+	// authorizer with known keys
+	scheme := chain.GetServerChain().GetSignatureScheme()
+	require.NoError(t, scheme.GenerateKeys())
+
+	tr := CreateTransactionToZcnsc("signer", 100)
+	an := GetNewAuthorizer(scheme.GetPublicKey(), "signer", "https://localhost:9876")
+	_, _, err := an.Staking.DigPool(tr.Hash, tr)
+	require.NoError(t, err)
+
+	ans, err := GetAuthorizerNodes(ctx)
+	require.NoError(t, err)
+	require.NoError(t, ans.AddAuthorizer(an))
+	require.NoError(t, ans.Save(ctx))
+
+	gn, err := GetGlobalNode(ctx)
+	require.NoError(t, err)
+	gn.BurnFee = 30
+	require.NoError(t, gn.Save(ctx))
+
+	burn := CreateDefaultTransactionToZcnsc()
+	_, err = sc.Burn(burn, createBurnPayload().Encode(), ctx)
+	require.NoError(t, err)
+
+	bt, err := GetBurnTicket(burn.Hash, ctx)
+	require.NoError(t, err)
+
+	// not an authorizer
+	payload := &BurnTicketSignature{TxnID: burn.Hash}
+	_, err = sc.SignBurnTicket(CreateTransactionToZcnsc("stranger", 0), payload.Encode(), ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "doesn't exist or not active")
+
+	// invalid signature
+	payload.Signature, err = scheme.Sign(encryption.Hash(burn.Hash))
+	require.NoError(t, err)
+	_, err = sc.SignBurnTicket(tr, payload.Encode(), ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid signature")
+
+	payload.Signature, err = scheme.Sign(bt.GetStringToSign())
+	require.NoError(t, err)
+	_, err = sc.SignBurnTicket(tr, payload.Encode(), ctx)
+	require.NoError(t, err)
+
+	bt, err = GetBurnTicket(burn.Hash, ctx)
+	require.NoError(t, err)
+	require.True(t, bt.Proved)
+	require.Len(t, bt.Signatures, 1)
+	require.Equal(t, an.ID, bt.Signatures[0].ID)
+
+	pending, err := GetPendingBurnTickets(ctx)
+	require.NoError(t, err)
+	require.Empty(t, pending.TxnIDs)
+
+	// the escrowed fee is paid to the signer
+	transfers := ctx.GetTransfers()
+	last := transfers[len(transfers)-1]
+	require.Equal(t, ADDRESS, last.ClientID)
+	require.Equal(t, an.ID, last.ToClientID)
+	require.EqualValues(t, 30, last.Amount)
+
+	ans, err = GetAuthorizerNodes(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 30, ans.NodeMap[an.ID].Rewards)
+
+	// signed once
+	_, err = sc.SignBurnTicket(tr, payload.Encode(), ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already signed")
+}
+
+func Test_BurnTickets_PruneExpired(t *testing.T) {
+	ctx := MakeMockStateContext()
+	sc := CreateZCNSmartContract()
+
+	gn, err := GetGlobalNode(ctx)
+	require.NoError(t, err)
+	gn.BurnFee = 30
+	gn.BurnTicketLifetime = time.Hour
+	require.NoError(t, gn.Save(ctx))
+
+	first := CreateDefaultTransactionToZcnsc()
+	_, err = sc.Burn(first, createBurnPayload().Encode(), ctx)
+	require.NoError(t, err)
+
+	// the first ticket expires
+	payload := createBurnPayload()
+	payload.Nonce = 2
+	second := CreateDefaultTransactionToZcnsc()
+	second.Hash = "second"
+	second.CreationDate += common.Timestamp(time.Hour / time.Second)
+	_, err = sc.Burn(second, payload.Encode(), ctx)
+	require.NoError(t, err)
+
+	_, err = GetBurnTicket(first.Hash, ctx)
+	require.Error(t, err)
+	pending, err := GetPendingBurnTickets(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{second.Hash}, pending.TxnIDs)
+
+	// the fee of the expired ticket is returned
+	transfers := ctx.GetTransfers()
+	last := transfers[len(transfers)-1]
+	require.Equal(t, ADDRESS, last.ClientID)
+	require.Equal(t, first.ClientID, last.ToClientID)
+	require.EqualValues(t, 30, last.Amount)
+
+	// an expired ticket can't be signed
+	an := CreateMockAuthorizer(authorizers[0])
+	signer := CreateTransactionToZcnsc(an.ID, 0)
+	signer.CreationDate = second.CreationDate + common.Timestamp(time.Hour/time.Second)
+	_, err = sc.SignBurnTicket(signer, (&BurnTicketSignature{TxnID: second.Hash}).Encode(), ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "expired")
+}
+
+func Test_BurnTicketHandlers(t *testing.T) {
+	ctx := MakeMockStateContext()
+	sc := NewZCNSmartContract().(*ZCNSmartContract)
+
+	tr := CreateDefaultTransactionToZcnsc()
+	_, err := sc.Burn(tr, createBurnPayload().Encode(), ctx)
+	require.NoError(t, err)
+
+	getBurnTicket := sc.RestHandlers["/getBurnTicket"]
+	_, err = getBurnTicket(context.Background(), url.Values{}, ctx)
+	require.Error(t, err)
+
+	resp, err := getBurnTicket(context.Background(), url.Values{"hash": {tr.Hash}}, ctx)
+	require.NoError(t, err)
+	require.Equal(t, tr.Hash, resp.(*BurnTicket).TxnID)
+
+	getPending := sc.RestHandlers["/getPendingBurnTickets"]
+	resp, err = getPending(context.Background(), url.Values{}, ctx)
+	require.NoError(t, err)
+	require.Len(t, resp, 1)
+
+	resp, err = getPending(context.Background(), url.Values{"authorizer_id": {authorizers[0]}}, ctx)
+	require.NoError(t, err)
+	require.Len(t, resp, 1)
+}
@@ -165,6 +165,52 @@ func MakeMockStateContext() *mocks.StateContextI {
 				return nil
 			})
 
+	/// Other nodes
+
+	nodes := make(map[datastore.Key]util.Serializable)
+
+	for _, nodeType := range []string{
+		"*zcnsc.AuthorizerSlashing",
+		"*zcnsc.BurnTicket",
+		"*zcnsc.BurnTickets",
+		"*zcnsc.MintRecord",
+	} {
+		ctx.
+			On("InsertTrieNode", mock.AnythingOfType("string"), mock.AnythingOfType(nodeType)).
+			Return(
+				func(key datastore.Key, node util.Serializable) datastore.Key {
+					nodes[key] = node
+					return ""
+				},
+				func(_ datastore.Key, _ util.Serializable) error {
+					return nil
+				})
+	}
+
+	ctx.
+		On("GetTrieNode", mock.AnythingOfType("string")).
+		Return(
+			func(key datastore.Key) util.Serializable {
+				return nodes[key]
+			},
+			func(key datastore.Key) error {
+				if _, ok := nodes[key]; !ok {
+					return util.ErrValueNotPresent
+				}
+				return nil
+			})
+
+	ctx.
+		On("DeleteTrieNode", mock.AnythingOfType("string")).
+		Return(
+			func(key datastore.Key) datastore.Key {
+				delete(nodes, key)
+				return key
+			},
+			func(_ datastore.Key) error {
+				return nil
+			})
+
 	/// AddMint
 
 	for _, authorizer := range authorizers {
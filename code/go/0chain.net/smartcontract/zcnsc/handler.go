@@ -6,6 +6,7 @@ import (
 	"net/url"
 
 	cState "0chain.net/chaincore/chain/state"
+	"0chain.net/core/common"
 )
 
 func (zcn *ZCNSmartContract) getAuthorizerNodes(
@@ -19,3 +20,47 @@ func (zcn *ZCNSmartContract) getAuthorizerNodes(
 	}
 	return an, err
 }
+
+// getBurnTicket returns the burn ticket with signatures of the authorizers,
+// the signed ticket is the proof of the burn
+func (zcn *ZCNSmartContract) getBurnTicket(
+	_ context.Context,
+	params url.Values,
+	balances cState.StateContextI,
+) (interface{}, error) {
+	hash := params.Get("hash")
+	if hash == "" {
+		return nil, common.NewErrBadRequest("missing 'hash' URL query parameter")
+	}
+	bt, err := GetBurnTicket(hash, balances)
+	if err != nil {
+		return nil, smartcontract.NewErrNoResourceOrErrInternal(err, true, "can't get burn ticket")
+	}
+	return bt, nil
+}
+
+// getPendingBurnTickets returns not proved burn tickets, given an
+// 'authorizer_id' the tickets signed by the authorizer are omitted
+func (zcn *ZCNSmartContract) getPendingBurnTickets(
+	_ context.Context,
+	params url.Values,
+	balances cState.StateContextI,
+) (interface{}, error) {
+	authorizerID := params.Get("authorizer_id")
+	pending, err := GetPendingBurnTickets(balances)
+	if err != nil {
+		return nil, smartcontract.NewErrNoResourceOrErrInternal(err, true, "can't get pending burn tickets")
+	}
+	tickets := make([]*BurnTicket, 0, len(pending.TxnIDs))
+	for _, id := range pending.TxnIDs {
+		bt, err := GetBurnTicket(id, balances)
+		if err != nil {
+			return nil, smartcontract.NewErrNoResourceOrErrInternal(err, true, "can't get burn ticket")
+		}
+		if authorizerID != "" && bt.isSignedBy(authorizerID) {
+			continue
+		}
+		tickets = append(tickets, bt)
+	}
+	return tickets, nil
+}
@@ -28,6 +28,16 @@ func (zcn *ZCNSmartContract) Mint(trans *transaction.Transaction, inputData []by
 		return
 	}
 
+	// the ethereum transaction can be minted only once across all users
+	minted, err := isMinted(payload.EthereumTxnID, balances)
+	if err != nil {
+		return "", common.NewError("failed to mint", "can't check mint records: "+err.Error())
+	}
+	if minted {
+		err = common.NewError("failed to mint", fmt.Sprintf("ethereum transaction %s is already minted", payload.EthereumTxnID))
+		return
+	}
+
 	// get user node
 	un, err := GetUserNode(trans.ClientID, balances)
 	if err != nil && payload.Nonce != 1 {
@@ -61,20 +71,27 @@ func (zcn *ZCNSmartContract) Mint(trans *transaction.Transaction, inputData []by
 		return
 	}
 
-	// check number of authorizers
-	signaturesNeeded := int(gn.PercentAuthorizers * float64(len(ans.NodeMap)))
+	// check number of authorizers, only active ones are counted
+	signaturesNeeded := int(gn.PercentAuthorizers * float64(len(ans.GetActiveAuthorizers(gn))))
 	if signaturesNeeded > len(payload.Signatures) {
 		err = common.NewError("failed to mint", fmt.Sprintf("number of authorizers(%v) is lower than need signatures (%v)", len(payload.Signatures), signaturesNeeded))
 		return
 	}
 
 	// verify signatures of authorizers
-	err = payload.verifySignatures(ans)
+	err = payload.verifySignatures(ans, gn)
 	if err != nil {
 		err = common.NewError("failed to mint", "failed to verify signatures with error: "+err.Error())
 		return
 	}
 
+	// pay the fee to the signers
+	share, fee := splitFee(gn.MintFee, len(payload.Signatures))
+	if fee > payload.Amount {
+		err = common.NewError("failed to mint", fmt.Sprintf("amount requested(%v) is lower than mint fee (%v)", payload.Amount, fee))
+		return
+	}
+
 	// increase the nonce
 	un.Nonce++
 
@@ -83,19 +100,51 @@ func (zcn *ZCNSmartContract) Mint(trans *transaction.Transaction, inputData []by
 		&state.Mint{
 			Minter:     gn.ID,
 			ToClientID: trans.ClientID,
-			Amount:     payload.Amount,
+			Amount:     payload.Amount - fee,
 		})
 
 	if err != nil {
 		return
 	}
 
+	if share > 0 {
+		for _, sig := range payload.Signatures {
+			err = balances.AddMint(
+				&state.Mint{
+					Minter:     gn.ID,
+					ToClientID: sig.ID,
+					Amount:     share,
+				})
+			if err != nil {
+				return
+			}
+			ans.NodeMap[sig.ID].Rewards += share
+		}
+
+		err = ans.Save(balances)
+		if err != nil {
+			return
+		}
+	}
+
 	// Save the user node
 	err = un.Save(balances)
 	if err != nil {
 		return
 	}
 
+	record := &MintRecord{
+		EthereumTxnID: payload.EthereumTxnID,
+		ClientID:      trans.ClientID,
+		Amount:        int64(payload.Amount),
+		Nonce:         payload.Nonce,
+		TxnID:         trans.Hash,
+	}
+	err = record.Save(balances)
+	if err != nil {
+		return "", common.NewError("failed to mint", "saving mint record: "+err.Error())
+	}
+
 	resp = string(payload.Encode())
 	return
 }
@@ -47,6 +47,9 @@ func Test_FuzzyMintTest(t *testing.T) {
 	for _, authorizer := range authorizers {
 		transaction := CreateTransactionToZcnsc(authorizer, tokens)
 
+		// an ethereum transaction can be minted once
+		payload.EthereumTxnID = txHash + authorizer
+
 		response, err := contract.Mint(transaction, payload.Encode(), ctx)
 
 		require.NoError(t, err, "Testing authorizer: '%s'", authorizer)
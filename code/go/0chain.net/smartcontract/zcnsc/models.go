@@ -34,6 +34,22 @@ type GlobalNode struct {
 	MinStakeAmount     int64         `json:"min_stake_amount"`
 	BurnAddress        string        `json:"burn_address"`
 	MinAuthorizers     int64         `json:"min_authorizers"`
+	// MintFee is paid from minted tokens to authorizers signed the mint.
+	MintFee state.Balance `json:"mint_fee"`
+	// BurnFee is paid from burned tokens to the authorizers signed the
+	// burn ticket.
+	BurnFee state.Balance `json:"burn_fee"`
+	// UnbondingPeriod is time the stake of a deleted authorizer is locked
+	// and can be slashed before it's released.
+	UnbondingPeriod time.Duration `json:"unbonding_period"`
+	// BurnTicketLifetime is time a burn ticket is kept, an expired not
+	// proved ticket can't be signed and its fee is returned to the client.
+	BurnTicketLifetime time.Duration `json:"burn_ticket_lifetime"`
+	// SlashRate is part of the stake slashed for a conflicting signature.
+	SlashRate float64 `json:"slash_rate"`
+	// SlashReporterShare is part of the slashed tokens paid to the reporter,
+	// the rest is burned.
+	SlashReporterShare float64 `json:"slash_reporter_share"`
 }
 
 func (gn *GlobalNode) GetKey() datastore.Key {
@@ -96,6 +112,12 @@ func GetGlobalNode(balances cstate.StateContextI) (*GlobalNode, error) {
 	gn.MinBurnAmount = config.SmartContractConfig.GetInt64("smart_contracts.zcn.min_burn_amount")
 	gn.MinStakeAmount = config.SmartContractConfig.GetInt64("smart_contracts.zcn.min_stake_amount")
 	gn.BurnAddress = config.SmartContractConfig.GetString("smart_contracts.zcn.burn_address")
+	gn.MintFee = state.Balance(config.SmartContractConfig.GetInt64("smart_contracts.zcn.mint_fee"))
+	gn.BurnFee = state.Balance(config.SmartContractConfig.GetInt64("smart_contracts.zcn.burn_fee"))
+	gn.UnbondingPeriod = config.SmartContractConfig.GetDuration("smart_contracts.zcn.unbonding_period")
+	gn.BurnTicketLifetime = config.SmartContractConfig.GetDuration("smart_contracts.zcn.burn_ticket_lifetime")
+	gn.SlashRate = config.SmartContractConfig.GetFloat64("smart_contracts.zcn.slash_rate")
+	gn.SlashReporterShare = config.SmartContractConfig.GetFloat64("smart_contracts.zcn.slash_reporter_share")
 
 	return gn, nil
 }
@@ -205,9 +227,17 @@ func (mp *MintPayload) GetStringToSign() string {
 	return encryption.Hash(fmt.Sprintf("%v:%v:%v:%v", mp.EthereumTxnID, mp.Amount, mp.Nonce, mp.ReceivingClientID))
 }
 
-func (mp *MintPayload) verifySignatures(ans *AuthorizerNodes) (err error) {
+func (mp *MintPayload) verifySignatures(ans *AuthorizerNodes, gn *GlobalNode) (err error) {
 	signatureScheme := chain.GetServerChain().GetSignatureScheme()
 	toSign := mp.GetStringToSign()
+	signed := make(map[string]bool, len(mp.Signatures))
+	for _, v := range mp.Signatures {
+		if signed[v.ID] {
+			return fmt.Errorf("duplicate signature of authorizer %s", v.ID)
+		}
+		signed[v.ID] = true
+	}
+
 	for _, v := range mp.Signatures {
 		if v.ID == "" {
 			return errors.New("authorizer ID is empty in a signature")
@@ -217,6 +247,10 @@ func (mp *MintPayload) verifySignatures(ans *AuthorizerNodes) (err error) {
 			return fmt.Errorf("authorizer %s not found in authorizers", v.ID)
 		}
 
+		if !ans.NodeMap[v.ID].IsActive(gn) {
+			return fmt.Errorf("authorizer %s is not active", v.ID)
+		}
+
 		key := ans.NodeMap[v.ID].PublicKey
 		_ = signatureScheme.SetPublicKey(key)
 
@@ -285,6 +319,13 @@ type AuthorizerNode struct {
 	PublicKey string                    `json:"public_key"`
 	Staking   *tokenpool.ZcnLockingPool `json:"staking"`
 	URL       string                    `json:"url"`
+	// UnbondingStart is time the authorizer was deleted, it doesn't sign
+	// anymore and waits for the stake release.
+	UnbondingStart common.Timestamp `json:"unbonding_start,omitempty"`
+	// Rewards is total of fees paid to the authorizer.
+	Rewards state.Balance `json:"rewards,omitempty"`
+	// Slashed is total of tokens slashed from the stake.
+	Slashed state.Balance `json:"slashed,omitempty"`
 }
 
 func (an *AuthorizerNode) Encode() []byte {
@@ -331,6 +372,30 @@ func (an *AuthorizerNode) Decode(input []byte) error {
 		an.URL = *urlStr
 	}
 
+	unbondingStart, ok := objMap["unbonding_start"]
+	if ok {
+		err = json.Unmarshal(*unbondingStart, &an.UnbondingStart)
+		if err != nil {
+			return err
+		}
+	}
+
+	rewards, ok := objMap["rewards"]
+	if ok {
+		err = json.Unmarshal(*rewards, &an.Rewards)
+		if err != nil {
+			return err
+		}
+	}
+
+	slashed, ok := objMap["slashed"]
+	if ok {
+		err = json.Unmarshal(*slashed, &an.Slashed)
+		if err != nil {
+			return err
+		}
+	}
+
 	if an.Staking == nil {
 		an.Staking = &tokenpool.ZcnLockingPool{
 			ZcnPool: tokenpool.ZcnPool{
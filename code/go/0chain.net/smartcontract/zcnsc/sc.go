@@ -20,6 +20,8 @@ const (
 	DeleteAuthorizerFunc = "DeleteAuthorizer"
 	MintFunc             = "mint"
 	BurnFunc             = "burn"
+	SlashAuthorizerFunc  = "SlashAuthorizer"
+	SignBurnTicketFunc   = "SignBurnTicket"
 )
 
 // ZCNSmartContract ...
@@ -42,6 +44,8 @@ func (zcn *ZCNSmartContract) InitSC() {}
 func (zcn *ZCNSmartContract) setSC(sc *smartcontractinterface.SmartContract, _ smartcontractinterface.BCContextI) {
 	zcn.SmartContract = sc
 	zcn.SmartContract.RestHandlers["/getAuthorizerNodes"] = zcn.getAuthorizerNodes
+	zcn.SmartContract.RestHandlers["/getBurnTicket"] = zcn.getBurnTicket
+	zcn.SmartContract.RestHandlers["/getPendingBurnTickets"] = zcn.getPendingBurnTickets
 	zcn.SmartContractExecutionStats[AddAuthorizerFunc] = metrics.GetOrRegisterTimer(fmt.Sprintf("sc:%v:func:%v", zcn.ID, AddAuthorizerFunc), nil)
 }
 
@@ -84,6 +88,10 @@ func (zcn *ZCNSmartContract) Execute(
 		return zcn.AddAuthorizer(trans, inputData, balances)
 	case DeleteAuthorizerFunc:
 		return zcn.DeleteAuthorizer(trans, inputData, balances)
+	case SlashAuthorizerFunc:
+		return zcn.SlashAuthorizer(trans, inputData, balances)
+	case SignBurnTicketFunc:
+		return zcn.SignBurnTicket(trans, inputData, balances)
 	default:
 		return common.NewError("failed execution", "no function with that name").Error(), nil
 	}
@@ -0,0 +1,219 @@
+package zcnsc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+
+	"0chain.net/chaincore/chain"
+	cstate "0chain.net/chaincore/chain/state"
+	"0chain.net/chaincore/state"
+	"0chain.net/chaincore/transaction"
+	"0chain.net/core/common"
+	"0chain.net/core/datastore"
+	"0chain.net/core/encryption"
+	"0chain.net/core/util"
+)
+
+// IsActive returns true if the authorizer signs mints and burns: it's not
+// unbonding and its stake is not less than the min stake amount
+func (an *AuthorizerNode) IsActive(gn *GlobalNode) bool {
+	return an.UnbondingStart == 0 && an.Staking != nil &&
+		int64(an.Staking.Balance) >= gn.MinStakeAmount
+}
+
+// startUnbonding locks the stake of the authorizer for the unbonding period
+func (an *AuthorizerNode) startUnbonding(now common.Timestamp, gn *GlobalNode) {
+	an.UnbondingStart = now
+	an.Staking.TokenLockInterface = &TokenLock{
+		StartTime: now,
+		Duration:  gn.UnbondingPeriod,
+		Owner:     an.ID,
+	}
+}
+
+// slash given part of the stake
+func (an *AuthorizerNode) slash(rate float64) (slashed state.Balance) {
+	slashed = state.Balance(float64(an.Staking.Balance) * rate)
+	if slashed > an.Staking.Balance {
+		slashed = an.Staking.Balance
+	}
+	an.Staking.Balance -= slashed
+	an.Slashed += slashed
+	return
+}
+
+// GetActiveAuthorizers returns sorted IDs of active authorizers
+func (an *AuthorizerNodes) GetActiveAuthorizers(gn *GlobalNode) (ids []string) {
+	for id, node := range an.NodeMap {
+		if node.IsActive(gn) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return
+}
+
+// splitFee splits given fee between given number of authorizers equally,
+// returns share of an authorizer and total of the shares
+func splitFee(fee state.Balance, n int) (share, total state.Balance) {
+	if fee <= 0 || n == 0 {
+		return 0, 0
+	}
+	share = fee / state.Balance(n)
+	return share, share * state.Balance(n)
+}
+
+// SlashPayload is an evidence of two different mint payloads for the same
+// Ethereum transaction signed by the same authorizer.
+type SlashPayload struct {
+	AuthorizerID string         `json:"authorizer_id"`
+	Payloads     []*MintPayload `json:"payloads"`
+}
+
+func (sp *SlashPayload) Encode() []byte {
+	buff, _ := json.Marshal(sp)
+	return buff
+}
+
+func (sp *SlashPayload) Decode(input []byte) error {
+	err := json.Unmarshal(input, sp)
+	return err
+}
+
+// verify the payloads conflict and both are signed by the authorizer
+func (sp *SlashPayload) verify(an *AuthorizerNode) error {
+	if len(sp.Payloads) != 2 || sp.Payloads[0] == nil || sp.Payloads[1] == nil {
+		return errors.New("two mint payloads expected")
+	}
+
+	var a, b = sp.Payloads[0], sp.Payloads[1]
+	if a.EthereumTxnID != b.EthereumTxnID {
+		return errors.New("payloads of different ethereum transactions")
+	}
+	if a.GetStringToSign() == b.GetStringToSign() {
+		return errors.New("the same payload")
+	}
+
+	for _, mp := range sp.Payloads {
+		var signature string
+		for _, sig := range mp.Signatures {
+			if sig != nil && sig.ID == an.ID {
+				signature = sig.Signature
+				break
+			}
+		}
+		if signature == "" {
+			return fmt.Errorf("payload is not signed by authorizer %s", an.ID)
+		}
+
+		signatureScheme := chain.GetServerChain().GetSignatureScheme()
+		if err := signatureScheme.SetPublicKey(an.PublicKey); err != nil {
+			return fmt.Errorf("invalid public key: %v", err)
+		}
+		ok, err := signatureScheme.Verify(signature, mp.GetStringToSign())
+		if err != nil || !ok {
+			return fmt.Errorf("invalid signature of authorizer %s", an.ID)
+		}
+	}
+
+	return nil
+}
+
+func (sp *SlashPayload) getKey() datastore.Key {
+	return ADDRESS + "slashing" + sp.AuthorizerID +
+		encryption.Hash(sp.Payloads[0].EthereumTxnID)
+}
+
+// AuthorizerSlashing is result of a slash payload submitted
+type AuthorizerSlashing struct {
+	AuthorizerID  string        `json:"authorizer_id"`
+	EthereumTxnID string        `json:"ethereum_txn_id"`
+	Reporter      string        `json:"reporter"`
+	Slashed       state.Balance `json:"slashed"`
+	Reward        state.Balance `json:"reward"`
+}
+
+func (as *AuthorizerSlashing) Encode() []byte {
+	buff, _ := json.Marshal(as)
+	return buff
+}
+
+func (as *AuthorizerSlashing) Decode(input []byte) error {
+	err := json.Unmarshal(input, as)
+	return err
+}
+
+// SlashAuthorizer sc API function
+// inputData is a SlashPayload.
+// Anyone can report an authorizer signed two different mint payloads for
+// the same Ethereum transaction. The authorizer's stake is slashed, a part
+// of slashed tokens is paid to the reporter and the rest is burned.
+func (zcn *ZCNSmartContract) SlashAuthorizer(trans *transaction.Transaction, inputData []byte, balances cstate.StateContextI) (resp string, err error) {
+	gn, err := GetGlobalNode(balances)
+	if err != nil {
+		return "", common.NewError("failed to slash authorizer", fmt.Sprintf("failed to get global node error: %s", err.Error()))
+	}
+
+	payload := &SlashPayload{}
+	err = payload.Decode(inputData)
+	if err != nil {
+		return "", common.NewError("failed to slash authorizer", "malformed payload: "+err.Error())
+	}
+
+	ans, err := GetAuthorizerNodes(balances)
+	if err != nil {
+		return "", common.NewError("failed to slash authorizer", err.Error())
+	}
+
+	an := ans.NodeMap[payload.AuthorizerID]
+	if an == nil {
+		return "", common.NewError("failed to slash authorizer", fmt.Sprintf("authorizer (%v) doesn't exist", payload.AuthorizerID))
+	}
+
+	err = payload.verify(an)
+	if err != nil {
+		return "", common.NewError("failed to slash authorizer", "invalid evidence: "+err.Error())
+	}
+
+	switch _, err = balances.GetTrieNode(payload.getKey()); err {
+	case nil:
+		return "", common.NewError("failed to slash authorizer", "the authorizer is already slashed for the transaction")
+	case util.ErrValueNotPresent:
+	default:
+		return "", common.NewError("failed to slash authorizer", err.Error())
+	}
+
+	slashing := &AuthorizerSlashing{
+		AuthorizerID:  an.ID,
+		EthereumTxnID: payload.Payloads[0].EthereumTxnID,
+		Reporter:      trans.ClientID,
+		Slashed:       an.slash(gn.SlashRate),
+	}
+	slashing.Reward = state.Balance(float64(slashing.Slashed) * gn.SlashReporterShare)
+
+	if slashing.Reward > 0 {
+		err = balances.AddTransfer(state.NewTransfer(ADDRESS, trans.ClientID, slashing.Reward))
+		if err != nil {
+			return "", common.NewError("failed to slash authorizer", "paying reporter: "+err.Error())
+		}
+	}
+	if burned := slashing.Slashed - slashing.Reward; burned > 0 {
+		err = balances.AddTransfer(state.NewTransfer(ADDRESS, gn.BurnAddress, burned))
+		if err != nil {
+			return "", common.NewError("failed to slash authorizer", "burning slashed tokens: "+err.Error())
+		}
+	}
+
+	err = ans.Save(balances)
+	if err != nil {
+		return "", common.NewError("failed to slash authorizer", "saving authorizers: "+err.Error())
+	}
+	_, err = balances.InsertTrieNode(payload.getKey(), slashing)
+	if err != nil {
+		return "", common.NewError("failed to slash authorizer", "saving slashing: "+err.Error())
+	}
+
+	return string(slashing.Encode()), nil
+}
@@ -0,0 +1,193 @@
+package zcnsc_test
+
+import (
+	"testing"
+	"time"
+
+	"0chain.net/chaincore/chain"
+	"0chain.net/chaincore/state"
+	"0chain.net/core/common"
+	. "0chain.net/smartcontract/zcnsc"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Mint_PaysFeeToSigners(t *testing.T) {
+	ctx := MakeMockStateContext()
+	ctx.On("AddMint", mock.AnythingOfType("*state.Mint")).Return(nil)
+	contract := CreateZCNSmartContract()
+
+	gn, err := GetGlobalNode(ctx)
+	require.NoError(t, err)
+	gn.MintFee = 31
+	require.NoError(t, gn.Save(ctx))
+
+	payload, _, err := CreateMintPayload(clientId, authorizers)
+	require.NoError(t, err)
+
+	tr := CreateDefaultTransactionToZcnsc()
+	_, err = contract.Mint(tr, payload.Encode(), ctx)
+	require.NoError(t, err)
+
+	ctx.AssertCalled(t, "AddMint", &state.Mint{Minter: ADDRESS, ToClientID: tr.ClientID, Amount: payload.Amount - 30})
+	for _, id := range authorizers {
+		ctx.AssertCalled(t, "AddMint", &state.Mint{Minter: ADDRESS, ToClientID: id, Amount: 10})
+	}
+
+	ans, err := GetAuthorizerNodes(ctx)
+	require.NoError(t, err)
+	for _, id := range authorizers {
+		require.EqualValues(t, 10, ans.NodeMap[id].Rewards)
+	}
+}
+
+func Test_Mint_DuplicateSignature_Fails(t *testing.T) {
+	ctx := MakeMockStateContext()
+	contract := CreateZCNSmartContract()
+
+	payload, _, err := CreateMintPayload(clientId, []string{authorizers[0], authorizers[0]})
+	require.NoError(t, err)
+
+	_, err = contract.Mint(CreateDefaultTransactionToZcnsc(), payload.Encode(), ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "duplicate signature")
+}
+
+func Test_Burn_EscrowsFee(t *testing.T) {
+	ctx := MakeMockStateContext()
+	contract := CreateZCNSmartContract()
+
+	gn, err := GetGlobalNode(ctx)
+	require.NoError(t, err)
+	gn.BurnFee = 30
+	require.NoError(t, gn.Save(ctx))
+
+	tr := CreateDefaultTransactionToZcnsc()
+	_, err = contract.Burn(tr, createBurnPayload().Encode(), ctx)
+	require.NoError(t, err)
+
+	transfers := ctx.GetTransfers()
+	require.Len(t, transfers, 2)
+	require.Equal(t, ADDRESS, transfers[0].ToClientID)
+	require.EqualValues(t, 30, transfers[0].Amount)
+	require.Equal(t, gn.BurnAddress, transfers[1].ToClientID)
+	require.EqualValues(t, tr.Value-30, transfers[1].Amount)
+
+	bt, err := GetBurnTicket(tr.Hash, ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 30, bt.Fee)
+}
+
+func Test_DeleteAuthorizer_Unbonding(t *testing.T) {
+	ctx := MakeMockStateContext()
+	sc := CreateZCNSmartContract()
+
+	gn, err := GetGlobalNode(ctx)
+	require.NoError(t, err)
+	gn.UnbondingPeriod = time.Hour
+	require.NoError(t, gn.Save(ctx))
+
+	// start unbonding
+	tr := CreateTransactionToZcnsc(authorizers[0], 10)
+	_, err = sc.DeleteAuthorizer(tr, nil, ctx)
+	require.NoError(t, err)
+
+	ans, err := GetAuthorizerNodes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(ans.NodeMap))
+	require.Equal(t, tr.CreationDate, ans.NodeMap[authorizers[0]].UnbondingStart)
+	require.False(t, ans.NodeMap[authorizers[0]].IsActive(gn))
+	require.Equal(t, []string{authorizers[1], authorizers[2]}, ans.GetActiveAuthorizers(gn))
+
+	// the stake is locked
+	_, err = sc.DeleteAuthorizer(tr, nil, ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "locked")
+
+	// the stake is released
+	tr.CreationDate += common.Timestamp(time.Hour / time.Second)
+	_, err = sc.DeleteAuthorizer(tr, nil, ctx)
+	require.NoError(t, err)
+
+	ans, err = GetAuthorizerNodes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(ans.NodeMap))
+}
+
+func Test_SlashAuthorizer(t *testing.T) {
+	ctx := MakeMockStateContext()
+	sc := CreateZCNSmartContract()
+
+	gn, err := GetGlobalNode(ctx)
+	require.NoError(t, err)
+	gn.SlashRate = 0.5
+	gn.SlashReporterShare = 0.2
+	require.NoError(t, gn.Save(ctx))
+
+	// authorizer with known keys
+	scheme := chain.GetServerChain().GetSignatureScheme()
+	require.NoError(t, scheme.GenerateKeys())
+
+	tr := CreateTransactionToZcnsc("slashed", 100)
+	an := GetNewAuthorizer(scheme.GetPublicKey(), "slashed", "https://localhost:9876")
+	_, _, err = an.Staking.DigPool(tr.Hash, tr)
+	require.NoError(t, err)
+
+	ans, err := GetAuthorizerNodes(ctx)
+	require.NoError(t, err)
+	require.NoError(t, ans.AddAuthorizer(an))
+	require.NoError(t, ans.Save(ctx))
+
+	sign := func(amount state.Balance) *MintPayload {
+		mp := &MintPayload{
+			EthereumTxnID:     txHash,
+			Amount:            amount,
+			Nonce:             1,
+			ReceivingClientID: clientId,
+		}
+		sig, err := scheme.Sign(mp.GetStringToSign())
+		require.NoError(t, err)
+		mp.Signatures = []*AuthorizerSignature{{ID: an.ID, Signature: sig}}
+		return mp
+	}
+
+	payload := &SlashPayload{
+		AuthorizerID: an.ID,
+		Payloads:     []*MintPayload{sign(200), sign(200)},
+	}
+
+	// not conflicting
+	reporter := CreateTransactionToZcnsc(clientId, 0)
+	_, err = sc.SlashAuthorizer(reporter, payload.Encode(), ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "the same payload")
+
+	// invalid signature
+	payload.Payloads[1] = sign(300)
+	payload.Payloads[1].Signatures[0].Signature = payload.Payloads[0].Signatures[0].Signature
+	_, err = sc.SlashAuthorizer(reporter, payload.Encode(), ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "invalid signature")
+
+	payload.Payloads[1] = sign(300)
+	_, err = sc.SlashAuthorizer(reporter, payload.Encode(), ctx)
+	require.NoError(t, err)
+
+	stake := zcnToBalance(100)
+	ans, err = GetAuthorizerNodes(ctx)
+	require.NoError(t, err)
+	require.Equal(t, stake/2, ans.NodeMap[an.ID].Staking.Balance)
+	require.Equal(t, stake/2, ans.NodeMap[an.ID].Slashed)
+
+	transfers := ctx.GetTransfers()
+	require.Len(t, transfers, 2)
+	require.Equal(t, clientId, transfers[0].ToClientID)
+	require.Equal(t, stake/10, transfers[0].Amount)
+	require.Equal(t, gn.BurnAddress, transfers[1].ToClientID)
+	require.Equal(t, stake/2-stake/10, transfers[1].Amount)
+
+	// slashed once
+	_, err = sc.SlashAuthorizer(reporter, payload.Encode(), ctx)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already slashed")
+}